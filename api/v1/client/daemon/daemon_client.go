@@ -6,6 +6,8 @@ package daemon
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"io"
+
 	"github.com/go-openapi/runtime"
 
 	strfmt "github.com/go-openapi/strfmt"
@@ -55,6 +57,38 @@ func (a *Client) GetConfig(params *GetConfigParams) (*GetConfigOK, error) {
 
 }
 
+/*
+GetConfigLabels gets identity label filter of cilium daemon
+
+Returns the list of label prefixes used to determine which labels
+of an endpoint are relevant for its security identity.
+
+*/
+func (a *Client) GetConfigLabels(params *GetConfigLabelsParams) (*GetConfigLabelsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetConfigLabelsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetConfigLabels",
+		Method:             "GET",
+		PathPattern:        "/config/labels",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetConfigLabelsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetConfigLabelsOK), nil
+
+}
+
 /*
 GetDebuginfo retrieves information about the agent and evironment for debugging
 */
@@ -83,6 +117,39 @@ func (a *Client) GetDebuginfo(params *GetDebuginfoParams) (*GetDebuginfoOK, erro
 
 }
 
+/*
+GetFlows retrieves recent flows observed on the node
+
+Returns the flows retained in the flow buffer of the node which
+match the provided filter. Flows are returned in the order they
+were observed, oldest first.
+
+*/
+func (a *Client) GetFlows(params *GetFlowsParams) (*GetFlowsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetFlowsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetFlows",
+		Method:             "GET",
+		PathPattern:        "/flows",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetFlowsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetFlowsOK), nil
+
+}
+
 /*
 GetHealthz gets health of cilium daemon
 
@@ -172,6 +239,40 @@ func (a *Client) GetMapName(params *GetMapNameParams) (*GetMapNameOK, error) {
 
 }
 
+/*
+GetPcap retrieves recent packet samples in pcapng format
+
+Returns the packet samples of the recent drop and trace
+notifications retained in the packet sample buffer of the node.
+The samples are returned in pcapng format, oldest first. Each
+packet carries a comment describing the notification.
+
+*/
+func (a *Client) GetPcap(params *GetPcapParams, writer io.Writer) (*GetPcapOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetPcapParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetPcap",
+		Method:             "GET",
+		PathPattern:        "/pcap",
+		ProducesMediaTypes: []string{"application/octet-stream"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetPcapReader{formats: a.formats, writer: writer},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetPcapOK), nil
+
+}
+
 /*
 PatchConfig modifies daemon configuration
 
@@ -205,6 +306,40 @@ func (a *Client) PatchConfig(params *PatchConfigParams) (*PatchConfigOK, error)
 
 }
 
+/*
+PutConfigLabels replaces identity label filter of cilium daemon
+
+Replaces the list of label prefixes used to determine the security
+identity of endpoints. The identity of all endpoints is recomputed
+with the new filter. If dry-run is set, the filter is not applied
+and only the impact of the change is reported.
+
+*/
+func (a *Client) PutConfigLabels(params *PutConfigLabelsParams) (*PutConfigLabelsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPutConfigLabelsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "PutConfigLabels",
+		Method:             "PUT",
+		PathPattern:        "/config/labels",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PutConfigLabelsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*PutConfigLabelsOK), nil
+
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
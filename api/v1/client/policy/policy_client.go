@@ -142,6 +142,40 @@ func (a *Client) GetPolicy(params *GetPolicyParams) (*GetPolicyOK, error) {
 
 }
 
+/*
+GetPolicyExplain explains the policy decision for a connection to an endpoint
+
+Evaluates the ingress policy of an endpoint for a connection from
+a security identity, e.g. of a packet dropped by policy. Returns the
+rules selecting the endpoint and the reason why they do or do not
+allow the connection.
+
+*/
+func (a *Client) GetPolicyExplain(params *GetPolicyExplainParams) (*GetPolicyExplainOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetPolicyExplainParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetPolicyExplain",
+		Method:             "GET",
+		PathPattern:        "/policy/explain",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetPolicyExplainReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetPolicyExplainOK), nil
+
+}
+
 /*
 GetPolicyResolve resolves policy for an identity context
 */
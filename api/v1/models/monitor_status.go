@@ -6,6 +6,8 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"strconv"
+
 	strfmt "github.com/go-openapi/strfmt"
 
 	"github.com/go-openapi/errors"
@@ -20,9 +22,20 @@ type MonitorStatus struct {
 	// Number of CPUs to listen on for events.
 	Cpus int64 `json:"cpus,omitempty"`
 
+	// Number of events dropped because the queue of a listener was full,
+	// including listeners which have disconnected.
+	ListenerQueueLost int64 `json:"listener-queue-lost,omitempty"`
+
+	// Listeners currently connected to the node monitor.
+	Listeners []*MonitorListenerStatus `json:"listeners"`
+
 	// Number of samples lost by perf.
 	Lost int64 `json:"lost,omitempty"`
 
+	// Number of samples lost by perf on each CPU since the node monitor
+	// started, indexed by CPU.
+	LostPerCPU []int64 `json:"lost-per-cpu"`
+
 	// Number of pages used for the perf ring buffer.
 	Npages int64 `json:"npages,omitempty"`
 
@@ -35,8 +48,14 @@ type MonitorStatus struct {
 
 /* polymorph MonitorStatus cpus false */
 
+/* polymorph MonitorStatus listener-queue-lost false */
+
+/* polymorph MonitorStatus listeners false */
+
 /* polymorph MonitorStatus lost false */
 
+/* polymorph MonitorStatus lost-per-cpu false */
+
 /* polymorph MonitorStatus npages false */
 
 /* polymorph MonitorStatus pagesize false */
@@ -47,12 +66,44 @@ type MonitorStatus struct {
 func (m *MonitorStatus) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateListeners(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+func (m *MonitorStatus) validateListeners(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Listeners) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Listeners); i++ {
+
+		if swag.IsZero(m.Listeners[i]) { // not required
+			continue
+		}
+
+		if m.Listeners[i] != nil {
+
+			if err := m.Listeners[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("listeners" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *MonitorStatus) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -19,6 +19,9 @@ import (
 
 type NodeElement struct {
 
+	// Disagreements between the sources announcing the node
+	Conflicts []*NodeConflict `json:"conflicts"`
+
 	// Address used for probing cluster connectivity
 	HealthEndpointAddress *NodeAddressing `json:"health-endpoint-address,omitempty"`
 
@@ -32,8 +35,13 @@ type NodeElement struct {
 
 	// Alternative addresses assigned to the node
 	SecondaryAddresses []*NodeAddressingElement `json:"secondary-addresses"`
+
+	// Source of the node information currently in effect
+	Source string `json:"source,omitempty"`
 }
 
+/* polymorph NodeElement conflicts false */
+
 /* polymorph NodeElement health-endpoint-address false */
 
 /* polymorph NodeElement name false */
@@ -42,10 +50,17 @@ type NodeElement struct {
 
 /* polymorph NodeElement secondary-addresses false */
 
+/* polymorph NodeElement source false */
+
 // Validate validates this node element
 func (m *NodeElement) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateConflicts(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateHealthEndpointAddress(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -67,6 +82,33 @@ func (m *NodeElement) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *NodeElement) validateConflicts(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Conflicts) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Conflicts); i++ {
+
+		if swag.IsZero(m.Conflicts[i]) { // not required
+			continue
+		}
+
+		if m.Conflicts[i] != nil {
+
+			if err := m.Conflicts[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("conflicts" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *NodeElement) validateHealthEndpointAddress(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.HealthEndpointAddress) { // not required
@@ -6,6 +6,8 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"strconv"
+
 	strfmt "github.com/go-openapi/strfmt"
 
 	"github.com/go-openapi/errors"
@@ -22,22 +24,69 @@ type ProxyStatus struct {
 
 	// Port range used for proxying
 	PortRange string `json:"port-range,omitempty"`
+
+	// Redirects configured in the proxy, including redirects being drained
+	Redirects []*ProxyRedirect `json:"redirects"`
+
+	// Total number of redirects being drained
+	TotalDrainingRedirects int64 `json:"total-draining-redirects,omitempty"`
+
+	// Total number of redirects, including redirects being drained
+	TotalRedirects int64 `json:"total-redirects,omitempty"`
 }
 
 /* polymorph ProxyStatus ip false */
 
 /* polymorph ProxyStatus port-range false */
 
+/* polymorph ProxyStatus redirects false */
+
+/* polymorph ProxyStatus total-draining-redirects false */
+
+/* polymorph ProxyStatus total-redirects false */
+
 // Validate validates this proxy status
 func (m *ProxyStatus) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateRedirects(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+func (m *ProxyStatus) validateRedirects(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Redirects) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Redirects); i++ {
+
+		if swag.IsZero(m.Redirects[i]) { // not required
+			continue
+		}
+
+		if m.Redirects[i] != nil {
+
+			if err := m.Redirects[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("redirects" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *ProxyStatus) MarshalBinary() ([]byte, error) {
 	if m == nil {
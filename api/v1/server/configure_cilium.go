@@ -35,6 +35,8 @@ func configureAPI(api *restapi.CiliumAPI) http.Handler {
 
 	api.JSONConsumer = runtime.JSONConsumer()
 
+	api.BinProducer = runtime.ByteStreamProducer()
+
 	api.JSONProducer = runtime.JSONProducer()
 
 	api.ServerShutdown = func() {}
@@ -80,6 +80,54 @@ func init() {
         }
       }
     },
+    "/config/labels": {
+      "get": {
+        "description": "Returns the list of label prefixes used to determine which labels\nof an endpoint are relevant for its security identity.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Get identity label filter of Cilium daemon",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/IdentityLabelFilter"
+            }
+          }
+        }
+      },
+      "put": {
+        "description": "Replaces the list of label prefixes used to determine the security\nidentity of endpoints. The identity of all endpoints is recomputed\nwith the new filter. If dry-run is set, the filter is not applied\nand only the impact of the change is reported.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Replace identity label filter of Cilium daemon",
+        "parameters": [
+          {
+            "name": "filter",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/IdentityLabelFilter"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/IdentityLabelFilterChange"
+            }
+          },
+          "400": {
+            "description": "Invalid label prefix",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          }
+        }
+      }
+    },
     "/debuginfo": {
       "get": {
         "tags": [
@@ -452,6 +500,46 @@ func init() {
         }
       }
     },
+    "/flows": {
+      "get": {
+        "description": "Returns the flows retained in the flow buffer of the node which\nmatch the provided filter. Flows are returned in the order they\nwere observed, oldest first.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Retrieve recent flows observed on the node",
+        "parameters": [
+          {
+            "name": "filter",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/FlowFilter"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/Flow"
+              }
+            }
+          },
+          "400": {
+            "description": "Invalid flow filter",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          },
+          "501": {
+            "description": "Flow buffer disabled",
+            "x-go-name": "Disabled"
+          }
+        }
+      }
+    },
     "/healthz": {
       "get": {
         "description": "Returns health and status information of the Cilium daemon and related\ncomponents such as the local container runtime, connected datastore,\nKubernetes integration.\n",
@@ -713,6 +801,31 @@ func init() {
         }
       }
     },
+    "/pcap": {
+      "get": {
+        "description": "Returns the packet samples of the recent drop and trace\nnotifications retained in the packet sample buffer of the node.\nThe samples are returned in pcapng format, oldest first. Each\npacket carries a comment describing the notification.\n",
+        "produces": [
+          "application/octet-stream"
+        ],
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Retrieve recent packet samples in pcapng format",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "string",
+              "format": "binary"
+            }
+          },
+          "501": {
+            "description": "Packet sample buffer disabled",
+            "x-go-name": "Disabled"
+          }
+        }
+      }
+    },
     "/policy": {
       "get": {
         "description": "Returns the entire policy tree with all children.\n",
@@ -822,6 +935,35 @@ func init() {
         }
       }
     },
+    "/policy/explain": {
+      "get": {
+        "description": "Evaluates the ingress policy of an endpoint for a connection from\na security identity, e.g. of a packet dropped by policy. Returns the\nrules selecting the endpoint and the reason why they do or do not\nallow the connection.\n",
+        "tags": [
+          "policy"
+        ],
+        "summary": "Explain the policy decision for a connection to an endpoint",
+        "parameters": [
+          {
+            "$ref": "#/parameters/explain-selector"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/PolicyExplanation"
+            }
+          },
+          "404": {
+            "description": "Endpoint or identity not found",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "NotFound"
+          }
+        }
+      }
+    },
     "/policy/resolve": {
       "get": {
         "tags": [
@@ -1739,6 +1881,144 @@ func init() {
     "Error": {
       "type": "string"
     },
+    "Flow": {
+      "description": "Flow observed by the datapath or the L7 proxy",
+      "type": "object",
+      "properties": {
+        "destination": {
+          "description": "Destination of the flow",
+          "$ref": "#/definitions/FlowEndpoint"
+        },
+        "observation-point": {
+          "description": "Point in the datapath or proxy at which the flow was observed",
+          "type": "string"
+        },
+        "protocol": {
+          "description": "L4 or L7 protocol of the flow",
+          "type": "string"
+        },
+        "reason": {
+          "description": "Drop reason or connection tracking state of the flow",
+          "type": "string"
+        },
+        "source": {
+          "description": "Source of the flow",
+          "$ref": "#/definitions/FlowEndpoint"
+        },
+        "summary": {
+          "description": "Human readable summary of the flow",
+          "type": "string"
+        },
+        "time": {
+          "description": "Time the flow was observed at in RFC3339 format",
+          "type": "string"
+        },
+        "type": {
+          "description": "Type of the monitor event the flow was derived from",
+          "type": "string"
+        },
+        "verdict": {
+          "description": "Verdict of the flow (forwarded, dropped, denied, rate-limited, error)",
+          "type": "string"
+        }
+      }
+    },
+    "FlowEndpoint": {
+      "description": "Source or destination of a flow",
+      "type": "object",
+      "properties": {
+        "id": {
+          "description": "Local endpoint ID",
+          "type": "integer"
+        },
+        "identity": {
+          "description": "Security identity",
+          "type": "integer"
+        },
+        "ip": {
+          "description": "IP address",
+          "type": "string"
+        },
+        "labels": {
+          "description": "Labels of the security identity",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "port": {
+          "description": "L4 port",
+          "type": "integer"
+        }
+      }
+    },
+    "FlowFilter": {
+      "description": "Filter to select flows from the flow buffer",
+      "type": "object",
+      "properties": {
+        "endpoints": {
+          "description": "Only select flows from or to one of these local endpoint IDs",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
+        "identities": {
+          "description": "Only select flows from or to one of these security identities",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
+        "ips": {
+          "description": "Only select flows from or to one of these IP addresses",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "labels": {
+          "description": "Only select flows whose source or destination has all of these labels",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "limit": {
+          "description": "Maximum number of flows to return, the most recent flows are returned",
+          "type": "integer"
+        },
+        "ports": {
+          "description": "Only select flows from or to one of these L4 ports",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
+        "since": {
+          "description": "Only select flows observed after this point in time, either in RFC3339 format or as a duration relative to now such as 5m",
+          "type": "string"
+        },
+        "types": {
+          "description": "Only select flows derived from one of these monitor event types",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "until": {
+          "description": "Only select flows observed before this point in time, either in RFC3339 format or as a duration relative to now such as 5m",
+          "type": "string"
+        },
+        "verdicts": {
+          "description": "Only select flows with one of these verdicts",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
     "FrontendAddress": {
       "description": "Layer 4 address",
       "type": "object",
@@ -1814,6 +2094,48 @@ func init() {
         }
       }
     },
+    "IdentityLabelFilter": {
+      "description": "Label prefixes used to determine the security identity of endpoints",
+      "type": "object",
+      "properties": {
+        "dry-run": {
+          "description": "Only report the impact of the filter without applying it",
+          "type": "boolean"
+        },
+        "prefixes": {
+          "description": "List of label prefixes in the format [source:][!]prefix",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "IdentityLabelFilterChange": {
+      "description": "Impact of a change of the identity label filter",
+      "type": "object",
+      "properties": {
+        "affected-endpoints": {
+          "description": "Number of endpoints whose identity labels change",
+          "type": "integer"
+        },
+        "applied": {
+          "description": "True if the filter has been applied",
+          "type": "boolean"
+        },
+        "new-identities": {
+          "description": "Number of identities which do not exist yet and need to be allocated",
+          "type": "integer"
+        },
+        "prefixes": {
+          "description": "List of label prefixes of the filter",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
     "K8sStatus": {
       "description": "Status of Kubernetes integration",
       "type": "object",
@@ -1972,6 +2294,19 @@ func init() {
         }
       }
     },
+    "MonitorListenerStatus": {
+      "description": "Status of a listener connected to the node monitor",
+      "properties": {
+        "queue-lost": {
+          "description": "Number of events dropped because the queue of the listener was full.",
+          "type": "integer"
+        },
+        "version": {
+          "description": "API version used by the listener.",
+          "type": "string"
+        }
+      }
+    },
     "MonitorStatus": {
       "description": "Status of the node monitor",
       "properties": {
@@ -1979,10 +2314,28 @@ func init() {
           "description": "Number of CPUs to listen on for events.",
           "type": "integer"
         },
+        "listener-queue-lost": {
+          "description": "Number of events dropped because the queue of a listener was full,\nincluding listeners which have disconnected.\n",
+          "type": "integer"
+        },
+        "listeners": {
+          "description": "Listeners currently connected to the node monitor.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/MonitorListenerStatus"
+          }
+        },
         "lost": {
           "description": "Number of samples lost by perf.",
           "type": "integer"
         },
+        "lost-per-cpu": {
+          "description": "Number of samples lost by perf on each CPU since the node monitor\nstarted, indexed by CPU.\n",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
         "npages": {
           "description": "Number of pages used for the perf ring buffer.",
           "type": "integer"
@@ -2031,9 +2384,38 @@ func init() {
         }
       }
     },
+    "NodeConflict": {
+      "description": "Disagreement between a source and the node information in effect",
+      "type": "object",
+      "properties": {
+        "effective-value": {
+          "description": "Value currently in effect",
+          "type": "string"
+        },
+        "field": {
+          "description": "Name of the conflicting node attribute",
+          "type": "string"
+        },
+        "source": {
+          "description": "Source announcing the conflicting value",
+          "type": "string"
+        },
+        "value": {
+          "description": "Value announced by the source",
+          "type": "string"
+        }
+      }
+    },
     "NodeElement": {
       "description": "Known node in the cluster",
       "properties": {
+        "conflicts": {
+          "description": "Disagreements between the sources announcing the node",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/NodeConflict"
+          }
+        },
         "health-endpoint-address": {
           "description": "Address used for probing cluster connectivity",
           "$ref": "#/definitions/NodeAddressing"
@@ -2052,6 +2434,10 @@ func init() {
           "items": {
             "$ref": "#/definitions/NodeAddressingElement"
           }
+        },
+        "source": {
+          "description": "Source of the node information currently in effect",
+          "type": "string"
         }
       }
     },
@@ -2069,6 +2455,60 @@ func init() {
         }
       }
     },
+    "PolicyExplainSelector": {
+      "description": "Connection to an endpoint for which to explain the policy decision",
+      "type": "object",
+      "properties": {
+        "dport": {
+          "description": "Destination port and protocol of the connection",
+          "$ref": "#/definitions/Port"
+        },
+        "endpoint-id": {
+          "description": "ID of the local destination endpoint",
+          "type": "integer"
+        },
+        "source-identity": {
+          "description": "Security identity of the source of the connection",
+          "type": "integer"
+        }
+      }
+    },
+    "PolicyExplanation": {
+      "description": "Explanation of the policy decision for a connection to an endpoint",
+      "type": "object",
+      "properties": {
+        "endpoint-labels": {
+          "description": "Labels of the security identity of the endpoint",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "log": {
+          "description": "Trace of the policy evaluation",
+          "type": "string"
+        },
+        "policy": {
+          "description": "JSON representation of the rules selecting the endpoint on ingress",
+          "type": "string"
+        },
+        "reason": {
+          "description": "Reason why the connection is allowed or denied",
+          "type": "string"
+        },
+        "source-labels": {
+          "description": "Labels of the source identity",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "verdict": {
+          "description": "Policy verdict of the connection",
+          "type": "string"
+        }
+      }
+    },
     "PolicyRule": {
       "description": "A policy rule including the rule labels it derives from",
       "properties": {
@@ -2156,6 +2596,37 @@ func init() {
         }
       }
     },
+    "ProxyRedirect": {
+      "description": "Configured proxy redirection state",
+      "type": "object",
+      "properties": {
+        "drain-deadline": {
+          "description": "Time at which a draining redirect is closed",
+          "type": "string",
+          "format": "date-time"
+        },
+        "name": {
+          "description": "Name of the proxy redirect",
+          "type": "string"
+        },
+        "proxy": {
+          "description": "Name of the proxy this redirect points to",
+          "type": "string"
+        },
+        "proxy-port": {
+          "description": "Host port that this redirect points to",
+          "type": "integer"
+        },
+        "state": {
+          "description": "State of the redirect, draining redirects only serve connections established before their removal",
+          "type": "string",
+          "enum": [
+            "active",
+            "draining"
+          ]
+        }
+      }
+    },
     "ProxyStatistics": {
       "description": "Statistics of a set of proxy redirects for an endpoint",
       "type": "object",
@@ -2197,6 +2668,21 @@ func init() {
         "port-range": {
           "description": "Port range used for proxying",
           "type": "string"
+        },
+        "redirects": {
+          "description": "Redirects configured in the proxy, including redirects being drained",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/ProxyRedirect"
+          }
+        },
+        "total-draining-redirects": {
+          "description": "Total number of redirects being drained",
+          "type": "integer"
+        },
+        "total-redirects": {
+          "description": "Total number of redirects, including redirects being drained",
+          "type": "integer"
         }
       }
     },
@@ -2389,6 +2875,14 @@ func init() {
       "in": "path",
       "required": true
     },
+    "explain-selector": {
+      "description": "Connection for which to explain the policy decision",
+      "name": "explain-selector",
+      "in": "body",
+      "schema": {
+        "$ref": "#/definitions/PolicyExplainSelector"
+      }
+    },
     "identity-id": {
       "type": "string",
       "description": "Cluster wide unique identifier of a security identity.\n",
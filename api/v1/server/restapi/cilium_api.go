@@ -42,6 +42,7 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		APIKeyAuthenticator: security.APIKeyAuth,
 		BearerAuthenticator: security.BearerAuth,
 		JSONConsumer:        runtime.JSONConsumer(),
+		BinProducer:         runtime.ByteStreamProducer(),
 		JSONProducer:        runtime.JSONProducer(),
 		EndpointDeleteEndpointIDHandler: endpoint.DeleteEndpointIDHandlerFunc(func(params endpoint.DeleteEndpointIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointDeleteEndpointID has not yet been implemented")
@@ -58,6 +59,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		DaemonGetConfigHandler: daemon.GetConfigHandlerFunc(func(params daemon.GetConfigParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetConfig has not yet been implemented")
 		}),
+		DaemonGetConfigLabelsHandler: daemon.GetConfigLabelsHandlerFunc(func(params daemon.GetConfigLabelsParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetConfigLabels has not yet been implemented")
+		}),
 		DaemonGetDebuginfoHandler: daemon.GetDebuginfoHandlerFunc(func(params daemon.GetDebuginfoParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetDebuginfo has not yet been implemented")
 		}),
@@ -79,6 +83,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		EndpointGetEndpointIDLogHandler: endpoint.GetEndpointIDLogHandlerFunc(func(params endpoint.GetEndpointIDLogParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointGetEndpointIDLog has not yet been implemented")
 		}),
+		DaemonGetFlowsHandler: daemon.GetFlowsHandlerFunc(func(params daemon.GetFlowsParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetFlows has not yet been implemented")
+		}),
 		DaemonGetHealthzHandler: daemon.GetHealthzHandlerFunc(func(params daemon.GetHealthzParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetHealthz has not yet been implemented")
 		}),
@@ -97,9 +104,15 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		MetricsGetMetricsHandler: metrics.GetMetricsHandlerFunc(func(params metrics.GetMetricsParams) middleware.Responder {
 			return middleware.NotImplemented("operation MetricsGetMetrics has not yet been implemented")
 		}),
+		DaemonGetPcapHandler: daemon.GetPcapHandlerFunc(func(params daemon.GetPcapParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetPcap has not yet been implemented")
+		}),
 		PolicyGetPolicyHandler: policy.GetPolicyHandlerFunc(func(params policy.GetPolicyParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyGetPolicy has not yet been implemented")
 		}),
+		PolicyGetPolicyExplainHandler: policy.GetPolicyExplainHandlerFunc(func(params policy.GetPolicyExplainParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyGetPolicyExplain has not yet been implemented")
+		}),
 		PolicyGetPolicyResolveHandler: policy.GetPolicyResolveHandlerFunc(func(params policy.GetPolicyResolveParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyGetPolicyResolve has not yet been implemented")
 		}),
@@ -133,6 +146,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		IPAMPostIPAMIPHandler: ipam.PostIPAMIPHandlerFunc(func(params ipam.PostIPAMIPParams) middleware.Responder {
 			return middleware.NotImplemented("operation IPAMPostIPAMIP has not yet been implemented")
 		}),
+		DaemonPutConfigLabelsHandler: daemon.PutConfigLabelsHandlerFunc(func(params daemon.PutConfigLabelsParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonPutConfigLabels has not yet been implemented")
+		}),
 		EndpointPutEndpointIDHandler: endpoint.PutEndpointIDHandlerFunc(func(params endpoint.PutEndpointIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointPutEndpointID has not yet been implemented")
 		}),
@@ -168,6 +184,9 @@ type CiliumAPI struct {
 	// JSONConsumer registers a consumer for a "application/json" mime type
 	JSONConsumer runtime.Consumer
 
+	// BinProducer registers a producer for a "application/octet-stream" mime type
+	BinProducer runtime.Producer
+
 	// JSONProducer registers a producer for a "application/json" mime type
 	JSONProducer runtime.Producer
 
@@ -181,6 +200,8 @@ type CiliumAPI struct {
 	ServiceDeleteServiceIDHandler service.DeleteServiceIDHandler
 	// DaemonGetConfigHandler sets the operation handler for the get config operation
 	DaemonGetConfigHandler daemon.GetConfigHandler
+	// DaemonGetConfigLabelsHandler sets the operation handler for the get config labels operation
+	DaemonGetConfigLabelsHandler daemon.GetConfigLabelsHandler
 	// DaemonGetDebuginfoHandler sets the operation handler for the get debuginfo operation
 	DaemonGetDebuginfoHandler daemon.GetDebuginfoHandler
 	// EndpointGetEndpointHandler sets the operation handler for the get endpoint operation
@@ -195,6 +216,8 @@ type CiliumAPI struct {
 	EndpointGetEndpointIDLabelsHandler endpoint.GetEndpointIDLabelsHandler
 	// EndpointGetEndpointIDLogHandler sets the operation handler for the get endpoint ID log operation
 	EndpointGetEndpointIDLogHandler endpoint.GetEndpointIDLogHandler
+	// DaemonGetFlowsHandler sets the operation handler for the get flows operation
+	DaemonGetFlowsHandler daemon.GetFlowsHandler
 	// DaemonGetHealthzHandler sets the operation handler for the get healthz operation
 	DaemonGetHealthzHandler daemon.GetHealthzHandler
 	// PolicyGetIdentityHandler sets the operation handler for the get identity operation
@@ -207,8 +230,12 @@ type CiliumAPI struct {
 	DaemonGetMapNameHandler daemon.GetMapNameHandler
 	// MetricsGetMetricsHandler sets the operation handler for the get metrics operation
 	MetricsGetMetricsHandler metrics.GetMetricsHandler
+	// DaemonGetPcapHandler sets the operation handler for the get pcap operation
+	DaemonGetPcapHandler daemon.GetPcapHandler
 	// PolicyGetPolicyHandler sets the operation handler for the get policy operation
 	PolicyGetPolicyHandler policy.GetPolicyHandler
+	// PolicyGetPolicyExplainHandler sets the operation handler for the get policy explain operation
+	PolicyGetPolicyExplainHandler policy.GetPolicyExplainHandler
 	// PolicyGetPolicyResolveHandler sets the operation handler for the get policy resolve operation
 	PolicyGetPolicyResolveHandler policy.GetPolicyResolveHandler
 	// PrefilterGetPrefilterHandler sets the operation handler for the get prefilter operation
@@ -231,6 +258,8 @@ type CiliumAPI struct {
 	IPAMPostIPAMHandler ipam.PostIPAMHandler
 	// IPAMPostIPAMIPHandler sets the operation handler for the post IP a m IP operation
 	IPAMPostIPAMIPHandler ipam.PostIPAMIPHandler
+	// DaemonPutConfigLabelsHandler sets the operation handler for the put config labels operation
+	DaemonPutConfigLabelsHandler daemon.PutConfigLabelsHandler
 	// EndpointPutEndpointIDHandler sets the operation handler for the put endpoint ID operation
 	EndpointPutEndpointIDHandler endpoint.PutEndpointIDHandler
 	// PolicyPutPolicyHandler sets the operation handler for the put policy operation
@@ -296,6 +325,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "JSONConsumer")
 	}
 
+	if o.BinProducer == nil {
+		unregistered = append(unregistered, "BinProducer")
+	}
+
 	if o.JSONProducer == nil {
 		unregistered = append(unregistered, "JSONProducer")
 	}
@@ -320,6 +353,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "daemon.GetConfigHandler")
 	}
 
+	if o.DaemonGetConfigLabelsHandler == nil {
+		unregistered = append(unregistered, "daemon.GetConfigLabelsHandler")
+	}
+
 	if o.DaemonGetDebuginfoHandler == nil {
 		unregistered = append(unregistered, "daemon.GetDebuginfoHandler")
 	}
@@ -348,6 +385,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "endpoint.GetEndpointIDLogHandler")
 	}
 
+	if o.DaemonGetFlowsHandler == nil {
+		unregistered = append(unregistered, "daemon.GetFlowsHandler")
+	}
+
 	if o.DaemonGetHealthzHandler == nil {
 		unregistered = append(unregistered, "daemon.GetHealthzHandler")
 	}
@@ -372,10 +413,18 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "metrics.GetMetricsHandler")
 	}
 
+	if o.DaemonGetPcapHandler == nil {
+		unregistered = append(unregistered, "daemon.GetPcapHandler")
+	}
+
 	if o.PolicyGetPolicyHandler == nil {
 		unregistered = append(unregistered, "policy.GetPolicyHandler")
 	}
 
+	if o.PolicyGetPolicyExplainHandler == nil {
+		unregistered = append(unregistered, "policy.GetPolicyExplainHandler")
+	}
+
 	if o.PolicyGetPolicyResolveHandler == nil {
 		unregistered = append(unregistered, "policy.GetPolicyResolveHandler")
 	}
@@ -420,6 +469,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "ipam.PostIPAMIPHandler")
 	}
 
+	if o.DaemonPutConfigLabelsHandler == nil {
+		unregistered = append(unregistered, "daemon.PutConfigLabelsHandler")
+	}
+
 	if o.EndpointPutEndpointIDHandler == nil {
 		unregistered = append(unregistered, "endpoint.PutEndpointIDHandler")
 	}
@@ -481,6 +534,9 @@ func (o *CiliumAPI) ProducersFor(mediaTypes []string) map[string]runtime.Produce
 	for _, mt := range mediaTypes {
 		switch mt {
 
+		case "application/octet-stream":
+			result["application/octet-stream"] = o.BinProducer
+
 		case "application/json":
 			result["application/json"] = o.JSONProducer
 
@@ -547,6 +603,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/config"] = daemon.NewGetConfig(o.context, o.DaemonGetConfigHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/config/labels"] = daemon.NewGetConfigLabels(o.context, o.DaemonGetConfigLabelsHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -582,6 +643,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/endpoint/{id}/log"] = endpoint.NewGetEndpointIDLog(o.context, o.EndpointGetEndpointIDLogHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/flows"] = daemon.NewGetFlows(o.context, o.DaemonGetFlowsHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -612,11 +678,21 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/metrics"] = metrics.NewGetMetrics(o.context, o.MetricsGetMetricsHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/pcap"] = daemon.NewGetPcap(o.context, o.DaemonGetPcapHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/policy"] = policy.NewGetPolicy(o.context, o.PolicyGetPolicyHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/policy/explain"] = policy.NewGetPolicyExplain(o.context, o.PolicyGetPolicyExplainHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -672,6 +748,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["POST"]["/ipam/{ip}"] = ipam.NewPostIPAMIP(o.context, o.IPAMPostIPAMIPHandler)
 
+	if o.handlers["PUT"] == nil {
+		o.handlers["PUT"] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/config/labels"] = daemon.NewPutConfigLabels(o.context, o.DaemonPutConfigLabelsHandler)
+
 	if o.handlers["PUT"] == nil {
 		o.handlers["PUT"] = make(map[string]http.Handler)
 	}
@@ -40,6 +40,7 @@
 #include "lib/lxc.h"
 #include "lib/nat46.h"
 #include "lib/policy.h"
+#include "lib/policy_log.h"
 #include "lib/lb.h"
 #include "lib/drop.h"
 #include "lib/dbg.h"
@@ -111,6 +112,20 @@ static inline bool redirect_to_proxy(int verdict, int dir)
 	return verdict > 0 && (dir == CT_NEW || dir == CT_ESTABLISHED);
 }
 
+/* Established connections which were redirected to a proxy when they were
+ * created keep being redirected to the same proxy port as long as they are
+ * allowed, so that a redirect being removed or replaced can drain them. The
+ * proxy port is only recorded in the CT entry if draining is enabled. */
+static inline int established_proxy_verdict(int verdict, int dir,
+					    const struct ct_state *ct_state)
+{
+#ifdef ENABLE_PROXY_DRAIN
+	if (dir == CT_ESTABLISHED && verdict >= 0 && ct_state->proxy_port)
+		return ct_state->proxy_port;
+#endif
+	return verdict;
+}
+
 static inline int ipv6_l3_from_lxc(struct __sk_buff *skb,
 				   struct ipv6_ct_tuple *tuple, int l3_off,
 				   struct ethhdr *eth, struct ipv6hdr *ip6,
@@ -118,6 +133,7 @@ static inline int ipv6_l3_from_lxc(struct __sk_buff *skb,
 {
 	union macaddr router_mac = NODE_MAC;
 	int ret, verdict, l4_off, forwarding_reason, hdrlen;
+	__u8 match_type = POLICY_MATCH_NONE;
 	struct csum_offset csum_off = {};
 	struct endpoint_info *ep;
 	struct lb6_service *svc;
@@ -218,7 +234,7 @@ skip_service_lookup:
 	 * within the cluster, it must match policy or be dropped. If it's
 	 * bound for the host/outside, perform the CIDR policy check. */
 	verdict = policy_can_egress6(skb, tuple, *dstID,
-				     ipv6_ct_tuple_get_daddr(tuple));
+				     ipv6_ct_tuple_get_daddr(tuple), &match_type);
 	if (ret != CT_REPLY && ret != CT_RELATED && verdict < 0) {
 		/* If the connection was previously known and packet is now
 		 * denied, remove the connection tracking entry */
@@ -236,13 +252,18 @@ skip_service_lookup:
 		 * reverse NAT.
 		 */
 		ct_state_new.src_sec_id = SECLABEL;
+		ct_state_new.proxy_port = verdict > 0 ? verdict : 0;
 		ret = ct_create6(get_ct_map6(tuple), tuple, skb, CT_EGRESS, &ct_state_new);
 		if (IS_ERR(ret))
 			return ret;
+		send_policy_verdict_notify(skb, *dstID, tuple->dport,
+					   tuple->nexthdr, 1, match_type,
+					   verdict > 0 ? verdict : 0);
 		monitor = TRACE_PAYLOAD_LEN;
 		break;
 
 	case CT_ESTABLISHED:
+		verdict = established_proxy_verdict(verdict, ret, &ct_state);
 		break;
 
 	case CT_RELATED:
@@ -437,6 +458,7 @@ static inline int handle_ipv4_from_lxc(struct __sk_buff *skb, __u32 *dstID)
 	struct iphdr *ip4;
 	struct ethhdr *eth;
 	int ret, verdict, l3_off = ETH_HLEN, l4_off, forwarding_reason;
+	__u8 match_type = POLICY_MATCH_NONE;
 	struct csum_offset csum_off = {};
 	struct endpoint_info *ep;
 	struct lb4_service *svc;
@@ -524,7 +546,8 @@ skip_service_lookup:
 	/* If the packet is in the establishing direction and it's destined
 	 * within the cluster, it must match policy or be dropped. If it's
 	 * bound for the host/outside, perform the CIDR policy check. */
-	verdict = policy_can_egress4(skb, &tuple, *dstID, ipv4_ct_tuple_get_daddr(&tuple));
+	verdict = policy_can_egress4(skb, &tuple, *dstID, ipv4_ct_tuple_get_daddr(&tuple),
+				     &match_type);
 	if (ret != CT_REPLY && ret != CT_RELATED && verdict < 0) {
 		/* If the connection was previously known and packet is now
 		 * denied, remove the connection tracking entry */
@@ -542,13 +565,18 @@ skip_service_lookup:
 		 * reverse NAT.
 		 */
 		ct_state_new.src_sec_id = SECLABEL;
+		ct_state_new.proxy_port = verdict > 0 ? verdict : 0;
 		ret = ct_create4(get_ct_map4(&tuple), &tuple, skb, CT_EGRESS,
 				 &ct_state_new);
 		if (IS_ERR(ret))
 			return ret;
+		send_policy_verdict_notify(skb, *dstID, tuple.dport,
+					   tuple.nexthdr, 1, match_type,
+					   verdict > 0 ? verdict : 0);
 		break;
 
 	case CT_ESTABLISHED:
+		verdict = established_proxy_verdict(verdict, ret, &ct_state);
 		break;
 
 	case CT_RELATED:
@@ -749,6 +777,7 @@ ipv6_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 	struct ipv6hdr *ip6;
 	struct csum_offset csum_off = {};
 	int ret, l4_off, verdict, hdrlen;
+	__u8 match_type = POLICY_MATCH_NONE;
 	struct ct_state ct_state = {};
 	struct ct_state ct_state_new = {};
 	bool skip_proxy = false;
@@ -813,7 +842,7 @@ ipv6_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 
 	verdict = policy_can_access_ingress(skb, src_label, tuple.dport,
 					    tuple.nexthdr, sizeof(tuple.saddr),
-					    &tuple.saddr, false);
+					    &tuple.saddr, false, &match_type);
 
 	/* Reply packets and related packets are allowed, all others must be
 	 * permitted by policy */
@@ -826,17 +855,25 @@ ipv6_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 		return verdict;
 	}
 
+	verdict = established_proxy_verdict(verdict, ret, &ct_state);
 	if (skip_proxy)
 		verdict = 0;
 
 	if (ret == CT_NEW) {
+		__u8 proto = tuple.nexthdr;
+
 		ct_state_new.orig_dport = tuple.dport;
 		ct_state_new.src_sec_id = src_label;
+		ct_state_new.proxy_port = verdict > 0 ? verdict : 0;
 		ret = ct_create6(get_ct_map6(&tuple), &tuple, skb, CT_INGRESS, &ct_state_new);
 		if (IS_ERR(ret))
 			return ret;
 
 		/* NOTE: tuple has been invalidated after this */
+
+		send_policy_verdict_notify(skb, src_label,
+					   ct_state_new.orig_dport, proto, 0,
+					   match_type, verdict > 0 ? verdict : 0);
 	}
 
 	if (redirect_to_proxy(verdict, *forwarding_reason)) {
@@ -894,6 +931,7 @@ ipv4_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 	struct iphdr *ip4;
 	struct csum_offset csum_off = {};
 	int ret, verdict, l4_off;
+	__u8 match_type = POLICY_MATCH_NONE;
 	struct ct_state ct_state = {};
 	struct ct_state ct_state_new = {};
 	bool skip_proxy = false;
@@ -947,7 +985,7 @@ ipv4_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 
 	verdict = policy_can_access_ingress(skb, src_label, tuple.dport,
 					    tuple.nexthdr, sizeof(orig_sip),
-					    &orig_sip, is_fragment);
+					    &orig_sip, is_fragment, &match_type);
 
 	/* Reply packets and related packets are allowed, all others must be
 	 * permitted by policy */
@@ -960,17 +998,25 @@ ipv4_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 		return verdict;
 	}
 
+	verdict = established_proxy_verdict(verdict, ret, &ct_state);
 	if (skip_proxy)
 		verdict = 0;
 
 	if (ret == CT_NEW) {
+		__u8 proto = tuple.nexthdr;
+
 		ct_state_new.orig_dport = tuple.dport;
 		ct_state_new.src_sec_id = src_label;
+		ct_state_new.proxy_port = verdict > 0 ? verdict : 0;
 		ret = ct_create4(get_ct_map4(&tuple), &tuple, skb, CT_INGRESS, &ct_state_new);
 		if (IS_ERR(ret))
 			return ret;
 
 		/* NOTE: tuple has been invalidated after this */
+
+		send_policy_verdict_notify(skb, src_label,
+					   ct_state_new.orig_dport, proto, 0,
+					   match_type, verdict > 0 ? verdict : 0);
 	}
 
 	if (redirect_to_proxy(verdict, *forwarding_reason)) {
@@ -34,6 +34,13 @@
 #include "lib/drop.h"
 #include "lib/policy.h"
 
+/* Other nodes encapsulate their own traffic with their host identity, which
+ * is the remote node identity from the perspective of this node. */
+static inline __u32 tunnel_src_identity(__u32 identity)
+{
+	return identity == HOST_ID ? REMOTE_NODE_ID : identity;
+}
+
 static inline int handle_ipv6(struct __sk_buff *skb)
 {
 	void *data_end, *data;
@@ -63,7 +70,9 @@ static inline int handle_ipv6(struct __sk_buff *skb)
 			return hdrlen;
 
 		l4_off = l3_off + hdrlen;
-		return ipv6_local_delivery(skb, l3_off, l4_off, key.tunnel_id, ip6, nexthdr, ep, METRIC_INGRESS);
+		return ipv6_local_delivery(skb, l3_off, l4_off,
+					   tunnel_src_identity(key.tunnel_id),
+					   ip6, nexthdr, ep, METRIC_INGRESS);
 	}
 
 to_host:
@@ -112,7 +121,9 @@ static inline int handle_ipv4(struct __sk_buff *skb)
 		if (ep->flags & ENDPOINT_F_HOST)
 			goto to_host;
 
-		return ipv4_local_delivery(skb, ETH_HLEN, l4_off, key.tunnel_id, ip4, ep, METRIC_INGRESS);
+		return ipv4_local_delivery(skb, ETH_HLEN, l4_off,
+					   tunnel_src_identity(key.tunnel_id),
+					   ip4, ep, METRIC_INGRESS);
 	}
 
 to_host:
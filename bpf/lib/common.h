@@ -212,6 +212,7 @@ enum {
 	CILIUM_NOTIFY_DBG_MSG,
 	CILIUM_NOTIFY_DBG_CAPTURE,
 	CILIUM_NOTIFY_TRACE,
+	CILIUM_NOTIFY_POLICY_VERDICT,
 };
 
 #define NOTIFY_COMMON_HDR \
@@ -405,6 +406,15 @@ struct ct_entry {
 	 * notification was sent for the transmit/receive direction. */
 	__u32 last_tx_report;
 	__u32 last_rx_report;
+
+#ifdef ENABLE_PROXY_DRAIN
+	/* Proxy port the connection was redirected to when it was created,
+	 * or 0. Established connections keep being redirected to it while
+	 * the redirect is being drained. */
+	__be16 proxy_port;
+	__u16 pad1;
+	__u32 pad2;
+#endif
 };
 
 struct lb6_key {
@@ -460,6 +470,7 @@ struct ct_state {
 	__be32 svc_addr;
 	__u32 src_sec_id;
 	__u16 slave;
+	__be16 proxy_port;
 };
 
 /* Lifetime of a proxy redirection entry. All proxies should be using TCP
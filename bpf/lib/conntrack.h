@@ -236,6 +236,9 @@ static inline int __inline__ __ct_lookup(void *map, struct __sk_buff *skb,
 			ct_state->rev_nat_index = entry->rev_nat_index;
 			ct_state->loopback = entry->lb_loopback;
 			ct_state->slave = entry->slave;
+#ifdef ENABLE_PROXY_DRAIN
+			ct_state->proxy_port = entry->proxy_port;
+#endif
 		}
 
 #ifdef LXC_NAT46
@@ -625,6 +628,9 @@ static inline int __inline__ ct_create6(void *map, struct ipv6_ct_tuple *tuple,
 	entry.rev_nat_index = ct_state->rev_nat_index;
 	entry.lb_loopback = ct_state->loopback;
 	entry.slave = ct_state->slave;
+#ifdef ENABLE_PROXY_DRAIN
+	entry.proxy_port = ct_state->proxy_port;
+#endif
 	seen_flags.syn = is_tcp;
 	ct_update_timeout(&entry, is_tcp, dir, seen_flags);
 
@@ -700,6 +706,9 @@ static inline int __inline__ ct_create4(void *map, struct ipv4_ct_tuple *tuple,
 	entry.rev_nat_index = ct_state->rev_nat_index;
 	entry.lb_loopback = ct_state->loopback;
 	entry.slave = ct_state->slave;
+#ifdef ENABLE_PROXY_DRAIN
+	entry.proxy_port = ct_state->proxy_port;
+#endif
 	seen_flags.syn = is_tcp;
 	ct_update_timeout(&entry, is_tcp, dir, seen_flags);
 
@@ -21,6 +21,7 @@
 #include "drop.h"
 #include "eps.h"
 #include "maps.h"
+#include "policy_log.h"
 
 /**
  * identity_is_reserved is used to determine whether an identity is one of the
@@ -36,6 +37,11 @@
  * - ReservedIdentityHealth	(4)
  * - ReservedIdentityInit	(5)
  *
+ * The remote node identity is derived from the ipcache for traffic of other
+ * nodes, or from the host identity carried in the tunnel key of traffic which
+ * other nodes encapsulate (see tunnel_src_identity()):
+ * - ReservedIdentityRemoteNode	(6)
+ *
  * Identities 128 and higher are guaranteed to be generated based on user input.
  */
 static inline bool identity_is_reserved(__u32 identity)
@@ -100,7 +106,7 @@ get_proxy_port:
 static inline int __inline__
 __policy_can_access(void *map, struct __sk_buff *skb, __u32 identity,
 		    __u16 dport, __u8 proto, size_t cidr_addr_size,
-		    void *cidr_addr, int dir, bool is_fragment)
+		    void *cidr_addr, int dir, bool is_fragment, __u8 *match_type)
 {
 	struct policy_entry *policy;
 
@@ -121,6 +127,7 @@ __policy_can_access(void *map, struct __sk_buff *skb, __u32 identity,
 			/* FIXME: Use per cpu counters */
 			__sync_fetch_and_add(&policy->packets, 1);
 			__sync_fetch_and_add(&policy->bytes, skb->len);
+			*match_type = POLICY_MATCH_L3_L4;
 			goto get_proxy_port;
 		}
 	}
@@ -133,6 +140,7 @@ __policy_can_access(void *map, struct __sk_buff *skb, __u32 identity,
 		/* FIXME: Use per cpu counters */
 		__sync_fetch_and_add(&policy->packets, 1);
 		__sync_fetch_and_add(&policy->bytes, skb->len);
+		*match_type = POLICY_MATCH_L3_ONLY;
 		return TC_ACT_OK;
 	}
 
@@ -145,12 +153,15 @@ __policy_can_access(void *map, struct __sk_buff *skb, __u32 identity,
 			/* FIXME: Use per cpu counters */
 			__sync_fetch_and_add(&policy->packets, 1);
 			__sync_fetch_and_add(&policy->bytes, skb->len);
+			*match_type = POLICY_MATCH_L4_ONLY;
 			goto get_proxy_port;
 		}
 	}
 
-	if (skb->cb[CB_POLICY])
+	if (skb->cb[CB_POLICY]) {
+		*match_type = POLICY_MATCH_ALL;
 		goto allow;
+	}
 
 	if (is_fragment)
 		return DROP_FRAG_NOSUPPORT;
@@ -171,6 +182,7 @@ allow:
  * @arg proto		L3 Protocol of this packet
  * @arg cidr_addr_size	Size of the destination CIDR of this packet
  * @arg cidr_addr	Destination CIDR of this packet
+ * @arg match_type	Set to the policy map entry which allowed the traffic
  *
  * Returns:
  *   - Positive integer indicating the proxy_port to handle this traffic
@@ -180,13 +192,13 @@ allow:
 static inline int __inline__
 policy_can_access_ingress(struct __sk_buff *skb, __u32 src_identity,
 			  __u16 dport, __u8 proto, size_t cidr_addr_size,
-			  void *cidr_addr, bool is_fragment)
+			  void *cidr_addr, bool is_fragment, __u8 *match_type)
 {
 	int ret;
 
 	ret = __policy_can_access(&POLICY_MAP, skb, src_identity, dport,
 				      proto, cidr_addr_size, cidr_addr,
-				      CT_INGRESS, is_fragment);
+				      CT_INGRESS, is_fragment, match_type);
 	if (ret >= TC_ACT_OK)
 		return ret;
 
@@ -202,10 +214,11 @@ policy_can_access_ingress(struct __sk_buff *skb, __u32 src_identity,
 #if defined LXC_ID
 
 static inline int __inline__
-policy_can_egress(struct __sk_buff *skb, __u32 identity, __u16 dport, __u8 proto)
+policy_can_egress(struct __sk_buff *skb, __u32 identity, __u16 dport, __u8 proto,
+		  __u8 *match_type)
 {
 	int ret = __policy_can_access(&POLICY_MAP, skb, identity, dport, proto,
-				      0, NULL, CT_EGRESS, false);
+				      0, NULL, CT_EGRESS, false, match_type);
 	if (ret >= 0)
 		return ret;
 
@@ -220,31 +233,37 @@ policy_can_egress(struct __sk_buff *skb, __u32 identity, __u16 dport, __u8 proto
 
 static inline int policy_can_egress6(struct __sk_buff *skb,
 				     struct ipv6_ct_tuple *tuple,
-				     __u32 identity, union v6addr *daddr)
+				     __u32 identity, union v6addr *daddr,
+				     __u8 *match_type)
 {
-	return policy_can_egress(skb, identity, tuple->dport, tuple->nexthdr);
+	return policy_can_egress(skb, identity, tuple->dport, tuple->nexthdr,
+				 match_type);
 }
 
 static inline int policy_can_egress4(struct __sk_buff *skb,
 				     struct ipv4_ct_tuple *tuple,
-				     __u32 identity, __be32 daddr)
+				     __u32 identity, __be32 daddr,
+				     __u8 *match_type)
 {
-	return policy_can_egress(skb, identity, tuple->dport, tuple->nexthdr);
+	return policy_can_egress(skb, identity, tuple->dport, tuple->nexthdr,
+				 match_type);
 }
 
 #else /* LXC_ID */
 
 static inline int
 policy_can_egress6(struct __sk_buff *skb, struct ipv6_ct_tuple *tuple,
-		   __u32 identity, union v6addr *daddr)
+		   __u32 identity, union v6addr *daddr, __u8 *match_type)
 {
+	*match_type = POLICY_MATCH_ALL;
 	return TC_ACT_OK;
 }
 
 static inline int
 policy_can_egress4(struct __sk_buff *skb, struct ipv4_ct_tuple *tuple,
-		   __u32 identity, __be32 daddr)
+		   __u32 identity, __be32 daddr, __u8 *match_type)
 {
+	*match_type = POLICY_MATCH_ALL;
 	return TC_ACT_OK;
 }
 #endif /* LXC_ID */
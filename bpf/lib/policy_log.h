@@ -0,0 +1,110 @@
+/*
+ *  Copyright (C) 2018 Authors of Cilium
+ *
+ *  This program is free software; you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation; either version 2 of the License, or
+ *  (at your option) any later version.
+ *
+ *  This program is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with this program; if not, write to the Free Software
+ *  Foundation, Inc., 51 Franklin St, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+/*
+ * Policy verdict notification via perf event ring buffer.
+ *
+ * API:
+ * void send_policy_verdict_notify(skb, remote_label, dst_port, proto, egress,
+ *				   match_type, proxy_port)
+ *
+ * If POLICY_VERDICT_NOTIFY is not defined, the API will be compiled in as a NOP.
+ */
+
+#ifndef __LIB_POLICY_LOG__
+#define __LIB_POLICY_LOG__
+
+#include "dbg.h"
+#include "events.h"
+#include "common.h"
+#include "utils.h"
+
+/* Policy map entries which allowed a new connection. */
+enum {
+	POLICY_MATCH_NONE,
+	POLICY_MATCH_L3_L4,	/* identity and port */
+	POLICY_MATCH_L3_ONLY,	/* identity, any port */
+	POLICY_MATCH_L4_ONLY,	/* port, any identity */
+	POLICY_MATCH_ALL,	/* policy enforcement skipped */
+};
+
+#ifdef POLICY_VERDICT_NOTIFY
+
+struct policy_verdict_notify {
+	NOTIFY_COMMON_HDR
+	__u32		len_orig;
+	__u32		len_cap;
+	__u32		remote_label;
+	__u16		dst_port;
+	__u16		proxy_port;
+	__u8		proto;
+	__u8		egress;
+	__u16		pad;
+};
+
+/**
+ * send_policy_verdict_notify
+ * @skb:	socket buffer
+ * @remote_label: identity of the remote end of the connection
+ * @dst_port:	destination port in network byte order
+ * @proto:	L4 protocol
+ * @egress:	1 if the connection was allowed on egress, 0 on ingress
+ * @match_type:	policy map entry which allowed the connection (POLICY_MATCH_*)
+ * @proxy_port:	port of the proxy the connection is redirected to, or 0
+ *
+ * Generate a notification to indicate which policy decision allowed a new
+ * connection.
+ */
+static inline void
+send_policy_verdict_notify(struct __sk_buff *skb, __u32 remote_label,
+			   __u16 dst_port, __u8 proto, __u8 egress,
+			   __u8 match_type, __u16 proxy_port)
+{
+	uint64_t skb_len = (uint64_t)skb->len, cap_len = min((uint64_t)TRACE_PAYLOAD_LEN, (uint64_t)skb_len);
+	uint32_t hash = get_hash_recalc(skb);
+	struct policy_verdict_notify msg = {
+		.type = CILIUM_NOTIFY_POLICY_VERDICT,
+		.subtype = match_type,
+		.source = EVENT_SOURCE,
+		.hash = hash,
+		.len_orig = skb_len,
+		.len_cap = cap_len,
+		.remote_label = remote_label,
+		.dst_port = bpf_ntohs(dst_port),
+		.proxy_port = proxy_port,
+		.proto = proto,
+		.egress = egress,
+		.pad = 0,
+	};
+
+	skb_event_output(skb, &cilium_events,
+			 (cap_len << 32) | BPF_F_CURRENT_CPU,
+			 &msg, sizeof(msg));
+}
+
+#else
+
+static inline void
+send_policy_verdict_notify(struct __sk_buff *skb, __u32 remote_label,
+			   __u16 dst_port, __u8 proto, __u8 egress,
+			   __u8 match_type, __u16 proxy_port)
+{
+}
+
+#endif
+
+#endif /* __LIB_POLICY_LOG__ */
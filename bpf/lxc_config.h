@@ -37,6 +37,7 @@
 #endif
 #define DROP_NOTIFY
 #define TRACE_NOTIFY
+#define POLICY_VERDICT_NOTIFY
 #define CT_MAP_TCP6 cilium_ct_tcp6_111
 #define CT_MAP_ANY6 cilium_ct_any6_111
 #define CT_MAP_TCP4 cilium_ct_tcp4_111
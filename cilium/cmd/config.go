@@ -22,6 +22,7 @@ import (
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/command"
+	"github.com/cilium/cilium/pkg/defaults"
 	"github.com/cilium/cilium/pkg/option"
 
 	"github.com/spf13/cobra"
@@ -48,7 +49,7 @@ var configCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.Flags().BoolVarP(&listOptions, "list-options", "", false, "List available options")
-	configCmd.Flags().IntVarP(&numPages, "num-pages", "n", 0, "Number of pages for perf ring buffer. New values have to be > 0")
+	configCmd.Flags().IntVarP(&numPages, "num-pages", "n", 0, fmt.Sprintf("Number of pages for perf ring buffer. New values have to be a power of 2 not exceeding %d", defaults.MonitorMaxNumPages))
 	command.AddJSONOutput(configCmd)
 }
 
@@ -65,7 +66,7 @@ func configDaemon(cmd *cobra.Command, opts []string) {
 
 	cfgStatus := resp.Status
 	if numPages > 0 {
-		if cfgStatus.NodeMonitor != nil && numPages != int(cfgStatus.NodeMonitor.Npages) {
+		if cfgStatus.NodeMonitor == nil || numPages != int(cfgStatus.NodeMonitor.Npages) {
 			dOpts["MonitorNumPages"] = strconv.Itoa(numPages)
 		}
 	} else if len(opts) == 0 {
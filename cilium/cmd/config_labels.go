@@ -0,0 +1,136 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	labelPrefixesSet    []string
+	labelPrefixesAdd    []string
+	labelPrefixesDelete []string
+	labelPrefixesDryRun bool
+)
+
+// configLabelsCmd represents the config labels command
+var configLabelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "Manage label prefixes used to determine the identity of endpoints",
+	Long: `Manage label prefixes used to determine the identity of endpoints.
+
+Prefixes are specified in the format [source:][!]prefix. A prefix starting with
+! excludes matching labels from the identity. Changing the prefixes causes the
+identity of all affected endpoints to be recomputed. Use --dry-run to preview
+the number of affected endpoints and new identities without applying the change.`,
+	Example: `  cilium config labels
+  cilium config labels --add k8s:app --dry-run
+  cilium config labels --delete '!io.kubernetes'
+  cilium config labels --set 'k8s:io.kubernetes.pod.namespace,k8s:app'`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configLabels(cmd)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configLabelsCmd)
+	configLabelsCmd.Flags().StringSliceVarP(&labelPrefixesSet, "set", "s", []string{}, "Replace all label prefixes")
+	configLabelsCmd.Flags().StringSliceVarP(&labelPrefixesAdd, "add", "a", []string{}, "Add label prefixes")
+	configLabelsCmd.Flags().StringSliceVarP(&labelPrefixesDelete, "delete", "d", []string{}, "Delete label prefixes")
+	configLabelsCmd.Flags().BoolVarP(&labelPrefixesDryRun, "dry-run", "", false, "Only report the impact of the change")
+	command.AddJSONOutput(configLabelsCmd)
+}
+
+func configLabels(cmd *cobra.Command) {
+	filter, err := client.ConfigLabelsGet()
+	if err != nil {
+		Fatalf("Error while retrieving label prefixes: %s", err)
+	}
+
+	if !cmd.Flags().Changed("set") && len(labelPrefixesAdd) == 0 && len(labelPrefixesDelete) == 0 {
+		if command.OutputJSON() {
+			if err := command.PrintOutput(filter); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+		for _, p := range filter.Prefixes {
+			fmt.Println(p)
+		}
+		return
+	}
+
+	prefixes := filter.Prefixes
+	if cmd.Flags().Changed("set") {
+		prefixes = labelPrefixesSet
+	}
+	prefixes = updateLabelPrefixes(prefixes, labelPrefixesAdd, labelPrefixesDelete)
+
+	change, err := client.ConfigLabelsPut(&models.IdentityLabelFilter{
+		Prefixes: prefixes,
+		DryRun:   labelPrefixesDryRun,
+	})
+	if err != nil {
+		Fatalf("Error while updating label prefixes: %s", err)
+	}
+
+	if command.OutputJSON() {
+		if err := command.PrintOutput(change); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if change.Applied {
+		fmt.Println("Label prefixes updated:")
+	} else {
+		fmt.Println("Label prefixes not applied (dry run):")
+	}
+	for _, p := range change.Prefixes {
+		fmt.Printf(" - %s\n", p)
+	}
+	fmt.Printf("Affected endpoints: %d\n", change.AffectedEndpoints)
+	fmt.Printf("New identities:     %d\n", change.NewIdentities)
+}
+
+// updateLabelPrefixes returns prefixes with all prefixes in del removed and
+// all prefixes in add appended if not present yet
+func updateLabelPrefixes(prefixes, add, del []string) []string {
+	deleted := make(map[string]struct{}, len(del))
+	for _, p := range del {
+		deleted[p] = struct{}{}
+	}
+
+	result := []string{}
+	present := map[string]struct{}{}
+	for _, p := range append(prefixes, add...) {
+		if _, ok := deleted[p]; ok {
+			continue
+		}
+		if _, ok := present[p]; ok {
+			continue
+		}
+		present[p] = struct{}{}
+		result = append(result, p)
+	}
+
+	return result
+}
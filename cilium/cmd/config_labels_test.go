@@ -0,0 +1,32 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package cmd
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *CMDHelpersSuite) TestUpdateLabelPrefixes(c *C) {
+	prefixes := []string{"k8s:app", "!io.kubernetes"}
+
+	c.Assert(updateLabelPrefixes(prefixes, nil, nil), DeepEquals, prefixes)
+	c.Assert(updateLabelPrefixes(prefixes, []string{"k8s:role", "k8s:app"}, nil), DeepEquals,
+		[]string{"k8s:app", "!io.kubernetes", "k8s:role"})
+	c.Assert(updateLabelPrefixes(prefixes, []string{"k8s:role"}, []string{"!io.kubernetes"}), DeepEquals,
+		[]string{"k8s:app", "k8s:role"})
+	c.Assert(updateLabelPrefixes(prefixes, nil, prefixes), DeepEquals, []string{})
+}
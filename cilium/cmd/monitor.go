@@ -46,7 +46,24 @@ var (
 programs attached to endpoints and devices. This includes:
   * Dropped packet notifications
   * Captured packet traces
-  * Debugging information`,
+  * Debugging information
+
+In verbose and JSON output, the security identities, endpoints and IPs of
+dropped and traced packets are resolved to labels, pod names and ipcache
+identities.
+
+With --since or --until, the flows retained in the flow buffer of the agent
+are retrieved instead of listening for new events. The flow buffer is enabled
+with the agent option --flow-buffer-size.
+
+With --pcap, the packets of drop and trace notifications are written to a
+file in pcapng format instead of being printed. Each packet carries a comment
+with the drop reason, identities and observation point. Together with
+--buffered, the packet samples retained by the agent are written instead.
+
+With --explain, drops denied by the ingress policy of an endpoint are followed
+by the policy verdict of the connection, the reason for it and, in verbose
+output, the rules selecting the endpoint.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			runMonitor(args)
 		},
@@ -63,6 +80,16 @@ func init() {
 	monitorCmd.Flags().Var(&printer.Related, "related-to", "Filter by either source or destination endpoint id")
 	monitorCmd.Flags().BoolVarP(&printer.Verbose, "verbose", "v", false, "Enable verbose output")
 	monitorCmd.Flags().BoolVarP(&printer.JSONOutput, "json", "j", false, "Enable json output. Shadows -v flag")
+	monitorCmd.Flags().StringVar(&flowSince, "since", "", "Retrieve flows observed since a time in RFC3339 format or a duration, e.g. 5m")
+	monitorCmd.Flags().StringVar(&flowUntil, "until", "", "Retrieve flows observed until a time in RFC3339 format or a duration, e.g. 1m")
+	monitorCmd.Flags().UintSliceVar(&flowIdentities, "identity", []uint{}, "Filter by source or destination identity")
+	monitorCmd.Flags().StringSliceVar(&flowLabels, "label", []string{}, "Filter retrieved flows by source or destination labels")
+	monitorCmd.Flags().StringSliceVar(&flowIPs, "ip", []string{}, "Filter retrieved flows by source or destination IP")
+	monitorCmd.Flags().UintSliceVar(&flowPorts, "port", []uint{}, "Filter retrieved flows by source or destination port")
+	monitorCmd.Flags().StringSliceVar(&flowVerdicts, "verdict", []string{}, "Filter retrieved flows by verdict (forwarded, dropped, denied, rate-limited, error)")
+	monitorCmd.Flags().StringVar(&pcapFile, "pcap", "", "Write the packets of drop and trace notifications to a file in pcapng format")
+	monitorCmd.Flags().BoolVar(&monitorExplain, "explain", false, "Explain drops denied by policy with the rules selecting the destination endpoint")
+	monitorCmd.Flags().BoolVar(&pcapBuffered, "buffered", false, "Write the packet samples buffered by the agent instead of listening for new events (requires --pcap)")
 }
 
 func setVerbosity() {
@@ -86,11 +113,38 @@ func setupSigHandler() {
 	}()
 }
 
+// getMonitorFilter returns the filter registered with the node monitor for
+// the command line flags
+func getMonitorFilter() *listener.Filter {
+	filter := &listener.Filter{
+		Types:            printer.EventTypes,
+		FromEndpoints:    printer.FromSource,
+		ToEndpoints:      printer.ToDst,
+		RelatedEndpoints: printer.Related,
+	}
+
+	for _, id := range flowIdentities {
+		filter.Identities = append(filter.Identities, uint32(id))
+	}
+
+	return filter
+}
+
 // openMonitorSock attempts to open a version specific monitor socket It
 // returns a connection, with a version, or an error.
 func openMonitorSock() (conn net.Conn, version listener.Version, err error) {
 	errors := make([]string, 0)
 
+	// try the 1.3 socket and register the filter
+	conn, err = net.Dial("unix", defaults.MonitorSockPath1_3)
+	if err == nil {
+		if err = listener.WriteFilter(conn, getMonitorFilter()); err == nil {
+			return conn, listener.Version1_3, nil
+		}
+		conn.Close()
+	}
+	errors = append(errors, defaults.MonitorSockPath1_3+": "+err.Error())
+
 	// try the 1.2 socket
 	conn, err = net.Dial("unix", defaults.MonitorSockPath1_2)
 	if err == nil {
@@ -119,17 +173,32 @@ func consumeMonitorEvents(conn net.Conn, version listener.Version) error {
 		return err
 	}
 
+	// Older node monitors do not support filters, apply the filter locally
+	var filter *listener.Filter
+	if version != listener.Version1_3 {
+		filter = getMonitorFilter()
+	}
+
 	for {
 		pl, err := getParsedPayload()
 		if err != nil {
 			return err
 		}
+		if !filter.IsEmpty() && !filter.Match(listener.NewEventInfo(pl)) {
+			continue
+		}
+		if pcapWriter != nil {
+			writePcapSample(pl)
+			continue
+		}
 		if !printer.FormatEvent(pl) {
 			// earlier code used an else to handle this case, along with pl.Type ==
 			// payload.RecordLost above. It should be safe to call lostEvent to match
 			// the earlier behaviour, despite it not being wholly correct.
 			log.WithError(err).WithField("type", pl.Type).Warn("Unknown payload type")
 			format.LostEvent(pl.Lost, pl.CPU)
+		} else if monitorExplain {
+			explainDrop(pl)
 		}
 	}
 }
@@ -156,13 +225,13 @@ func getMonitorParser(conn net.Conn, version listener.Version) (parser eventPars
 			return &pl, nil
 		}, nil
 
-	case listener.Version1_2:
+	case listener.Version1_2, listener.Version1_3:
 		var (
 			pl  payload.Payload
 			dec = gob.NewDecoder(conn)
 		)
-		// This implemenents the newer 1.2 API. Each listener maintains its own gob
-		// session, and type information is only ever sent once.
+		// This implemenents the newer 1.2 and 1.3 API. Each listener maintains its
+		// own gob session, and type information is only ever sent once.
 		return func() (*payload.Payload, error) {
 			if err := pl.DecodeBinary(dec); err != nil {
 				return nil, err
@@ -182,6 +251,39 @@ func runMonitor(args []string) {
 	}
 
 	setVerbosity()
+
+	if pcapBuffered {
+		if pcapFile == "" {
+			Fatalf("--buffered requires --pcap")
+		}
+		retrievePcap()
+		return
+	}
+
+	if flowSince != "" || flowUntil != "" {
+		retrieveFlows()
+		return
+	}
+	if flowFilterSet() {
+		Fatalf("--label, --ip, --port and --verdict require --since or --until")
+	}
+
+	// Resolve identities, endpoints and IPs in verbose and JSON output
+	if printer.Verbosity != format.INFO {
+		printer.Resolver = newMonitorResolver()
+	}
+
+	if monitorExplain {
+		initExplainCache()
+	}
+
+	if pcapFile != "" {
+		f, w := createPcapFile()
+		defer f.Close()
+		pcapWriter = w
+		fmt.Printf("Writing packets of drop and trace notifications to %s\n", pcapFile)
+	}
+
 	setupSigHandler()
 	if resp, err := client.Daemon.GetHealthz(nil); err == nil {
 		if nm := resp.Payload.NodeMonitor; nm != nil {
@@ -0,0 +1,151 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/format"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+
+	"github.com/hashicorp/golang-lru"
+)
+
+const (
+	// explainCacheSize is the number of policy explanations retained to
+	// explain repeated drops of the same connection
+	explainCacheSize = 1024
+
+	// explainCacheTimeout is the time after which a retained policy
+	// explanation is requested again to reflect policy changes
+	explainCacheTimeout = 5 * time.Second
+)
+
+var (
+	monitorExplain bool
+
+	// explainCache caches policy explanations by explainKey, nil unless
+	// --explain is set
+	explainCache *lru.Cache
+)
+
+// explainKey identifies the connections sharing the same policy explanation
+type explainKey struct {
+	identity uint32
+	endpoint uint32
+	port     uint16
+	protocol string
+}
+
+type explainEntry struct {
+	explanation *models.PolicyExplanation
+	expires     time.Time
+}
+
+func initExplainCache() {
+	cache, err := lru.New(explainCacheSize)
+	if err != nil {
+		Fatalf("Unable to create policy explanation cache: %s", err)
+	}
+	explainCache = cache
+}
+
+// getExplanation returns the policy explanation for the connection
+// identified by key, either from the cache or from the agent
+func getExplanation(key explainKey) (*models.PolicyExplanation, error) {
+	if v, ok := explainCache.Get(key); ok {
+		if entry := v.(explainEntry); time.Now().Before(entry.expires) {
+			return entry.explanation, nil
+		}
+	}
+
+	sel := &models.PolicyExplainSelector{
+		SourceIdentity: int64(key.identity),
+		EndpointID:     int64(key.endpoint),
+	}
+	if key.port != 0 {
+		sel.Dport = &models.Port{
+			Port:     key.port,
+			Protocol: strings.ToUpper(key.protocol),
+		}
+	}
+
+	explanation, err := client.PolicyExplainGet(sel)
+	if err != nil {
+		return nil, err
+	}
+
+	explainCache.Add(key, explainEntry{
+		explanation: explanation,
+		expires:     time.Now().Add(explainCacheTimeout),
+	})
+
+	return explanation, nil
+}
+
+// explainDrop prints the policy explanation of a drop notification denied by
+// the ingress policy of an endpoint. All other events are ignored.
+func explainDrop(pl *payload.Payload) {
+	if pl.Type != payload.EventSample || len(pl.Data) == 0 || pl.Data[0] != monitor.MessageTypeDrop {
+		return
+	}
+
+	dn := monitor.DropNotify{}
+	if err := binary.Read(bytes.NewReader(pl.Data), byteorder.Native, &dn); err != nil {
+		return
+	}
+
+	// Only drops at the destination endpoint are explained
+	if !monitor.IsPolicyDrop(dn.SubType) || dn.DstID == 0 {
+		return
+	}
+
+	key := explainKey{identity: dn.SrcLabel, endpoint: dn.DstID}
+	if dn.CapLen > 0 && len(pl.Data) > monitor.DropNotifyLen {
+		tuple := monitor.GetConnectionTuple(pl.Data[monitor.DropNotifyLen:])
+		if tuple.Protocol == "tcp" || tuple.Protocol == "udp" {
+			key.port, key.protocol = tuple.DstPort, tuple.Protocol
+		}
+	}
+
+	explanation, err := getExplanation(key)
+	if err != nil {
+		log.WithError(err).Warn("Unable to explain policy drop")
+		return
+	}
+
+	switch printer.Verbosity {
+	case format.JSON:
+		out, err := json.Marshal(explanation)
+		if err == nil {
+			fmt.Println(string(out))
+		}
+	case format.INFO:
+		fmt.Printf("   policy %s: %s\n", explanation.Verdict, explanation.Reason)
+	default:
+		fmt.Printf("  Policy verdict: %s\n  Reason: %s\n", explanation.Verdict, explanation.Reason)
+		fmt.Printf("  Source labels: %s\n", strings.Join(explanation.SourceLabels, " "))
+		fmt.Printf("  Endpoint labels: %s\n", strings.Join(explanation.EndpointLabels, " "))
+		fmt.Printf("  Rules selecting the endpoint:\n%s\n", explanation.Policy)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/monitor"
+)
+
+var (
+	flowSince      string
+	flowUntil      string
+	flowIdentities []uint
+	flowLabels     []string
+	flowIPs        []string
+	flowPorts      []uint
+	flowVerdicts   []string
+)
+
+// flowFilterSet returns true if any filter only applicable to the retrieval
+// of flows has been specified
+func flowFilterSet() bool {
+	return len(flowLabels) > 0 || len(flowIPs) > 0 || len(flowPorts) > 0 || len(flowVerdicts) > 0
+}
+
+// getFlowFilter returns the flow filter for the command line flags. Endpoint
+// filters are translated into endpoints which must be either source or
+// destination, the direction is checked by matchFlowEndpoints().
+func getFlowFilter() *models.FlowFilter {
+	filter := &models.FlowFilter{
+		Since:    flowSince,
+		Until:    flowUntil,
+		Labels:   flowLabels,
+		Ips:      flowIPs,
+		Verdicts: flowVerdicts,
+	}
+
+	for _, typ := range printer.EventTypes {
+		filter.Types = append(filter.Types, monitor.MessageTypeName(typ))
+	}
+
+	for _, ids := range [][]uint16{printer.FromSource, printer.ToDst, printer.Related} {
+		for _, id := range ids {
+			filter.Endpoints = append(filter.Endpoints, int64(id))
+		}
+	}
+
+	for _, id := range flowIdentities {
+		filter.Identities = append(filter.Identities, int64(id))
+	}
+
+	for _, port := range flowPorts {
+		filter.Ports = append(filter.Ports, int64(port))
+	}
+
+	return filter
+}
+
+func containsEndpoint(ids []uint16, id int64) bool {
+	for _, v := range ids {
+		if int64(v) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFlowEndpoints returns true if the flow matches the --from, --to and
+// --related-to filters
+func matchFlowEndpoints(f *models.Flow) bool {
+	var src, dst int64
+	if f.Source != nil {
+		src = f.Source.ID
+	}
+	if f.Destination != nil {
+		dst = f.Destination.ID
+	}
+
+	switch {
+	case len(printer.FromSource) > 0 && !containsEndpoint(printer.FromSource, src):
+		return false
+	case len(printer.ToDst) > 0 && !containsEndpoint(printer.ToDst, dst):
+		return false
+	case len(printer.Related) > 0 && !containsEndpoint(printer.Related, src) && !containsEndpoint(printer.Related, dst):
+		return false
+	}
+
+	return true
+}
+
+func formatFlowEndpoint(ep *models.FlowEndpoint) string {
+	if ep == nil {
+		return "unknown"
+	}
+
+	s := fmt.Sprintf("identity %d", ep.Identity)
+	if ep.ID != 0 {
+		s = fmt.Sprintf("endpoint %d, %s", ep.ID, s)
+	}
+	if len(ep.Labels) > 0 {
+		s += " [" + strings.Join(ep.Labels, " ") + "]"
+	}
+
+	return s
+}
+
+// formatFlow returns the single line representation of a flow
+func formatFlow(f *models.Flow) string {
+	s := f.Time + " " + f.Type + " " + f.Verdict
+	if f.Reason != "" {
+		s += " (" + f.Reason + ")"
+	}
+	if f.ObservationPoint != "" {
+		s += " " + f.ObservationPoint
+	}
+	s += " " + formatFlowEndpoint(f.Source) + " -> " + formatFlowEndpoint(f.Destination)
+	if f.Summary != "" {
+		s += ": " + f.Summary
+	}
+
+	return s
+}
+
+// retrieveFlows prints the flows retained in the flow buffer of the agent
+func retrieveFlows() {
+	flows, err := client.FlowsGet(getFlowFilter())
+	if err != nil {
+		Fatalf("Error while retrieving flows: %s", err)
+	}
+
+	for _, f := range flows {
+		if !matchFlowEndpoints(f) {
+			continue
+		}
+
+		if printer.JSONOutput {
+			b, err := json.Marshal(f)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Unable to encode flow: %s\n", err)
+				continue
+			}
+			fmt.Println(string(b))
+		} else {
+			fmt.Println(formatFlow(f))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package cmd
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/monitor/format"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *CMDHelpersSuite) TestFormatFlow(c *C) {
+	f := &models.Flow{
+		Time:    "2018-10-01T12:00:00Z",
+		Type:    "drop",
+		Verdict: "dropped",
+		Reason:  "Policy denied (L3)",
+		Source: &models.FlowEndpoint{
+			ID:       10,
+			Identity: 100,
+			Labels:   []string{"k8s:app=foo"},
+		},
+		Destination: &models.FlowEndpoint{Identity: 200},
+		Summary:     "10.0.0.1:34567 -> 10.0.0.2:80 tcp SYN",
+	}
+
+	c.Assert(formatFlow(f), Equals, "2018-10-01T12:00:00Z drop dropped (Policy denied (L3)) "+
+		"endpoint 10, identity 100 [k8s:app=foo] -> identity 200: 10.0.0.1:34567 -> 10.0.0.2:80 tcp SYN")
+}
+
+func (s *CMDHelpersSuite) TestMatchFlowEndpoints(c *C) {
+	old := printer
+	defer func() { printer = old }()
+	printer = format.NewMonitorFormatter(format.INFO)
+
+	f := &models.Flow{
+		Source:      &models.FlowEndpoint{ID: 10},
+		Destination: &models.FlowEndpoint{ID: 20},
+	}
+	c.Assert(matchFlowEndpoints(f), Equals, true)
+
+	printer.FromSource = format.Uint16Flags{10}
+	c.Assert(matchFlowEndpoints(f), Equals, true)
+	printer.ToDst = format.Uint16Flags{10}
+	c.Assert(matchFlowEndpoints(f), Equals, false)
+
+	printer.FromSource, printer.ToDst = nil, nil
+	printer.Related = format.Uint16Flags{20}
+	c.Assert(matchFlowEndpoints(f), Equals, true)
+	printer.Related = format.Uint16Flags{30}
+	c.Assert(matchFlowEndpoints(f), Equals, false)
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cilium/cilium/pkg/monitor/format"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/monitor/pcap"
+)
+
+var (
+	pcapFile     string
+	pcapBuffered bool
+
+	// pcapWriter writes the packet samples of monitor events to pcapFile,
+	// nil if events are printed
+	pcapWriter *pcap.Writer
+)
+
+// createPcapFile creates pcapFile and a pcapng writer for it
+func createPcapFile() (*os.File, *pcap.Writer) {
+	f, err := os.Create(pcapFile)
+	if err != nil {
+		Fatalf("Unable to create pcap file: %s", err)
+	}
+
+	w, err := pcap.NewWriter(f)
+	if err != nil {
+		f.Close()
+		Fatalf("Unable to write to pcap file: %s", err)
+	}
+
+	return f, w
+}
+
+// retrievePcap writes the packet samples buffered by the agent to pcapFile
+func retrievePcap() {
+	f, err := os.Create(pcapFile)
+	if err != nil {
+		Fatalf("Unable to create pcap file: %s", err)
+	}
+	defer f.Close()
+
+	if err := client.PcapGet(f); err != nil {
+		Fatalf("Unable to retrieve packet samples: %s", err)
+	}
+
+	fmt.Printf("Wrote buffered packet samples to %s\n", pcapFile)
+}
+
+// writePcapSample writes the packet sample of a monitor event to the pcap
+// file. Events without packet sample are ignored, lost events are reported.
+func writePcapSample(pl *payload.Payload) {
+	switch pl.Type {
+	case payload.EventSample:
+		sample, err := pcap.NewSample(pl.Data, time.Now())
+		switch {
+		case err == pcap.ErrNoSample:
+			return
+		case err != nil:
+			log.WithError(err).Warn("Unable to decode packet sample")
+			return
+		}
+
+		if err := pcapWriter.WriteSample(sample); err != nil {
+			Fatalf("Unable to write to pcap file: %s", err)
+		}
+
+	case payload.RecordLost:
+		format.LostEvent(pl.Lost, pl.CPU)
+
+	case payload.ListenerQueueLost:
+		format.QueueLostEvent(pl.Lost)
+	}
+}
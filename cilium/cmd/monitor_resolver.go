@@ -0,0 +1,145 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/maps/ipcache"
+
+	"github.com/hashicorp/golang-lru"
+)
+
+const (
+	// resolverCacheSize is the number of identities, endpoints and IPs
+	// retained by the monitor resolver
+	resolverCacheSize = 4096
+
+	// ipcacheDumpInterval is the minimum interval in which the BPF ipcache
+	// is dumped to resolve IPs which are not cached yet
+	ipcacheDumpInterval = 5 * time.Second
+)
+
+// endpointName is the namespace and pod name of an endpoint
+type endpointName struct {
+	namespace string
+	pod       string
+}
+
+// monitorResolver resolves the identities, endpoints and IPs of monitor
+// events using the agent API and the BPF ipcache. All results are kept in
+// LRU caches, so identities which have been released in the meantime can
+// still be resolved as long as they have been seen before.
+type monitorResolver struct {
+	identities *lru.Cache
+	endpoints  *lru.Cache
+	ips        *lru.Cache
+
+	mutex        lock.Mutex
+	ipcacheDump  map[string][]string
+	lastDumpTime time.Time
+}
+
+func newMonitorResolver() *monitorResolver {
+	r := &monitorResolver{}
+	// lru.New only fails for a non-positive size
+	r.identities, _ = lru.New(resolverCacheSize)
+	r.endpoints, _ = lru.New(resolverCacheSize)
+	r.ips, _ = lru.New(resolverCacheSize)
+	return r
+}
+
+// ResolveIdentity returns the labels of the security identity
+func (r *monitorResolver) ResolveIdentity(id uint32) []string {
+	if lbls, ok := r.identities.Get(id); ok {
+		return lbls.([]string)
+	}
+
+	var lbls []string
+	if identity, err := client.IdentityGet(strconv.FormatUint(uint64(id), 10)); err == nil {
+		lbls = identity.Labels
+	} else {
+		log.WithError(err).WithField("identity", id).Debug("Unable to resolve identity")
+	}
+
+	r.identities.Add(id, lbls)
+	return lbls
+}
+
+// ResolveEndpoint returns the namespace and pod name of the endpoint
+func (r *monitorResolver) ResolveEndpoint(id uint16) (string, string) {
+	if name, ok := r.endpoints.Get(id); ok {
+		n := name.(endpointName)
+		return n.namespace, n.pod
+	}
+
+	n := endpointName{}
+	ep, err := client.EndpointGet(strconv.FormatUint(uint64(id), 10))
+	if err != nil {
+		log.WithError(err).WithField("endpoint", id).Debug("Unable to resolve endpoint")
+	} else if ep.Status != nil && ep.Status.ExternalIdentifiers != nil {
+		// The pod name is provided in the form namespace/pod
+		if s := strings.SplitN(ep.Status.ExternalIdentifiers.PodName, "/", 2); len(s) == 2 {
+			n.namespace, n.pod = s[0], s[1]
+		}
+	}
+
+	r.endpoints.Add(id, n)
+	return n.namespace, n.pod
+}
+
+// ResolveIP returns the security identity of the IP according to the BPF
+// ipcache. The ipcache is dumped at most once per ipcacheDumpInterval, IPs
+// which cannot be resolved are not cached.
+func (r *monitorResolver) ResolveIP(ip net.IP) (uint32, bool) {
+	key := ip.String()
+	if id, ok := r.ips.Get(key); ok {
+		return id.(uint32), true
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.ipcacheDump == nil || time.Since(r.lastDumpTime) > ipcacheDumpInterval {
+		dump := map[string][]string{}
+		if err := ipcache.IPCache.Dump(dump); err != nil {
+			log.WithError(err).Debug("Unable to dump ipcache")
+		}
+		r.ipcacheDump = dump
+		r.lastDumpTime = time.Now()
+	}
+
+	value, ok := getLPMValue(ip, r.ipcacheDump)
+	if !ok {
+		return 0, false
+	}
+
+	ids := value.([]string)
+	if len(ids) == 0 {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(ids[0], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	r.ips.Add(key, uint32(id))
+	return uint32(id), true
+}
@@ -19,6 +19,7 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/cilium/cilium/api/v1/models"
@@ -58,11 +59,16 @@ var nodeListCmd = &cobra.Command{
 
 func init() {
 	nodeCmd.AddCommand(nodeListCmd)
-	command.AddJSONOutput(nodeListCmd)
+	command.AddJSONOrWideOutput(nodeListCmd)
 }
 
 func formatStatusResponse(w io.Writer, cluster *models.ClusterStatus) {
-	nodesOutput := []string{"Name\tIPv4 Address\tEndpoint CIDR\tIPv6 Address\tEndpoint CIDR\n"}
+	wide := command.OutputWide()
+	header := "Name\tIPv4 Address\tEndpoint CIDR\tIPv6 Address\tEndpoint CIDR"
+	if wide {
+		header += "\tSource\tConflicts"
+	}
+	nodesOutput := []string{header + "\n"}
 
 	for _, node := range cluster.Nodes {
 		ipv4, ipv4Range, ipv6, ipv6Range := "", "", "", ""
@@ -77,8 +83,11 @@ func formatStatusResponse(w io.Writer, cluster *models.ClusterStatus) {
 			}
 		}
 
-		nodesOutput = append(nodesOutput, fmt.Sprintf("%s\t%s\t%s\t%s\t%s\n",
-			node.Name, ipv4, ipv4Range, ipv6, ipv6Range))
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s", node.Name, ipv4, ipv4Range, ipv6, ipv6Range)
+		if wide {
+			line += fmt.Sprintf("\t%s\t%s", node.Source, formatNodeConflicts(node.Conflicts))
+		}
+		nodesOutput = append(nodesOutput, line+"\n")
 	}
 
 	if len(nodesOutput) > 1 {
@@ -90,3 +99,16 @@ func formatStatusResponse(w io.Writer, cluster *models.ClusterStatus) {
 		tab.Flush()
 	}
 }
+
+func formatNodeConflicts(conflicts []*models.NodeConflict) string {
+	if len(conflicts) == 0 {
+		return "none"
+	}
+
+	strs := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		strs = append(strs, fmt.Sprintf("%s: %s=%s (effective: %s)", c.Source, c.Field, c.Value, c.EffectiveValue))
+	}
+
+	return strings.Join(strs, ", ")
+}
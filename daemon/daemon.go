@@ -48,6 +48,7 @@ import (
 	"github.com/cilium/cilium/pkg/endpoint"
 	"github.com/cilium/cilium/pkg/endpointmanager"
 	"github.com/cilium/cilium/pkg/envoy"
+	"github.com/cilium/cilium/pkg/flow"
 	"github.com/cilium/cilium/pkg/fqdn"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/ipam"
@@ -69,6 +70,7 @@ import (
 	"github.com/cilium/cilium/pkg/maps/sockmap"
 	"github.com/cilium/cilium/pkg/maps/tunnel"
 	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/pcap"
 	"github.com/cilium/cilium/pkg/mtu"
 	"github.com/cilium/cilium/pkg/node"
 	"github.com/cilium/cilium/pkg/option"
@@ -131,6 +133,22 @@ type Daemon struct {
 	nodeMonitor  *monitorLaunch.NodeMonitor
 	ciliumHealth *health.CiliumHealth
 
+	// flows contains the recent flows observed by the node monitor, nil
+	// if the flow buffer is disabled
+	flows *flow.Ring
+
+	// flowMetrics exports the flows observed by the node monitor as
+	// metrics, nil if disabled
+	flowMetrics *flow.MetricsExporter
+
+	// flowLog writes the flows observed by the node monitor to a log
+	// file, nil if the flow log is disabled
+	flowLog *flow.Logger
+
+	// pcapSamples contains the packet samples of recent drop and trace
+	// notifications, nil if the packet sample buffer is disabled
+	pcapSamples *pcap.Ring
+
 	// dnsPoller is used to implement ToFQDN rules
 	dnsPoller *fqdn.DNSPoller
 
@@ -974,6 +992,7 @@ func createNodeConfigHeaderfile() error {
 	fmt.Fprintf(fw, "#define HEALTH_ID %d\n", identity.GetReservedID(labels.IDNameHealth))
 	fmt.Fprintf(fw, "#define UNMANAGED_ID %d\n", identity.GetReservedID(labels.IDNameUnmanaged))
 	fmt.Fprintf(fw, "#define INIT_ID %d\n", identity.GetReservedID(labels.IDNameInit))
+	fmt.Fprintf(fw, "#define REMOTE_NODE_ID %d\n", identity.GetReservedID(labels.IDNameRemoteNode))
 	fmt.Fprintf(fw, "#define LB_RR_MAX_SEQ %d\n", lbmap.MaxSeq)
 	fmt.Fprintf(fw, "#define CILIUM_LB_MAP_MAX_ENTRIES %d\n", lbmap.MaxEntries)
 	fmt.Fprintf(fw, "#define TUNNEL_ENDPOINT_MAP_SIZE %d\n", tunnel.MaxEntries)
@@ -988,6 +1007,12 @@ func createNodeConfigHeaderfile() error {
 	fmt.Fprintf(fw, "#define TRACE_PAYLOAD_LEN %dULL\n", tracePayloadLen)
 	fmt.Fprintf(fw, "#define MTU %d\n", mtu.GetDeviceMTU())
 
+	// Established connections are only redirected to the proxy port
+	// recorded in their CT entry if removed redirects are drained.
+	if option.Config.ProxyRedirectDrainPeriod > 0 {
+		fmt.Fprintf(fw, "#define ENABLE_PROXY_DRAIN\n")
+	}
+
 	fw.Flush()
 	f.Close()
 
@@ -1109,7 +1134,8 @@ func NewDaemon() (*Daemon, *endpointRestoreState, error) {
 		return nil, nil, fmt.Errorf("invalid daemon configuration: %s", err)
 	}
 
-	ctmap.InitMapInfo(option.Config.CTMapEntriesGlobalTCP, option.Config.CTMapEntriesGlobalAny)
+	ctmap.InitMapInfo(option.Config.CTMapEntriesGlobalTCP, option.Config.CTMapEntriesGlobalAny,
+		option.Config.ProxyRedirectDrainPeriod > 0)
 
 	if err := workloads.Setup(option.Config.Workloads, map[string]string{}); err != nil {
 		return nil, nil, fmt.Errorf("unable to setup workload: %s", err)
@@ -1302,6 +1328,8 @@ func NewDaemon() (*Daemon, *endpointRestoreState, error) {
 		log.Infof("  Loopback IPv4: %s", node.GetIPv4Loopback().String())
 	}
 
+	node.SetNotifier(&d)
+
 	if err := node.ConfigureLocalNode(); err != nil {
 		log.WithError(err).Fatal("Unable to initialize local node")
 	}
@@ -1340,7 +1368,10 @@ func NewDaemon() (*Daemon, *endpointRestoreState, error) {
 
 	// FIXME: Make the port range configurable.
 	d.l7Proxy = proxy.StartProxySupport(10000, 20000, option.Config.RunDir,
-		option.Config.AccessLog, &d, option.Config.AgentLabels)
+		option.Config.AccessLog, &d, option.Config.AgentLabels,
+		func(proxyPort uint16) {
+			endpointmanager.FlushCTEntriesOfProxyPort(!option.Config.IPv4Disabled, true, proxyPort)
+		})
 
 	d.startStatusCollector()
 
@@ -1464,6 +1495,10 @@ func changedOption(key string, value option.OptionSetting, data interface{}) {
 	d.policy.BumpRevision() // force policy recalculation
 }
 
+// monitorNumPagesOption is the configuration option to resize the perf ring
+// buffer of the node monitor
+const monitorNumPagesOption = "MonitorNumPages"
+
 type patchConfig struct {
 	daemon *Daemon
 }
@@ -1479,6 +1514,29 @@ func (h *patchConfig) Handle(params PatchConfigParams) middleware.Responder {
 
 	cfgSpec := params.Configuration
 
+	// The size of the perf ring buffer of the node monitor is not a daemon
+	// option and is applied separately
+	if numPagesEntry, ok := cfgSpec.Options[monitorNumPagesOption]; ok {
+		numPages, err := strconv.Atoi(numPagesEntry)
+		if err == nil {
+			err = option.ValidateMonitorNumPages(numPages)
+		}
+		if err != nil {
+			msg := fmt.Errorf("Invalid value for %s: %s", monitorNumPagesOption, err)
+			return api.Error(PatchConfigBadRequestCode, msg)
+		}
+
+		if err := d.nodeMonitor.SetNumPages(numPages); err != nil {
+			msg := fmt.Errorf("Unable to resize perf ring buffer of node monitor: %s", err)
+			return api.Error(PatchConfigFailureCode, msg)
+		}
+
+		delete(cfgSpec.Options, monitorNumPagesOption)
+		if len(cfgSpec.Options) == 0 && cfgSpec.PolicyEnforcement == "" {
+			return NewPatchConfigOK()
+		}
+	}
+
 	om, err := option.Config.Opts.Library.ValidateConfigurationMap(cfgSpec.Options)
 	if err != nil {
 		msg := fmt.Errorf("Invalid configuration option %s", err)
@@ -1489,18 +1547,6 @@ func (h *patchConfig) Handle(params PatchConfigParams) middleware.Responder {
 	option.Config.ConfigPatchMutex.Lock()
 	defer option.Config.ConfigPatchMutex.Unlock()
 
-	nmArgs := d.nodeMonitor.GetArgs()
-	if numPagesEntry, ok := cfgSpec.Options["MonitorNumPages"]; ok && nmArgs[0] != numPagesEntry {
-		if len(nmArgs) == 0 || nmArgs[0] != numPagesEntry {
-			args := []string{"--num-pages %s", numPagesEntry}
-			d.nodeMonitor.Restart(args)
-		}
-		if len(cfgSpec.Options) == 0 {
-			return NewPatchConfigOK()
-		}
-		delete(cfgSpec.Options, "MonitorNumPages")
-	}
-
 	// Track changes to daemon's configuration
 	var changes int
 
@@ -0,0 +1,205 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	. "github.com/cilium/cilium/api/v1/server/restapi/daemon"
+	"github.com/cilium/cilium/monitor/listener"
+	"github.com/cilium/cilium/pkg/api"
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/flow"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/monitor/pcap"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// flowCollectorRetryInterval is the interval in which the flow collector
+// attempts to reconnect to the node monitor
+const flowCollectorRetryInterval = 10 * time.Second
+
+// startFlowCollector starts collecting the flows and packet samples observed
+// by the node monitor. Depending on the configuration, flows are retained in
+// the flow buffer, exported as metrics and written to the flow log, and
+// packet samples are retained in the packet sample buffer.
+func (d *Daemon) startFlowCollector() error {
+	if option.Config.FlowMetrics {
+		exporter, err := flow.NewMetricsExporter(option.Config.FlowMetricsLabels, option.Config.FlowMetricsWorkloadLabel)
+		if err != nil {
+			return err
+		}
+		metrics.MustRegister(exporter)
+		d.flowMetrics = exporter
+	}
+
+	if option.Config.FlowBufferSize > 0 {
+		d.flows = flow.NewRing(option.Config.FlowBufferSize)
+	}
+
+	if option.Config.PcapBufferSize > 0 {
+		d.pcapSamples = pcap.NewRing(option.Config.PcapBufferSize)
+	}
+
+	if option.Config.FlowLogFile != "" {
+		logger, err := flow.NewLogger(flow.LogConfig{
+			Path:             option.Config.FlowLogFile,
+			MaxSize:          option.Config.FlowLogMaxSize,
+			MaxBackups:       option.Config.FlowLogMaxBackups,
+			RotationInterval: option.Config.FlowLogRotationInterval,
+			Compress:         option.Config.FlowLogCompress,
+			Namespaces:       option.Config.FlowLogNamespaces,
+		})
+		if err != nil {
+			return err
+		}
+		d.flowLog = logger
+	}
+
+	go d.collectFlows()
+	return nil
+}
+
+// flowEventFilter selects the monitor events which describe flows
+var flowEventFilter = &listener.Filter{
+	Types: []int{
+		monitor.MessageTypeDrop,
+		monitor.MessageTypeTrace,
+		monitor.MessageTypePolicyVerdict,
+		monitor.MessageTypeAccessLog,
+	},
+}
+
+// collectFlows connects to the node monitor as a listener and adds all flows
+// to the flow buffer. The connection is re-established whenever it is lost,
+// e.g. on restart of the node monitor.
+func (d *Daemon) collectFlows() {
+	for ; ; time.Sleep(flowCollectorRetryInterval) {
+		conn, err := net.Dial("unix", defaults.MonitorSockPath1_3)
+		if err != nil {
+			log.WithError(err).Debug("Unable to connect to node monitor to collect flows")
+			continue
+		}
+
+		if err := listener.WriteFilter(conn, flowEventFilter); err != nil {
+			conn.Close()
+			log.WithError(err).Debug("Unable to register filter with node monitor to collect flows")
+			continue
+		}
+
+		err = d.consumeFlows(conn)
+		log.WithError(err).Debug("Connection to node monitor lost, reconnecting to collect flows")
+	}
+}
+
+// consumeFlows decodes the monitor events received on conn into flows until
+// the connection fails
+func (d *Daemon) consumeFlows(conn net.Conn) error {
+	defer conn.Close()
+
+	dec := gob.NewDecoder(conn)
+	for {
+		pl := payload.Payload{}
+		if err := pl.DecodeBinary(dec); err != nil {
+			return err
+		}
+
+		if pl.Type != payload.EventSample {
+			continue
+		}
+
+		now := time.Now()
+		if d.pcapSamples != nil {
+			if sample, err := pcap.NewSample(pl.Data, now); err == nil {
+				d.pcapSamples.Add(sample)
+			}
+		}
+
+		f, err := flow.Decode(pl.Data, now)
+		switch {
+		case err == flow.ErrUnsupportedType:
+			continue
+		case err != nil:
+			log.WithError(err).Debug("Unable to decode flow")
+			continue
+		}
+
+		resolveFlowLabels(&f.Source)
+		resolveFlowLabels(&f.Destination)
+
+		if d.flows != nil {
+			d.flows.Add(f)
+		}
+		if d.flowMetrics != nil {
+			d.flowMetrics.Export(f)
+		}
+		if d.flowLog != nil {
+			if err := d.flowLog.Log(f); err != nil {
+				log.WithError(err).Warning("Unable to write flow log")
+			}
+		}
+	}
+}
+
+// resolveFlowLabels resolves the labels of the security identity of a flow
+// endpoint unless the labels are already known
+func resolveFlowLabels(ep *flow.Endpoint) {
+	if len(ep.Labels) > 0 || ep.Identity == 0 {
+		return
+	}
+
+	if id := identity.LookupIdentityByID(identity.NumericIdentity(ep.Identity)); id != nil {
+		ep.Labels = id.LabelArray.GetModel()
+	}
+}
+
+type getFlows struct {
+	daemon *Daemon
+}
+
+func NewGetFlowsHandler(d *Daemon) GetFlowsHandler {
+	return &getFlows{daemon: d}
+}
+
+func (h *getFlows) Handle(params GetFlowsParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /flows request")
+
+	if h.daemon.flows == nil {
+		return NewGetFlowsDisabled()
+	}
+
+	filter, err := flow.NewFilterFromModel(params.Filter, time.Now())
+	if err != nil {
+		return api.Error(GetFlowsBadRequestCode, fmt.Errorf("invalid flow filter: %s", err))
+	}
+
+	flows := h.daemon.flows.Query(filter)
+	result := make([]*models.Flow, 0, len(flows))
+	for _, f := range flows {
+		result = append(result, f.GetModel())
+	}
+
+	return NewGetFlowsOK().WithPayload(result)
+}
@@ -58,10 +58,15 @@ func (h *getIdentityID) Handle(params GetIdentityIDParams) middleware.Responder
 		return NewGetIdentityIDBadRequest()
 	}
 
-	identity := identity.LookupIdentityByID(nid)
-	if identity == nil {
+	id := identity.LookupIdentityByID(nid)
+	if id == nil {
+		// Fall back to recently released identities so that
+		// events referring to them can still be resolved
+		id = identity.LookupReleasedIdentityByID(nid)
+	}
+	if id == nil {
 		return NewGetIdentityIDNotFound()
 	}
 
-	return NewGetIdentityIDOK().WithPayload(identity.GetModel())
+	return NewGetIdentityIDOK().WithPayload(id.GetModel())
 }
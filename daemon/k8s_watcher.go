@@ -73,6 +73,7 @@ const (
 	k8sAPIGroupServiceV1Core    = "core/v1::Service"
 	k8sAPIGroupEndpointV1Core   = "core/v1::Endpoint"
 	k8sAPIGroupPodV1Core        = "core/v1::Pods"
+	k8sAPIGroupSecretV1Core     = "core/v1::Secret"
 	k8sAPIGroupNetworkingV1Core = "networking.k8s.io/v1::NetworkPolicy"
 	k8sAPIGroupIngressV1Beta1   = "extensions/v1beta1::Ingress"
 	k8sAPIGroupCiliumV2         = "cilium/v2::CiliumNetworkPolicy"
@@ -85,6 +86,7 @@ const (
 	metricNS       = "Namespace"
 	metricNode     = "Node"
 	metricPod      = "Pod"
+	metricSecret   = "Secret"
 	metricService  = "Service"
 	metricCreate   = "create"
 	metricDelete   = "delete"
@@ -507,6 +509,12 @@ func (d *Daemon) EnableK8sWatcher(reSyncPeriod time.Duration) error {
 	go endpointController.Run(wait.NeverStop)
 	d.k8sAPIGroups.addAPI(k8sAPIGroupEndpointV1Core)
 
+	// Secrets referred to by policies are read from the cache filled by
+	// the secret watchers, so that policy computation never blocks on the
+	// apiserver. Only the secrets referred to by policies are watched.
+	k8s.SetSecretWatcher(d.watchK8sSecretV1)
+	d.k8sAPIGroups.addAPI(k8sAPIGroupSecretV1Core)
+
 	if option.Config.IsLBEnabled() {
 		ingressController := k8sUtils.ControllerFactory(
 			k8s.Client().ExtensionsV1beta1().RESTClient(),
@@ -2171,6 +2179,68 @@ func (d *Daemon) missingK8sNamespaceV1(m versioned.Map) versioned.Map {
 	return missing
 }
 
+// updateK8sSecretV1 stores secret in the secret cache and regenerates the
+// policies if they refer to it.
+func (d *Daemon) updateK8sSecretV1(secret *v1.Secret) {
+	if k8s.UpdateSecret(secret) {
+		d.TriggerPolicyUpdates(true, "Kubernetes secret referred to by policy updated")
+	}
+}
+
+// deleteK8sSecretV1 removes secret from the secret cache and regenerates the
+// policies if they refer to it.
+func (d *Daemon) deleteK8sSecretV1(secret *v1.Secret) {
+	if k8s.DeleteSecret(secret) {
+		d.TriggerPolicyUpdates(true, "Kubernetes secret referred to by policy deleted")
+	}
+}
+
+// watchK8sSecretV1 starts watching the secret with the given namespace and
+// name. Each secret is watched on its own, so that the agent only needs
+// access to the secrets referred to by policies. Until the secret has been
+// received, the policies referring to it fail to match.
+func (d *Daemon) watchK8sSecretV1(namespace, name string) {
+	log.WithFields(logrus.Fields{
+		logfields.K8sNamespace: namespace,
+		"secret":               name,
+	}).Debug("Watching Kubernetes secret referred to by policy")
+
+	_, secretController := cache.NewInformer(
+		cache.NewListWatchFromClient(
+			k8s.Client().CoreV1().RESTClient(),
+			"secrets",
+			namespace,
+			fields.OneTermEqualSelector("metadata.name", name),
+		),
+		&v1.Secret{},
+		0,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if secret, ok := obj.(*v1.Secret); ok {
+					d.updateK8sSecretV1(secret)
+					updateK8sEventMetric(metricSecret, metricCreate, true)
+				}
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				if secret, ok := newObj.(*v1.Secret); ok {
+					d.updateK8sSecretV1(secret)
+					updateK8sEventMetric(metricSecret, metricUpdate, true)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				if deleted, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = deleted.Obj
+				}
+				if secret, ok := obj.(*v1.Secret); ok {
+					d.deleteK8sSecretV1(secret)
+					updateK8sEventMetric(metricSecret, metricDelete, true)
+				}
+			},
+		},
+	)
+	go secretController.Run(wait.NeverStop)
+}
+
 func (d *Daemon) updateK8sNodeTunneling(k8sNodeOld, k8sNodeNew *v1.Node) error {
 	nodeNew := k8s.ParseNode(k8sNodeNew, node.FromKubernetes)
 	// Ignore own node
@@ -2284,7 +2354,7 @@ func (d *Daemon) deleteK8sNodeV1(k8sNode *v1.Node) error {
 		Cluster: option.Config.ClusterName,
 	}
 
-	node.DeleteNode(ni, node.TunnelRoute|node.DirectRoute)
+	node.DeleteNode(ni, node.FromKubernetes, node.TunnelRoute|node.DirectRoute)
 
 	id, exists := ipcache.IPIdentityCache.LookupByIP(ip)
 	if !exists {
@@ -0,0 +1,156 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+	. "github.com/cilium/cilium/api/v1/server/restapi/daemon"
+	"github.com/cilium/cilium/pkg/api"
+	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+type getConfigLabels struct {
+	daemon *Daemon
+}
+
+func NewGetConfigLabelsHandler(d *Daemon) GetConfigLabelsHandler {
+	return &getConfigLabels{daemon: d}
+}
+
+func (h *getConfigLabels) Handle(params GetConfigLabelsParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /config/labels request")
+
+	filter := &models.IdentityLabelFilter{
+		Prefixes: labels.GetLabelPrefixCfg().Prefixes(),
+	}
+
+	return NewGetConfigLabelsOK().WithPayload(filter)
+}
+
+type putConfigLabels struct {
+	daemon *Daemon
+}
+
+func NewPutConfigLabelsHandler(d *Daemon) PutConfigLabelsHandler {
+	return &putConfigLabels{daemon: d}
+}
+
+// labelsUpdate is the new set of orchestration labels of an endpoint after
+// filtering them with a new label prefix configuration
+type labelsUpdate struct {
+	ep             *endpoint.Endpoint
+	identityLabels labels.Labels
+	infoLabels     labels.Labels
+}
+
+// refilterEndpointLabels filters the orchestration labels of all endpoints
+// with cfg and returns the endpoints whose identity labels change as well as
+// the number of identities which do not exist yet for the new label sets.
+func refilterEndpointLabels(cfg *labels.LabelPrefixCfg) ([]labelsUpdate, int) {
+	updates := []labelsUpdate{}
+	newIdentities := map[string]struct{}{}
+
+	for _, ep := range endpointmanager.GetEndpoints() {
+		if err := ep.RLockAlive(); err != nil {
+			continue
+		}
+
+		// Endpoints with reserved identities are not subject to
+		// label filtering
+		if ep.OpLabels.OrchestrationIdentity.FindReserved() != nil {
+			ep.RUnlock()
+			continue
+		}
+
+		all := labels.Labels{}
+		all.MergeLabels(ep.OpLabels.OrchestrationIdentity)
+		all.MergeLabels(ep.OpLabels.Disabled)
+		all.MergeLabels(ep.OpLabels.OrchestrationInfo)
+		oldIdentityLabels := ep.OpLabels.IdentityLabels()
+		custom := ep.OpLabels.Custom.DeepCopy()
+		disabled := ep.OpLabels.Disabled.DeepCopy()
+		ep.RUnlock()
+
+		identityLabels, infoLabels := cfg.FilterLabels(all)
+
+		newIdentityLabels := custom
+		for k, v := range identityLabels {
+			if _, ok := disabled[k]; !ok {
+				newIdentityLabels[k] = v
+			}
+		}
+
+		if newIdentityLabels.Equals(oldIdentityLabels) {
+			continue
+		}
+
+		updates = append(updates, labelsUpdate{
+			ep:             ep,
+			identityLabels: identityLabels,
+			infoLabels:     infoLabels,
+		})
+
+		key := string(newIdentityLabels.SortedList())
+		if _, ok := newIdentities[key]; !ok && identity.LookupIdentity(newIdentityLabels) == nil {
+			newIdentities[key] = struct{}{}
+		}
+	}
+
+	return updates, len(newIdentities)
+}
+
+func (h *putConfigLabels) Handle(params PutConfigLabelsParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("PUT /config/labels request")
+
+	d := h.daemon
+	filter := params.Filter
+
+	cfg, err := labels.NewLabelPrefixCfg(filter.Prefixes)
+	if err != nil {
+		return api.Error(PutConfigLabelsBadRequestCode, err)
+	}
+
+	updates, newIdentities := refilterEndpointLabels(cfg)
+
+	change := &models.IdentityLabelFilterChange{
+		Prefixes:          cfg.Prefixes(),
+		AffectedEndpoints: int64(len(updates)),
+		NewIdentities:     int64(newIdentities),
+	}
+
+	if filter.DryRun {
+		return NewPutConfigLabelsOK().WithPayload(change)
+	}
+
+	labels.SetLabelPrefixCfg(cfg)
+
+	for _, u := range updates {
+		log.WithFields(logrus.Fields{
+			logfields.EndpointID:     u.ep.StringID(),
+			logfields.IdentityLabels: u.identityLabels.String(),
+		}).Info("Updating identity labels of endpoint after label filter change")
+		u.ep.UpdateLabels(d, u.identityLabels, u.infoLabels)
+	}
+	change.Applied = true
+
+	return NewPutConfigLabelsOK().WithPayload(change)
+}
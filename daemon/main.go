@@ -31,6 +31,7 @@ import (
 	health "github.com/cilium/cilium/cilium-health/launch"
 	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/common/addressing"
+	monitorLaunch "github.com/cilium/cilium/monitor/launch"
 	_ "github.com/cilium/cilium/pkg/alignchecker"
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/components"
@@ -38,6 +39,7 @@ import (
 	"github.com/cilium/cilium/pkg/defaults"
 	"github.com/cilium/cilium/pkg/endpointmanager"
 	"github.com/cilium/cilium/pkg/envoy"
+	"github.com/cilium/cilium/pkg/flow"
 	"github.com/cilium/cilium/pkg/flowdebug"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/ipam"
@@ -401,12 +403,41 @@ func init() {
 	viper.BindEnv("disable-envoy-version-check", "CILIUM_DISABLE_ENVOY_BUILD")
 	flags.Var(option.NewNamedMapOptions("fixed-identity-mapping", &fixedIdentity, fixedIdentityValidator),
 		"fixed-identity-mapping", "Key-value for the fixed identity mapping which allows to use reserved label for fixed identities")
+	flags.IntVar(&option.Config.FlowBufferSize,
+		option.FlowBufferSizeName, defaults.FlowBufferSize,
+		"Number of recent flows retained for retrieval via the API (0 = disabled)")
+	flags.BoolVar(&option.Config.FlowMetrics,
+		option.FlowMetricsName, false, "Export the flows observed by the node monitor as Prometheus metrics")
+	flags.StringSliceVar(&option.Config.FlowMetricsLabels,
+		option.FlowMetricsLabelsName, flow.DefaultMetricLabels,
+		fmt.Sprintf("Labels of the flow metrics in addition to type and verdict %v", flow.MetricLabels()))
+	flags.StringVar(&option.Config.FlowMetricsWorkloadLabel,
+		option.FlowMetricsWorkloadLabelName, defaults.FlowMetricsWorkloadLabel,
+		"Identity label reported as workload by the flow metrics")
+	flags.StringVar(&option.Config.FlowLogFile,
+		option.FlowLogFileName, "", "Path of the file the observed flows are logged to as JSON lines (empty = disabled)")
+	flags.IntVar(&option.Config.FlowLogMaxSize,
+		option.FlowLogMaxSizeName, defaults.FlowLogMaxSize, "Size in megabytes at which the flow log is rotated")
+	flags.IntVar(&option.Config.FlowLogMaxBackups,
+		option.FlowLogMaxBackupsName, defaults.FlowLogMaxBackups, "Number of rotated flow logs to retain (0 = all)")
+	flags.DurationVar(&option.Config.FlowLogRotationInterval,
+		option.FlowLogRotationIntervalName, 0, "Interval in which the flow log is rotated regardless of its size (0 = disabled)")
+	flags.BoolVar(&option.Config.FlowLogCompress,
+		option.FlowLogCompressName, true, "Compress rotated flow logs")
+	flags.StringSliceVar(&option.Config.FlowLogNamespaces,
+		option.FlowLogNamespacesName, []string{}, "Only log flows from or to pods in these namespaces (empty = all)")
 	flags.IntVar(&v4ClusterCidrMaskSize,
 		"ipv4-cluster-cidr-mask-size", 8, "Mask size for the cluster wide CIDR")
 	flags.StringVar(&v4Prefix,
 		"ipv4-range", AutoCIDR, "Per-node IPv4 endpoint prefix, e.g. 10.16.0.0/16")
 	flags.StringVar(&v6Prefix,
 		"ipv6-range", AutoCIDR, "Per-node IPv6 endpoint prefix, must be /96, e.g. fd02:1:1::/96")
+	flags.IntVar(&option.Config.IdentityAllocationConcurrency,
+		option.IdentityAllocationConcurrencyName, defaults.IdentityAllocationConcurrency,
+		"Maximum number of parallel identity allocations in the kvstore (0 = unlimited)")
+	flags.DurationVar(&option.Config.IdentityAllocationBackoffMax,
+		option.IdentityAllocationBackoffMaxName, defaults.IdentityAllocationBackoffMax,
+		"Maximum time to back off between failed identity allocation attempts")
 	flags.StringVar(&option.Config.IPv6ClusterAllocCIDR,
 		option.IPv6ClusterAllocCIDRName, defaults.IPv6ClusterAllocCIDR, "IPv6 /64 CIDR used to allocate per node endpoint /96 CIDR")
 	flags.StringVar(&v4ServicePrefix,
@@ -426,6 +457,9 @@ func init() {
 		"keep-config", false, "When restoring state, keeps containers' configuration in place")
 	flags.BoolVar(&option.Config.KeepTemplates,
 		"keep-bpf-templates", false, "Do not restore BPF template files from binary")
+	flags.String(option.KafkaProxyName, option.KafkaProxyAgent,
+		fmt.Sprintf("Proxy enforcing Kafka policies {%s}", option.GetKafkaProxyModes()))
+	viper.BindEnv(option.KafkaProxyName, option.KafkaProxyNameEnv)
 	flags.StringVar(&kvStore,
 		"kvstore", "", "Key-value store type")
 	flags.Var(option.NewNamedMapOptions("kvstore-opts", &kvStoreOpts, nil),
@@ -457,11 +491,22 @@ func init() {
 		"Maximum interval (in seconds) between controller runs. Zero is no limit.")
 	viper.BindEnv(option.MaxCtrlIntervalName, option.MaxCtrlIntervalNameEnv)
 	flags.MarkHidden(option.MaxCtrlIntervalName)
+	flags.StringVar(&option.Config.MonitorGRPCAddress,
+		option.MonitorGRPCAddressName, "", "Additional TCP address on which the node monitor serves its gRPC API, e.g. \"localhost:4244\", must be a loopback address unless TLS is configured")
+	flags.StringVar(&option.Config.MonitorGRPCTLSCertFile,
+		option.MonitorGRPCTLSCertFileName, "", "TLS certificate of the node monitor gRPC API served on --"+option.MonitorGRPCAddressName)
+	flags.StringVar(&option.Config.MonitorGRPCTLSKeyFile,
+		option.MonitorGRPCTLSKeyFileName, "", "TLS private key of the node monitor gRPC API served on --"+option.MonitorGRPCAddressName)
+	flags.StringVar(&option.Config.MonitorGRPCTLSClientCAFile,
+		option.MonitorGRPCTLSClientCAFileName, "", "CA certificates verifying the required client certificates of the node monitor gRPC API served on --"+option.MonitorGRPCAddressName)
 	flags.String(option.MonitorAggregationName, "None",
 		"Level of monitor aggregation for traces from the datapath")
 	viper.BindEnv(option.MonitorAggregationName, "CILIUM_MONITOR_AGGREGATION_LEVEL")
 	flags.IntVar(&option.Config.MTU,
 		option.MTUName, mtu.AutoDetect(), "Overwrite auto-detected MTU of underlying network")
+	flags.IntVar(&option.Config.PcapBufferSize,
+		option.PcapBufferSizeName, defaults.PcapBufferSize,
+		"Number of packet samples of recent drop and trace notifications retained for retrieval via the API (0 = disabled)")
 	flags.Bool(option.PrependIptablesChainsName, true, "Prepend custom iptables chains instead of appending")
 	viper.BindEnv(option.PrependIptablesChainsName, option.PrependIptablesChainsNameEnv)
 	flags.StringVar(&v6Address,
@@ -503,6 +548,9 @@ func init() {
 		"prometheus-serve-addr", "", "IP:Port on which to serve prometheus metrics (pass \":Port\" to bind on all interfaces, \"\" is off)")
 	viper.BindEnv("prometheus-serve-addr", "CILIUM_PROMETHEUS_SERVE_ADDR")
 	viper.BindEnv("prometheus-serve-addr-deprecated", "PROMETHEUS_SERVE_ADDR")
+	flags.DurationVar(&option.Config.ProxyRedirectDrainPeriod,
+		option.ProxyRedirectDrainPeriodName, 0,
+		"Period in which removed proxy redirects keep serving established connections (0 = close immediately, toggling it clears the CT table on restart)")
 
 	flags.Int(option.CTMapEntriesGlobalTCPName, option.CTMapEntriesGlobalTCPDefault, "Maximum number of entries in TCP CT table")
 	viper.BindEnv(option.CTMapEntriesGlobalTCPName, option.CTMapEntriesGlobalTCPNameEnv)
@@ -714,6 +762,7 @@ func initEnv(cmd *cobra.Command) {
 
 	option.Config.Opts.SetBool(option.DropNotify, true)
 	option.Config.Opts.SetBool(option.TraceNotify, true)
+	option.Config.Opts.SetBool(option.PolicyVerdictNotify, true)
 	option.Config.Opts.SetBool(option.PolicyTracing, enableTracing)
 	option.Config.Opts.SetBool(option.Conntrack, !disableConntrack)
 	option.Config.Opts.SetBool(option.ConntrackAccounting, !disableConntrack)
@@ -867,7 +916,24 @@ func runDaemon() {
 	}
 
 	log.Info("Launching node monitor daemon")
-	go d.nodeMonitor.Run(path.Join(defaults.RuntimePath, defaults.EventsPipe), bpf.GetMapRoot())
+	go d.nodeMonitor.Run(path.Join(defaults.RuntimePath, defaults.EventsPipe), bpf.GetMapRoot(), monitorLaunch.GRPCConfig{
+		Address:         option.Config.MonitorGRPCAddress,
+		TLSCertFile:     option.Config.MonitorGRPCTLSCertFile,
+		TLSKeyFile:      option.Config.MonitorGRPCTLSKeyFile,
+		TLSClientCAFile: option.Config.MonitorGRPCTLSClientCAFile,
+	})
+
+	if option.Config.FlowBufferSize > 0 || option.Config.FlowMetrics || option.Config.PcapBufferSize > 0 || option.Config.FlowLogFile != "" {
+		log.WithFields(logrus.Fields{
+			"size":     option.Config.FlowBufferSize,
+			"metrics":  option.Config.FlowMetrics,
+			"pcapSize": option.Config.PcapBufferSize,
+			"log":      option.Config.FlowLogFile,
+		}).Info("Starting flow collector")
+		if err := d.startFlowCollector(); err != nil {
+			log.WithError(err).Fatal("Unable to start flow collector")
+		}
+	}
 
 	if err := d.EnableK8sWatcher(5 * time.Minute); err != nil {
 		log.WithError(err).Fatal("Unable to establish connection to Kubernetes apiserver")
@@ -1011,6 +1077,10 @@ func runDaemon() {
 	api.DaemonGetConfigHandler = NewGetConfigHandler(d)
 	api.DaemonPatchConfigHandler = NewPatchConfigHandler(d)
 
+	// /config/labels
+	api.DaemonGetConfigLabelsHandler = NewGetConfigLabelsHandler(d)
+	api.DaemonPutConfigLabelsHandler = NewPutConfigLabelsHandler(d)
+
 	// /endpoint/
 	api.EndpointGetEndpointHandler = NewGetEndpointHandler(d)
 
@@ -1045,6 +1115,7 @@ func runDaemon() {
 
 	// /policy/resolve/
 	api.PolicyGetPolicyResolveHandler = NewGetPolicyResolveHandler(d)
+	api.PolicyGetPolicyExplainHandler = NewGetPolicyExplainHandler(d)
 
 	// /service/{id}/
 	api.ServiceGetServiceIDHandler = NewGetServiceIDHandler(d)
@@ -1066,6 +1137,10 @@ func runDaemon() {
 	// /debuginfo
 	api.DaemonGetDebuginfoHandler = NewGetDebugInfoHandler(d)
 
+	// /flows
+	api.DaemonGetFlowsHandler = NewGetFlowsHandler(d)
+	api.DaemonGetPcapHandler = NewGetPcapHandler(d)
+
 	// /map
 	api.DaemonGetMapHandler = NewGetMapHandler(d)
 	api.DaemonGetMapNameHandler = NewGetMapNameHandler(d)
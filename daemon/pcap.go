@@ -0,0 +1,46 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	. "github.com/cilium/cilium/api/v1/server/restapi/daemon"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+type getPcap struct {
+	daemon *Daemon
+}
+
+func NewGetPcapHandler(d *Daemon) GetPcapHandler {
+	return &getPcap{daemon: d}
+}
+
+func (h *getPcap) Handle(params GetPcapParams) middleware.Responder {
+	log.Debug("GET /pcap request")
+
+	if h.daemon.pcapSamples == nil {
+		return NewGetPcapDisabled()
+	}
+
+	// Writing to a bytes.Buffer cannot fail
+	buf := &bytes.Buffer{}
+	h.daemon.pcapSamples.Dump(buf)
+
+	return NewGetPcapOK().WithPayload(ioutil.NopCloser(buf))
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/cilium/cilium/pkg/api"
 	"github.com/cilium/cilium/pkg/endpoint"
 	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/ipcache"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/logging/logfields"
@@ -154,6 +155,104 @@ func (h *getPolicyResolve) Handle(params GetPolicyResolveParams) middleware.Resp
 	return NewGetPolicyResolveOK().WithPayload(&result)
 }
 
+type getPolicyExplain struct {
+	daemon *Daemon
+}
+
+func NewGetPolicyExplainHandler(d *Daemon) GetPolicyExplainHandler {
+	return &getPolicyExplain{daemon: d}
+}
+
+// explainIngress evaluates the ingress policy of an endpoint with the labels
+// in ctx.To for a connection from ctx.From and returns the verdict and the
+// reason for it. The policy repository mutex must be held.
+func explainIngress(repo *policy.Repository, ctx *policy.SearchContext, rules policyAPI.Rules) (policyAPI.Decision, string) {
+	switch policy.GetPolicyEnabled() {
+	case option.NeverEnforce:
+		return policyAPI.Allowed, "Policy enforcement is disabled for the daemon"
+	case option.DefaultEnforcement:
+		if len(rules) == 0 {
+			return policyAPI.Allowed, "No rules select the endpoint on ingress, " +
+				"policy enforcement is disabled for the endpoint"
+		}
+	}
+
+	if repo.AllowsIngressRLocked(ctx) == policyAPI.Allowed {
+		return policyAPI.Allowed, fmt.Sprintf("Allowed by the %d rules selecting the endpoint", len(rules))
+	}
+
+	// Evaluate the label based policy again without tracing to tell apart
+	// unsatisfied requirements from missing allow rules
+	labelCtx := policy.SearchContext{From: ctx.From, To: ctx.To}
+	switch {
+	case len(rules) == 0:
+		return policyAPI.Denied, "No rules select the endpoint on ingress " +
+			"and policy enforcement is always enabled"
+	case repo.CanReachIngressRLocked(&labelCtx) == policyAPI.Denied:
+		return policyAPI.Denied, "The source does not satisfy a FromRequires " +
+			"constraint of a rule selecting the endpoint"
+	case len(ctx.DPorts) > 0:
+		return policyAPI.Denied, fmt.Sprintf("None of the %d rules selecting the endpoint "+
+			"allows the source on port %d/%s", len(rules), ctx.DPorts[0].Port, ctx.DPorts[0].Protocol)
+	default:
+		return policyAPI.Denied, fmt.Sprintf("None of the %d rules selecting the endpoint "+
+			"allows the source", len(rules))
+	}
+}
+
+func (h *getPolicyExplain) Handle(params GetPolicyExplainParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /policy/explain request")
+
+	d := h.daemon
+	sel := params.ExplainSelector
+	if sel == nil {
+		sel = &models.PolicyExplainSelector{}
+	}
+
+	ep := endpointmanager.LookupCiliumID(uint16(sel.EndpointID))
+	if ep == nil {
+		return api.Error(GetPolicyExplainNotFoundCode,
+			fmt.Errorf("endpoint %d not found", sel.EndpointID))
+	}
+
+	epIdentity := identity.LookupIdentityByID(ep.GetIdentity())
+	if epIdentity == nil {
+		return api.Error(GetPolicyExplainNotFoundCode,
+			fmt.Errorf("endpoint %d has no security identity", sel.EndpointID))
+	}
+
+	srcIdentity := identity.LookupIdentityByID(identity.NumericIdentity(sel.SourceIdentity))
+	if srcIdentity == nil {
+		return api.Error(GetPolicyExplainNotFoundCode,
+			fmt.Errorf("identity %d not found", sel.SourceIdentity))
+	}
+
+	buffer := new(bytes.Buffer)
+	searchCtx := policy.SearchContext{
+		Trace:   policy.TRACE_VERBOSE,
+		Logging: logging.NewLogBackend(buffer, "", 0),
+		From:    srcIdentity.LabelArray,
+		To:      epIdentity.LabelArray,
+	}
+	if sel.Dport != nil && sel.Dport.Port != 0 {
+		searchCtx.DPorts = []*models.Port{sel.Dport}
+	}
+
+	d.policy.Mutex.RLock()
+	rules := d.policy.GetIngressRulesSelectingRLocked(epIdentity.LabelArray)
+	verdict, reason := explainIngress(d.policy, &searchCtx, rules)
+	d.policy.Mutex.RUnlock()
+
+	return NewGetPolicyExplainOK().WithPayload(&models.PolicyExplanation{
+		Verdict:        verdict.String(),
+		Reason:         reason,
+		SourceLabels:   srcIdentity.Labels.GetModel(),
+		EndpointLabels: epIdentity.Labels.GetModel(),
+		Policy:         policy.JSONMarshalRules(rules),
+		Log:            buffer.String(),
+	})
+}
+
 // AddOptions are options which can be passed to PolicyAdd
 type AddOptions struct {
 	// Replace if true indicates that existing rules with identical labels should be replaced
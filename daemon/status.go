@@ -79,8 +79,13 @@ func (d *Daemon) getNodeStatus() *models.ClusterStatus {
 	clusterStatus := models.ClusterStatus{
 		Self: node.GetLocalNode().Fullname(),
 	}
-	for _, node := range node.GetNodes() {
-		clusterStatus.Nodes = append(clusterStatus.Nodes, node.GetModel(ipv4))
+	conflicts := node.GetNodeConflicts()
+	for id, n := range node.GetNodes() {
+		model := n.GetModel(ipv4)
+		for _, c := range conflicts[id] {
+			model.Conflicts = append(model.Conflicts, c.GetModel())
+		}
+		clusterStatus.Nodes = append(clusterStatus.Nodes, model)
 	}
 	return &clusterStatus
 }
@@ -157,6 +157,48 @@ void AccessLog::Entry::UpdateFromResponse(
   }
 }
 
+bool AccessLog::Entry::UpdateGrpcStatus(const Http::HeaderMap &headers) {
+  const Http::HeaderEntry *status_entry = headers.GrpcStatus();
+  if (status_entry) {
+    uint64_t status;
+    if (StringUtil::atoul(status_entry->value().c_str(), status, 10)) {
+      entry.mutable_http()->mutable_grpc_status()->set_value(status);
+      return true;
+    }
+  }
+  return false;
+}
+
+void AccessLog::Entry::AddMissingHeader(const std::string &name) {
+  ::cilium::KeyValue *kv = entry.mutable_http()->add_missing_headers();
+  kv->set_key(name);
+}
+
+void AccessLog::Entry::AddRejectedHeader(const std::string &name,
+                                         const std::string &value) {
+  ::cilium::KeyValue *kv = entry.mutable_http()->add_rejected_headers();
+  kv->set_key(name);
+  kv->set_value(value);
+}
+
+void AccessLog::Entry::RedactHeaders(
+    const std::unordered_set<std::string> &names) {
+  if (names.empty()) {
+    return;
+  }
+  ::cilium::HttpLogEntry *http_entry = entry.mutable_http();
+  for (auto &kv : *http_entry->mutable_headers()) {
+    if (names.find(kv.key()) != names.end()) {
+      kv.set_value("[redacted]");
+    }
+  }
+  for (auto &kv : *http_entry->mutable_rejected_headers()) {
+    if (names.find(kv.key()) != names.end()) {
+      kv.set_value("[redacted]");
+    }
+  }
+}
+
 void AccessLog::Log(AccessLog::Entry &entry_,
                     ::cilium::EntryType entry_type) {
   ::cilium::LogEntry &entry = entry_.entry;
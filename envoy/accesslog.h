@@ -3,6 +3,7 @@
 #include <map>
 #include <mutex>
 #include <string>
+#include <unordered_set>
 
 #include "envoy/http/header_map.h"
 #include "envoy/network/connection.h"
@@ -27,8 +28,19 @@ public:
     void InitFromRequest(std::string policy_name, bool ingress, const Network::Connection *,
                          const Http::HeaderMap &, const RequestInfo::RequestInfo &);
     void UpdateFromResponse(const Http::HeaderMap &, const RequestInfo::RequestInfo &);
+    // Sets the gRPC status from the "grpc-status" header of the response
+    // trailers, or of the response headers of a trailers-only response.
+    // Returns false if the header is missing or invalid.
+    bool UpdateGrpcStatus(const Http::HeaderMap &);
+    void AddMissingHeader(const std::string &name);
+    void AddRejectedHeader(const std::string &name, const std::string &value);
+    // Replaces the values of the named request headers, which must be in
+    // lower case, with a placeholder.
+    void RedactHeaders(const std::unordered_set<std::string> &names);
 
     ::cilium::LogEntry entry{};
+    // Set if the request matched a policy rule whose rate limit was exceeded.
+    bool rate_limited{false};
   };
   void Log(Entry &entry, ::cilium::EntryType);
 
@@ -5,7 +5,10 @@
 #include <sys/socket.h>
 #include <sys/un.h>
 #include <unistd.h>
+#include <condition_variable>
+#include <mutex>
 #include <string>
+#include <vector>
 
 #include "envoy/network/listen_socket.h"
 #include "envoy/registry/registry.h"
@@ -77,6 +80,21 @@ public:
       thread_.reset();
     }
   }
+
+  // Waits for an entry of the given type to be received and copies it to 'entry'.
+  bool WaitForEntry(::cilium::EntryType type, ::cilium::LogEntry& entry) {
+    std::unique_lock<std::mutex> lock(entries_mutex_);
+    return entries_cv_.wait_for(lock, std::chrono::seconds(5), [&]() -> bool {
+	for (const auto& e: entries_) {
+	  if (e.entry_type() == type) {
+	    entry = e;
+	    return true;
+	  }
+	}
+	return false;
+      });
+  }
+
 private:
   void Close() {
     ::shutdown(fd_, SHUT_RD);
@@ -138,6 +156,9 @@ private:
 		entry.clear_status();
 	      }
 	      ENVOY_LOG(info, "Access log entry: {}", entry.DebugString());
+	      std::lock_guard<std::mutex> lock(entries_mutex_);
+	      entries_.push_back(entry);
+	      entries_cv_.notify_all();
 	    }
 	  }
 	}
@@ -151,6 +172,9 @@ private:
   std::atomic<int> fd_;
   std::atomic<int> fd2_;
   Thread::ThreadPtr thread_;
+  std::mutex entries_mutex_;
+  std::condition_variable entries_cv_;
+  std::vector<::cilium::LogEntry> entries_;
 };
 
 std::string host_map_config;
@@ -797,6 +821,66 @@ TEST_P(CiliumIntegrationTest, DuplicatePort) {
   EXPECT_STREQ("403", response->headers().Status()->value().c_str());
 }
 
+const std::string SECRET_HEADER_POLICY = R"EOF(version_info: "0"
+resources:
+- "@type": type.googleapis.com/cilium.NetworkPolicy
+  name: '173'
+  policy: 3
+  ingress_per_port_policies:
+  - port: 80
+    rules:
+    - remote_policies: [ 1 ]
+      http_rules:
+        http_rules:
+        - headers: [ { name: ':path', exact_match: '/allowed' } ]
+          header_matches:
+          - header: { name: 'x-api-key', exact_match: 's3cr3t' }
+            mismatch_action: REPLACE_ON_MISMATCH
+            value: 's3cr3t'
+            secret: true
+)EOF";
+
+TEST_P(CiliumIntegrationTest, SecretHeaderRedacted) {
+  policy_config = SECRET_HEADER_POLICY;
+  initialize();
+  codec_client_ = makeHttpConnection(lookupPort("http"));
+  Http::TestHeaderMapImpl headers =
+    {{":method", "GET"}, {":path", "/allowed"}, {":authority", "host"},
+     {"x-api-key", "wrong-key"}, {"x-other", "visible"}};
+  auto response = sendRequestAndWaitForResponse(headers, 0, default_response_headers_, 0);
+
+  EXPECT_STREQ("200", response->headers().Status()->value().c_str());
+  EXPECT_STREQ("s3cr3t",
+	       upstream_request_->headers().get(Http::LowerCaseString("x-api-key"))->value().c_str());
+
+  ::cilium::LogEntry entry;
+  ASSERT_TRUE(accessLogServer_.WaitForEntry(::cilium::EntryType::Request, entry));
+  const auto& http = entry.http();
+
+  // Neither the value sent by the client nor the secret is logged.
+  bool found_key = false, found_other = false;
+  for (const auto& kv: http.headers()) {
+    EXPECT_NE("wrong-key", kv.value());
+    EXPECT_NE("s3cr3t", kv.value());
+    if (kv.key() == "x-api-key") {
+      EXPECT_EQ("[redacted]", kv.value());
+      found_key = true;
+    } else if (kv.key() == "x-other") {
+      EXPECT_EQ("visible", kv.value());
+      found_other = true;
+    }
+  }
+  EXPECT_TRUE(found_key);
+  EXPECT_TRUE(found_other);
+
+  ASSERT_EQ(1, http.rejected_headers_size());
+  EXPECT_EQ("x-api-key", http.rejected_headers(0).key());
+  EXPECT_EQ("[redacted]", http.rejected_headers(0).value());
+  ASSERT_EQ(1, http.missing_headers_size());
+  EXPECT_EQ("x-api-key", http.missing_headers(0).key());
+  EXPECT_EQ("", http.missing_headers(0).value());
+}
+
 class CiliumIntegrationEgressTest : public CiliumHttpIntegrationTest {
 public:
   CiliumIntegrationEgressTest()
@@ -8,8 +8,10 @@
 
 #include "common/buffer/buffer_impl.h"
 #include "common/common/enum_to_int.h"
+#include "common/common/utility.h"
 #include "common/config/utility.h"
 #include "common/http/header_map_impl.h"
+#include "common/http/headers.h"
 
 #include "cilium_network_policy.h"
 #include "cilium_socket_option.h"
@@ -122,17 +124,23 @@ void Config::Log(AccessLog::Entry &entry, ::cilium::EntryType type) {
   }
 }
 
-void AccessFilter::onDestroy() {}
+void AccessFilter::onDestroy() {
+  // The stream ended without trailers, log the response without a gRPC status
+  if (grpc_status_pending_) {
+    grpc_status_pending_ = false;
+    LogResponse();
+  }
+}
 
 Http::FilterHeadersStatus AccessFilter::decodeHeaders(Http::HeaderMap& headers, bool) {
   headers.remove(Http::Headers::get().EnvoyOriginalDstHost);
   const auto& conn = callbacks_->connection();
   bool ingress = false;
   bool allowed = false;
+  const Cilium::SocketOption* option = nullptr;
   if (config_->npmap_ && conn) {
     const auto& options_ = conn->socketOptions();
     if (options_) {
-      const Cilium::SocketOption* option = nullptr;
       for (const auto& option_: *options_) {
 	option = dynamic_cast<const Cilium::SocketOption*>(option_.get());
 	if (option) {
@@ -141,17 +149,6 @@ Http::FilterHeadersStatus AccessFilter::decodeHeaders(Http::HeaderMap& headers,
 	  } else {
 	    ingress = option->ingress_;
 	  }
-	  if (ingress) {
-	    allowed = config_->npmap_->Allowed(config_->policy_name_, ingress, option->port_,
-					       option->identity_, headers);
-	  } else {
-	    allowed = config_->npmap_->Allowed(config_->policy_name_, ingress, option->port_,
-					       option->destination_identity_, headers);
-	  }
-	  ENVOY_LOG(debug, "Cilium L7: {} ({}->{}) policy lookup for endpoint {}: {}",
-		    ingress ? "Ingress" : "Egress",
-		    option->identity_, option->destination_identity_,
-		    config_->policy_name_, allowed ? "ALLOW" : "DENY");
 	  break;
 	}
       }
@@ -165,11 +162,28 @@ Http::FilterHeadersStatus AccessFilter::decodeHeaders(Http::HeaderMap& headers,
     ENVOY_LOG(warn, "Cilium L7: No policy map or no connection");
   }
 
-  // Fill in the log entry
+  // Fill in the log entry before the policy may modify the request headers
   log_entry_.InitFromRequest(config_->policy_name_, ingress, callbacks_->connection(),
                              headers, callbacks_->requestInfo());
+  if (option) {
+    allowed = config_->npmap_->Allowed(config_->policy_name_, ingress, option->port_,
+				       ingress ? option->identity_ : option->destination_identity_,
+				       headers, log_entry_);
+    ENVOY_LOG(debug, "Cilium L7: {} ({}->{}) policy lookup for endpoint {}: {}",
+	      ingress ? "Ingress" : "Egress",
+	      option->identity_, option->destination_identity_,
+	      config_->policy_name_, allowed ? "ALLOW" : "DENY");
+  }
+
   if (!allowed) {
     denied_ = true;
+    if (log_entry_.rate_limited) {
+      config_->stats_.rate_limited_.inc();
+
+      // Return a 429 response
+      callbacks_->sendLocalReply(Http::Code::TooManyRequests, "", nullptr);
+      return Http::FilterHeadersStatus::StopIteration;
+    }
     config_->stats_.access_denied_.inc();
 
     // Return a 403 response
@@ -182,12 +196,38 @@ Http::FilterHeadersStatus AccessFilter::decodeHeaders(Http::HeaderMap& headers,
 }
 
 Http::FilterHeadersStatus AccessFilter::encodeHeaders(Http::HeaderMap &headers,
-                                                      bool) {
+                                                      bool end_stream) {
   log_entry_.UpdateFromResponse(headers, callbacks_->requestInfo());
-  config_->Log(log_entry_, denied_ ? ::cilium::EntryType::Denied
-                                   : ::cilium::EntryType::Response);
+
+  // A gRPC call returns its status in the "grpc-status" trailer, unless the
+  // response is trailers-only. Delay logging until the trailers arrive.
+  const Http::HeaderEntry *content_type = headers.ContentType();
+  if (content_type &&
+      StringUtil::startsWith(content_type->value().c_str(),
+                             Http::Headers::get().ContentTypeValues.Grpc) &&
+      !log_entry_.UpdateGrpcStatus(headers) && !end_stream) {
+    grpc_status_pending_ = true;
+    return Http::FilterHeadersStatus::Continue;
+  }
+
+  LogResponse();
   return Http::FilterHeadersStatus::Continue;
 }
 
+Http::FilterTrailersStatus AccessFilter::encodeTrailers(Http::HeaderMap &trailers) {
+  if (grpc_status_pending_) {
+    grpc_status_pending_ = false;
+    log_entry_.UpdateGrpcStatus(trailers);
+    LogResponse();
+  }
+  return Http::FilterTrailersStatus::Continue;
+}
+
+void AccessFilter::LogResponse() {
+  config_->Log(log_entry_, !denied_ ? ::cilium::EntryType::Response
+                           : log_entry_.rate_limited ? ::cilium::EntryType::RateLimited
+                                                     : ::cilium::EntryType::Denied);
+}
+
 } // namespace Cilium
 } // namespace Envoy
@@ -23,6 +23,7 @@ namespace Cilium {
 // clang-format off
 #define ALL_CILIUM_STATS(COUNTER)                                                                  \
   COUNTER(access_denied)                                                                           \
+  COUNTER(rate_limited)                                                                            \
 // clang-format on
 
 /**
@@ -90,16 +91,19 @@ public:
   Http::FilterDataStatus encodeData(Buffer::Instance&, bool) override {
     return Http::FilterDataStatus::Continue;
   }
-  Http::FilterTrailersStatus encodeTrailers(Http::HeaderMap&) override {
-    return Http::FilterTrailersStatus::Continue;
-  }
+  Http::FilterTrailersStatus encodeTrailers(Http::HeaderMap& trailers) override;
   void setEncoderFilterCallbacks(Http::StreamEncoderFilterCallbacks&) override {}
 
 private:
+  void LogResponse();
+
   ConfigSharedPtr config_;
   Http::StreamDecoderFilterCallbacks* callbacks_;
 
   bool denied_;
+  // Set while the response log entry of a gRPC call waits for the
+  // "grpc-status" trailer.
+  bool grpc_status_pending_{false};
   AccessLog::Entry log_entry_;
 };
 
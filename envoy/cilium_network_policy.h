@@ -1,5 +1,9 @@
 #pragma once
 
+#include <algorithm>
+#include <chrono>
+#include <mutex>
+
 #include "envoy/local_info/local_info.h"
 #include "envoy/upstream/cluster_manager.h"
 #include "envoy/event/dispatcher.h"
@@ -13,6 +17,8 @@
 
 #include "cilium/npds.pb.h"
 
+#include "accesslog.h"
+
 namespace Envoy {
 namespace Cilium {
 
@@ -63,14 +69,169 @@ public:
 		    : header_data.header_match_type_ == Http::HeaderUtility::HeaderMatchType::Regex
 		    ? "<REGEX>" : "<UNKNOWN>");
 	}
+	for (const auto& header_match: rule.header_matches()) {
+	  header_matches_.emplace_back(header_match);
+	}
+	if (rule.has_rate_limit()) {
+	  rate_limit_ = std::make_shared<RateLimit>(rule.rate_limit());
+	}
       }
 
-      bool Matches(const Envoy::Http::HeaderMap& headers) const {
+      // Header matches with a non-failing mismatch action are only applied once the request is
+      // known to match this rule, so that the request is not modified by rules that do not match.
+      bool Matches(Envoy::Http::HeaderMap& headers, AccessLog::Entry& log_entry) const {
 	// Empty set matches any headers.
-	return Envoy::Http::HeaderUtility::matchHeaders(headers, headers_);
+	if (!Envoy::Http::HeaderUtility::matchHeaders(headers, headers_)) {
+	  return false;
+	}
+	for (const auto& header_match: header_matches_) {
+	  if (header_match.action_ == cilium::HeaderMatch::FAIL_ON_MISMATCH &&
+	      !header_match.Matches(headers)) {
+	    return false;
+	  }
+	}
+	// Only requests that otherwise match this rule count against its rate limit.
+	if (rate_limit_ && !rate_limit_->Allow(log_entry)) {
+	  log_entry.rate_limited = true;
+	  return false;
+	}
+	for (const auto& header_match: header_matches_) {
+	  if (header_match.action_ != cilium::HeaderMatch::FAIL_ON_MISMATCH &&
+	      !header_match.Matches(headers)) {
+	    header_match.OnMismatch(headers, log_entry);
+	  }
+	}
+	return true;
       }
 
+      class HeaderMatch : public Logger::Loggable<Logger::Id::config> {
+      public:
+	HeaderMatch(const cilium::HeaderMatch& config)
+	  : name_(config.header().name()), header_data_(config.header()),
+	    action_(config.mismatch_action()), value_(config.value()) {
+	  ENVOY_LOG(trace, "Cilium L7 HeaderMatch(): {} with mismatch action {}", name_.get(),
+		    cilium::HeaderMatch::MismatchAction_Name(action_));
+	}
+
+	bool Matches(const Envoy::Http::HeaderMap& headers) const {
+	  if (headers.get(name_) == nullptr) {
+	    // A missing header only matches an inverted presence match.
+	    return header_data_.invert_match_ &&
+	      header_data_.header_match_type_ == Envoy::Http::HeaderUtility::HeaderMatchType::Present;
+	  }
+	  return Envoy::Http::HeaderUtility::matchHeaders(headers, header_data_);
+	}
+
+	void OnMismatch(Envoy::Http::HeaderMap& headers, AccessLog::Entry& log_entry) const {
+	  // Values of the policy are not logged, as they may be secret.
+	  switch (action_) {
+	  case cilium::HeaderMatch::CONTINUE_ON_MISMATCH:
+	    log_entry.AddMissingHeader(name_.get());
+	    break;
+	  case cilium::HeaderMatch::ADD_ON_MISMATCH:
+	    log_entry.AddMissingHeader(name_.get());
+	    headers.addCopy(name_, value_);
+	    break;
+	  case cilium::HeaderMatch::REPLACE_ON_MISMATCH: {
+	    const Envoy::Http::HeaderEntry* entry = headers.get(name_);
+	    if (entry) {
+	      log_entry.AddRejectedHeader(name_.get(), entry->value().c_str());
+	      headers.remove(name_);
+	    }
+	    log_entry.AddMissingHeader(name_.get());
+	    headers.addCopy(name_, value_);
+	    break;
+	  }
+	  default:
+	    break;
+	  }
+	}
+
+	const Envoy::Http::LowerCaseString name_;
+	const Envoy::Http::HeaderUtility::HeaderData header_data_;
+	const cilium::HeaderMatch::MismatchAction action_;
+	const std::string value_;
+      };
+
+      // Token buckets limiting the rate of the requests matching a rule, one for each source.
+      // Shared by all worker threads.
+      class RateLimit : public Logger::Loggable<Logger::Id::config> {
+      public:
+	RateLimit(const cilium::HttpRateLimit& config)
+	  : burst_(std::max(config.burst(), config.requests())), scope_(config.scope()) {
+	  const double interval = config.interval().seconds() + config.interval().nanos() / 1e9;
+	  rate_ = config.requests() / interval;
+	  ENVOY_LOG(trace, "Cilium L7 RateLimit(): {} requests/s, burst {}, scope {}", rate_, burst_,
+		    cilium::HttpRateLimit::Scope_Name(scope_));
+	}
+
+	// Takes a token from the bucket of the source of the request, if there is one left.
+	bool Allow(const AccessLog::Entry& log_entry) {
+	  const std::string key = scope_ == cilium::HttpRateLimit::PER_SOURCE_ADDRESS
+	    ? SourceAddress(log_entry.entry.source_address())
+	    : std::to_string(log_entry.entry.source_security_id());
+	  const auto now = std::chrono::steady_clock::now();
+
+	  std::lock_guard<std::mutex> lock(mutex_);
+	  if (buckets_.size() >= max_buckets_) {
+	    Expire(now);
+	  }
+	  auto it = buckets_.find(key);
+	  if (it == buckets_.end()) {
+	    it = buckets_.emplace(key, Bucket{double(burst_), now}).first;
+	  }
+	  Bucket& bucket = it->second;
+	  Refill(bucket, now);
+	  if (bucket.tokens_ < 1.0) {
+	    ENVOY_LOG(debug, "Cilium L7 RateLimit(): Rate limit exceeded for source {}", key);
+	    return false;
+	  }
+	  bucket.tokens_ -= 1.0;
+	  return true;
+	}
+
+      private:
+	struct Bucket {
+	  double tokens_;
+	  std::chrono::steady_clock::time_point last_;
+	};
+
+	void Refill(Bucket& bucket, std::chrono::steady_clock::time_point now) const {
+	  const double elapsed = std::chrono::duration<double>(now - bucket.last_).count();
+	  bucket.tokens_ = std::min(double(burst_), bucket.tokens_ + elapsed * rate_);
+	  bucket.last_ = now;
+	}
+
+	// Full buckets are removed, as they are equivalent to the bucket of a new source.
+	void Expire(std::chrono::steady_clock::time_point now) {
+	  for (auto it = buckets_.begin(); it != buckets_.end();) {
+	    Refill(it->second, now);
+	    if (it->second.tokens_ >= burst_) {
+	      it = buckets_.erase(it);
+	    } else {
+	      it++;
+	    }
+	  }
+	}
+
+	// Strips the port from an "<ip>:<port>" address.
+	static std::string SourceAddress(const std::string& address) {
+	  auto pos = address.rfind(':');
+	  return pos == std::string::npos ? address : address.substr(0, pos);
+	}
+
+	static constexpr size_t max_buckets_ = 65536;
+
+	const uint32_t burst_;
+	const cilium::HttpRateLimit::Scope scope_;
+	double rate_; // Tokens per second.
+	std::mutex mutex_;
+	std::unordered_map<std::string, Bucket> buckets_;
+      };
+
       std::vector<Envoy::Http::HeaderUtility::HeaderData> headers_; // Allowed if empty.
+      std::vector<HeaderMatch> header_matches_; // Applied in order.
+      std::shared_ptr<RateLimit> rate_limit_; // Not limited if null.
     };
     
     class PortNetworkPolicyRule : public Logger::Loggable<Logger::Id::config> {
@@ -87,7 +248,8 @@ public:
 	}
       }
 
-      bool Matches(uint64_t remote_id, const Envoy::Http::HeaderMap& headers) const {
+      bool Matches(uint64_t remote_id, Envoy::Http::HeaderMap& headers,
+		   AccessLog::Entry& log_entry) const {
 	// Remote ID must match if we have any.
 	if (allowed_remotes_.size() > 0) {
 	  auto search = allowed_remotes_.find(remote_id);
@@ -97,7 +259,7 @@ public:
 	}
 	if (http_rules_.size() > 0) {
 	  for (const auto& rule: http_rules_) {
-	    if (rule.Matches(headers)) {
+	    if (rule.Matches(headers, log_entry)) {
 	      return true;
 	    }
 	  }
@@ -125,7 +287,8 @@ public:
 	}
       }
 
-      bool Matches(uint64_t remote_id, const Envoy::Http::HeaderMap& headers) const {
+      bool Matches(uint64_t remote_id, Envoy::Http::HeaderMap& headers,
+		   AccessLog::Entry& log_entry) const {
 	if (!have_http_rules_) {
 	  // If there are no L7 rules, host proxy will not create a proxy redirect at all,
 	  // whereby the decicion made by the bpf datapath is final. Emulate the same behavior
@@ -138,7 +301,7 @@ public:
 	  return true;
 	}
 	for (const auto& rule: rules_) {
-	  if (rule.Matches(remote_id, headers)) {
+	  if (rule.Matches(remote_id, headers, log_entry)) {
 	    return true;
 	  }
 	}
@@ -153,6 +316,16 @@ public:
     public:
       PortNetworkPolicy(const google::protobuf::RepeatedPtrField<cilium::PortNetworkPolicy>& rules) {
 	for (const auto& it: rules) {
+	  for (const auto& rule: it.rules()) {
+	    for (const auto& http_rule: rule.http_rules().http_rules()) {
+	      for (const auto& header_match: http_rule.header_matches()) {
+		if (header_match.secret()) {
+		  secret_headers_.emplace(
+		    Envoy::Http::LowerCaseString(header_match.header().name()).get());
+		}
+	      }
+	    }
+	  }
 	  // Only TCP supported for HTTP
 	  if (it.protocol() == envoy::api::v2::core::SocketAddress::TCP) {
 	    // Port may be zero, which matches any port.
@@ -166,11 +339,12 @@ public:
 	}
       }
 
-      bool Matches(uint32_t port, uint64_t remote_id, const Envoy::Http::HeaderMap& headers) const {
+      bool Matches(uint32_t port, uint64_t remote_id, Envoy::Http::HeaderMap& headers,
+		   AccessLog::Entry& log_entry) const {
 	bool found_port_rule = false;
 	auto it = rules_.find(port);
 	if (it != rules_.end()) {
-	  if (it->second.Matches(remote_id, headers)) {
+	  if (it->second.Matches(remote_id, headers, log_entry)) {
 	    return true;
 	  }
 	  found_port_rule = true;
@@ -178,7 +352,7 @@ public:
 	// Check for any rules that wildcard the port
 	it = rules_.find(0);
 	if (it != rules_.end()) {
-	  if (it->second.Matches(remote_id, headers)) {
+	  if (it->second.Matches(remote_id, headers, log_entry)) {
 	    return true;
 	  }
 	  found_port_rule = true;
@@ -192,14 +366,19 @@ public:
       }
 
       std::unordered_map<uint32_t, PortNetworkPolicyRules> rules_;
+      // Names of the headers matched against a secret on any port. Their values are
+      // redacted in the access log regardless of the rule matched, as the request may
+      // carry the secret even if it does not match.
+      std::unordered_set<std::string> secret_headers_;
     };
 
   public:
     bool Allowed(bool ingress, uint32_t port, uint64_t remote_id,
-		 const Envoy::Http::HeaderMap& headers) const {
-      return ingress
-	? ingress_.Matches(port, remote_id, headers)
-	: egress_.Matches(port, remote_id, headers);
+		 Envoy::Http::HeaderMap& headers, AccessLog::Entry& log_entry) const {
+      const PortNetworkPolicy& policy = ingress ? ingress_ : egress_;
+      bool allowed = policy.Matches(port, remote_id, headers, log_entry);
+      log_entry.RedactHeaders(policy.secret_headers_);
+      return allowed;
     }
 
   private:
@@ -221,7 +400,7 @@ public:
   }
 
   bool Allowed(const std::string& endpoint_policy_name, bool ingress, uint32_t port, uint64_t remote_id,
-	       const Envoy::Http::HeaderMap& headers) const {
+	       Envoy::Http::HeaderMap& headers, AccessLog::Entry& log_entry) const {
     ENVOY_LOG(trace, "Cilium L7 NetworkPolicyMap::Allowed(): {} policy lookup for endpoint {}, port {}, remote_id: {}", ingress ? "Ingress" : "Egress", endpoint_policy_name, port, remote_id);
     if (tls_->get().get() == nullptr) {
       ENVOY_LOG(warn, "Cilium L7 NetworkPolicyMap::Allowed(): NULL TLS object!");
@@ -233,7 +412,7 @@ public:
       ENVOY_LOG(trace, "Cilium L7 NetworkPolicyMap::Allowed(): No policy found for endpoint {}", endpoint_policy_name);
       return false;
     }
-    return it->second->Allowed(ingress, port, remote_id, headers);
+    return it->second->Allowed(ingress, port, remote_id, headers, log_entry);
   }
 
   // Config::SubscriptionCallbacks
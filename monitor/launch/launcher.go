@@ -17,21 +17,29 @@ package launch
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/gob"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
+	monitorAPI "github.com/cilium/cilium/api/v1/monitor"
 	"github.com/cilium/cilium/pkg/backoff"
+	"github.com/cilium/cilium/pkg/defaults"
 	"github.com/cilium/cilium/pkg/launcher"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/metrics"
 	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/option"
+
+	"google.golang.org/grpc"
 )
 
 var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "monitor-launcher")
@@ -41,14 +49,61 @@ const (
 
 	// queueSize is the size of the message queue
 	queueSize = 524288
+
+	// grpcTimeout is the timeout of requests to the gRPC API of the
+	// monitor
+	grpcTimeout = 10 * time.Second
 )
 
+// GRPCConfig is the configuration of the gRPC API of the node monitor served
+// on an additional TCP address
+type GRPCConfig struct {
+	// Address is the TCP address, the API is not served on TCP if empty
+	Address string
+
+	// TLSCertFile and TLSKeyFile are the TLS certificate and private key
+	// of the API. The API is served without TLS if empty.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// TLSClientCAFile is the file with the CA certificates verifying the
+	// required client certificates
+	TLSClientCAFile string
+}
+
+// args returns the arguments of the node monitor for the configuration
+func (c GRPCConfig) args() []string {
+	var args []string
+	if c.Address != "" {
+		args = append(args, "--grpc-address", c.Address)
+	}
+	if c.TLSCertFile != "" {
+		args = append(args, "--grpc-tls-cert-file", c.TLSCertFile)
+	}
+	if c.TLSKeyFile != "" {
+		args = append(args, "--grpc-tls-key-file", c.TLSKeyFile)
+	}
+	if c.TLSClientCAFile != "" {
+		args = append(args, "--grpc-tls-client-ca-file", c.TLSClientCAFile)
+	}
+	return args
+}
+
 // NodeMonitor is used to wrap the node executable binary.
 type NodeMonitor struct {
 	launcher.Launcher
 
 	state *models.MonitorStatus
 
+	// lostBase is the state the lost event metrics were last exported
+	// for. It is reset whenever the monitor is launched as the counters
+	// of the monitor restart at zero.
+	lostBase *models.MonitorStatus
+
+	// numPages is the number of pages of the perf ring buffer of each CPU,
+	// 0 for the default of the monitor
+	numPages int
+
 	// The following members are protected by pipeLock
 	pipeLock lock.Mutex
 	pipe     *os.File
@@ -79,7 +134,7 @@ func (nm *NodeMonitor) GetPid() int {
 // returns with an error if the FIFO cannot be created, opened or if the an
 // error was encountered while reading stdout from the monitor. The FIFO is always
 // removed again when the function returns.
-func (nm *NodeMonitor) run(sockPath, bpfRoot string) error {
+func (nm *NodeMonitor) run(sockPath, bpfRoot string, grpcConfig GRPCConfig) error {
 	os.Remove(sockPath)
 	if err := syscall.Mkfifo(sockPath, 0600); err != nil {
 		return fmt.Errorf("Unable to create named pipe %s: %s", sockPath, err)
@@ -98,7 +153,16 @@ func (nm *NodeMonitor) run(sockPath, bpfRoot string) error {
 	nm.pipe = pipe
 	nm.pipeLock.Unlock()
 
-	nm.Launcher.SetArgs([]string{"--bpf-root", bpfRoot})
+	args := append([]string{"--bpf-root", bpfRoot}, grpcConfig.args()...)
+	if numPages := nm.getNumPages(); numPages > 0 {
+		args = append(args, "--num-pages", strconv.Itoa(numPages))
+	}
+	nm.Launcher.SetArgs(args)
+
+	nm.Mutex.Lock()
+	nm.lostBase = nil
+	nm.Mutex.Unlock()
+
 	if err := nm.Launcher.Run(); err != nil {
 		return err
 	}
@@ -124,12 +188,12 @@ func (nm *NodeMonitor) run(sockPath, bpfRoot string) error {
 
 // Run starts the node monitor and keeps on restarting it. The function will
 // never return.
-func (nm *NodeMonitor) Run(sockPath, bpfRoot string) {
+func (nm *NodeMonitor) Run(sockPath, bpfRoot string, grpcConfig GRPCConfig) {
 	backoffConfig := backoff.Exponential{Min: time.Second, Max: 2 * time.Minute}
 
 	nm.SetTarget(targetName)
 	for {
-		if err := nm.run(sockPath, bpfRoot); err != nil {
+		if err := nm.run(sockPath, bpfRoot, grpcConfig); err != nil {
 			log.WithError(err).Warning("Error while running monitor")
 		}
 
@@ -145,11 +209,87 @@ func (nm *NodeMonitor) State() *models.MonitorStatus {
 	return state
 }
 
-// setState sets the internal state monitor with the given state.
+// setState sets the internal state monitor with the given state and exports
+// the events lost since the previous state as metrics.
 func (nm *NodeMonitor) setState(state *models.MonitorStatus) {
 	nm.Mutex.Lock()
+	base := nm.lostBase
 	nm.state = state
+	nm.lostBase = state
 	nm.Mutex.Unlock()
+
+	exportLostEvents(base, state)
+}
+
+// exportLostEvents increments the lost event metrics by the events lost
+// between the base state and the current state. A nil base stands for a
+// monitor which has just been launched.
+func exportLostEvents(base, state *models.MonitorStatus) {
+	if state == nil {
+		return
+	}
+	if base == nil {
+		base = &models.MonitorStatus{}
+	}
+
+	for cpu, lost := range state.LostPerCPU {
+		if cpu < len(base.LostPerCPU) {
+			lost -= base.LostPerCPU[cpu]
+		}
+		if lost > 0 {
+			metrics.MonitorPerfLostEvents.WithLabelValues(strconv.Itoa(cpu)).Add(float64(lost))
+		}
+	}
+
+	if lost := state.ListenerQueueLost - base.ListenerQueueLost; lost > 0 {
+		metrics.MonitorListenerQueueLostEvents.Add(float64(lost))
+	}
+}
+
+// getNumPages returns the number of pages of the perf ring buffer
+func (nm *NodeMonitor) getNumPages() int {
+	nm.Mutex.RLock()
+	defer nm.Mutex.RUnlock()
+	return nm.numPages
+}
+
+// SetNumPages sets the number of pages of the perf ring buffer of each CPU.
+// The size is retained across restarts of the monitor. The ring buffer of a
+// running monitor is resized via its gRPC API without disconnecting the
+// monitor listeners.
+func (nm *NodeMonitor) SetNumPages(numPages int) error {
+	if err := option.ValidateMonitorNumPages(numPages); err != nil {
+		return err
+	}
+
+	nm.Mutex.Lock()
+	nm.numPages = numPages
+	nm.Mutex.Unlock()
+
+	if nm.GetProcess() == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), grpcTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, defaults.MonitorGRPCSockPath,
+		grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", addr, timeout)
+		}))
+	if err != nil {
+		return fmt.Errorf("unable to connect to monitor: %s", err)
+	}
+	defer conn.Close()
+
+	_, err = monitorAPI.NewMonitorClient(conn).SetPerfRingSize(ctx,
+		&monitorAPI.SetPerfRingSizeRequest{NumPages: uint32(numPages)})
+	if err != nil {
+		return fmt.Errorf("unable to resize perf ring buffer: %s", err)
+	}
+
+	return nil
 }
 
 // SendEvent sends an event to the node monitor which will then distribute to
@@ -0,0 +1,220 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listener
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"io"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+)
+
+// Filter selects the events sent to a listener. The filter is registered by
+// the listener when connecting and is applied by the node monitor before a
+// payload is enqueued. All non-empty fields must match for an event to be
+// selected. Lost event records are never filtered.
+type Filter struct {
+	// Types are the message types of the events to select, see
+	// monitor.MessageType*
+	Types []int
+
+	// FromEndpoints are the IDs of the endpoints which must be the source
+	// of the event
+	FromEndpoints []uint16
+
+	// ToEndpoints are the IDs of the endpoints which must be the
+	// destination of the event
+	ToEndpoints []uint16
+
+	// RelatedEndpoints are the IDs of the endpoints which must be either
+	// source or destination of the event
+	RelatedEndpoints []uint16
+
+	// Identities are the security identities which must be either source
+	// or destination of the event
+	Identities []uint32
+
+	// DropReasons restricts drop notifications to the given drop reasons.
+	// Other events are not affected.
+	DropReasons []uint8
+}
+
+// IsEmpty returns true if the filter selects all events
+func (f *Filter) IsEmpty() bool {
+	return f == nil || (len(f.Types) == 0 && len(f.FromEndpoints) == 0 &&
+		len(f.ToEndpoints) == 0 && len(f.RelatedEndpoints) == 0 &&
+		len(f.Identities) == 0 && len(f.DropReasons) == 0)
+}
+
+// EventInfo is the information of a monitor event which is relevant for
+// filtering. Fields which are not provided by an event are zero.
+type EventInfo struct {
+	Lost                bool
+	Type                int
+	Source              uint16
+	Destination         uint16
+	SourceIdentity      uint32
+	DestinationIdentity uint32
+	DropReason          uint8
+}
+
+// NewEventInfo extracts the information relevant for filtering from a
+// payload. Payloads which cannot be parsed only provide the message type.
+func NewEventInfo(pl *payload.Payload) *EventInfo {
+	info := &EventInfo{}
+
+	if pl.Type == payload.RecordLost || pl.Type == payload.ListenerQueueLost {
+		info.Lost = true
+		return info
+	}
+
+	if len(pl.Data) == 0 {
+		return info
+	}
+
+	info.Type = int(pl.Data[0])
+
+	switch info.Type {
+	case monitor.MessageTypeDrop:
+		dn := monitor.DropNotify{}
+		if binary.Read(bytes.NewReader(pl.Data), byteorder.Native, &dn) == nil {
+			info.Source, info.Destination = dn.Source, uint16(dn.DstID)
+			info.SourceIdentity, info.DestinationIdentity = dn.SrcLabel, dn.DstLabel
+			info.DropReason = dn.SubType
+		}
+
+	case monitor.MessageTypeTrace:
+		tn := monitor.TraceNotify{}
+		if binary.Read(bytes.NewReader(pl.Data), byteorder.Native, &tn) == nil {
+			info.Source, info.Destination = tn.Source, tn.DstID
+			info.SourceIdentity, info.DestinationIdentity = tn.SrcLabel, tn.DstLabel
+		}
+
+	case monitor.MessageTypePolicyVerdict:
+		pn := monitor.PolicyVerdictNotify{}
+		if binary.Read(bytes.NewReader(pl.Data), byteorder.Native, &pn) == nil {
+			info.Source, info.Destination = pn.SourceEndpoint(), pn.DestinationEndpoint()
+			info.SourceIdentity, info.DestinationIdentity = pn.SourceIdentity(), pn.DestinationIdentity()
+		}
+
+	case monitor.MessageTypeDebug:
+		dm := monitor.DebugMsg{}
+		if binary.Read(bytes.NewReader(pl.Data), byteorder.Native, &dm) == nil {
+			info.Source = dm.Source
+		}
+
+	case monitor.MessageTypeCapture:
+		dc := monitor.DebugCapture{}
+		if binary.Read(bytes.NewReader(pl.Data), byteorder.Native, &dc) == nil {
+			info.Source = dc.Source
+		}
+
+	case monitor.MessageTypeAccessLog:
+		lr := monitor.LogRecordNotify{}
+		if gob.NewDecoder(bytes.NewReader(pl.Data[1:])).Decode(&lr) == nil {
+			info.Source = uint16(lr.SourceEndpoint.ID)
+			info.Destination = uint16(lr.DestinationEndpoint.ID)
+			info.SourceIdentity = uint32(lr.SourceEndpoint.Identity)
+			info.DestinationIdentity = uint32(lr.DestinationEndpoint.Identity)
+		}
+	}
+
+	return info
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint16(values []uint16, v uint16) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint32(values []uint32, v uint32) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint8(values []uint8, v uint8) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns true if the event is selected by the filter
+func (f *Filter) Match(info *EventInfo) bool {
+	if f.IsEmpty() || info.Lost {
+		return true
+	}
+
+	switch {
+	case len(f.Types) > 0 && !containsInt(f.Types, info.Type):
+		return false
+	case len(f.FromEndpoints) > 0 && !containsUint16(f.FromEndpoints, info.Source):
+		return false
+	case len(f.ToEndpoints) > 0 && !containsUint16(f.ToEndpoints, info.Destination):
+		return false
+	case len(f.RelatedEndpoints) > 0 &&
+		!containsUint16(f.RelatedEndpoints, info.Source) && !containsUint16(f.RelatedEndpoints, info.Destination):
+		return false
+	case len(f.Identities) > 0 &&
+		!containsUint32(f.Identities, info.SourceIdentity) && !containsUint32(f.Identities, info.DestinationIdentity):
+		return false
+	case len(f.DropReasons) > 0 && info.Type == monitor.MessageTypeDrop &&
+		!containsUint8(f.DropReasons, info.DropReason):
+		return false
+	}
+
+	return true
+}
+
+// WriteFilter sends the filter of a listener to the node monitor. It is
+// sent by Version1_3 listeners right after connecting.
+func WriteFilter(w io.Writer, f *Filter) error {
+	if f == nil {
+		f = &Filter{}
+	}
+	return gob.NewEncoder(w).Encode(f)
+}
+
+// ReadFilter reads the filter sent by a Version1_3 listener
+func ReadFilter(r io.Reader) (*Filter, error) {
+	f := &Filter{}
+	if err := gob.NewDecoder(r).Decode(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
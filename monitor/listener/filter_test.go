@@ -0,0 +1,171 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package listener
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"testing"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type ListenerSuite struct{}
+
+var _ = Suite(&ListenerSuite{})
+
+func testDropPayload(c *C) *payload.Payload {
+	buf := &bytes.Buffer{}
+	err := binary.Write(buf, byteorder.Native, monitor.DropNotify{
+		Type:     monitor.MessageTypeDrop,
+		SubType:  133,
+		Source:   10,
+		SrcLabel: 100,
+		DstLabel: 200,
+		DstID:    20,
+	})
+	c.Assert(err, IsNil)
+	return &payload.Payload{Data: buf.Bytes(), Type: payload.EventSample}
+}
+
+func (s *ListenerSuite) TestNewEventInfo(c *C) {
+	c.Assert(NewEventInfo(testDropPayload(c)), DeepEquals, &EventInfo{
+		Type:                monitor.MessageTypeDrop,
+		Source:              10,
+		Destination:         20,
+		SourceIdentity:      100,
+		DestinationIdentity: 200,
+		DropReason:          133,
+	})
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte(monitor.MessageTypeAccessLog)
+	err := gob.NewEncoder(buf).Encode(accesslog.LogRecord{
+		SourceEndpoint:      accesslog.EndpointInfo{ID: 10, Identity: 100},
+		DestinationEndpoint: accesslog.EndpointInfo{ID: 20, Identity: 200},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(NewEventInfo(&payload.Payload{Data: buf.Bytes(), Type: payload.EventSample}), DeepEquals, &EventInfo{
+		Type:                monitor.MessageTypeAccessLog,
+		Source:              10,
+		Destination:         20,
+		SourceIdentity:      100,
+		DestinationIdentity: 200,
+	})
+
+	// Policy verdicts of ingress connections have the local endpoint as
+	// destination
+	buf = &bytes.Buffer{}
+	err = binary.Write(buf, byteorder.Native, monitor.PolicyVerdictNotify{
+		Type:        monitor.MessageTypePolicyVerdict,
+		Source:      20,
+		RemoteLabel: 100,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(NewEventInfo(&payload.Payload{Data: buf.Bytes(), Type: payload.EventSample}), DeepEquals, &EventInfo{
+		Type:           monitor.MessageTypePolicyVerdict,
+		Destination:    20,
+		SourceIdentity: 100,
+	})
+
+	c.Assert(NewEventInfo(&payload.Payload{Type: payload.RecordLost}), DeepEquals, &EventInfo{Lost: true})
+	c.Assert(NewEventInfo(&payload.Payload{Type: payload.ListenerQueueLost}), DeepEquals, &EventInfo{Lost: true})
+
+	// Truncated notifications only provide the type
+	c.Assert(NewEventInfo(&payload.Payload{Data: []byte{monitor.MessageTypeTrace}, Type: payload.EventSample}),
+		DeepEquals, &EventInfo{Type: monitor.MessageTypeTrace})
+}
+
+func (s *ListenerSuite) TestFilterMatch(c *C) {
+	drop := NewEventInfo(testDropPayload(c))
+	lost := &EventInfo{Lost: true}
+	agent := &EventInfo{Type: monitor.MessageTypeAgent}
+
+	var filter *Filter
+	c.Assert(filter.IsEmpty(), Equals, true)
+	c.Assert(filter.Match(drop), Equals, true)
+	c.Assert((&Filter{}).IsEmpty(), Equals, true)
+
+	filter = &Filter{Types: []int{monitor.MessageTypeTrace}}
+	c.Assert(filter.IsEmpty(), Equals, false)
+	c.Assert(filter.Match(drop), Equals, false)
+	c.Assert(filter.Match(lost), Equals, true)
+
+	filter = &Filter{FromEndpoints: []uint16{10}}
+	c.Assert(filter.Match(drop), Equals, true)
+	c.Assert(filter.Match(agent), Equals, false)
+
+	filter = &Filter{ToEndpoints: []uint16{10}}
+	c.Assert(filter.Match(drop), Equals, false)
+
+	filter = &Filter{RelatedEndpoints: []uint16{20}}
+	c.Assert(filter.Match(drop), Equals, true)
+
+	filter = &Filter{Identities: []uint32{200}}
+	c.Assert(filter.Match(drop), Equals, true)
+	filter = &Filter{Identities: []uint32{300}}
+	c.Assert(filter.Match(drop), Equals, false)
+
+	// Drop reasons only restrict drop notifications
+	filter = &Filter{DropReasons: []uint8{133}}
+	c.Assert(filter.Match(drop), Equals, true)
+	filter = &Filter{DropReasons: []uint8{159}}
+	c.Assert(filter.Match(drop), Equals, false)
+	c.Assert(filter.Match(agent), Equals, true)
+
+	// All fields must match
+	filter = &Filter{
+		Types:         []int{monitor.MessageTypeDrop},
+		FromEndpoints: []uint16{10},
+		Identities:    []uint32{300},
+	}
+	c.Assert(filter.Match(drop), Equals, false)
+}
+
+func (s *ListenerSuite) TestReadWriteFilter(c *C) {
+	filter := &Filter{
+		Types:       []int{monitor.MessageTypeDrop},
+		Identities:  []uint32{100},
+		DropReasons: []uint8{133},
+	}
+
+	buf := &bytes.Buffer{}
+	c.Assert(WriteFilter(buf, filter), IsNil)
+	read, err := ReadFilter(buf)
+	c.Assert(err, IsNil)
+	c.Assert(read, DeepEquals, filter)
+
+	buf.Reset()
+	c.Assert(WriteFilter(buf, nil), IsNil)
+	read, err = ReadFilter(buf)
+	c.Assert(err, IsNil)
+	c.Assert(read.IsEmpty(), Equals, true)
+
+	_, err = ReadFilter(bytes.NewBufferString("invalid"))
+	c.Assert(err, Not(IsNil))
+}
@@ -23,12 +23,17 @@ import (
 )
 
 // Version is the version of a node-monitor listener client. There are
-// two API versions:
+// three API versions:
 // - 1.0 which encodes the gob type information with each payload sent, and
 //   adds a meta object before it.
 // - 1.2 which maintains a gob session per listener, thus only encoding the
 //   type information on the first payload sent. It does NOT prepend the a meta
 //   object.
+// - 1.3 which is identical to 1.2 except that the client first sends a gob
+//   encoded Filter. Only the payloads matching the filter are sent to the
+//   client.
+// Additionally, clients of the gRPC API are represented by listeners of
+// version "grpc".
 type Version string
 
 const (
@@ -40,6 +45,13 @@ const (
 
 	// Version1_2 is the API 1.0 version of the protocol (see above).
 	Version1_2 = Version("1.2")
+
+	// Version1_3 is the API 1.3 version of the protocol (see above).
+	Version1_3 = Version("1.3")
+
+	// VersionGRPC is a client of the gRPC API which receives the events as
+	// protobuf messages (see api/v1/monitor/monitor.proto).
+	VersionGRPC = Version("grpc")
 )
 
 // MonitorListener is a generic consumer of monitor events. Implementers are
@@ -51,6 +63,10 @@ type MonitorListener interface {
 
 	// Version returns the API version of this listener
 	Version() Version
+
+	// QueueLost returns the number of payloads the listener dropped
+	// because its queue was full
+	QueueLost() uint64
 }
 
 // IsDisconnected is a convenience function that wraps the absurdly long set of
@@ -26,6 +26,8 @@ import (
 // cilium 1.2
 // cleanupFn is called on exit
 type listenerv1_2 struct {
+	queueLoss
+
 	conn      net.Conn
 	queue     chan *payload.Payload
 	cleanupFn func(listener.MonitorListener)
@@ -44,11 +46,14 @@ func newListenerv1_2(c net.Conn, queueSize int, cleanupFn func(listener.MonitorL
 }
 
 func (ml *listenerv1_2) Enqueue(pl *payload.Payload) {
-	select {
-	case ml.queue <- pl:
-	default:
-		log.Debug("Per listener queue is full, dropping message")
-	}
+	ml.enqueue(pl, func(pl *payload.Payload) bool {
+		select {
+		case ml.queue <- pl:
+			return true
+		default:
+			return false
+		}
+	})
 }
 
 // drainQueue encodes and sends monitor payloads to the listener. It is
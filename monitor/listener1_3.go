@@ -0,0 +1,111 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/gob"
+	"io"
+	"io/ioutil"
+	"net"
+
+	"github.com/cilium/cilium/monitor/listener"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+)
+
+// listenerv1_3 implements the cilium-node-monitor API protocol compatible with
+// cilium 1.3. The filter sent by the client is applied by the monitor before
+// payloads are enqueued, the encoding is identical to listenerv1_2.
+// cleanupFn is called on exit
+type listenerv1_3 struct {
+	queueLoss
+
+	conn      net.Conn
+	queue     chan *payload.Payload
+	cleanupFn func(listener.MonitorListener)
+
+	// closed is closed when the client has closed the connection
+	closed chan struct{}
+}
+
+func newListenerv1_3(c net.Conn, queueSize int, cleanupFn func(listener.MonitorListener)) *listenerv1_3 {
+	ml := &listenerv1_3{
+		queueLoss: queueLoss{notify: true},
+		conn:      c,
+		queue:     make(chan *payload.Payload, queueSize),
+		cleanupFn: cleanupFn,
+		closed:    make(chan struct{}),
+	}
+
+	go ml.drainQueue()
+	go ml.watchConn()
+
+	return ml
+}
+
+func (ml *listenerv1_3) Enqueue(pl *payload.Payload) {
+	ml.enqueue(pl, func(pl *payload.Payload) bool {
+		select {
+		case ml.queue <- pl:
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+// drainQueue encodes and sends monitor payloads to the listener. It is
+// intended to be a goroutine.
+func (ml *listenerv1_3) drainQueue() {
+	defer func() {
+		ml.conn.Close()
+		ml.cleanupFn(ml)
+	}()
+
+	enc := gob.NewEncoder(ml.conn)
+	for {
+		select {
+		case pl := <-ml.queue:
+			if err := pl.EncodeBinary(enc); err != nil {
+				switch {
+				case listener.IsDisconnected(err):
+					log.Debug("Listener disconnected")
+					return
+
+				default:
+					log.WithError(err).Warn("Removing listener due to write failure")
+					return
+				}
+			}
+
+		case <-ml.closed:
+			log.Debug("Listener disconnected")
+			return
+		}
+	}
+}
+
+// watchConn reads from the connection until it is closed and then stops
+// drainQueue. Clients send nothing after the filter, so this detects
+// disconnected clients even if their filter never selects a payload and
+// drainQueue never writes to the connection. It is intended to be a
+// goroutine.
+func (ml *listenerv1_3) watchConn() {
+	io.Copy(ioutil.Discard, ml.conn)
+	close(ml.closed)
+}
+
+func (ml *listenerv1_3) Version() listener.Version {
+	return listener.Version1_3
+}
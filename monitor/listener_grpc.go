@@ -0,0 +1,161 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"time"
+
+	monitorAPI "github.com/cilium/cilium/api/v1/monitor"
+	"github.com/cilium/cilium/monitor/listener"
+	"github.com/cilium/cilium/pkg/monitor/events"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/option"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// receivedPayload is a payload along with the time it was received at
+type receivedPayload struct {
+	pl *payload.Payload
+	ts time.Time
+}
+
+// listenerGRPC implements a MonitorListener for clients of the gRPC API. The
+// payloads are converted into protobuf events before they are sent.
+type listenerGRPC struct {
+	queueLoss
+
+	queue chan receivedPayload
+}
+
+func newListenerGRPC(queueSize int) *listenerGRPC {
+	return &listenerGRPC{
+		queueLoss: queueLoss{notify: true},
+		queue:     make(chan receivedPayload, queueSize),
+	}
+}
+
+func (ml *listenerGRPC) Enqueue(pl *payload.Payload) {
+	ts := time.Now()
+	ml.enqueue(pl, func(pl *payload.Payload) bool {
+		select {
+		case ml.queue <- receivedPayload{pl: pl, ts: ts}:
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+// drainQueue converts and sends the queued payloads to the client until the
+// stream is closed or sending fails.
+func (ml *listenerGRPC) drainQueue(stream monitorAPI.Monitor_GetEventsServer) error {
+	for {
+		select {
+		case <-stream.Context().Done():
+			log.Debug("gRPC listener disconnected")
+			return nil
+
+		case rp := <-ml.queue:
+			ev, err := events.NewEvent(rp.pl, rp.ts)
+			if err != nil {
+				log.WithError(err).Debug("Unable to convert monitor payload into gRPC event")
+				continue
+			}
+
+			if err := stream.Send(ev); err != nil {
+				log.WithError(err).Warn("Removing gRPC listener due to write failure")
+				return err
+			}
+		}
+	}
+}
+
+func (ml *listenerGRPC) Version() listener.Version {
+	return listener.VersionGRPC
+}
+
+// newGRPCFilter converts the event filter of the gRPC API into the filter
+// applied by the monitor
+func newGRPCFilter(f *monitorAPI.EventFilter) *listener.Filter {
+	if f == nil {
+		return nil
+	}
+
+	filter := &listener.Filter{
+		Identities: f.Identities,
+	}
+	for _, typ := range f.Types {
+		filter.Types = append(filter.Types, int(typ))
+	}
+	for _, id := range f.FromEndpoints {
+		filter.FromEndpoints = append(filter.FromEndpoints, uint16(id))
+	}
+	for _, id := range f.ToEndpoints {
+		filter.ToEndpoints = append(filter.ToEndpoints, uint16(id))
+	}
+	for _, id := range f.RelatedEndpoints {
+		filter.RelatedEndpoints = append(filter.RelatedEndpoints, uint16(id))
+	}
+	for _, reason := range f.DropReasons {
+		filter.DropReasons = append(filter.DropReasons, uint8(reason))
+	}
+
+	return filter
+}
+
+// GetEvents implements the monitor gRPC API. It registers a listener which
+// streams all events matching the filter of the request until the client
+// closes the stream.
+func (m *Monitor) GetEvents(req *monitorAPI.GetEventsRequest, stream monitorAPI.Monitor_GetEventsServer) error {
+	ml := newListenerGRPC(queueSize)
+	if err := m.registerListener(m.ctx, ml, newGRPCFilter(req.GetFilter())); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer m.removeListener(ml)
+
+	return ml.drainQueue(stream)
+}
+
+// SetPerfRingSize implements the monitor gRPC API. It resizes the perf ring
+// buffer without disconnecting the listeners.
+func (m *Monitor) SetPerfRingSize(ctx context.Context, req *monitorAPI.SetPerfRingSizeRequest) (*monitorAPI.SetPerfRingSizeResponse, error) {
+	numPages := int(req.GetNumPages())
+	if err := option.ValidateMonitorNumPages(numPages); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	previous, err := m.setNumPages(numPages)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &monitorAPI.SetPerfRingSizeResponse{PreviousNumPages: uint32(previous)}, nil
+}
+
+// readOnlyMonitor implements the monitor gRPC API served on the additional
+// TCP address. Only the events can be read, the monitor configuration can
+// only be changed via the local UNIX domain socket.
+type readOnlyMonitor struct {
+	*Monitor
+}
+
+// SetPerfRingSize implements the monitor gRPC API. It always fails as the
+// perf ring buffer can only be resized via the local UNIX domain socket.
+func (m readOnlyMonitor) SetPerfRingSize(ctx context.Context, req *monitorAPI.SetPerfRingSizeRequest) (*monitorAPI.SetPerfRingSizeResponse, error) {
+	return nil, status.Error(codes.PermissionDenied, "the perf ring buffer can only be resized via the local socket")
+}
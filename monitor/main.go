@@ -16,21 +16,29 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"os/signal"
 	"path"
 	"syscall"
 
+	monitorAPI "github.com/cilium/cilium/api/v1/monitor"
 	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/pkg/api"
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/defaults"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/option"
 
 	gops "github.com/google/gops/agent"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 var (
@@ -54,11 +62,27 @@ var (
 	// bpfRoot is the path to the BPF mount. This can be non-default if
 	// cilium-agent mounts bpf at an alternate location.
 	bpfRoot string
+
+	// grpcAddress is an additional TCP address to serve the gRPC API on
+	grpcAddress string
+
+	// grpcTLSCertFile and grpcTLSKeyFile are the TLS certificate and
+	// private key of the gRPC API served on grpcAddress
+	grpcTLSCertFile string
+	grpcTLSKeyFile  string
+
+	// grpcTLSClientCAFile is the file with the CA certificates verifying
+	// the client certificates required on grpcAddress
+	grpcTLSClientCAFile string
 )
 
 func init() {
 	rootCmd.Flags().IntVar(&npages, "num-pages", 64, "Number of pages for ring buffer")
 	rootCmd.Flags().StringVar(&bpfRoot, "bpf-root", "/sys/fs/bpf", "Path to the root of the bpf mount")
+	rootCmd.Flags().StringVar(&grpcAddress, "grpc-address", "", "Additional TCP address to serve the gRPC API on, must be a loopback address unless TLS is configured")
+	rootCmd.Flags().StringVar(&grpcTLSCertFile, "grpc-tls-cert-file", "", "TLS certificate of the gRPC API served on --grpc-address")
+	rootCmd.Flags().StringVar(&grpcTLSKeyFile, "grpc-tls-key-file", "", "TLS private key of the gRPC API served on --grpc-address")
+	rootCmd.Flags().StringVar(&grpcTLSClientCAFile, "grpc-tls-client-ca-file", "", "CA certificates verifying the required client certificates on --grpc-address")
 }
 
 func execute() {
@@ -93,6 +117,49 @@ func buildServerOrExit(path string) net.Listener {
 	return server
 }
 
+// grpcServerOptions returns the options of the gRPC server serving the API on
+// grpcAddress
+func grpcServerOptions() ([]grpc.ServerOption, error) {
+	if err := option.ValidateMonitorGRPCAddress(grpcAddress, grpcTLSCertFile, grpcTLSKeyFile, grpcTLSClientCAFile); err != nil {
+		return nil, err
+	}
+	if grpcTLSCertFile == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(grpcTLSCertFile, grpcTLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load TLS certificate: %s", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if grpcTLSClientCAFile != "" {
+		pem, err := ioutil.ReadFile(grpcTLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client CA certificates: %s", err)
+		}
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no client CA certificates found in %s", grpcTLSClientCAFile)
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(config))}, nil
+}
+
+// serveGRPC serves the gRPC API on the listener in the background
+func serveGRPC(server *grpc.Server, lis net.Listener) {
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			log.WithError(err).WithField("address", lis.Addr()).Error("Error while serving gRPC API")
+		}
+	}()
+}
+
 func runNodeMonitor() {
 	bpf.SetMapRoot(bpfRoot)
 
@@ -110,6 +177,10 @@ func runNodeMonitor() {
 
 	common.RequireRootPrivilege(targetName)
 
+	if err := option.ValidateMonitorNumPages(npages); err != nil {
+		log.WithError(err).Fatal("Invalid option --num-pages")
+	}
+
 	server1_0 := buildServerOrExit(defaults.MonitorSockPath1_0)
 	defer server1_0.Close() // Stop accepting new v1.0 connections
 	log.Infof("Serving cilium node monitor v1.0 API at unix://%s", defaults.MonitorSockPath1_0)
@@ -118,13 +189,41 @@ func runNodeMonitor() {
 	defer server1_2.Close() // Stop accepting new v1.2 connections
 	log.Infof("Serving cilium node monitor v1.2 API at unix://%s", defaults.MonitorSockPath1_2)
 
+	server1_3 := buildServerOrExit(defaults.MonitorSockPath1_3)
+	defer server1_3.Close() // Stop accepting new v1.3 connections
+	log.Infof("Serving cilium node monitor v1.3 API at unix://%s", defaults.MonitorSockPath1_3)
+
 	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
 
-	monitorSingleton, err = NewMonitor(mainCtx, npages, pipe, server1_0, server1_2)
+	monitorSingleton, err = NewMonitor(mainCtx, npages, pipe, server1_0, server1_2, server1_3)
 	if err != nil {
 		log.WithError(err).Fatal("Error initialising monitor handlers")
 	}
 
+	grpcServer := grpc.NewServer()
+	monitorAPI.RegisterMonitorServer(grpcServer, monitorSingleton)
+	defer grpcServer.Stop() // Close all gRPC streams
+
+	serveGRPC(grpcServer, buildServerOrExit(defaults.MonitorGRPCSockPath))
+	log.Infof("Serving cilium node monitor gRPC API at unix://%s", defaults.MonitorGRPCSockPath)
+
+	if grpcAddress != "" {
+		opts, err := grpcServerOptions()
+		if err != nil {
+			log.WithError(err).WithField("address", grpcAddress).Fatal("Invalid gRPC API configuration")
+		}
+		tcpServer := grpc.NewServer(opts...)
+		monitorAPI.RegisterMonitorServer(tcpServer, readOnlyMonitor{monitorSingleton})
+		defer tcpServer.Stop() // Close all gRPC streams
+
+		server, err := net.Listen("tcp", grpcAddress)
+		if err != nil {
+			log.WithError(err).WithField("address", grpcAddress).Fatal("Cannot listen on address")
+		}
+		serveGRPC(tcpServer, server)
+		log.WithField("tls", grpcTLSCertFile != "").Infof("Serving cilium node monitor gRPC API at %s", grpcAddress)
+	}
+
 	shutdownChan := make(chan os.Signal)
 	signal.Notify(shutdownChan, syscall.SIGQUIT, syscall.SIGINT, syscall.SIGTERM, syscall.SIGINT)
 	sig := <-shutdownChan
@@ -40,6 +40,10 @@ const (
 
 	// queueSize is the size of the message queue
 	queueSize = 65536
+
+	// filterTimeout is the time in which a 1.3 listener must send its
+	// filter after connecting
+	filterTimeout = 5 * time.Second
 )
 
 // isCtxDone is a utility function that returns true when the context's Done()
@@ -69,9 +73,17 @@ type Monitor struct {
 
 	ctx              context.Context
 	perfReaderCancel context.CancelFunc
-	listeners        map[listener.MonitorListener]struct{}
+	listeners        map[listener.MonitorListener]*listener.Filter
 	nPages           int
 	monitorEvents    *bpf.PerCpuEvents
+
+	// lostPerCPU is the number of events lost in the perf ring buffer
+	// of each CPU since the monitor started, indexed by CPU
+	lostPerCPU []uint64
+
+	// queueLost is the number of payloads dropped by listeners which have
+	// disconnected because their queue was full
+	queueLost uint64
 }
 
 // agentPipeReader reads agent events from the agentPipe and distributes to all listeners
@@ -103,10 +115,10 @@ func (m *Monitor) agentPipeReader(ctx context.Context, agentPipe io.Reader) {
 // handling.
 // Note that the perf buffer reader is started only when listeners are
 // connected.
-func NewMonitor(ctx context.Context, nPages int, agentPipe io.Reader, server1_0, server1_2 net.Listener) (m *Monitor, err error) {
+func NewMonitor(ctx context.Context, nPages int, agentPipe io.Reader, server1_0, server1_2, server1_3 net.Listener) (m *Monitor, err error) {
 	m = &Monitor{
 		ctx:              ctx,
-		listeners:        make(map[listener.MonitorListener]struct{}),
+		listeners:        make(map[listener.MonitorListener]*listener.Filter),
 		nPages:           nPages,
 		perfReaderCancel: func() {}, // no-op to avoid doing null checks everywhere
 	}
@@ -114,6 +126,7 @@ func NewMonitor(ctx context.Context, nPages int, agentPipe io.Reader, server1_0,
 	// start new MonitorListener handler
 	go m.connectionHandler1_0(ctx, server1_0)
 	go m.connectionHandler1_2(ctx, server1_2)
+	go m.connectionHandler1_3(ctx, server1_3)
 
 	// start agent event pipe reader
 	go m.agentPipeReader(ctx, agentPipe)
@@ -130,22 +143,20 @@ func (m *Monitor) registerNewListener(parentCtx context.Context, conn net.Conn,
 	m.Lock()
 	defer m.Unlock()
 
-	// If this is the first listener, start the perf reader
-	if len(m.listeners) == 0 {
-		m.perfReaderCancel() // don't leak any old readers, just in case.
-		perfEventReaderCtx, cancel := context.WithCancel(parentCtx)
-		m.perfReaderCancel = cancel
-		go m.perfEventReader(perfEventReaderCtx, m.nPages)
+	if err := m.startPerfReaderIfFirst(parentCtx); err != nil {
+		conn.Close()
+		log.WithError(err).Error("Closing new connection from monitor client as the perf ring buffer cannot be opened")
+		return
 	}
 
 	switch version {
 	case listener.Version1_0:
 		newListener := newListenerv1_0(conn, queueSize, m.removeListener)
-		m.listeners[newListener] = struct{}{}
+		m.listeners[newListener] = nil
 
 	case listener.Version1_2:
 		newListener := newListenerv1_2(conn, queueSize, m.removeListener)
-		m.listeners[newListener] = struct{}{}
+		m.listeners[newListener] = nil
 
 	default:
 		conn.Close()
@@ -158,6 +169,112 @@ func (m *Monitor) registerNewListener(parentCtx context.Context, conn net.Conn,
 	}).Debug("New listener connected")
 }
 
+// newPerfEvents opens the perf ring buffer of each CPU with nPages pages
+func newPerfEvents(nPages int) (*bpf.PerCpuEvents, error) {
+	c := bpf.DefaultPerfEventConfig()
+	c.NumPages = nPages
+
+	monitorEvents, err := bpf.NewPerCpuEvents(c)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialise BPF perf ring buffer sockets: %s", err)
+	}
+	return monitorEvents, nil
+}
+
+// startPerfReaderIfFirst starts the perf reader if no listeners are
+// registered yet. m must be locked.
+func (m *Monitor) startPerfReaderIfFirst(parentCtx context.Context) error {
+	if len(m.listeners) == 0 {
+		monitorEvents, err := newPerfEvents(m.nPages)
+		if err != nil {
+			return err
+		}
+		m.startPerfReader(parentCtx, monitorEvents)
+	}
+	return nil
+}
+
+// startPerfReader stops the running perf reader, if any, and starts reading
+// monitorEvents. The perf reader closes monitorEvents when it stops. m must be
+// locked.
+func (m *Monitor) startPerfReader(parentCtx context.Context, monitorEvents *bpf.PerCpuEvents) {
+	m.perfReaderCancel() // don't leak any old readers, just in case.
+	perfEventReaderCtx, cancel := context.WithCancel(parentCtx)
+	m.perfReaderCancel = cancel
+	m.monitorEvents = monitorEvents
+	go m.perfEventReader(perfEventReaderCtx, monitorEvents)
+}
+
+// setNumPages sets the number of pages of the perf ring buffer of each CPU.
+// If the perf reader is running, the new ring buffer is opened before the
+// running one is closed, so that the running one is retained if the new one
+// cannot be opened. The previous number of pages is returned.
+func (m *Monitor) setNumPages(nPages int) (int, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	previous := m.nPages
+	if nPages == previous {
+		return previous, nil
+	}
+
+	if len(m.listeners) > 0 {
+		monitorEvents, err := newPerfEvents(nPages)
+		if err != nil {
+			return 0, err
+		}
+		m.startPerfReader(m.ctx, monitorEvents)
+	}
+
+	m.nPages = nPages
+	log.WithFields(logrus.Fields{
+		"previous": previous,
+		"pages":    nPages,
+	}).Info("Resized perf ring buffer")
+
+	return previous, nil
+}
+
+// registerListener adds an already created MonitorListener along with its
+// filter to the global list and starts the perf reader if this is the first
+// listener. A nil filter selects all events. An error is returned if the perf
+// reader cannot be started.
+func (m *Monitor) registerListener(parentCtx context.Context, ml listener.MonitorListener, filter *listener.Filter) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if err := m.startPerfReaderIfFirst(parentCtx); err != nil {
+		return err
+	}
+	m.listeners[ml] = filter
+
+	log.WithFields(logrus.Fields{
+		"count.listener": len(m.listeners),
+		"version":        ml.Version(),
+	}).Debug("New listener connected")
+
+	return nil
+}
+
+// registerNewListener1_3 reads the filter sent by a 1.3 listener and
+// registers the listener. The connection is closed if no valid filter is
+// received within filterTimeout.
+func (m *Monitor) registerNewListener1_3(parentCtx context.Context, conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(filterTimeout))
+	filter, err := listener.ReadFilter(conn)
+	if err != nil {
+		conn.Close()
+		log.WithError(err).Warn("Closing new connection from monitor client which did not send a valid filter")
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	if err := m.registerListener(parentCtx, newListenerv1_3(conn, queueSize, m.removeListener), filter); err != nil {
+		conn.Close()
+		log.WithError(err).Error("Closing new connection from monitor client as the perf ring buffer cannot be opened")
+	}
+}
+
 // removeListener deletes the MonitorListener from the list, closes its queue, and
 // stops perfReader if this is the last MonitorListener
 func (m *Monitor) removeListener(ml listener.MonitorListener) {
@@ -165,6 +282,7 @@ func (m *Monitor) removeListener(ml listener.MonitorListener) {
 	defer m.Unlock()
 
 	delete(m.listeners, ml)
+	m.queueLost += ml.QueueLost()
 	log.WithFields(logrus.Fields{
 		"count.listener": len(m.listeners),
 		"version":        ml.Version(),
@@ -180,29 +298,19 @@ func (m *Monitor) removeListener(ml listener.MonitorListener) {
 	}
 }
 
-// perfEventReader is a goroutine that reads events from the perf buffer. It
-// will exit when stopCtx is done. Note, however, that it will block in the
-// Poll call but assumes enough events are generated that these blocks are
-// short.
-func (m *Monitor) perfEventReader(stopCtx context.Context, nPages int) {
+// perfEventReader is a goroutine that reads events from monitorEvents. It
+// will exit and close monitorEvents when stopCtx is done. Note, however, that
+// it will block in the Poll call but assumes enough events are generated that
+// these blocks are short.
+func (m *Monitor) perfEventReader(stopCtx context.Context, monitorEvents *bpf.PerCpuEvents) {
 	scopedLog := log.WithField(logfields.StartTime, time.Now())
 	scopedLog.Info("Beginning to read perf buffer")
 	defer scopedLog.Info("Stopped reading perf buffer")
 
-	// configure BPF perf buffer reader
-	c := bpf.DefaultPerfEventConfig()
-	c.NumPages = nPages
-
-	monitorEvents, err := bpf.NewPerCpuEvents(c)
-	if err != nil {
-		scopedLog.WithError(err).Fatal("Cannot initialise BPF perf ring buffer sockets")
-	}
 	defer monitorEvents.CloseAll()
 
-	// update the class's monitorEvents This is only accessed by .DumpStats()
-	// also grab the callbacks we need to avoid locking again. These methods never change.
+	// grab the callbacks we need to avoid locking again. These methods never change.
 	m.Lock()
-	m.monitorEvents = monitorEvents
 	receiveEvent := m.receiveEvent
 	lostEvent := m.lostEvent
 	errorEvent := m.errorEvent
@@ -247,6 +355,23 @@ func (m *Monitor) dumpStat() {
 	l, _, u := m.monitorEvents.Stats()
 	ms := models.MonitorStatus{Cpus: c, Npages: n, Pagesize: p, Lost: int64(l), Unknown: int64(u)}
 
+	ms.LostPerCPU = make([]int64, 0, len(m.lostPerCPU))
+	for _, lost := range m.lostPerCPU {
+		ms.LostPerCPU = append(ms.LostPerCPU, int64(lost))
+	}
+
+	queueLost := m.queueLost
+	ms.Listeners = make([]*models.MonitorListenerStatus, 0, len(m.listeners))
+	for ml := range m.listeners {
+		lost := ml.QueueLost()
+		queueLost += lost
+		ms.Listeners = append(ms.Listeners, &models.MonitorListenerStatus{
+			Version:   string(ml.Version()),
+			QueueLost: int64(lost),
+		})
+	}
+	ms.ListenerQueueLost = int64(queueLost)
+
 	mp, err := json.Marshal(ms)
 	if err != nil {
 		log.WithError(err).Error("Error marshalling JSON")
@@ -301,11 +426,46 @@ func (m *Monitor) connectionHandler1_2(parentCtx context.Context, server net.Lis
 	}
 }
 
-// send enqueues the payload to all listeners.
+// connectionHandler1_3 handles all the incoming connections and sets up the
+// listener objects once the listener has sent its filter. It will block on
+// Accept, but expects the caller to close server, inducing a return.
+func (m *Monitor) connectionHandler1_3(parentCtx context.Context, server net.Listener) {
+	for !isCtxDone(parentCtx) {
+		conn, err := server.Accept()
+		switch {
+		case isCtxDone(parentCtx) && conn != nil:
+			conn.Close()
+			fallthrough
+
+		case isCtxDone(parentCtx) && conn == nil:
+			return
+
+		case err != nil:
+			log.WithError(err).Warn("Error accepting connection")
+			continue
+		}
+
+		go m.registerNewListener1_3(parentCtx, conn)
+	}
+}
+
+// send enqueues the payload to all listeners whose filter selects the
+// payload. The payload is only parsed if at least one listener has
+// registered a filter.
 func (m *Monitor) send(pl *payload.Payload) {
 	m.Lock()
 	defer m.Unlock()
-	for ml := range m.listeners {
+
+	var info *listener.EventInfo
+	for ml, filter := range m.listeners {
+		if !filter.IsEmpty() {
+			if info == nil {
+				info = listener.NewEventInfo(pl)
+			}
+			if !filter.Match(info) {
+				continue
+			}
+		}
 		ml.Enqueue(pl)
 	}
 }
@@ -316,6 +476,13 @@ func (m *Monitor) receiveEvent(es *bpf.PerfEventSample, c int) {
 }
 
 func (m *Monitor) lostEvent(el *bpf.PerfEventLost, c int) {
+	m.Lock()
+	for len(m.lostPerCPU) <= c {
+		m.lostPerCPU = append(m.lostPerCPU, 0)
+	}
+	m.lostPerCPU[c] += el.Lost
+	m.Unlock()
+
 	pl := payload.Payload{Data: []byte{}, CPU: c, Lost: el.Lost, Type: payload.RecordLost}
 	m.send(&pl)
 }
@@ -0,0 +1,75 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+)
+
+// queueLoss accounts for the payloads a listener dropped because its queue
+// was full. It is embedded into all listeners.
+type queueLoss struct {
+	mutex lock.Mutex
+
+	// notify is true if the listener understands ListenerQueueLost
+	// payloads. Listeners of older API versions only account for the
+	// dropped payloads.
+	notify bool
+
+	// pending is the number of dropped payloads which have not been
+	// reported to the listener yet
+	pending uint64
+
+	// total is the number of payloads dropped since the listener connected
+	total uint64
+}
+
+// enqueue enqueues pl using tryEnqueue, which must return false instead of
+// blocking if the queue is full. If payloads have been dropped since the
+// last call, a ListenerQueueLost payload reporting them is enqueued ahead of
+// pl so that the listener can show the gap.
+func (q *queueLoss) enqueue(pl *payload.Payload, tryEnqueue func(*payload.Payload) bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.notify && q.pending > 0 {
+		lost := &payload.Payload{Data: []byte{}, Lost: q.pending, Type: payload.ListenerQueueLost}
+		if !tryEnqueue(lost) {
+			q.drop()
+			return
+		}
+		q.pending = 0
+	}
+
+	if !tryEnqueue(pl) {
+		q.drop()
+	}
+}
+
+// drop accounts for a dropped payload. q.mutex must be held.
+func (q *queueLoss) drop() {
+	q.pending++
+	q.total++
+	log.Debug("Per listener queue is full, dropping message")
+}
+
+// QueueLost returns the number of payloads dropped since the listener
+// connected
+func (q *queueLoss) QueueLost() uint64 {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.total
+}
@@ -16,6 +16,7 @@ package backoff
 
 import (
 	"math"
+	"math/rand"
 	"time"
 
 	"github.com/cilium/cilium/pkg/logging"
@@ -46,17 +47,17 @@ type Exponential struct {
 	// for logging purposes.
 	Name string
 
+	// Jitter, if true, randomizes the backoff time between 1/2 and the
+	// full calculated backoff time. This avoids many users subject to
+	// the same backoff configuration, such as all agents of a cluster
+	// competing for the same kvstore lock, to retry in lockstep.
+	Jitter bool
+
 	attempt int
 }
 
-// Wait waits for the required time using an exponential backoff
-func (b *Exponential) Wait() {
-	b.attempt++
-
-	if b.Name == "" {
-		b.Name = uuid.NewUUID().String()
-	}
-
+// Duration returns the backoff time for the given attempt
+func (b *Exponential) Duration(attempt int) time.Duration {
 	min := time.Duration(1) * time.Second
 	if b.Min != time.Duration(0) {
 		min = b.Min
@@ -67,12 +68,29 @@ func (b *Exponential) Wait() {
 		factor = b.Factor
 	}
 
-	t := time.Duration(float64(min) * math.Pow(factor, float64(b.attempt)))
+	t := time.Duration(float64(min) * math.Pow(factor, float64(attempt)))
 
 	if b.Max != time.Duration(0) && t > b.Max {
 		t = b.Max
 	}
 
+	if b.Jitter {
+		t = t/2 + time.Duration(rand.Int63n(int64(t/2)+1))
+	}
+
+	return t
+}
+
+// Wait waits for the required time using an exponential backoff
+func (b *Exponential) Wait() {
+	b.attempt++
+
+	if b.Name == "" {
+		b.Name = uuid.NewUUID().String()
+	}
+
+	t := b.Duration(b.attempt)
+
 	log.WithFields(logrus.Fields{
 		"time":    t,
 		"attempt": b.attempt,
@@ -0,0 +1,52 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type BackoffSuite struct{}
+
+var _ = Suite(&BackoffSuite{})
+
+func (b *BackoffSuite) TestDuration(c *C) {
+	e := Exponential{Min: time.Millisecond, Factor: 2.0}
+	c.Assert(e.Duration(0), Equals, time.Millisecond)
+	c.Assert(e.Duration(1), Equals, 2*time.Millisecond)
+	c.Assert(e.Duration(4), Equals, 16*time.Millisecond)
+
+	e.Max = 10 * time.Millisecond
+	c.Assert(e.Duration(4), Equals, 10*time.Millisecond)
+}
+
+func (b *BackoffSuite) TestJitter(c *C) {
+	e := Exponential{Min: time.Second, Max: 8 * time.Second, Jitter: true}
+	for attempt := 0; attempt < 10; attempt++ {
+		full := Exponential{Min: e.Min, Max: e.Max}
+		t := e.Duration(attempt)
+		c.Assert(t >= full.Duration(attempt)/2, Equals, true)
+		c.Assert(t <= full.Duration(attempt), Equals, true)
+	}
+}
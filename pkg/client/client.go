@@ -130,6 +130,16 @@ func timeSince(since time.Time) string {
 	return out
 }
 
+// timeUntil returns the time remaining until the deadline in seconds
+func timeUntil(deadline time.Time) string {
+	t := deadline.Sub(time.Now())
+	if t < 0 {
+		t = 0
+	}
+	t -= t % time.Second
+	return t.String()
+}
+
 func stateUnhealthy(state string) bool {
 	return state == models.StatusStateWarning ||
 		state == models.StatusStateFailure
@@ -211,6 +221,19 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allAddresses,
 		if nm.Lost != 0 || nm.Unknown != 0 {
 			fmt.Fprintf(w, "\t%d events lost, %d unknown notifications\n", nm.Lost, nm.Unknown)
 		}
+		for cpu, lost := range nm.LostPerCPU {
+			if lost != 0 {
+				fmt.Fprintf(w, "\tCPU %02d: %d events lost in perf ring buffer\n", cpu, lost)
+			}
+		}
+		if nm.ListenerQueueLost != 0 {
+			fmt.Fprintf(w, "\t%d events dropped due to full listener queues\n", nm.ListenerQueueLost)
+		}
+		for _, l := range nm.Listeners {
+			if l.QueueLost != 0 {
+				fmt.Fprintf(w, "\tListener (API %s): %d events dropped due to full queue\n", l.Version, l.QueueLost)
+			}
+		}
 	} else {
 		fmt.Fprintf(w, "NodeMonitor:\tDisabled\n")
 	}
@@ -294,8 +317,20 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allAddresses,
 	}
 
 	if sr.Proxy != nil {
-		fmt.Fprintf(w, "Proxy Status:\tOK, ip %s, port-range %s\n",
-			sr.Proxy.IP, sr.Proxy.PortRange)
+		fmt.Fprintf(w, "Proxy Status:\tOK, ip %s, port-range %s, %d redirects (%d draining)\n",
+			sr.Proxy.IP, sr.Proxy.PortRange, sr.Proxy.TotalRedirects, sr.Proxy.TotalDrainingRedirects)
+		if allRedirects && len(sr.Proxy.Redirects) > 0 {
+			tab := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+			fmt.Fprint(tab, "  Protocol\tRedirect\tProxy Port\tState\n")
+			for _, r := range sr.Proxy.Redirects {
+				state := r.State
+				if r.State == models.ProxyRedirectStateDraining {
+					state = fmt.Sprintf("%s, closed in %s", r.State, timeUntil(time.Time(r.DrainDeadline)))
+				}
+				fmt.Fprintf(tab, "  %s\t%s\t%d\t%s\n", r.Proxy, r.Name, r.ProxyPort, state)
+			}
+			tab.Flush()
+		}
 	} else {
 		fmt.Fprintf(w, "Proxy Status:\tNo managed proxy redirect\n")
 	}
@@ -47,3 +47,22 @@ func (c *Client) ConfigPatch(cfg models.DaemonConfigurationSpec) error {
 	_, err = c.Daemon.PatchConfig(params)
 	return Hint(err)
 }
+
+// ConfigLabelsGet returns the identity label filter of the daemon.
+func (c *Client) ConfigLabelsGet() (*models.IdentityLabelFilter, error) {
+	resp, err := c.Daemon.GetConfigLabels(nil)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// ConfigLabelsPut replaces the identity label filter of the daemon.
+func (c *Client) ConfigLabelsPut(filter *models.IdentityLabelFilter) (*models.IdentityLabelFilterChange, error) {
+	params := daemon.NewPutConfigLabelsParams().WithFilter(filter).WithTimeout(api.ClientTimeout)
+	resp, err := c.Daemon.PutConfigLabels(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
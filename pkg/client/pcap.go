@@ -0,0 +1,29 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"io"
+
+	"github.com/cilium/cilium/api/v1/client/daemon"
+	"github.com/cilium/cilium/pkg/api"
+)
+
+// PcapGet writes the recent packet samples of the node in pcapng format to w
+func (c *Client) PcapGet(w io.Writer) error {
+	params := daemon.NewGetPcapParams().WithTimeout(api.ClientTimeout)
+	_, err := c.Daemon.GetPcap(params, w)
+	return Hint(err)
+}
@@ -59,3 +59,13 @@ func (c *Client) PolicyResolveGet(traceSelector *models.TraceSelector) (*models.
 	}
 	return resp.Payload, nil
 }
+
+// PolicyExplainGet explains the policy decision for a connection to an endpoint
+func (c *Client) PolicyExplainGet(sel *models.PolicyExplainSelector) (*models.PolicyExplanation, error) {
+	params := policy.NewGetPolicyExplainParams().WithExplainSelector(sel).WithTimeout(api.ClientTimeout)
+	resp, err := c.Policy.GetPolicyExplain(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
@@ -29,7 +29,12 @@ var outputOpt string
 
 // OutputJSON returns true if the JSON output option was specified
 func OutputJSON() bool {
-	return len(outputOpt) > 0
+	return len(outputOpt) > 0 && !OutputWide()
+}
+
+// OutputWide returns true if the wide output option was specified
+func OutputWide() bool {
+	return outputOpt == "wide"
 }
 
 //AddJSONOutput adds the -o|--output option to any cmd to export to json
@@ -37,6 +42,12 @@ func AddJSONOutput(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&outputOpt, "output", "o", "", "json| jsonpath='{}'")
 }
 
+// AddJSONOrWideOutput adds the -o|--output option to any cmd to export to
+// json or to print additional columns
+func AddJSONOrWideOutput(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&outputOpt, "output", "o", "", "json| jsonpath='{}'| wide")
+}
+
 //PrintOutput receives an interface and dump the data using the --output flag.
 //ATM only json or jsonpath. In the future yaml
 func PrintOutput(data interface{}) error {
@@ -0,0 +1,126 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/lock"
+)
+
+// Reconciler maintains a set of desired routes. Routes are installed when
+// they are added to the reconciler. Once started, a controller periodically
+// compares the desired routes with the routing table of the kernel and
+// re-installs all routes which have been removed or modified.
+type Reconciler struct {
+	name        string
+	mutex       lock.Mutex
+	routes      map[string]Route
+	controllers *controller.Manager
+}
+
+// NewReconciler returns a new route reconciler. The name is used as the name
+// of the controller performing the reconciliation.
+func NewReconciler(name string) *Reconciler {
+	return &Reconciler{
+		name:        name,
+		routes:      map[string]Route{},
+		controllers: controller.NewManager(),
+	}
+}
+
+// routeKey returns the key identifying a route in the set of desired routes.
+// A device can only have a single route for a particular prefix.
+func routeKey(route Route) string {
+	return route.Device + "/" + route.Prefix.String()
+}
+
+// Upsert adds the route to the set of desired routes, replacing any previous
+// route for the same prefix and device, and installs it.
+func (r *Reconciler) Upsert(route Route) error {
+	r.mutex.Lock()
+	r.routes[routeKey(route)] = route
+	r.mutex.Unlock()
+
+	return ReplaceRoute(route)
+}
+
+// Delete removes the route from the set of desired routes and from the
+// routing table
+func (r *Reconciler) Delete(route Route) error {
+	r.mutex.Lock()
+	delete(r.routes, routeKey(route))
+	r.mutex.Unlock()
+
+	return DeleteRoute(route)
+}
+
+// Routes returns a copy of all desired routes
+func (r *Reconciler) Routes() []Route {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	routes := make([]Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+// Reconcile compares all desired routes with the routing table and
+// re-installs all routes which are missing or have been modified. It returns
+// the number of repaired routes.
+func (r *Reconciler) Reconcile() (int, error) {
+	repaired, failed := 0, 0
+
+	for _, route := range r.Routes() {
+		replaced, err := replaceRoute(route)
+		if err != nil {
+			route.getLogger().WithError(err).Warning("Unable to repair route")
+			failed++
+		} else if replaced {
+			route.getLogger().Warning("Repaired route which was missing or modified")
+			repaired++
+		}
+	}
+
+	if failed > 0 {
+		return repaired, fmt.Errorf("unable to repair %d routes", failed)
+	}
+
+	return repaired, nil
+}
+
+// Start starts the controller which reconciles the desired routes in the
+// given interval
+func (r *Reconciler) Start(interval time.Duration) {
+	r.controllers.UpdateController(r.name,
+		controller.ControllerParams{
+			DoFunc: func() error {
+				_, err := r.Reconcile()
+				return err
+			},
+			RunInterval: interval,
+		})
+}
+
+// Stop stops the reconciliation controller. The desired routes remain
+// installed.
+func (r *Reconciler) Stop() {
+	r.controllers.RemoveAll()
+}
@@ -0,0 +1,119 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build privileged_tests
+
+package route
+
+import (
+	"net"
+	"runtime"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// withTestNetNS runs f in a new network namespace containing the veth pair
+// "veth0" and "veth1". Both devices are up and veth0 is configured with the
+// given addresses.
+func withTestNetNS(c *C, addrs []string, f func(link netlink.Link)) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	origNS, err := netns.Get()
+	c.Assert(err, IsNil)
+	defer origNS.Close()
+
+	testNS, err := netns.New()
+	c.Assert(err, IsNil)
+	defer testNS.Close()
+	defer netns.Set(origNS)
+
+	err = netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: "veth0"},
+		PeerName:  "veth1",
+	})
+	c.Assert(err, IsNil)
+
+	peer, err := netlink.LinkByName("veth1")
+	c.Assert(err, IsNil)
+	c.Assert(netlink.LinkSetUp(peer), IsNil)
+
+	link, err := netlink.LinkByName("veth0")
+	c.Assert(err, IsNil)
+	c.Assert(netlink.LinkSetUp(link), IsNil)
+
+	for _, a := range addrs {
+		addr, err := netlink.ParseAddr(a)
+		c.Assert(err, IsNil)
+		c.Assert(netlink.AddrAdd(link, addr), IsNil)
+	}
+
+	f(link)
+}
+
+func testReconcile(c *C, link netlink.Link, prefixStr, nexthopStr string) {
+	_, prefix, err := net.ParseCIDR(prefixStr)
+	c.Assert(err, IsNil)
+
+	rt := Route{
+		Device:  link.Attrs().Name,
+		Prefix:  *prefix,
+		Nexthop: parseIP(nexthopStr),
+	}
+
+	r := NewReconciler("test-route-reconciler")
+	c.Assert(r.Upsert(rt), IsNil)
+	c.Assert(r.Routes(), HasLen, 1)
+
+	spec := rt.getNetlinkRoute()
+	spec.LinkIndex = link.Attrs().Index
+	c.Assert(lookup(link, &spec), Not(IsNil))
+
+	// Routes in place do not require any repair
+	repaired, err := r.Reconcile()
+	c.Assert(err, IsNil)
+	c.Assert(repaired, Equals, 0)
+
+	// Removal of the route behind the back of the reconciler is repaired
+	c.Assert(deleteRoute(rt), IsNil)
+	c.Assert(lookup(link, &spec), IsNil)
+
+	repaired, err = r.Reconcile()
+	c.Assert(err, IsNil)
+	c.Assert(repaired, Equals, 1)
+	c.Assert(lookup(link, &spec), Not(IsNil))
+
+	// Deleted routes are no longer reconciled
+	c.Assert(r.Delete(rt), IsNil)
+	c.Assert(r.Routes(), HasLen, 0)
+	c.Assert(lookup(link, &spec), IsNil)
+
+	repaired, err = r.Reconcile()
+	c.Assert(err, IsNil)
+	c.Assert(repaired, Equals, 0)
+	c.Assert(lookup(link, &spec), IsNil)
+
+	c.Assert(DeleteNexthopRoute(rt), IsNil)
+	c.Assert(lookup(link, createNexthopRoute(link, rt.getNexthopAsIPNet())), IsNil)
+}
+
+func (p *RouteSuitePrivileged) TestReconcile(c *C) {
+	withTestNetNS(c, []string{"192.168.10.1/24", "fd00:10::1/64"}, func(link netlink.Link) {
+		testReconcile(c, link, "10.20.0.0/16", "192.168.10.2")
+		testReconcile(c, link, "f00d::a02:200:0:0/96", "fd00:10::2")
+	})
+}
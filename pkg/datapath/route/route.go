@@ -283,3 +283,25 @@ func DeleteRoute(route Route) error {
 
 	return nil
 }
+
+// DeleteNexthopRoute removes the L2 route for the nexthop of a route as
+// installed by ReplaceRoute. It may only be called if no other route on the
+// device uses the same nexthop.
+func DeleteNexthopRoute(route Route) error {
+	routerNet := route.getNexthopAsIPNet()
+	if routerNet == nil {
+		return nil
+	}
+
+	link, err := netlink.LinkByName(route.Device)
+	if err != nil {
+		return fmt.Errorf("unable to lookup interface %s: %s", route.Device, err)
+	}
+
+	if err := deleteNexthopRoute(link, routerNet); err != nil {
+		route.getLogger().WithError(err).Error("Unable to delete L2 nexthop route")
+		return err
+	}
+
+	return nil
+}
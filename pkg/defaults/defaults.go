@@ -59,6 +59,16 @@ const (
 	// This is the 1.2 protocol version.
 	MonitorSockPath1_2 = RuntimePath + "/monitor1_2.sock"
 
+	// MonitorSockPath1_3 is the path to the UNIX domain socket used to
+	// distribute BPF and agent events to listeners which register a
+	// filter.
+	// This is the 1.3 protocol version.
+	MonitorSockPath1_3 = RuntimePath + "/monitor1_3.sock"
+
+	// MonitorGRPCSockPath is the path to the UNIX domain socket on which
+	// the node monitor serves its gRPC API.
+	MonitorGRPCSockPath = RuntimePath + "/monitor-grpc.sock"
+
 	// PidFilePath is the path to the pid file for the agent.
 	PidFilePath = RuntimePath + "/cilium.pid"
 
@@ -94,4 +104,36 @@ const (
 	// already been allocated and other nodes in the cluster have a chance
 	// to whitelist the new upcoming identity of the endpoint.
 	IdentityChangeGracePeriod = 25 * time.Second
+
+	// IdentityAllocationConcurrency is the default maximum number of
+	// identity allocations performed against the kvstore in parallel
+	IdentityAllocationConcurrency = 16
+
+	// IdentityAllocationBackoffMax is the default maximum time to back off
+	// between failed identity allocation attempts
+	IdentityAllocationBackoffMax = 30 * time.Second
+
+	// FlowBufferSize is the default number of flows retained in the flow
+	// buffer of the agent, the flow buffer is disabled by default
+	FlowBufferSize = 0
+
+	// FlowMetricsWorkloadLabel is the default identity label reported as
+	// workload by the flow metrics
+	FlowMetricsWorkloadLabel = "k8s:app"
+
+	// PcapBufferSize is the default number of packet samples retained in
+	// the packet sample buffer of the agent
+	PcapBufferSize = 1024
+
+	// FlowLogMaxSize is the default size in megabytes at which the flow
+	// log is rotated
+	FlowLogMaxSize = 100
+
+	// FlowLogMaxBackups is the default number of rotated flow logs to
+	// retain
+	FlowLogMaxBackups = 3
+
+	// MonitorMaxNumPages is the maximum number of pages of the perf ring
+	// buffer of each CPU of the node monitor
+	MonitorMaxNumPages = 1024
 )
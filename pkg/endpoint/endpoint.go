@@ -2254,7 +2254,7 @@ func (e *Endpoint) UpdateProxyStatistics(l7Protocol string, port uint16, ingress
 	case accesslog.VerdictForwarded:
 		stats.Forwarded++
 		metrics.ProxyForwarded.Inc()
-	case accesslog.VerdictDenied:
+	case accesslog.VerdictDenied, accesslog.VerdictRateLimited:
 		stats.Denied++
 		metrics.ProxyDenied.Inc()
 	case accesslog.VerdictError:
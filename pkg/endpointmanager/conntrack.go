@@ -91,6 +91,20 @@ func createGCFilter(initialScan bool, restoredEndpoints []*endpoint.Endpoint) *c
 	return filter
 }
 
+// FlushCTEntriesOfProxyPort removes the entries of the connections which
+// were redirected to the given proxy port from the global CT maps and from the
+// local CT maps of all endpoints.
+func FlushCTEntriesOfProxyPort(ipv4, ipv6 bool, proxyPort uint16) {
+	filter := &ctmap.GCFilter{ProxyPort: proxyPort}
+
+	runGC(nil, ipv4, ipv6, filter)
+	for _, e := range GetEndpoints() {
+		if e.ConntrackLocal() {
+			runGC(e, ipv4, ipv6, filter)
+		}
+	}
+}
+
 // EnableConntrackGC enables the connection tracking garbage collection.
 func EnableConntrackGC(ipv4, ipv6 bool, gcinterval int, restoredEndpoints []*endpoint.Endpoint) {
 	initialScan := true
@@ -17,6 +17,7 @@ package envoy
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,10 +26,12 @@ import (
 
 	"github.com/cilium/cilium/pkg/envoy/cilium"
 	"github.com/cilium/cilium/pkg/flowdebug"
+	"github.com/cilium/cilium/pkg/kafka"
 	"github.com/cilium/cilium/pkg/proxy/accesslog"
 	"github.com/cilium/cilium/pkg/proxy/logger"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/sirupsen/logrus"
 )
 
@@ -106,7 +109,7 @@ func (s *accessLogServer) accessLogger(conn *net.UnixConn) {
 			log.Warning("Envoy: Discarded truncated access log message")
 			continue
 		}
-		pblog := cilium.LogEntry{} // TODO: Support Kafka.
+		pblog := cilium.LogEntry{}
 		err = proto.Unmarshal(buf[:n], &pblog)
 		if err != nil {
 			log.WithError(err).Warning("Envoy: Discarded invalid access log message")
@@ -128,17 +131,57 @@ func (s *accessLogServer) accessLogger(conn *net.UnixConn) {
 	}
 }
 
+func (s *accessLogServer) newLogRecord(localEndpoint logger.EndpointUpdater, pblog *cilium.LogEntry, l7tags logger.LogTag) *logger.LogRecord {
+	return logger.NewLogRecord(s.endpointInfoRegistry, localEndpoint, pblog.GetFlowType(), pblog.IsIngress,
+		logger.LogTags.Timestamp(time.Unix(int64(pblog.Timestamp/1000000000), int64(pblog.Timestamp%1000000000))),
+		logger.LogTags.Verdict(pblog.GetVerdict(), pblog.CiliumRuleRef),
+		logger.LogTags.Addressing(logger.AddressingInfo{
+			SrcIPPort:   pblog.SourceAddress,
+			DstIPPort:   pblog.DestinationAddress,
+			SrcIdentity: pblog.SourceSecurityId,
+		}), l7tags)
+}
+
+// logKafkaRecord logs a Kafka request or response with a record for each
+// of the topics of the request, as the Kafka proxy of the agent does
+func (s *accessLogServer) logKafkaRecord(localEndpoint logger.EndpointUpdater, pblog *cilium.LogEntry, k *cilium.KafkaLogEntry) {
+	r := s.newLogRecord(localEndpoint, pblog, logger.LogTags.Kafka(&accesslog.LogRecordKafka{
+		ErrorCode:     int(k.ErrorCode),
+		APIVersion:    int16(k.ApiVersion),
+		APIKey:        kafka.APIKeyToString(int16(k.ApiKey)),
+		CorrelationID: k.CorrelationId,
+	}))
+
+	for _, topic := range k.Topics {
+		r.Kafka.Topic.Topic = topic
+		r.Log()
+	}
+
+	// Count each request only once
+	ingress := r.ObservationPoint == accesslog.Ingress
+	request := r.Type == accesslog.TypeRequest
+	localEndpoint.UpdateProxyStatistics("kafka", r.DestinationEndpoint.Port, ingress, request, r.Verdict)
+}
+
 func (s *accessLogServer) logRecord(localEndpoint logger.EndpointUpdater, pblog *cilium.LogEntry) {
-	// TODO: Support Kafka.
+	if k := pblog.GetKafka(); k != nil {
+		s.logKafkaRecord(localEndpoint, pblog, k)
+		return
+	}
 
 	var l7tags logger.LogTag
 	if http := pblog.GetHttp(); http != nil {
+		url := http.ParseURL()
+		headers := http.GetNetHttpHeaders()
 		l7tags = logger.LogTags.HTTP(&accesslog.LogRecordHTTP{
-			Method:   http.Method,
-			Code:     int(http.Status),
-			URL:      http.ParseURL(),
-			Protocol: http.GetProtocol(),
-			Headers:  http.GetNetHttpHeaders(),
+			Method:          http.Method,
+			Code:            int(http.Status),
+			URL:             url,
+			Protocol:        http.GetProtocol(),
+			Headers:         headers,
+			MissingHeaders:  http.GetNetHttpMissingHeaders(),
+			RejectedHeaders: http.GetNetHttpRejectedHeaders(),
+			GRPC:            getGRPCLogRecord(url.Path, headers, http.GetGrpcStatus()),
 		})
 	} else if l7 := pblog.GetGenericL7(); l7 != nil {
 		l7tags = logger.LogTags.L7(&accesslog.LogRecordL7{
@@ -156,15 +199,7 @@ func (s *accessLogServer) logRecord(localEndpoint logger.EndpointUpdater, pblog
 		})
 	}
 
-	r := logger.NewLogRecord(s.endpointInfoRegistry, localEndpoint, pblog.GetFlowType(), pblog.IsIngress,
-		logger.LogTags.Timestamp(time.Unix(int64(pblog.Timestamp/1000000000), int64(pblog.Timestamp%1000000000))),
-		logger.LogTags.Verdict(pblog.GetVerdict(), pblog.CiliumRuleRef),
-		logger.LogTags.Addressing(logger.AddressingInfo{
-			SrcIPPort:   pblog.SourceAddress,
-			DstIPPort:   pblog.DestinationAddress,
-			SrcIdentity: pblog.SourceSecurityId,
-		}), l7tags)
-
+	r := s.newLogRecord(localEndpoint, pblog, l7tags)
 	r.Log()
 
 	// Update stats for the endpoint.
@@ -172,3 +207,24 @@ func (s *accessLogServer) logRecord(localEndpoint logger.EndpointUpdater, pblog
 	request := r.Type == accesslog.TypeRequest
 	localEndpoint.UpdateProxyStatistics("http", r.DestinationEndpoint.Port, ingress, request, r.Verdict)
 }
+
+// getGRPCLogRecord returns the gRPC portion of an HTTP log record, or nil if
+// the request is not a gRPC call. The status is left unset if Envoy did not
+// find a "grpc-status" in the response.
+func getGRPCLogRecord(path string, headers http.Header, status *wrappers.UInt32Value) *accesslog.LogRecordGRPC {
+	if !strings.HasPrefix(headers.Get("Content-Type"), grpcContentTypePrefix) {
+		return nil
+	}
+
+	record := &accesslog.LogRecordGRPC{}
+	if status != nil {
+		code := int(status.GetValue())
+		record.Status = &code
+	}
+	// The path of a gRPC call is "/<service>/<method>"
+	if parts := strings.Split(path, "/"); len(parts) == 3 && parts[0] == "" {
+		record.Service = parts[1]
+		record.Method = parts[2]
+	}
+	return record
+}
@@ -17,8 +17,12 @@
 package envoy
 
 import (
+	"net/http"
+
 	"github.com/cilium/cilium/pkg/envoy/cilium"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
 
+	"github.com/golang/protobuf/ptypes/wrappers"
 	. "gopkg.in/check.v1"
 )
 
@@ -40,3 +44,28 @@ func (k *AccessLogServerSuite) TestParseURL(c *C) {
 		c.Assert(u.Path, Equals, "/foo")
 	}
 }
+
+func (k *AccessLogServerSuite) TestGetGRPCLogRecord(c *C) {
+	grpcHeaders := http.Header{}
+	grpcHeaders.Set("Content-Type", "application/grpc+proto")
+
+	c.Assert(getGRPCLogRecord("/foo", http.Header{}, nil), IsNil)
+
+	r := getGRPCLogRecord("/helloworld.Greeter/SayHello", grpcHeaders, &wrappers.UInt32Value{Value: 0})
+	ok := 0
+	c.Assert(r, DeepEquals, &accesslog.LogRecordGRPC{
+		Service: "helloworld.Greeter",
+		Method:  "SayHello",
+		Status:  &ok,
+	})
+
+	// A failed call is answered with HTTP 200 and carries the error in
+	// the "grpc-status" trailer
+	r = getGRPCLogRecord("/helloworld.Greeter/SayHello", grpcHeaders, &wrappers.UInt32Value{Value: 7})
+	c.Assert(r.Status, NotNil)
+	c.Assert(*r.Status, Equals, 7)
+
+	// The status is unknown if the response carries no "grpc-status"
+	r = getGRPCLogRecord("/invalid", grpcHeaders, nil)
+	c.Assert(r, DeepEquals, &accesslog.LogRecordGRPC{})
+}
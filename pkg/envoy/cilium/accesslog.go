@@ -79,6 +79,22 @@ func (m *HttpLogEntry) GetNetHttpHeaders() http.Header {
 	return getNetHttpHeaders(m.Headers)
 }
 
+// GetNetHttpMissingHeaders returns the MissingHeaders as net.http.Header
+func (m *HttpLogEntry) GetNetHttpMissingHeaders() http.Header {
+	if m == nil {
+		return make(http.Header)
+	}
+	return getNetHttpHeaders(m.MissingHeaders)
+}
+
+// GetNetHttpRejectedHeaders returns the RejectedHeaders as net.http.Header
+func (m *HttpLogEntry) GetNetHttpRejectedHeaders() http.Header {
+	if m == nil {
+		return make(http.Header)
+	}
+	return getNetHttpHeaders(m.RejectedHeaders)
+}
+
 // Deprecated
 func (m *LogEntry) GetNetHttpHeaders() http.Header {
 	if m == nil {
@@ -124,7 +140,7 @@ func (m *LogEntry) GetFlowType() accesslog.FlowType {
 
 	if m != nil {
 		switch m.EntryType {
-		case EntryType_Denied:
+		case EntryType_Denied, EntryType_RateLimited:
 			result = accesslog.TypeRequest
 		case EntryType_Request:
 			result = accesslog.TypeRequest
@@ -136,7 +152,8 @@ func (m *LogEntry) GetFlowType() accesslog.FlowType {
 	return result
 }
 
-// GetVerdict returns the verdict performed on the flow (forwarded|denied)
+// GetVerdict returns the verdict performed on the flow
+// (forwarded|denied|rate limited)
 func (m *LogEntry) GetVerdict() accesslog.FlowVerdict {
 	// the default verdict is forwarded
 	result := accesslog.VerdictForwarded
@@ -145,6 +162,8 @@ func (m *LogEntry) GetVerdict() accesslog.FlowVerdict {
 		switch m.EntryType {
 		case EntryType_Denied:
 			result = accesslog.VerdictDenied
+		case EntryType_RateLimited:
+			result = accesslog.VerdictRateLimited
 		}
 	}
 
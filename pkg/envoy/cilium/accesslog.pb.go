@@ -6,6 +6,7 @@ package cilium
 import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
 	math "math"
 )
 
@@ -51,21 +52,24 @@ func (HttpProtocol) EnumDescriptor() ([]byte, []int) {
 type EntryType int32
 
 const (
-	EntryType_Request  EntryType = 0
-	EntryType_Response EntryType = 1
-	EntryType_Denied   EntryType = 2
+	EntryType_Request     EntryType = 0
+	EntryType_Response    EntryType = 1
+	EntryType_Denied      EntryType = 2
+	EntryType_RateLimited EntryType = 3
 )
 
 var EntryType_name = map[int32]string{
 	0: "Request",
 	1: "Response",
 	2: "Denied",
+	3: "RateLimited",
 }
 
 var EntryType_value = map[string]int32{
-	"Request":  0,
-	"Response": 1,
-	"Denied":   2,
+	"Request":     0,
+	"Response":    1,
+	"Denied":      2,
+	"RateLimited": 3,
 }
 
 func (x EntryType) String() string {
@@ -130,13 +134,25 @@ type HttpLogEntry struct {
 	Host   string `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
 	Path   string `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
 	Method string `protobuf:"bytes,5,opt,name=method,proto3" json:"method,omitempty"`
-	// Request headers not included above
+	// Request headers not included above. The values of headers matched
+	// against a secret by the policy are redacted.
 	Headers []*KeyValue `protobuf:"bytes,6,rep,name=headers,proto3" json:"headers,omitempty"`
 	// Response info
-	Status               uint32   `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Status uint32 `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
+	// Names of the request headers that did not match a policy header match
+	// with a non-failing mismatch action. The values are not logged, as they
+	// may be secret.
+	MissingHeaders []*KeyValue `protobuf:"bytes,8,rep,name=missing_headers,json=missingHeaders,proto3" json:"missing_headers,omitempty"`
+	// Request headers removed or replaced due to a policy header match. The
+	// values of headers matched against a secret are redacted.
+	RejectedHeaders []*KeyValue `protobuf:"bytes,9,rep,name=rejected_headers,json=rejectedHeaders,proto3" json:"rejected_headers,omitempty"`
+	// gRPC status of the response, taken from the "grpc-status" trailer or
+	// the headers of a trailers-only response. Unset if the response is not
+	// a gRPC response or carries no status.
+	GrpcStatus           *wrappers.UInt32Value `protobuf:"bytes,10,opt,name=grpc_status,json=grpcStatus,proto3" json:"grpc_status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *HttpLogEntry) Reset()         { *m = HttpLogEntry{} }
@@ -213,6 +229,100 @@ func (m *HttpLogEntry) GetStatus() uint32 {
 	return 0
 }
 
+func (m *HttpLogEntry) GetMissingHeaders() []*KeyValue {
+	if m != nil {
+		return m.MissingHeaders
+	}
+	return nil
+}
+
+func (m *HttpLogEntry) GetRejectedHeaders() []*KeyValue {
+	if m != nil {
+		return m.RejectedHeaders
+	}
+	return nil
+}
+
+func (m *HttpLogEntry) GetGrpcStatus() *wrappers.UInt32Value {
+	if m != nil {
+		return m.GrpcStatus
+	}
+	return nil
+}
+
+type KafkaLogEntry struct {
+	// Kafka error code, zero if the request was not denied
+	ErrorCode     int32 `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ApiVersion    int32 `protobuf:"varint,2,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	ApiKey        int32 `protobuf:"varint,3,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	CorrelationId int32 `protobuf:"varint,4,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	// Topics of the request, if any
+	Topics               []string `protobuf:"bytes,5,rep,name=topics,proto3" json:"topics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KafkaLogEntry) Reset()         { *m = KafkaLogEntry{} }
+func (m *KafkaLogEntry) String() string { return proto.CompactTextString(m) }
+func (*KafkaLogEntry) ProtoMessage()    {}
+func (*KafkaLogEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f29d2fd7c3943de2, []int{2}
+}
+
+func (m *KafkaLogEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KafkaLogEntry.Unmarshal(m, b)
+}
+func (m *KafkaLogEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KafkaLogEntry.Marshal(b, m, deterministic)
+}
+func (m *KafkaLogEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KafkaLogEntry.Merge(m, src)
+}
+func (m *KafkaLogEntry) XXX_Size() int {
+	return xxx_messageInfo_KafkaLogEntry.Size(m)
+}
+func (m *KafkaLogEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_KafkaLogEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KafkaLogEntry proto.InternalMessageInfo
+
+func (m *KafkaLogEntry) GetErrorCode() int32 {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return 0
+}
+
+func (m *KafkaLogEntry) GetApiVersion() int32 {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return 0
+}
+
+func (m *KafkaLogEntry) GetApiKey() int32 {
+	if m != nil {
+		return m.ApiKey
+	}
+	return 0
+}
+
+func (m *KafkaLogEntry) GetCorrelationId() int32 {
+	if m != nil {
+		return m.CorrelationId
+	}
+	return 0
+}
+
+func (m *KafkaLogEntry) GetTopics() []string {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
 type L7LogEntry struct {
 	Proto                string            `protobuf:"bytes,1,opt,name=proto,proto3" json:"proto,omitempty"`
 	Fields               map[string]string `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
@@ -225,7 +335,7 @@ func (m *L7LogEntry) Reset()         { *m = L7LogEntry{} }
 func (m *L7LogEntry) String() string { return proto.CompactTextString(m) }
 func (*L7LogEntry) ProtoMessage()    {}
 func (*L7LogEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f29d2fd7c3943de2, []int{2}
+	return fileDescriptor_f29d2fd7c3943de2, []int{3}
 }
 
 func (m *L7LogEntry) XXX_Unmarshal(b []byte) error {
@@ -281,6 +391,7 @@ type LogEntry struct {
 	DestinationAddress string `protobuf:"bytes,8,opt,name=destination_address,json=destinationAddress,proto3" json:"destination_address,omitempty"`
 	// Types that are valid to be assigned to L7:
 	//	*LogEntry_Http
+	//	*LogEntry_Kafka
 	//	*LogEntry_GenericL7
 	L7 isLogEntry_L7 `protobuf_oneof:"l7"`
 	//
@@ -304,7 +415,7 @@ func (m *LogEntry) Reset()         { *m = LogEntry{} }
 func (m *LogEntry) String() string { return proto.CompactTextString(m) }
 func (*LogEntry) ProtoMessage()    {}
 func (*LogEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f29d2fd7c3943de2, []int{3}
+	return fileDescriptor_f29d2fd7c3943de2, []int{4}
 }
 
 func (m *LogEntry) XXX_Unmarshal(b []byte) error {
@@ -396,12 +507,18 @@ type LogEntry_Http struct {
 	Http *HttpLogEntry `protobuf:"bytes,100,opt,name=http,proto3,oneof"`
 }
 
+type LogEntry_Kafka struct {
+	Kafka *KafkaLogEntry `protobuf:"bytes,101,opt,name=kafka,proto3,oneof"`
+}
+
 type LogEntry_GenericL7 struct {
 	GenericL7 *L7LogEntry `protobuf:"bytes,102,opt,name=generic_l7,json=genericL7,proto3,oneof"`
 }
 
 func (*LogEntry_Http) isLogEntry_L7() {}
 
+func (*LogEntry_Kafka) isLogEntry_L7() {}
+
 func (*LogEntry_GenericL7) isLogEntry_L7() {}
 
 func (m *LogEntry) GetL7() isLogEntry_L7 {
@@ -418,6 +535,13 @@ func (m *LogEntry) GetHttp() *HttpLogEntry {
 	return nil
 }
 
+func (m *LogEntry) GetKafka() *KafkaLogEntry {
+	if x, ok := m.GetL7().(*LogEntry_Kafka); ok {
+		return x.Kafka
+	}
+	return nil
+}
+
 func (m *LogEntry) GetGenericL7() *L7LogEntry {
 	if x, ok := m.GetL7().(*LogEntry_GenericL7); ok {
 		return x.GenericL7
@@ -485,6 +609,7 @@ func (m *LogEntry) GetHeaders() []*KeyValue {
 func (*LogEntry) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _LogEntry_OneofMarshaler, _LogEntry_OneofUnmarshaler, _LogEntry_OneofSizer, []interface{}{
 		(*LogEntry_Http)(nil),
+		(*LogEntry_Kafka)(nil),
 		(*LogEntry_GenericL7)(nil),
 	}
 }
@@ -498,6 +623,11 @@ func _LogEntry_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Http); err != nil {
 			return err
 		}
+	case *LogEntry_Kafka:
+		b.EncodeVarint(101<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Kafka); err != nil {
+			return err
+		}
 	case *LogEntry_GenericL7:
 		b.EncodeVarint(102<<3 | proto.WireBytes)
 		if err := b.EncodeMessage(x.GenericL7); err != nil {
@@ -521,6 +651,14 @@ func _LogEntry_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffe
 		err := b.DecodeMessage(msg)
 		m.L7 = &LogEntry_Http{msg}
 		return true, err
+	case 101: // l7.kafka
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(KafkaLogEntry)
+		err := b.DecodeMessage(msg)
+		m.L7 = &LogEntry_Kafka{msg}
+		return true, err
 	case 102: // l7.generic_l7
 		if wire != proto.WireBytes {
 			return true, proto.ErrInternalBadWireType
@@ -543,6 +681,11 @@ func _LogEntry_OneofSizer(msg proto.Message) (n int) {
 		n += 2 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *LogEntry_Kafka:
+		s := proto.Size(x.Kafka)
+		n += 2 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case *LogEntry_GenericL7:
 		s := proto.Size(x.GenericL7)
 		n += 2 // tag and wire
@@ -560,6 +703,7 @@ func init() {
 	proto.RegisterEnum("cilium.EntryType", EntryType_name, EntryType_value)
 	proto.RegisterType((*KeyValue)(nil), "cilium.KeyValue")
 	proto.RegisterType((*HttpLogEntry)(nil), "cilium.HttpLogEntry")
+	proto.RegisterType((*KafkaLogEntry)(nil), "cilium.KafkaLogEntry")
 	proto.RegisterType((*L7LogEntry)(nil), "cilium.L7LogEntry")
 	proto.RegisterMapType((map[string]string)(nil), "cilium.L7LogEntry.FieldsEntry")
 	proto.RegisterType((*LogEntry)(nil), "cilium.LogEntry")
@@ -568,47 +712,60 @@ func init() {
 func init() { proto.RegisterFile("cilium/accesslog.proto", fileDescriptor_f29d2fd7c3943de2) }
 
 var fileDescriptor_f29d2fd7c3943de2 = []byte{
-	// 668 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xdb, 0x6e, 0xd3, 0x40,
-	0x10, 0x8d, 0xdd, 0xdc, 0x3c, 0xb9, 0xd4, 0x2c, 0x25, 0x58, 0x11, 0x97, 0x28, 0x12, 0x28, 0x8a,
-	0x50, 0xda, 0xa6, 0x52, 0x43, 0x91, 0x78, 0xa0, 0x02, 0x94, 0x42, 0x85, 0xaa, 0xa5, 0xe2, 0xd5,
-	0x32, 0xf6, 0x24, 0x59, 0xd5, 0xb1, 0x8d, 0x77, 0x8d, 0x94, 0x0f, 0xe1, 0x13, 0x11, 0xbf, 0x81,
-	0xbc, 0xbb, 0x76, 0xd2, 0x02, 0x12, 0x6f, 0x33, 0x67, 0xce, 0x99, 0xf1, 0xec, 0xee, 0x31, 0xf4,
-	0x7c, 0x16, 0xb2, 0x6c, 0x7d, 0xe8, 0xf9, 0x3e, 0x72, 0x1e, 0xc6, 0xcb, 0x49, 0x92, 0xc6, 0x22,
-	0x26, 0x75, 0x85, 0x0f, 0xa7, 0xd0, 0xfc, 0x88, 0x9b, 0x2f, 0x5e, 0x98, 0x21, 0xb1, 0x61, 0xef,
-	0x06, 0x37, 0x8e, 0x31, 0x30, 0x46, 0x16, 0xcd, 0x43, 0x72, 0x00, 0xb5, 0xef, 0x79, 0xc9, 0x31,
-	0x25, 0xa6, 0x92, 0xe1, 0x4f, 0x03, 0xda, 0x73, 0x21, 0x92, 0xcb, 0x78, 0xf9, 0x2e, 0x12, 0xe9,
-	0x86, 0x9c, 0x41, 0x67, 0x25, 0x44, 0xe2, 0xca, 0xd6, 0x7e, 0x1c, 0xca, 0x16, 0xdd, 0xe9, 0xc1,
-	0x44, 0x0d, 0x99, 0xe4, 0xe4, 0x2b, 0x5d, 0xa3, 0xed, 0xd5, 0x4e, 0x46, 0x7a, 0x50, 0xe7, 0xfe,
-	0x0a, 0xd7, 0xc5, 0x08, 0x9d, 0x11, 0x02, 0xd5, 0x55, 0xcc, 0x85, 0xb3, 0x27, 0x51, 0x19, 0xe7,
-	0x58, 0xe2, 0x89, 0x95, 0x53, 0x55, 0x58, 0x1e, 0xe7, 0xfa, 0x35, 0x8a, 0x55, 0x1c, 0x38, 0x35,
-	0xa5, 0x57, 0x19, 0x19, 0x43, 0x63, 0x85, 0x5e, 0x80, 0x29, 0x77, 0xea, 0x83, 0xbd, 0x51, 0x6b,
-	0x6a, 0x17, 0x1f, 0x53, 0xac, 0x4b, 0x0b, 0x82, 0xfc, 0x06, 0xe1, 0x89, 0x8c, 0x3b, 0x8d, 0x81,
-	0x31, 0xea, 0x50, 0x9d, 0x0d, 0x7f, 0x18, 0x00, 0x97, 0xb3, 0x72, 0xcb, 0x03, 0xa8, 0xc9, 0x05,
-	0xf5, 0x01, 0xa9, 0x84, 0x9c, 0x42, 0x7d, 0xc1, 0x30, 0x0c, 0xb8, 0x63, 0xca, 0x39, 0x4f, 0x8a,
-	0x39, 0x5b, 0xe5, 0xe4, 0xbd, 0x24, 0xc8, 0x98, 0x6a, 0x76, 0xff, 0x0c, 0x5a, 0x3b, 0xf0, 0xff,
-	0x9e, 0xfd, 0x2b, 0xf3, 0xa5, 0x31, 0xfc, 0x55, 0x83, 0x66, 0xf9, 0x55, 0x8f, 0xc0, 0x12, 0x6c,
-	0x8d, 0x5c, 0x78, 0xeb, 0x44, 0xca, 0xab, 0x74, 0x0b, 0x90, 0xc7, 0x00, 0x8c, 0xbb, 0x2c, 0x5a,
-	0xa6, 0xc8, 0xb9, 0xb3, 0x3f, 0x30, 0x46, 0x4d, 0x6a, 0x31, 0x7e, 0xa1, 0x00, 0x72, 0x04, 0x80,
-	0x79, 0x17, 0x57, 0x6c, 0x12, 0x94, 0x67, 0xdd, 0x9d, 0xde, 0x2b, 0x16, 0x90, 0xfd, 0xaf, 0x37,
-	0x09, 0x52, 0x0b, 0x8b, 0x90, 0x3c, 0x85, 0x56, 0x12, 0x87, 0xcc, 0xdf, 0xb8, 0x91, 0xb7, 0x46,
-	0x7d, 0x15, 0xa0, 0xa0, 0x4f, 0xde, 0x1a, 0xc9, 0x73, 0xd8, 0x57, 0x7a, 0x37, 0xcd, 0x42, 0x74,
-	0x53, 0x5c, 0xe8, 0x9b, 0xe9, 0x28, 0x98, 0x66, 0x21, 0x52, 0x5c, 0x90, 0x17, 0x40, 0x78, 0x9c,
-	0xa5, 0x3e, 0xba, 0x1c, 0xfd, 0x2c, 0x65, 0x62, 0xe3, 0xb2, 0xc0, 0xa9, 0xcb, 0x0b, 0xb0, 0x55,
-	0xe5, 0xb3, 0x2e, 0x5c, 0x04, 0xe4, 0x14, 0x1e, 0x06, 0xc8, 0x05, 0x8b, 0x3c, 0xc1, 0xe2, 0xe8,
-	0x96, 0xc4, 0x96, 0x92, 0x07, 0x3b, 0xe5, 0x1d, 0xdd, 0x33, 0xe8, 0xea, 0x29, 0x5e, 0x10, 0xc8,
-	0x33, 0x68, 0xa8, 0x8f, 0x51, 0xe8, 0x1b, 0x05, 0x92, 0x43, 0xb8, 0xbf, 0xdb, 0xbe, 0xe0, 0x36,
-	0x25, 0x97, 0xec, 0x94, 0x0a, 0xc1, 0x18, 0xaa, 0xf9, 0x33, 0x76, 0x82, 0x81, 0x31, 0x6a, 0xdd,
-	0x7e, 0xe8, 0xc5, 0xcd, 0xcc, 0x2b, 0x54, 0x72, 0xc8, 0x09, 0xc0, 0x12, 0x23, 0x4c, 0x99, 0xef,
-	0x86, 0x33, 0x67, 0x21, 0x15, 0xe4, 0xcf, 0x57, 0x32, 0xaf, 0x50, 0x4b, 0xf3, 0x2e, 0x67, 0xe4,
-	0xf5, 0x5d, 0x4b, 0x99, 0xff, 0xb6, 0xd4, 0xb9, 0xe9, 0x18, 0x77, 0x6c, 0xd5, 0x2f, 0x6d, 0x65,
-	0xe5, 0x3b, 0x48, 0x46, 0x61, 0xad, 0x9e, 0xb6, 0x16, 0x94, 0x15, 0x65, 0xaf, 0x9e, 0xb6, 0x57,
-	0x6b, 0x8b, 0x4b, 0x8b, 0xf5, 0x4b, 0x8b, 0xb5, 0xb7, 0xbd, 0xb4, 0xcd, 0xfa, 0xa5, 0x75, 0x3a,
-	0xf9, 0x35, 0xe8, 0x39, 0x12, 0x21, 0x93, 0xad, 0x05, 0xbb, 0x7f, 0xb7, 0xa0, 0xa4, 0x17, 0xa4,
-	0xf3, 0x2a, 0x98, 0xe1, 0xec, 0x43, 0xb5, 0x89, 0xf6, 0x82, 0xd6, 0x6e, 0xbc, 0xc5, 0x8d, 0x37,
-	0x3e, 0x56, 0x3f, 0x9a, 0x72, 0x2d, 0x80, 0xfa, 0xfc, 0xfa, 0xfa, 0xea, 0xf8, 0xc8, 0xae, 0x94,
-	0xf1, 0xb1, 0x6d, 0x10, 0x0b, 0x6a, 0x79, 0x3c, 0xb5, 0xcd, 0xf1, 0x14, 0xac, 0xf2, 0xe1, 0x92,
-	0x16, 0x34, 0x28, 0x7e, 0xcb, 0x90, 0x0b, 0xbb, 0x42, 0xda, 0xd0, 0xa4, 0xc8, 0x93, 0x38, 0xe2,
-	0x68, 0x1b, 0xb9, 0xfc, 0x2d, 0x46, 0x0c, 0x03, 0xdb, 0xfc, 0x5a, 0x97, 0xa7, 0x7c, 0xf2, 0x3b,
-	0x00, 0x00, 0xff, 0xff, 0x0d, 0x1d, 0x5e, 0x96, 0x2d, 0x05, 0x00, 0x00,
+	// 875 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x5f, 0x6f, 0xdc, 0x44,
+	0x10, 0x8f, 0xef, 0xbf, 0xe7, 0x72, 0x77, 0x66, 0x49, 0x53, 0x2b, 0x2a, 0xe5, 0x74, 0x52, 0xd1,
+	0x29, 0x82, 0x4b, 0x73, 0x91, 0x1a, 0x02, 0xea, 0x03, 0x2d, 0xa0, 0x8b, 0x12, 0xa1, 0x6a, 0x1b,
+	0xfa, 0x6a, 0xb9, 0xf6, 0xdc, 0xdd, 0x12, 0xdb, 0x6b, 0x76, 0xd7, 0x45, 0xf7, 0x11, 0xf8, 0x00,
+	0xbc, 0xf1, 0xce, 0xd7, 0x44, 0xbb, 0x6b, 0xfb, 0x4c, 0x21, 0x12, 0x6f, 0x33, 0xbf, 0xf9, 0xfd,
+	0xc6, 0xb3, 0x33, 0xe3, 0x81, 0xe3, 0x88, 0x25, 0xac, 0x48, 0xcf, 0xc2, 0x28, 0x42, 0x29, 0x13,
+	0xbe, 0x59, 0xe4, 0x82, 0x2b, 0x4e, 0x7a, 0x16, 0x3f, 0x79, 0xba, 0xe1, 0x7c, 0x93, 0xe0, 0x99,
+	0x41, 0xdf, 0x17, 0xeb, 0xb3, 0xdf, 0x44, 0x98, 0xe7, 0x28, 0xa4, 0xe5, 0xcd, 0x96, 0x30, 0xb8,
+	0xc1, 0xdd, 0xbb, 0x30, 0x29, 0x90, 0x78, 0xd0, 0xbe, 0xc7, 0x9d, 0xef, 0x4c, 0x9d, 0xb9, 0x4b,
+	0xb5, 0x49, 0x8e, 0xa0, 0xfb, 0x41, 0x87, 0xfc, 0x96, 0xc1, 0xac, 0x33, 0xfb, 0xb3, 0x0d, 0x87,
+	0x2b, 0xa5, 0xf2, 0x5b, 0xbe, 0xf9, 0x21, 0x53, 0x62, 0x47, 0xae, 0x60, 0xb4, 0x55, 0x2a, 0x0f,
+	0x4c, 0xca, 0x88, 0x27, 0x26, 0xc5, 0x78, 0x79, 0xb4, 0xb0, 0x45, 0x2c, 0x34, 0xf9, 0x4d, 0x19,
+	0xa3, 0x87, 0xdb, 0x86, 0x47, 0x8e, 0xa1, 0x27, 0xa3, 0x2d, 0xa6, 0xd5, 0x27, 0x4a, 0x8f, 0x10,
+	0xe8, 0x6c, 0xb9, 0x54, 0x7e, 0xdb, 0xa0, 0xc6, 0xd6, 0x58, 0x1e, 0xaa, 0xad, 0xdf, 0xb1, 0x98,
+	0xb6, 0xb5, 0x3e, 0x45, 0xb5, 0xe5, 0xb1, 0xdf, 0xb5, 0x7a, 0xeb, 0x91, 0x53, 0xe8, 0x6f, 0x31,
+	0x8c, 0x51, 0x48, 0xbf, 0x37, 0x6d, 0xcf, 0x87, 0x4b, 0xaf, 0x2a, 0xa6, 0x7a, 0x2e, 0xad, 0x08,
+	0xa6, 0x06, 0x15, 0xaa, 0x42, 0xfa, 0xfd, 0xa9, 0x33, 0x1f, 0xd1, 0xd2, 0x23, 0x57, 0x30, 0x49,
+	0x99, 0x94, 0x2c, 0xdb, 0x04, 0x55, 0xae, 0xc1, 0x03, 0xb9, 0xc6, 0x25, 0x71, 0x55, 0xa6, 0xfc,
+	0x16, 0x3c, 0x81, 0xbf, 0x60, 0xa4, 0x30, 0xae, 0xb5, 0xee, 0x03, 0xda, 0x49, 0xc5, 0xac, 0xc4,
+	0x2f, 0x61, 0xb8, 0x11, 0x79, 0x14, 0x94, 0x45, 0xc1, 0xd4, 0x99, 0x0f, 0x97, 0x4f, 0x16, 0x76,
+	0x92, 0x8b, 0x6a, 0x92, 0x8b, 0x9f, 0xaf, 0x33, 0x75, 0xb1, 0xb4, 0x39, 0x40, 0x0b, 0xde, 0x1a,
+	0xfe, 0xec, 0x2f, 0x07, 0x46, 0x37, 0xe1, 0xfa, 0x3e, 0xac, 0xe7, 0xf3, 0x19, 0x00, 0x0a, 0xc1,
+	0x45, 0x10, 0xf1, 0x18, 0xcd, 0x70, 0xba, 0xd4, 0x35, 0xc8, 0x6b, 0x1e, 0x23, 0xf9, 0x1c, 0x86,
+	0x61, 0xce, 0x82, 0x0f, 0x28, 0x24, 0xe3, 0x99, 0x19, 0x44, 0x97, 0x42, 0x98, 0xb3, 0x77, 0x16,
+	0x21, 0x8f, 0xa1, 0xaf, 0x09, 0x7a, 0x39, 0xda, 0x26, 0xd8, 0x0b, 0x73, 0x76, 0x83, 0x3b, 0xf2,
+	0x0c, 0xc6, 0x11, 0x17, 0x02, 0x93, 0x50, 0x31, 0x9e, 0x05, 0x2c, 0x36, 0xb3, 0xe9, 0xd2, 0x51,
+	0x03, 0xbd, 0x8e, 0x75, 0x83, 0x15, 0xcf, 0x59, 0x24, 0xfd, 0xee, 0xb4, 0xad, 0x87, 0x64, 0xbd,
+	0xd9, 0x1f, 0x0e, 0xc0, 0xed, 0x65, 0x5d, 0xe6, 0x11, 0x74, 0xcd, 0xe3, 0xca, 0x0d, 0xb4, 0x0e,
+	0x79, 0x01, 0xbd, 0x35, 0xc3, 0x24, 0x96, 0x7e, 0xcb, 0x34, 0xf0, 0x69, 0xd5, 0xc0, 0xbd, 0x72,
+	0xf1, 0xa3, 0x21, 0x18, 0x9b, 0x96, 0xec, 0x93, 0x2b, 0x18, 0x36, 0xe0, 0xff, 0xbb, 0xdc, 0xdf,
+	0xb4, 0xbe, 0x76, 0x66, 0xbf, 0xf7, 0x60, 0x50, 0x57, 0xf5, 0x04, 0x5c, 0xc5, 0x52, 0x94, 0x2a,
+	0x4c, 0x73, 0x23, 0xef, 0xd0, 0x3d, 0xa0, 0x5b, 0xcb, 0x64, 0xc0, 0xb2, 0x8d, 0x40, 0x29, 0xfd,
+	0xc9, 0xd4, 0x99, 0x0f, 0xa8, 0xcb, 0xe4, 0xb5, 0x05, 0xc8, 0x73, 0x00, 0xd4, 0x59, 0x02, 0xb5,
+	0xcb, 0xd1, 0x34, 0x6f, 0xbc, 0xfc, 0xa4, 0x7a, 0x80, 0xc9, 0x7f, 0xb7, 0xcb, 0x91, 0xba, 0x58,
+	0x99, 0x7a, 0x18, 0x39, 0x4f, 0x58, 0xb4, 0x0b, 0xb2, 0x30, 0xc5, 0x72, 0xd7, 0xc1, 0x42, 0x3f,
+	0x85, 0x29, 0x92, 0x2f, 0x60, 0x62, 0xf5, 0x81, 0x28, 0x12, 0x0c, 0x04, 0xae, 0xcb, 0xd5, 0x1f,
+	0x59, 0x98, 0x16, 0x09, 0x52, 0x5c, 0x93, 0x2f, 0x81, 0x48, 0x5e, 0x88, 0x08, 0x03, 0x89, 0x51,
+	0x21, 0x98, 0xda, 0xe9, 0xf9, 0xf4, 0xcc, 0x86, 0x7b, 0x36, 0xf2, 0xb6, 0x0c, 0x5c, 0xc7, 0xe4,
+	0x05, 0x3c, 0x8e, 0x51, 0x2a, 0x96, 0xd9, 0x49, 0x36, 0x25, 0x9e, 0x91, 0x3c, 0x6a, 0x84, 0x1b,
+	0xba, 0x67, 0x30, 0x2e, 0xbf, 0x12, 0xc6, 0xb1, 0xe9, 0x41, 0xdf, 0x16, 0x63, 0xd1, 0xef, 0x2c,
+	0x48, 0xce, 0xe0, 0xd3, 0x66, 0xfa, 0x8a, 0x3b, 0x30, 0x5c, 0xd2, 0x08, 0x55, 0x82, 0x53, 0xe8,
+	0xe8, 0x3b, 0xe1, 0xc7, 0x66, 0xf9, 0xff, 0x71, 0x49, 0xaa, 0xc9, 0xac, 0x0e, 0xa8, 0xe1, 0x90,
+	0xaf, 0xa0, 0x7b, 0xaf, 0xf7, 0xdd, 0x47, 0x43, 0x7e, 0x54, 0xff, 0x61, 0xcd, 0x9f, 0x60, 0x75,
+	0x40, 0x2d, 0x8b, 0x5c, 0x00, 0x6c, 0x30, 0x43, 0xc1, 0xa2, 0x20, 0xb9, 0xf4, 0xd7, 0x46, 0x43,
+	0xfe, 0xbd, 0x54, 0xab, 0x03, 0xea, 0x96, 0xbc, 0xdb, 0x4b, 0xf2, 0xf2, 0xe3, 0x13, 0xd7, 0x7a,
+	0xf8, 0xc4, 0xbd, 0x6a, 0xf9, 0xce, 0x47, 0x67, 0xee, 0xa4, 0x3e, 0x73, 0xae, 0x7e, 0xb2, 0x61,
+	0x94, 0x08, 0x39, 0x2e, 0x4f, 0x1d, 0xd4, 0x11, 0xe3, 0x6b, 0xdc, 0x9c, 0xbb, 0xe1, 0x1e, 0xd7,
+	0xbe, 0xce, 0x55, 0x9e, 0xbc, 0xc3, 0x7d, 0x2e, 0x8b, 0x98, 0xef, 0xd8, 0xab, 0x31, 0xd2, 0x53,
+	0x2b, 0xbf, 0x63, 0x10, 0xb2, 0xd8, 0x9f, 0xc4, 0xf1, 0x7f, 0x9f, 0x22, 0x43, 0xaf, 0x48, 0xaf,
+	0x3a, 0xd0, 0x4a, 0x2e, 0x4f, 0xcf, 0xed, 0xad, 0xaf, 0x5f, 0x02, 0xd0, 0x5b, 0xdd, 0xdd, 0xbd,
+	0x39, 0x7f, 0xee, 0x1d, 0xd4, 0xf6, 0xb9, 0xe7, 0x10, 0x17, 0xba, 0xda, 0x5e, 0x7a, 0xad, 0xd3,
+	0xd7, 0xe0, 0xd6, 0xab, 0x4d, 0x86, 0xd0, 0xa7, 0xf8, 0x6b, 0x81, 0x52, 0x79, 0x07, 0xe4, 0x10,
+	0x06, 0x14, 0x65, 0xce, 0x33, 0x89, 0x9e, 0xa3, 0xe5, 0xdf, 0x63, 0xc6, 0x30, 0xf6, 0x5a, 0x64,
+	0x02, 0x43, 0x1a, 0x2a, 0xbc, 0x65, 0x29, 0x53, 0x18, 0x7b, 0xed, 0xf7, 0x3d, 0xd3, 0xe9, 0x8b,
+	0xbf, 0x07, 0x00, 0xf8, 0xc2, 0x80, 0x4c, 0xe1, 0x06, 0x00, 0x00,
 }
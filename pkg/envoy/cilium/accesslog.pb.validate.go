@@ -114,6 +114,46 @@ func (m *HttpLogEntry) Validate() error {
 
 	// no validation rules for Status
 
+	for idx, item := range m.GetMissingHeaders() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return HttpLogEntryValidationError{
+					Field:  fmt.Sprintf("MissingHeaders[%v]", idx),
+					Reason: "embedded message failed validation",
+					Cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetRejectedHeaders() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return HttpLogEntryValidationError{
+					Field:  fmt.Sprintf("RejectedHeaders[%v]", idx),
+					Reason: "embedded message failed validation",
+					Cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if v, ok := interface{}(m.GetGrpcStatus()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return HttpLogEntryValidationError{
+				Field:  "GrpcStatus",
+				Reason: "embedded message failed validation",
+				Cause:  err,
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -148,6 +188,56 @@ func (e HttpLogEntryValidationError) Error() string {
 
 var _ error = HttpLogEntryValidationError{}
 
+// Validate checks the field values on KafkaLogEntry with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *KafkaLogEntry) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for ErrorCode
+
+	// no validation rules for ApiVersion
+
+	// no validation rules for ApiKey
+
+	// no validation rules for CorrelationId
+
+	return nil
+}
+
+// KafkaLogEntryValidationError is the validation error returned by
+// KafkaLogEntry.Validate if the designated constraints aren't met.
+type KafkaLogEntryValidationError struct {
+	Field  string
+	Reason string
+	Cause  error
+	Key    bool
+}
+
+// Error satisfies the builtin error interface
+func (e KafkaLogEntryValidationError) Error() string {
+	cause := ""
+	if e.Cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.Cause)
+	}
+
+	key := ""
+	if e.Key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sKafkaLogEntry.%s: %s%s",
+		key,
+		e.Field,
+		e.Reason,
+		cause)
+}
+
+var _ error = KafkaLogEntryValidationError{}
+
 // Validate checks the field values on L7LogEntry with the rules defined in the
 // proto definition for this message. If any rules are violated, an error is returned.
 func (m *L7LogEntry) Validate() error {
@@ -259,6 +349,18 @@ func (m *LogEntry) Validate() error {
 			}
 		}
 
+	case *LogEntry_Kafka:
+
+		if v, ok := interface{}(m.GetKafka()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return LogEntryValidationError{
+					Field:  "Kafka",
+					Reason: "embedded message failed validation",
+					Cause:  err,
+				}
+			}
+		}
+
 	case *LogEntry_GenericL7:
 
 		if v, ok := interface{}(m.GetGenericL7()).(interface{ Validate() error }); ok {
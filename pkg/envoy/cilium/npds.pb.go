@@ -9,6 +9,7 @@ import (
 	core "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/core"
 	route "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/route"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	_ "github.com/lyft/protoc-gen-validate/validate"
 	context "golang.org/x/net/context"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
@@ -27,6 +28,68 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type HttpRateLimit_Scope int32
+
+const (
+	// Limit the requests of all sources with the same security identity.
+	HttpRateLimit_PER_SOURCE_IDENTITY HttpRateLimit_Scope = 0
+	// Limit the requests of each source IP address.
+	HttpRateLimit_PER_SOURCE_ADDRESS HttpRateLimit_Scope = 1
+)
+
+var HttpRateLimit_Scope_name = map[int32]string{
+	0: "PER_SOURCE_IDENTITY",
+	1: "PER_SOURCE_ADDRESS",
+}
+
+var HttpRateLimit_Scope_value = map[string]int32{
+	"PER_SOURCE_IDENTITY": 0,
+	"PER_SOURCE_ADDRESS":  1,
+}
+
+func (x HttpRateLimit_Scope) String() string {
+	return proto.EnumName(HttpRateLimit_Scope_name, int32(x))
+}
+
+func (HttpRateLimit_Scope) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_282feee65b187334, []int{5, 0}
+}
+
+type HeaderMatch_MismatchAction int32
+
+const (
+	// Deny the request.
+	HeaderMatch_FAIL_ON_MISMATCH HeaderMatch_MismatchAction = 0
+	// Allow the request and log the mismatch in the access log.
+	HeaderMatch_CONTINUE_ON_MISMATCH HeaderMatch_MismatchAction = 1
+	// Add 'value' to the header, keeping any existing values.
+	HeaderMatch_ADD_ON_MISMATCH HeaderMatch_MismatchAction = 2
+	// Replace all values of the header with 'value'.
+	HeaderMatch_REPLACE_ON_MISMATCH HeaderMatch_MismatchAction = 3
+)
+
+var HeaderMatch_MismatchAction_name = map[int32]string{
+	0: "FAIL_ON_MISMATCH",
+	1: "CONTINUE_ON_MISMATCH",
+	2: "ADD_ON_MISMATCH",
+	3: "REPLACE_ON_MISMATCH",
+}
+
+var HeaderMatch_MismatchAction_value = map[string]int32{
+	"FAIL_ON_MISMATCH":     0,
+	"CONTINUE_ON_MISMATCH": 1,
+	"ADD_ON_MISMATCH":      2,
+	"REPLACE_ON_MISMATCH":  3,
+}
+
+func (x HeaderMatch_MismatchAction) String() string {
+	return proto.EnumName(HeaderMatch_MismatchAction_name, int32(x))
+}
+
+func (HeaderMatch_MismatchAction) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_282feee65b187334, []int{6, 0}
+}
+
 // A network policy that is enforced by a filter on the network flows to/from
 // associated hosts.
 type NetworkPolicy struct {
@@ -438,10 +501,22 @@ type HttpNetworkPolicyRule struct {
 	// * *:authority*: Also maps to the HTTP 1.1 *Host* header.
 	//
 	// Optional. If empty, matches any HTTP request.
-	Headers              []*route.HeaderMatcher `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+	Headers []*route.HeaderMatcher `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`
+	// A set of header matches that do not necessarily deny the request when
+	// they do not match. Each mismatch is handled according to the mismatch
+	// action of the match, after the request has been matched by 'headers'.
+	//
+	// Optional.
+	HeaderMatches []*HeaderMatch `protobuf:"bytes,2,rep,name=header_matches,json=headerMatches,proto3" json:"header_matches,omitempty"`
+	// A limit on the rate of requests allowed by this rule. A request that
+	// matches the rule after the limit has been reached is not allowed by this
+	// rule.
+	//
+	// Optional. If unset, the rate of requests is not limited.
+	RateLimit            *HttpRateLimit `protobuf:"bytes,3,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *HttpNetworkPolicyRule) Reset()         { *m = HttpNetworkPolicyRule{} }
@@ -476,6 +551,162 @@ func (m *HttpNetworkPolicyRule) GetHeaders() []*route.HeaderMatcher {
 	return nil
 }
 
+func (m *HttpNetworkPolicyRule) GetHeaderMatches() []*HeaderMatch {
+	if m != nil {
+		return m.HeaderMatches
+	}
+	return nil
+}
+
+func (m *HttpNetworkPolicyRule) GetRateLimit() *HttpRateLimit {
+	if m != nil {
+		return m.RateLimit
+	}
+	return nil
+}
+
+// A token bucket limiting the rate of HTTP requests. Each source has a bucket
+// of its own.
+type HttpRateLimit struct {
+	// The number of requests allowed per 'interval'.
+	// Required.
+	Requests uint32 `protobuf:"varint,1,opt,name=requests,proto3" json:"requests,omitempty"`
+	// The interval in which 'requests' requests are allowed.
+	// Required.
+	Interval *duration.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// The number of requests allowed in a burst. Bursts are limited to
+	// 'requests' if zero or less than 'requests'.
+	Burst                uint32              `protobuf:"varint,3,opt,name=burst,proto3" json:"burst,omitempty"`
+	Scope                HttpRateLimit_Scope `protobuf:"varint,4,opt,name=scope,proto3,enum=cilium.HttpRateLimit_Scope" json:"scope,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *HttpRateLimit) Reset()         { *m = HttpRateLimit{} }
+func (m *HttpRateLimit) String() string { return proto.CompactTextString(m) }
+func (*HttpRateLimit) ProtoMessage()    {}
+func (*HttpRateLimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_282feee65b187334, []int{5}
+}
+
+func (m *HttpRateLimit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HttpRateLimit.Unmarshal(m, b)
+}
+func (m *HttpRateLimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HttpRateLimit.Marshal(b, m, deterministic)
+}
+func (m *HttpRateLimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HttpRateLimit.Merge(m, src)
+}
+func (m *HttpRateLimit) XXX_Size() int {
+	return xxx_messageInfo_HttpRateLimit.Size(m)
+}
+func (m *HttpRateLimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_HttpRateLimit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HttpRateLimit proto.InternalMessageInfo
+
+func (m *HttpRateLimit) GetRequests() uint32 {
+	if m != nil {
+		return m.Requests
+	}
+	return 0
+}
+
+func (m *HttpRateLimit) GetInterval() *duration.Duration {
+	if m != nil {
+		return m.Interval
+	}
+	return nil
+}
+
+func (m *HttpRateLimit) GetBurst() uint32 {
+	if m != nil {
+		return m.Burst
+	}
+	return 0
+}
+
+func (m *HttpRateLimit) GetScope() HttpRateLimit_Scope {
+	if m != nil {
+		return m.Scope
+	}
+	return HttpRateLimit_PER_SOURCE_IDENTITY
+}
+
+// A match on an HTTP request header with an action to take if the header does
+// not match.
+type HeaderMatch struct {
+	// The header name and the value or pattern to match it against.
+	// Required.
+	Header         *route.HeaderMatcher       `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	MismatchAction HeaderMatch_MismatchAction `protobuf:"varint,2,opt,name=mismatch_action,json=mismatchAction,proto3,enum=cilium.HeaderMatch_MismatchAction" json:"mismatch_action,omitempty"`
+	// The header value to add or replace on mismatch. Only used with
+	// ADD_ON_MISMATCH and REPLACE_ON_MISMATCH.
+	Value string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	// Set if the value is a secret. The values of the header in the request
+	// are then redacted in the access log.
+	Secret               bool     `protobuf:"varint,4,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HeaderMatch) Reset()         { *m = HeaderMatch{} }
+func (m *HeaderMatch) String() string { return proto.CompactTextString(m) }
+func (*HeaderMatch) ProtoMessage()    {}
+func (*HeaderMatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_282feee65b187334, []int{6}
+}
+
+func (m *HeaderMatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeaderMatch.Unmarshal(m, b)
+}
+func (m *HeaderMatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeaderMatch.Marshal(b, m, deterministic)
+}
+func (m *HeaderMatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeaderMatch.Merge(m, src)
+}
+func (m *HeaderMatch) XXX_Size() int {
+	return xxx_messageInfo_HeaderMatch.Size(m)
+}
+func (m *HeaderMatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeaderMatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeaderMatch proto.InternalMessageInfo
+
+func (m *HeaderMatch) GetHeader() *route.HeaderMatcher {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *HeaderMatch) GetMismatchAction() HeaderMatch_MismatchAction {
+	if m != nil {
+		return m.MismatchAction
+	}
+	return HeaderMatch_FAIL_ON_MISMATCH
+}
+
+func (m *HeaderMatch) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *HeaderMatch) GetSecret() bool {
+	if m != nil {
+		return m.Secret
+	}
+	return false
+}
+
 // A set of network policy rules that match Kafka requests.
 type KafkaNetworkPolicyRules struct {
 	// The set of Kafka network policy rules.
@@ -491,7 +722,7 @@ func (m *KafkaNetworkPolicyRules) Reset()         { *m = KafkaNetworkPolicyRules
 func (m *KafkaNetworkPolicyRules) String() string { return proto.CompactTextString(m) }
 func (*KafkaNetworkPolicyRules) ProtoMessage()    {}
 func (*KafkaNetworkPolicyRules) Descriptor() ([]byte, []int) {
-	return fileDescriptor_282feee65b187334, []int{5}
+	return fileDescriptor_282feee65b187334, []int{7}
 }
 
 func (m *KafkaNetworkPolicyRules) XXX_Unmarshal(b []byte) error {
@@ -538,7 +769,12 @@ type KafkaNetworkPolicyRule struct {
 	// Optional. If not specified, all Kafka requests are matched by this predicate.
 	// If specified, this predicates only matches requests that contain this client ID, and never
 	// matches requests that don't contain any client ID.
-	ClientId             string   `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientId string `protobuf:"bytes,4,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// The SASL user name the Kafka client has authenticated as.
+	// Optional. If not specified, all Kafka requests are matched by this predicate.
+	// If specified, this predicate only matches requests sent after the client has authenticated
+	// as this user, and the SASL handshake and authentication requests needed to authenticate.
+	Principal            string   `protobuf:"bytes,5,opt,name=principal,proto3" json:"principal,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -548,7 +784,7 @@ func (m *KafkaNetworkPolicyRule) Reset()         { *m = KafkaNetworkPolicyRule{}
 func (m *KafkaNetworkPolicyRule) String() string { return proto.CompactTextString(m) }
 func (*KafkaNetworkPolicyRule) ProtoMessage()    {}
 func (*KafkaNetworkPolicyRule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_282feee65b187334, []int{6}
+	return fileDescriptor_282feee65b187334, []int{8}
 }
 
 func (m *KafkaNetworkPolicyRule) XXX_Unmarshal(b []byte) error {
@@ -597,6 +833,13 @@ func (m *KafkaNetworkPolicyRule) GetClientId() string {
 	return ""
 }
 
+func (m *KafkaNetworkPolicyRule) GetPrincipal() string {
+	if m != nil {
+		return m.Principal
+	}
+	return ""
+}
+
 // A set of network policy rules that match generic L7 requests.
 type L7NetworkPolicyRules struct {
 	// The set of generic key-value pair policy rules.
@@ -612,7 +855,7 @@ func (m *L7NetworkPolicyRules) Reset()         { *m = L7NetworkPolicyRules{} }
 func (m *L7NetworkPolicyRules) String() string { return proto.CompactTextString(m) }
 func (*L7NetworkPolicyRules) ProtoMessage()    {}
 func (*L7NetworkPolicyRules) Descriptor() ([]byte, []int) {
-	return fileDescriptor_282feee65b187334, []int{7}
+	return fileDescriptor_282feee65b187334, []int{9}
 }
 
 func (m *L7NetworkPolicyRules) XXX_Unmarshal(b []byte) error {
@@ -655,7 +898,7 @@ func (m *L7NetworkPolicyRule) Reset()         { *m = L7NetworkPolicyRule{} }
 func (m *L7NetworkPolicyRule) String() string { return proto.CompactTextString(m) }
 func (*L7NetworkPolicyRule) ProtoMessage()    {}
 func (*L7NetworkPolicyRule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_282feee65b187334, []int{8}
+	return fileDescriptor_282feee65b187334, []int{10}
 }
 
 func (m *L7NetworkPolicyRule) XXX_Unmarshal(b []byte) error {
@@ -684,11 +927,15 @@ func (m *L7NetworkPolicyRule) GetRule() map[string]string {
 }
 
 func init() {
+	proto.RegisterEnum("cilium.HttpRateLimit_Scope", HttpRateLimit_Scope_name, HttpRateLimit_Scope_value)
+	proto.RegisterEnum("cilium.HeaderMatch_MismatchAction", HeaderMatch_MismatchAction_name, HeaderMatch_MismatchAction_value)
 	proto.RegisterType((*NetworkPolicy)(nil), "cilium.NetworkPolicy")
 	proto.RegisterType((*PortNetworkPolicy)(nil), "cilium.PortNetworkPolicy")
 	proto.RegisterType((*PortNetworkPolicyRule)(nil), "cilium.PortNetworkPolicyRule")
 	proto.RegisterType((*HttpNetworkPolicyRules)(nil), "cilium.HttpNetworkPolicyRules")
 	proto.RegisterType((*HttpNetworkPolicyRule)(nil), "cilium.HttpNetworkPolicyRule")
+	proto.RegisterType((*HttpRateLimit)(nil), "cilium.HttpRateLimit")
+	proto.RegisterType((*HeaderMatch)(nil), "cilium.HeaderMatch")
 	proto.RegisterType((*KafkaNetworkPolicyRules)(nil), "cilium.KafkaNetworkPolicyRules")
 	proto.RegisterType((*KafkaNetworkPolicyRule)(nil), "cilium.KafkaNetworkPolicyRule")
 	proto.RegisterType((*L7NetworkPolicyRules)(nil), "cilium.L7NetworkPolicyRules")
@@ -699,59 +946,80 @@ func init() {
 func init() { proto.RegisterFile("cilium/npds.proto", fileDescriptor_282feee65b187334) }
 
 var fileDescriptor_282feee65b187334 = []byte{
-	// 828 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x41, 0x6f, 0xe3, 0x44,
-	0x14, 0xde, 0x49, 0x9c, 0xb4, 0x79, 0xd1, 0x2e, 0x74, 0xb6, 0x49, 0xdd, 0xb0, 0x4d, 0x83, 0x01,
-	0x29, 0x5b, 0xa9, 0xce, 0x2a, 0x3d, 0x84, 0x96, 0x03, 0xda, 0x88, 0x45, 0x45, 0x05, 0x14, 0xb9,
-	0x2b, 0x0e, 0x8b, 0xd8, 0x68, 0xd6, 0x7e, 0x6d, 0x47, 0x71, 0x3d, 0x66, 0x3c, 0x09, 0x0a, 0xc7,
-	0x15, 0x17, 0xae, 0xf0, 0x3b, 0x90, 0x38, 0x73, 0xda, 0xff, 0xc0, 0x5f, 0x80, 0x03, 0xbf, 0xa2,
-	0xc8, 0x33, 0x76, 0xb6, 0x56, 0xdd, 0x72, 0xe1, 0x62, 0x8d, 0xe7, 0x7d, 0xdf, 0x37, 0xef, 0x7d,
-	0xf3, 0x9e, 0x0d, 0x1b, 0x3e, 0x0f, 0xf9, 0xfc, 0x72, 0x10, 0xc5, 0x41, 0xe2, 0xc6, 0x52, 0x28,
-	0x41, 0xeb, 0x66, 0xab, 0xb3, 0x8b, 0xd1, 0x42, 0x2c, 0x07, 0x2c, 0xe6, 0x83, 0xc5, 0x70, 0xe0,
-	0x0b, 0x89, 0x03, 0x16, 0x04, 0x12, 0x93, 0x0c, 0xd8, 0x79, 0x54, 0x00, 0x04, 0x3c, 0xf1, 0xc5,
-	0x02, 0xe5, 0x32, 0x8b, 0x76, 0x0b, 0x51, 0x29, 0xe6, 0x0a, 0xcd, 0x33, 0x67, 0x9f, 0x0b, 0x71,
-	0x1e, 0xa2, 0x06, 0xb0, 0x28, 0x12, 0x8a, 0x29, 0x2e, 0xa2, 0x5c, 0x7b, 0x6b, 0xc1, 0x42, 0x1e,
-	0x30, 0x85, 0x83, 0x7c, 0x61, 0x02, 0xce, 0xdf, 0x04, 0xee, 0x7f, 0x8d, 0xea, 0x07, 0x21, 0x67,
-	0x13, 0x11, 0x72, 0x7f, 0x49, 0x29, 0x58, 0x11, 0xbb, 0x44, 0x9b, 0xf4, 0x48, 0xbf, 0xe1, 0xe9,
-	0x35, 0x6d, 0x43, 0x3d, 0xd6, 0x51, 0xbb, 0xd2, 0x23, 0x7d, 0xcb, 0xcb, 0xde, 0xe8, 0x73, 0xd8,
-	0xe6, 0xd1, 0x79, 0x5a, 0xc3, 0x34, 0x46, 0x39, 0x8d, 0x85, 0x54, 0x53, 0x1d, 0xe2, 0x98, 0xd8,
-	0xd5, 0x5e, 0xb5, 0xdf, 0x1c, 0x6e, 0xbb, 0xa6, 0x7e, 0x77, 0x22, 0xa4, 0x2a, 0x9c, 0xe4, 0xb5,
-	0x33, 0xee, 0x04, 0x65, 0x1a, 0x9c, 0x64, 0x44, 0xea, 0x81, 0x8d, 0xb7, 0x89, 0x5a, 0xff, 0x25,
-	0xda, 0xc2, 0x32, 0x4d, 0xe7, 0x77, 0x02, 0x1b, 0x37, 0xc0, 0x74, 0x17, 0xac, 0x54, 0x5e, 0xd7,
-	0x7a, 0x7f, 0xdc, 0xfc, 0xe3, 0x9f, 0x37, 0xd5, 0xfa, 0x9e, 0x65, 0x5f, 0x5d, 0x55, 0x3d, 0x1d,
-	0xa0, 0xcf, 0x60, 0x5d, 0xfb, 0xe4, 0x8b, 0x50, 0x97, 0xfe, 0x60, 0xf8, 0xd8, 0xd5, 0x17, 0xe1,
-	0xb2, 0x98, 0xbb, 0x8b, 0xa1, 0x9b, 0xde, 0xa3, 0x7b, 0x2a, 0xfc, 0x19, 0xaa, 0xa7, 0xd9, 0x6d,
-	0x4e, 0x32, 0x82, 0xb7, 0xa2, 0xd2, 0x03, 0xa8, 0xc9, 0x79, 0xb8, 0xf2, 0x64, 0xe7, 0xf6, 0xf4,
-	0xe7, 0x21, 0x7a, 0x06, 0xeb, 0xfc, 0x56, 0x81, 0x56, 0x29, 0x80, 0x1e, 0xc0, 0x3b, 0x12, 0x2f,
-	0x85, 0xc2, 0xb7, 0xbe, 0x90, 0x5e, 0xb5, 0x6f, 0x8d, 0x21, 0xad, 0xa0, 0xf6, 0x0b, 0xa9, 0xd8,
-	0xc4, 0x7b, 0x60, 0x20, 0x2b, 0x57, 0xb7, 0x61, 0x3d, 0x1c, 0x4d, 0x75, 0x4a, 0xba, 0x94, 0x86,
-	0xb7, 0x16, 0x8e, 0x74, 0xae, 0xf4, 0x53, 0x80, 0x0b, 0xa5, 0xe2, 0xa9, 0xc9, 0x31, 0xe8, 0x91,
-	0x7e, 0x73, 0xd8, 0xcd, 0x73, 0x3c, 0x56, 0x2a, 0xbe, 0x91, 0x42, 0x72, 0x7c, 0xcf, 0x6b, 0xa4,
-	0x1c, 0xfd, 0x42, 0xc7, 0xd0, 0x9c, 0xb1, 0xb3, 0x19, 0xcb, 0x14, 0x50, 0x2b, 0xec, 0xe6, 0x0a,
-	0x27, 0x69, 0xa8, 0x54, 0x02, 0x34, 0xcb, 0x68, 0x1c, 0xea, 0xfc, 0x8c, 0xc0, 0x99, 0x16, 0x78,
-	0x94, 0x0b, 0x7c, 0x39, 0x2a, 0x65, 0xaf, 0x85, 0x23, 0xbd, 0x1c, 0x5b, 0x50, 0x09, 0x47, 0xce,
-	0x2b, 0x68, 0x97, 0xe7, 0x4a, 0x8f, 0x0b, 0xf5, 0x91, 0xe2, 0x1d, 0x94, 0x72, 0xde, 0x3a, 0xb9,
-	0x4e, 0xae, 0x15, 0xea, 0x3c, 0x87, 0x56, 0x29, 0x9e, 0x7e, 0x02, 0x6b, 0x17, 0xc8, 0x02, 0x94,
-	0xb9, 0xfe, 0xfb, 0xc5, 0x3e, 0x31, 0xa3, 0x7a, 0xac, 0x21, 0x5f, 0x31, 0xe5, 0x5f, 0xa0, 0xf4,
-	0x72, 0x86, 0x73, 0x06, 0x5b, 0xb7, 0x78, 0x44, 0x4f, 0x8a, 0xce, 0x1a, 0xed, 0xee, 0xdd, 0xce,
-	0x16, 0x92, 0xbf, 0x66, 0xb1, 0xf3, 0x86, 0x40, 0xbb, 0x9c, 0x42, 0xb7, 0x60, 0x8d, 0xc5, 0x7c,
-	0x3a, 0xc3, 0xa5, 0x1e, 0x86, 0x9a, 0x57, 0x67, 0x31, 0x3f, 0xc1, 0x74, 0x44, 0x9a, 0x69, 0x60,
-	0x81, 0x32, 0xe1, 0x22, 0xd2, 0x9d, 0x53, 0xf3, 0x80, 0xc5, 0xfc, 0x1b, 0xb3, 0x93, 0xf6, 0xb6,
-	0x12, 0x31, 0xf7, 0xed, 0x6a, 0xda, 0x54, 0xe3, 0x9d, 0xf4, 0x6c, 0x5b, 0xb6, 0xed, 0x2b, 0x32,
-	0xdc, 0x78, 0xf9, 0x2d, 0xdb, 0xff, 0xf1, 0xe9, 0xfe, 0x8b, 0x27, 0xfb, 0x87, 0xee, 0x74, 0xff,
-	0xbb, 0xbd, 0x0f, 0x3d, 0x83, 0xa5, 0x23, 0x68, 0xf8, 0x21, 0xc7, 0x48, 0x4d, 0x79, 0x60, 0x5b,
-	0x9a, 0xd8, 0x49, 0x89, 0x2d, 0xf9, 0xb0, 0x8c, 0xb5, 0x6e, 0xc0, 0x5f, 0x04, 0xce, 0x0b, 0xd8,
-	0x2c, 0xeb, 0x06, 0x3a, 0xbe, 0xd6, 0x3d, 0xc6, 0xa4, 0xf7, 0xee, 0xe8, 0x9e, 0x82, 0x43, 0x79,
-	0x1b, 0x39, 0x3f, 0x13, 0x78, 0x58, 0x02, 0xa6, 0x87, 0x60, 0xa5, 0xc2, 0x99, 0xee, 0x47, 0x77,
-	0xe8, 0xba, 0xe9, 0xe3, 0x59, 0xa4, 0xe4, 0xd2, 0xd3, 0x94, 0xce, 0x08, 0x1a, 0xab, 0x2d, 0xfa,
-	0x2e, 0x54, 0x73, 0x7f, 0x1b, 0x5e, 0xba, 0xa4, 0x9b, 0x50, 0x5b, 0xb0, 0x70, 0x8e, 0xd9, 0x40,
-	0x9a, 0x97, 0xa3, 0xca, 0xc7, 0x64, 0xf8, 0x53, 0x05, 0x76, 0x0a, 0xf2, 0x9f, 0xe5, 0xff, 0x83,
-	0x53, 0x94, 0x0b, 0xee, 0x23, 0x7d, 0x09, 0xad, 0x53, 0x25, 0x91, 0x5d, 0x5e, 0x87, 0xa5, 0x83,
-	0xde, 0x2d, 0x76, 0xde, 0x8a, 0xe8, 0xe1, 0xf7, 0x73, 0x4c, 0x54, 0x67, 0xf7, 0xd6, 0x78, 0x12,
-	0x8b, 0x28, 0x41, 0xe7, 0x5e, 0x9f, 0x3c, 0x21, 0xf4, 0x35, 0x81, 0xcd, 0xcf, 0x51, 0xf9, 0x17,
-	0xff, 0xbb, 0xfe, 0xe3, 0xd7, 0x7f, 0xfe, 0xf5, 0x6b, 0xe5, 0x03, 0xa7, 0x5b, 0xf8, 0xcf, 0x1d,
-	0x45, 0xe6, 0x9c, 0xd5, 0x37, 0xed, 0x88, 0xec, 0xbd, 0xaa, 0xeb, 0xef, 0xd5, 0xc1, 0xbf, 0x01,
-	0x00, 0x00, 0xff, 0xff, 0x9f, 0x7b, 0xa5, 0xa3, 0x58, 0x07, 0x00, 0x00,
+	// 1165 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x6f, 0x1b, 0x45,
+	0x14, 0xcf, 0xac, 0xed, 0xc4, 0x7e, 0x21, 0x69, 0x3a, 0xf9, 0xb7, 0x4d, 0xdb, 0xc4, 0x2c, 0x54,
+	0x72, 0x23, 0x65, 0x5d, 0x5c, 0x24, 0xb7, 0xe1, 0x80, 0xec, 0xd8, 0x55, 0xac, 0xfc, 0xa9, 0x35,
+	0x4e, 0x91, 0x28, 0xa2, 0xab, 0xcd, 0x7a, 0xd2, 0xac, 0xb2, 0xde, 0x5d, 0x66, 0xc7, 0x46, 0xe1,
+	0x58, 0x71, 0x81, 0x23, 0x7c, 0x0b, 0x24, 0x24, 0xce, 0x5c, 0x40, 0x1c, 0xf8, 0x02, 0x7c, 0x05,
+	0x38, 0xf0, 0x25, 0x28, 0x9a, 0x99, 0x5d, 0xdb, 0x4b, 0x37, 0x81, 0x03, 0x17, 0x6b, 0x66, 0x7e,
+	0xef, 0xf7, 0xdb, 0xf7, 0x7e, 0xb3, 0xef, 0x79, 0xe1, 0xa6, 0xe3, 0x7a, 0xee, 0x70, 0x50, 0xf5,
+	0xc3, 0x7e, 0x64, 0x86, 0x2c, 0xe0, 0x01, 0x9e, 0x55, 0x47, 0x1b, 0x5b, 0xd4, 0x1f, 0x05, 0x97,
+	0x55, 0x3b, 0x74, 0xab, 0xa3, 0x5a, 0xd5, 0x09, 0x18, 0xad, 0xda, 0xfd, 0x3e, 0xa3, 0x51, 0x1c,
+	0xb8, 0x71, 0x27, 0x15, 0xd0, 0x77, 0x23, 0x27, 0x18, 0x51, 0x76, 0x19, 0xa3, 0x9b, 0x29, 0x94,
+	0x05, 0x43, 0x4e, 0xd5, 0x6f, 0xc2, 0x7e, 0x19, 0x04, 0x2f, 0x3d, 0x2a, 0x03, 0x6c, 0xdf, 0x0f,
+	0xb8, 0xcd, 0xdd, 0xc0, 0x4f, 0xb4, 0x37, 0x63, 0x54, 0xee, 0x4e, 0x87, 0x67, 0xd5, 0xfe, 0x90,
+	0xc9, 0x80, 0x18, 0x5f, 0x1f, 0xd9, 0x9e, 0xdb, 0xb7, 0x39, 0xad, 0x26, 0x0b, 0x05, 0x18, 0x7f,
+	0x20, 0x58, 0x38, 0xa6, 0xfc, 0xf3, 0x80, 0x5d, 0x74, 0x03, 0xcf, 0x75, 0x2e, 0x31, 0x86, 0xbc,
+	0x6f, 0x0f, 0xa8, 0x8e, 0xca, 0xa8, 0x52, 0x22, 0x72, 0x8d, 0xd7, 0x60, 0x36, 0x94, 0xa8, 0xae,
+	0x95, 0x51, 0x25, 0x4f, 0xe2, 0x1d, 0x3e, 0x81, 0x5b, 0xae, 0xff, 0x52, 0xd4, 0x68, 0x85, 0x94,
+	0x59, 0x61, 0xc0, 0xb8, 0x25, 0x21, 0x97, 0x46, 0x7a, 0xae, 0x9c, 0xab, 0xcc, 0xd7, 0x6e, 0x99,
+	0xca, 0x1f, 0xb3, 0x1b, 0x30, 0x9e, 0x7a, 0x12, 0x59, 0x8b, 0xb9, 0x5d, 0xca, 0x04, 0xd8, 0x8d,
+	0x89, 0x98, 0x80, 0x4e, 0xaf, 0x12, 0xcd, 0xff, 0x9b, 0xe8, 0x2a, 0xcd, 0xd2, 0x34, 0x7e, 0x40,
+	0x70, 0xf3, 0x8d, 0x60, 0xbc, 0x05, 0x79, 0x21, 0x2f, 0x6b, 0x5d, 0x68, 0xce, 0xff, 0xf8, 0xe7,
+	0xcf, 0xb9, 0xd9, 0xed, 0xbc, 0xfe, 0xfa, 0x75, 0x8e, 0x48, 0x00, 0xb7, 0xa1, 0x28, 0x7d, 0x72,
+	0x02, 0x4f, 0x96, 0xbe, 0x58, 0xbb, 0x6f, 0xca, 0x8b, 0x32, 0xed, 0xd0, 0x35, 0x47, 0x35, 0x53,
+	0xdc, 0xb3, 0xd9, 0x0b, 0x9c, 0x0b, 0xca, 0x1b, 0xf1, 0x6d, 0x77, 0x63, 0x02, 0x19, 0x53, 0xf1,
+	0x43, 0x28, 0xb0, 0xa1, 0x37, 0xf6, 0xe4, 0xee, 0xd5, 0xe9, 0x0f, 0x3d, 0x4a, 0x54, 0xac, 0xf1,
+	0xbd, 0x06, 0xab, 0x99, 0x01, 0xf8, 0x21, 0xdc, 0x60, 0x74, 0x10, 0x70, 0x3a, 0xf1, 0x05, 0x95,
+	0x73, 0x95, 0x7c, 0x13, 0x44, 0x05, 0x85, 0x6f, 0x90, 0xa6, 0x23, 0xb2, 0xa8, 0x42, 0xc6, 0xae,
+	0xde, 0x82, 0xa2, 0x57, 0xb7, 0x64, 0x4a, 0xb2, 0x94, 0x12, 0x99, 0xf3, 0xea, 0x32, 0x57, 0xfc,
+	0x21, 0xc0, 0x39, 0xe7, 0xa1, 0xa5, 0x72, 0xec, 0x97, 0x51, 0x65, 0xbe, 0xb6, 0x99, 0xe4, 0xb8,
+	0xcf, 0x79, 0xf8, 0x46, 0x0a, 0xd1, 0xfe, 0x0c, 0x29, 0x09, 0x8e, 0xdc, 0xe0, 0x26, 0xcc, 0x5f,
+	0xd8, 0x67, 0x17, 0x76, 0xac, 0x40, 0xa5, 0xc2, 0x56, 0xa2, 0x70, 0x20, 0xa0, 0x4c, 0x09, 0x90,
+	0x2c, 0xa5, 0xf1, 0x58, 0xe6, 0xa7, 0x04, 0xce, 0xa4, 0xc0, 0x9d, 0x44, 0xe0, 0xb0, 0x9e, 0xc9,
+	0x9e, 0xf3, 0xea, 0x72, 0xd9, 0xcc, 0x83, 0xe6, 0xd5, 0x8d, 0x53, 0x58, 0xcb, 0xce, 0x15, 0xef,
+	0xa7, 0xea, 0x43, 0xe9, 0x3b, 0xc8, 0xe4, 0x4c, 0x9c, 0x2c, 0xa2, 0xa9, 0x42, 0x8d, 0x5f, 0x11,
+	0xac, 0x66, 0x12, 0xf0, 0x07, 0x30, 0x77, 0x4e, 0xed, 0x3e, 0x65, 0xc9, 0x03, 0xde, 0x4e, 0xbf,
+	0x28, 0xaa, 0x97, 0xf7, 0x65, 0xc8, 0x91, 0xcd, 0x9d, 0x73, 0xca, 0x48, 0xc2, 0xc0, 0xbb, 0xb0,
+	0xa8, 0x96, 0xd6, 0x40, 0x42, 0x91, 0xae, 0x49, 0x8d, 0xe5, 0x71, 0x92, 0x13, 0x1e, 0x59, 0x38,
+	0x9f, 0x6c, 0x68, 0x84, 0xdf, 0x07, 0x60, 0x36, 0xa7, 0x96, 0xe7, 0x0e, 0x5c, 0xae, 0xe7, 0xa4,
+	0x73, 0xab, 0xd3, 0xc5, 0x11, 0x9b, 0xd3, 0x43, 0x01, 0x92, 0x12, 0x4b, 0x96, 0xc6, 0x5f, 0x08,
+	0x16, 0x52, 0x20, 0xbe, 0x07, 0x45, 0x46, 0x3f, 0x1b, 0xd2, 0x88, 0x47, 0x71, 0x3f, 0x94, 0x84,
+	0x07, 0xf9, 0x6d, 0xad, 0x3c, 0x43, 0xc6, 0x10, 0xde, 0x83, 0xa2, 0xeb, 0x73, 0xca, 0x46, 0xb6,
+	0xea, 0x08, 0xd1, 0x8c, 0x6a, 0xf8, 0x98, 0xc9, 0xf0, 0x31, 0x5b, 0xf1, 0xf0, 0x69, 0xbe, 0x25,
+	0x14, 0xe6, 0xbe, 0x43, 0xf9, 0x22, 0xda, 0x9e, 0x21, 0x63, 0x22, 0x5e, 0x81, 0xc2, 0xe9, 0x90,
+	0x45, 0x2a, 0xdd, 0x05, 0xa2, 0x36, 0xf8, 0x3d, 0x28, 0x44, 0x4e, 0x10, 0x52, 0x3d, 0x2f, 0x3b,
+	0xed, 0x76, 0x66, 0x11, 0x66, 0x4f, 0x84, 0x10, 0x15, 0x69, 0x3c, 0x82, 0x82, 0xdc, 0xe3, 0x75,
+	0x58, 0xee, 0xb6, 0x89, 0xd5, 0x7b, 0xfa, 0x8c, 0xec, 0xb5, 0xad, 0x4e, 0xab, 0x7d, 0x7c, 0xd2,
+	0x39, 0xf9, 0x78, 0x69, 0x06, 0xaf, 0x01, 0x9e, 0x02, 0x1a, 0xad, 0x16, 0x69, 0xf7, 0x7a, 0x4b,
+	0xc8, 0xf8, 0x49, 0x83, 0xf9, 0x29, 0x57, 0x71, 0x1b, 0x66, 0x95, 0xaf, 0xb2, 0xf8, 0xff, 0x72,
+	0x7d, 0xf1, 0x3b, 0xf2, 0x35, 0xd2, 0x96, 0x10, 0x89, 0xc9, 0xf8, 0x00, 0x6e, 0x0c, 0xdc, 0x48,
+	0xde, 0xa2, 0x65, 0x3b, 0xc2, 0x84, 0x78, 0x6e, 0x18, 0x19, 0x57, 0x69, 0x1e, 0xc5, 0xa1, 0x0d,
+	0x19, 0x49, 0x16, 0x07, 0xa9, 0xbd, 0xb0, 0x69, 0x64, 0x7b, 0x43, 0x2a, 0x6d, 0x2a, 0x11, 0xb5,
+	0x11, 0xc3, 0x38, 0xa2, 0x0e, 0xa3, 0x5c, 0xfa, 0x54, 0x24, 0xf1, 0xce, 0xf0, 0x61, 0xf1, 0xe8,
+	0x9f, 0xfc, 0xa5, 0x27, 0x8d, 0xce, 0xa1, 0xf5, 0xf4, 0xd8, 0x3a, 0xea, 0xf4, 0x8e, 0x1a, 0x27,
+	0x7b, 0xfb, 0x4b, 0x33, 0x58, 0x87, 0x95, 0xbd, 0xa7, 0xc7, 0x27, 0x9d, 0xe3, 0x67, 0xed, 0x14,
+	0x82, 0xf0, 0x32, 0xdc, 0x68, 0xb4, 0x5a, 0xa9, 0x43, 0x4d, 0x38, 0x4b, 0xda, 0xdd, 0xc3, 0xc6,
+	0x5e, 0x3a, 0x3a, 0x67, 0x9c, 0xc1, 0xfa, 0x15, 0x9d, 0x8d, 0x0f, 0xd2, 0xf3, 0x40, 0x35, 0xc4,
+	0xe6, 0xf5, 0xf3, 0x20, 0xd5, 0x72, 0x53, 0x83, 0xc1, 0xf8, 0x05, 0xc1, 0x5a, 0x36, 0x05, 0xaf,
+	0xc3, 0x9c, 0x1d, 0xba, 0xd6, 0x05, 0xbd, 0x94, 0xb7, 0x56, 0x20, 0xb3, 0x76, 0xe8, 0x1e, 0x50,
+	0x31, 0xd8, 0xe7, 0x05, 0x30, 0xa2, 0x2c, 0x4a, 0xae, 0xa0, 0x40, 0xc0, 0x0e, 0xdd, 0x8f, 0xd4,
+	0x89, 0x98, 0xc8, 0x3c, 0x08, 0x5d, 0x47, 0x59, 0xdb, 0xbc, 0x2b, 0x9e, 0xad, 0xb3, 0x35, 0xfd,
+	0x35, 0xaa, 0xdd, 0x7c, 0xf1, 0x89, 0xbd, 0xf3, 0x45, 0x63, 0xe7, 0xf9, 0x83, 0x9d, 0xc7, 0xa6,
+	0xb5, 0xf3, 0xe9, 0xf6, 0xbb, 0x44, 0xc5, 0xe2, 0xdb, 0x50, 0x72, 0x3c, 0x97, 0xfa, 0xdc, 0x72,
+	0xfb, 0xd2, 0xfc, 0x12, 0x29, 0xaa, 0x83, 0x4e, 0x1f, 0xdf, 0x81, 0x52, 0xc8, 0x5c, 0xdf, 0x71,
+	0x43, 0xdb, 0xd3, 0x0b, 0x12, 0x9c, 0x1c, 0x18, 0xcf, 0x61, 0x25, 0x6b, 0x8a, 0xe1, 0xe6, 0xd4,
+	0xd4, 0x53, 0x36, 0xdd, 0xbe, 0x66, 0xea, 0xa5, 0x3c, 0x4a, 0xc6, 0x9f, 0xf1, 0x15, 0x82, 0xe5,
+	0x8c, 0x60, 0xfc, 0x18, 0xf2, 0x42, 0x38, 0xd6, 0xbd, 0x77, 0x8d, 0xae, 0x29, 0x7e, 0xda, 0x3e,
+	0x67, 0x97, 0x44, 0x52, 0x36, 0xea, 0x50, 0x1a, 0x1f, 0xe1, 0x25, 0xc8, 0x25, 0x0e, 0x97, 0x88,
+	0x58, 0x4e, 0x5e, 0x4c, 0x6d, 0xea, 0xc5, 0xdc, 0xd5, 0x1e, 0xa1, 0xda, 0x97, 0x1a, 0xdc, 0x4d,
+	0xc9, 0xb7, 0x92, 0xef, 0x9c, 0x1e, 0x65, 0x23, 0xd7, 0xa1, 0xf8, 0x05, 0xac, 0xf6, 0x38, 0xa3,
+	0xf6, 0x60, 0x3a, 0x4c, 0xfc, 0x41, 0x6d, 0xa6, 0x3b, 0x6e, 0x4c, 0x24, 0x6a, 0xf4, 0x6c, 0x6c,
+	0x5d, 0x89, 0x47, 0x61, 0xe0, 0x47, 0xd4, 0x98, 0xa9, 0xa0, 0x07, 0x08, 0xbf, 0x42, 0xb0, 0xf2,
+	0x84, 0x72, 0xe7, 0xfc, 0x7f, 0xd7, 0xbf, 0xff, 0xea, 0xb7, 0xdf, 0xbf, 0xd5, 0xde, 0x31, 0x36,
+	0x53, 0xdf, 0x6f, 0xbb, 0xbe, 0x7a, 0xce, 0xf8, 0xbf, 0x78, 0x17, 0x6d, 0x9f, 0xce, 0xca, 0x59,
+	0xf8, 0xf0, 0xef, 0x01, 0x00, 0x3f, 0xf5, 0x61, 0x5f, 0x30, 0x0a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
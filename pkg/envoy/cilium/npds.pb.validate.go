@@ -364,6 +364,31 @@ func (m *HttpNetworkPolicyRule) Validate() error {
 
 	}
 
+	for idx, item := range m.GetHeaderMatches() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return HttpNetworkPolicyRuleValidationError{
+					Field:  fmt.Sprintf("HeaderMatches[%v]", idx),
+					Reason: "embedded message failed validation",
+					Cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if v, ok := interface{}(m.GetRateLimit()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return HttpNetworkPolicyRuleValidationError{
+				Field:  "RateLimit",
+				Reason: "embedded message failed validation",
+				Cause:  err,
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -398,6 +423,152 @@ func (e HttpNetworkPolicyRuleValidationError) Error() string {
 
 var _ error = HttpNetworkPolicyRuleValidationError{}
 
+// Validate checks the field values on HttpRateLimit with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *HttpRateLimit) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if m.GetRequests() <= 0 {
+		return HttpRateLimitValidationError{
+			Field:  "Requests",
+			Reason: "value must be greater than 0",
+		}
+	}
+
+	if m.GetInterval() == nil {
+		return HttpRateLimitValidationError{
+			Field:  "Interval",
+			Reason: "value is required",
+		}
+	}
+
+	if d := m.GetInterval(); d != nil {
+		dur, err := ptypes.Duration(d)
+		if err != nil {
+			return HttpRateLimitValidationError{
+				Field:  "Interval",
+				Reason: "value is not a valid duration",
+				Cause:  err,
+			}
+		}
+
+		gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+		if dur <= gt {
+			return HttpRateLimitValidationError{
+				Field:  "Interval",
+				Reason: "value must be greater than 0s",
+			}
+		}
+
+	}
+
+	// no validation rules for Burst
+
+	// no validation rules for Scope
+
+	return nil
+}
+
+// HttpRateLimitValidationError is the validation error returned by
+// HttpRateLimit.Validate if the designated constraints aren't met.
+type HttpRateLimitValidationError struct {
+	Field  string
+	Reason string
+	Cause  error
+	Key    bool
+}
+
+// Error satisfies the builtin error interface
+func (e HttpRateLimitValidationError) Error() string {
+	cause := ""
+	if e.Cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.Cause)
+	}
+
+	key := ""
+	if e.Key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sHttpRateLimit.%s: %s%s",
+		key,
+		e.Field,
+		e.Reason,
+		cause)
+}
+
+var _ error = HttpRateLimitValidationError{}
+
+// Validate checks the field values on HeaderMatch with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *HeaderMatch) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if m.GetHeader() == nil {
+		return HeaderMatchValidationError{
+			Field:  "Header",
+			Reason: "value is required",
+		}
+	}
+
+	if v, ok := interface{}(m.GetHeader()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return HeaderMatchValidationError{
+				Field:  "Header",
+				Reason: "embedded message failed validation",
+				Cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for MismatchAction
+
+	// no validation rules for Value
+
+	// no validation rules for Secret
+
+	return nil
+}
+
+// HeaderMatchValidationError is the validation error returned by
+// HeaderMatch.Validate if the designated constraints aren't met.
+type HeaderMatchValidationError struct {
+	Field  string
+	Reason string
+	Cause  error
+	Key    bool
+}
+
+// Error satisfies the builtin error interface
+func (e HeaderMatchValidationError) Error() string {
+	cause := ""
+	if e.Cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.Cause)
+	}
+
+	key := ""
+	if e.Key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sHeaderMatch.%s: %s%s",
+		key,
+		e.Field,
+		e.Reason,
+		cause)
+}
+
+var _ error = HeaderMatchValidationError{}
+
 // Validate checks the field values on KafkaNetworkPolicyRules with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, an error is returned.
@@ -488,12 +659,9 @@ func (m *KafkaNetworkPolicyRule) Validate() error {
 		}
 	}
 
-	if !_KafkaNetworkPolicyRule_ClientId_Pattern.MatchString(m.GetClientId()) {
-		return KafkaNetworkPolicyRuleValidationError{
-			Field:  "ClientId",
-			Reason: "value does not match regex pattern \"^[a-zA-Z0-9._-]*$\"",
-		}
-	}
+	// no validation rules for ClientId
+
+	// no validation rules for Principal
 
 	return nil
 }
@@ -531,8 +699,6 @@ var _ error = KafkaNetworkPolicyRuleValidationError{}
 
 var _KafkaNetworkPolicyRule_Topic_Pattern = regexp.MustCompile("^[a-zA-Z0-9._-]*$")
 
-var _KafkaNetworkPolicyRule_ClientId_Pattern = regexp.MustCompile("^[a-zA-Z0-9._-]*$")
-
 // Validate checks the field values on L7NetworkPolicyRules with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, an error is returned.
@@ -21,6 +21,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -34,15 +35,19 @@ import (
 	envoy_config_bootstrap_v2 "github.com/cilium/cilium/pkg/envoy/envoy/config/bootstrap/v2"
 	"github.com/cilium/cilium/pkg/envoy/xds"
 	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/k8s"
 	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/policy/api"
 	"github.com/cilium/cilium/pkg/proxy/logger"
 
 	"github.com/gogo/protobuf/sortkeys"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/struct"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
@@ -333,7 +338,78 @@ func getL7Rule(l7 *api.PortRuleL7) *cilium.L7NetworkPolicyRule {
 	return rule // No ruleRef
 }
 
-func getHTTPRule(h *api.PortRuleHTTP) (headers []*envoy_api_v2_route.HeaderMatcher, ruleRef string) {
+// getSecretValue returns the value of a Kubernetes secret referred to by an
+// HTTP header match from the cache of the secret watcher. The policy is
+// regenerated when the secret changes.
+var getSecretValue = k8s.GetSecretValue
+
+// getHeaderMatch translates an HTTP header match of a policy into a header
+// match of the Envoy policy. Matches on a secret that cannot be read never
+// match, regardless of the mismatch action.
+func getHeaderMatch(hm *api.HeaderMatch) (matches []*cilium.HeaderMatch, ruleRef string) {
+	value := hm.Value
+	if hm.Secret != nil {
+		var err error
+		value, err = getSecretValue(hm.Secret.Namespace, hm.Secret.Name, hm.Secret.Key)
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{
+				logfields.K8sNamespace: hm.Secret.Namespace,
+				"secret":               hm.Secret.Name,
+			}).Warning("Unable to get secret for HTTP header match, denying all requests matched against it")
+			// The header must be both present and absent.
+			matches = []*cilium.HeaderMatch{
+				{Header: &envoy_api_v2_route.HeaderMatcher{Name: hm.Name,
+					HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_PresentMatch{PresentMatch: true}},
+					Secret: true},
+				{Header: &envoy_api_v2_route.HeaderMatcher{Name: hm.Name,
+					HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_PresentMatch{PresentMatch: true},
+					InvertMatch:          true},
+					Secret: true},
+			}
+			return matches, `HeaderSecretUnavailable("` + hm.Name + `")`
+		}
+	}
+
+	header := &envoy_api_v2_route.HeaderMatcher{Name: hm.Name}
+	switch {
+	case hm.Secret != nil:
+		header.HeaderMatchSpecifier = &envoy_api_v2_route.HeaderMatcher_ExactMatch{ExactMatch: value}
+		ruleRef = `HeaderSecret("` + hm.Name + `","` + hm.Secret.Namespace + "/" + hm.Secret.Name + `")`
+	case value != "":
+		header.HeaderMatchSpecifier = &envoy_api_v2_route.HeaderMatcher_ExactMatch{ExactMatch: value}
+		ruleRef = `Header("` + hm.Name + `","` + value + `")`
+	case hm.Regex != "":
+		header.HeaderMatchSpecifier = &envoy_api_v2_route.HeaderMatcher_RegexMatch{RegexMatch: hm.Regex}
+		ruleRef = `HeaderRegexp("` + hm.Name + `","` + hm.Regex + `")`
+	case hm.Absent:
+		header.HeaderMatchSpecifier = &envoy_api_v2_route.HeaderMatcher_PresentMatch{PresentMatch: true}
+		header.InvertMatch = true
+		ruleRef = `HeaderAbsent("` + hm.Name + `")`
+	default:
+		header.HeaderMatchSpecifier = &envoy_api_v2_route.HeaderMatcher_PresentMatch{PresentMatch: true}
+		ruleRef = `Header("` + hm.Name + `")`
+	}
+
+	match := &cilium.HeaderMatch{Header: header, Secret: hm.Secret != nil}
+	switch hm.Mismatch {
+	case api.MismatchActionLog:
+		match.MismatchAction = cilium.HeaderMatch_CONTINUE_ON_MISMATCH
+	case api.MismatchActionAdd:
+		match.MismatchAction = cilium.HeaderMatch_ADD_ON_MISMATCH
+		match.Value = value
+	case api.MismatchActionReplace:
+		match.MismatchAction = cilium.HeaderMatch_REPLACE_ON_MISMATCH
+		match.Value = value
+	default:
+		match.MismatchAction = cilium.HeaderMatch_FAIL_ON_MISMATCH
+	}
+	if match.MismatchAction != cilium.HeaderMatch_FAIL_ON_MISMATCH {
+		ruleRef += "/" + string(hm.Mismatch)
+	}
+	return []*cilium.HeaderMatch{match}, ruleRef
+}
+
+func getHTTPRule(h *api.PortRuleHTTP) (headers []*envoy_api_v2_route.HeaderMatcher, headerMatches []*cilium.HeaderMatch, ruleRef string) {
 	// Count the number of header matches we need
 	cnt := len(h.Headers)
 	if h.Path != "" {
@@ -395,9 +471,92 @@ func getHTTPRule(h *api.PortRuleHTTP) (headers []*envoy_api_v2_route.HeaderMatch
 	} else {
 		SortHeaderMatchers(headers)
 	}
+
+	// Header matches are kept in the order of the policy, as they may
+	// modify the request.
+	for i := range h.HeaderMatches {
+		matches, ref := getHeaderMatch(&h.HeaderMatches[i])
+		headerMatches = append(headerMatches, matches...)
+		if ruleRef != "" {
+			ruleRef += " && "
+		}
+		ruleRef += ref
+	}
 	return
 }
 
+// getHTTPRateLimit translates the rate limit of an HTTP rule into the rate
+// limit of the Envoy policy, or nil if the rule has no rate limit.
+func getHTTPRateLimit(rl *api.RateLimit) *cilium.HttpRateLimit {
+	if rl == nil {
+		return nil
+	}
+	limit := &cilium.HttpRateLimit{
+		Requests: rl.Requests,
+		Interval: ptypes.DurationProto(rl.GetInterval()),
+		Burst:    rl.Burst,
+	}
+	if rl.Per == api.RateLimitPerEndpoint {
+		limit.Scope = cilium.HttpRateLimit_PER_SOURCE_ADDRESS
+	}
+	return limit
+}
+
+// grpcContentTypePrefix is the prefix of the content-type header of all gRPC
+// requests, e.g. "application/grpc" or "application/grpc+proto".
+const grpcContentTypePrefix = "application/grpc"
+
+// getGRPCRule translates a gRPC rule into the HTTP/2 header matches of the
+// equivalent HTTP rule. gRPC calls are POST requests to
+// "/<service>/<method>", and an empty service or method matches any.
+func getGRPCRule(g *api.PortRuleGRPC) (headers []*envoy_api_v2_route.HeaderMatcher, ruleRef string) {
+	service, method := "[^/]+", "[^/]+"
+	if g.Service != "" {
+		service = regexp.QuoteMeta(g.Service)
+	}
+	if g.Method != "" {
+		method = regexp.QuoteMeta(g.Method)
+	}
+
+	headers, _, ruleRef = getHTTPRule(&api.PortRuleHTTP{
+		Path:    "/" + service + "/" + method,
+		Method:  "POST",
+		Headers: g.Headers,
+	})
+	headers = append(headers, &envoy_api_v2_route.HeaderMatcher{Name: "content-type",
+		HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_PrefixMatch{PrefixMatch: grpcContentTypePrefix}})
+	ruleRef += ` && HeaderPrefix("content-type","` + grpcContentTypePrefix + `")`
+	SortHeaderMatchers(headers)
+	return
+}
+
+// getKafkaRules translates a Kafka rule into one Kafka network policy rule
+// per API key allowed by its APIKey or Role. A negative API key or version
+// matches any.
+func getKafkaRules(k *api.PortRuleKafka) []*cilium.KafkaNetworkPolicyRule {
+	apiVersion := int32(-1)
+	if version, isWildcard := k.GetAPIVersion(); !isWildcard {
+		apiVersion = int32(version)
+	}
+
+	apiKeys := k.GetAPIKeys()
+	if len(apiKeys) == 0 {
+		apiKeys = []int16{-1}
+	}
+
+	rules := make([]*cilium.KafkaNetworkPolicyRule, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		rules = append(rules, &cilium.KafkaNetworkPolicyRule{
+			ApiKey:     int32(apiKey),
+			ApiVersion: apiVersion,
+			Topic:      k.Topic,
+			ClientId:   k.ClientID,
+			Principal:  k.Principal,
+		})
+	}
+	return rules
+}
+
 func createBootstrap(filePath string, name, cluster, version string, xdsSock, egressClusterName, ingressClusterName string, adminPath string) {
 	bs := &envoy_config_bootstrap_v2.Bootstrap{
 		Node: &envoy_api_v2_core.Node{Id: name, Cluster: cluster, Metadata: nil, Locality: nil, BuildVersion: version},
@@ -500,10 +659,20 @@ func getPortNetworkPolicyRule(sel api.EndpointSelector, l7Parser policy.L7Parser
 
 	switch l7Parser {
 	case policy.ParserTypeHTTP:
-		if len(l7Rules.HTTP) > 0 { // Just cautious. This should never be false.
-			httpRules := make([]*cilium.HttpNetworkPolicyRule, 0, len(l7Rules.HTTP))
+		// HTTP and gRPC rules may be mixed when merged from several policy
+		// rules, as both are enforced by the HTTP proxy.
+		if len(l7Rules.HTTP)+len(l7Rules.GRPC) > 0 { // Just cautious. This should never be false.
+			httpRules := make([]*cilium.HttpNetworkPolicyRule, 0, len(l7Rules.HTTP)+len(l7Rules.GRPC))
 			for _, l7 := range l7Rules.HTTP {
-				headers, _ := getHTTPRule(&l7)
+				headers, headerMatches, _ := getHTTPRule(&l7)
+				httpRules = append(httpRules, &cilium.HttpNetworkPolicyRule{
+					Headers:       headers,
+					HeaderMatches: headerMatches,
+					RateLimit:     getHTTPRateLimit(l7.RateLimit),
+				})
+			}
+			for _, l7 := range l7Rules.GRPC {
+				headers, _ := getGRPCRule(&l7)
 				httpRules = append(httpRules, &cilium.HttpNetworkPolicyRule{Headers: headers})
 			}
 			SortHTTPNetworkPolicyRules(httpRules)
@@ -514,7 +683,17 @@ func getPortNetworkPolicyRule(sel api.EndpointSelector, l7Parser policy.L7Parser
 			}
 		}
 	case policy.ParserTypeKafka:
-		// TODO: Support Kafka. For now, just ignore any Kafka L7 rule.
+		if len(l7Rules.Kafka) > 0 {
+			kafkaRules := make([]*cilium.KafkaNetworkPolicyRule, 0, len(l7Rules.Kafka))
+			for _, l7 := range l7Rules.Kafka {
+				kafkaRules = append(kafkaRules, getKafkaRules(&l7)...)
+			}
+			r.L7 = &cilium.PortNetworkPolicyRule_KafkaRules{
+				KafkaRules: &cilium.KafkaNetworkPolicyRules{
+					KafkaRules: kafkaRules,
+				},
+			}
+		}
 
 	default:
 		// Assume unknown parser types use a Key-Value Pair policy
@@ -17,6 +17,8 @@
 package envoy
 
 import (
+	"fmt"
+
 	"github.com/cilium/cilium/pkg/checker"
 	"github.com/cilium/cilium/pkg/envoy/cilium"
 	envoy_api_v2_core "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/core"
@@ -26,6 +28,7 @@ import (
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/policy/api"
 
+	"github.com/golang/protobuf/ptypes/duration"
 	. "gopkg.in/check.v1"
 )
 
@@ -326,10 +329,154 @@ var L4Policy2 = &policy.L4Policy{
 }
 
 func (s *ServerSuite) TestGetHTTPRule(c *C) {
-	obtained, _ := getHTTPRule(PortRuleHTTP1)
+	obtained, _, _ := getHTTPRule(PortRuleHTTP1)
 	c.Assert(obtained, checker.DeepEquals, ExpectedHeaders1)
 }
 
+func (s *ServerSuite) TestGetHTTPRuleHeaderMatches(c *C) {
+	oldGetSecretValue := getSecretValue
+	defer func() { getSecretValue = oldGetSecretValue }()
+	getSecretValue = func(namespace, name, key string) (string, error) {
+		if namespace == "default" && name == "api-key" {
+			return "secret", nil
+		}
+		return "", fmt.Errorf("secret %s/%s not found", namespace, name)
+	}
+
+	headers, headerMatches, _ := getHTTPRule(&api.PortRuleHTTP{
+		Path: "/foo",
+		HeaderMatches: []api.HeaderMatch{
+			{Name: "x-api-key", Secret: &api.Secret{Namespace: "default", Name: "api-key"}},
+			{Name: "x-debug", Absent: true, Mismatch: api.MismatchActionLog},
+			{Name: "x-version", Regex: "v[12]"},
+			{Name: "x-tenant", Value: "cilium", Mismatch: api.MismatchActionReplace},
+		},
+	})
+	c.Assert(headers, checker.DeepEquals, []*envoy_api_v2_route.HeaderMatcher{
+		{
+			Name:                 ":path",
+			HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_RegexMatch{RegexMatch: "/foo"},
+		},
+	})
+	c.Assert(headerMatches, checker.DeepEquals, []*cilium.HeaderMatch{
+		{
+			Header: &envoy_api_v2_route.HeaderMatcher{Name: "x-api-key",
+				HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_ExactMatch{ExactMatch: "secret"}},
+			Secret: true,
+		},
+		{
+			Header: &envoy_api_v2_route.HeaderMatcher{Name: "x-debug",
+				HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_PresentMatch{PresentMatch: true},
+				InvertMatch:          true},
+			MismatchAction: cilium.HeaderMatch_CONTINUE_ON_MISMATCH,
+		},
+		{
+			Header: &envoy_api_v2_route.HeaderMatcher{Name: "x-version",
+				HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_RegexMatch{RegexMatch: "v[12]"}},
+		},
+		{
+			Header: &envoy_api_v2_route.HeaderMatcher{Name: "x-tenant",
+				HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_ExactMatch{ExactMatch: "cilium"}},
+			MismatchAction: cilium.HeaderMatch_REPLACE_ON_MISMATCH,
+			Value:          "cilium",
+		},
+	})
+
+	// A secret that cannot be read never matches, even if the mismatch
+	// action would not deny the request.
+	_, headerMatches, _ = getHTTPRule(&api.PortRuleHTTP{
+		HeaderMatches: []api.HeaderMatch{
+			{Name: "x-api-key", Secret: &api.Secret{Namespace: "default", Name: "missing"}, Mismatch: api.MismatchActionAdd},
+		},
+	})
+	c.Assert(headerMatches, checker.DeepEquals, []*cilium.HeaderMatch{
+		{
+			Header: &envoy_api_v2_route.HeaderMatcher{Name: "x-api-key",
+				HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_PresentMatch{PresentMatch: true}},
+			Secret: true,
+		},
+		{
+			Header: &envoy_api_v2_route.HeaderMatcher{Name: "x-api-key",
+				HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_PresentMatch{PresentMatch: true},
+				InvertMatch:          true},
+			Secret: true,
+		},
+	})
+}
+
+func (s *ServerSuite) TestGetHTTPRateLimit(c *C) {
+	c.Assert(getHTTPRateLimit(nil), IsNil)
+	c.Assert(getHTTPRateLimit(&api.RateLimit{Requests: 100}), checker.DeepEquals, &cilium.HttpRateLimit{
+		Requests: 100,
+		Interval: &duration.Duration{Seconds: 1},
+	})
+	c.Assert(getHTTPRateLimit(&api.RateLimit{Requests: 10, Interval: "1m30s", Burst: 20, Per: api.RateLimitPerEndpoint}),
+		checker.DeepEquals, &cilium.HttpRateLimit{
+			Requests: 10,
+			Interval: &duration.Duration{Seconds: 90},
+			Burst:    20,
+			Scope:    cilium.HttpRateLimit_PER_SOURCE_ADDRESS,
+		})
+	c.Assert(getHTTPRateLimit(&api.RateLimit{Requests: 1, Interval: "500ms", Per: api.RateLimitPerIdentity}),
+		checker.DeepEquals, &cilium.HttpRateLimit{
+			Requests: 1,
+			Interval: &duration.Duration{Nanos: 500000000},
+			Scope:    cilium.HttpRateLimit_PER_SOURCE_IDENTITY,
+		})
+}
+
+func (s *ServerSuite) TestGetGRPCRule(c *C) {
+	obtained, _ := getGRPCRule(&api.PortRuleGRPC{
+		Service: "helloworld.Greeter",
+		Headers: []string{"x-token: secret"},
+	})
+	c.Assert(obtained, checker.DeepEquals, []*envoy_api_v2_route.HeaderMatcher{
+		{
+			Name:                 ":method",
+			HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_RegexMatch{RegexMatch: "POST"},
+		},
+		{
+			Name:                 ":path",
+			HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_RegexMatch{RegexMatch: `/helloworld\.Greeter/[^/]+`},
+		},
+		{
+			Name:                 "content-type",
+			HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_PrefixMatch{PrefixMatch: "application/grpc"},
+		},
+		{
+			Name:                 "x-token",
+			HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_ExactMatch{ExactMatch: "secret"},
+		},
+	})
+}
+
+func (s *ServerSuite) TestGetKafkaRules(c *C) {
+	rule := api.PortRuleKafka{Role: "produce", Topic: "foo", Principal: "alice"}
+	c.Assert(rule.Sanitize(), IsNil)
+	obtained := getKafkaRules(&rule)
+	c.Assert(len(obtained), Equals, len(rule.GetAPIKeys()))
+	for i, apiKey := range rule.GetAPIKeys() {
+		c.Assert(obtained[i], checker.DeepEquals, &cilium.KafkaNetworkPolicyRule{
+			ApiKey:     int32(apiKey),
+			ApiVersion: -1,
+			Topic:      "foo",
+			Principal:  "alice",
+		})
+	}
+
+	rule = api.PortRuleKafka{APIVersion: "2", ClientID: "client"}
+	c.Assert(rule.Sanitize(), IsNil)
+	c.Assert(getKafkaRules(&rule), checker.DeepEquals, []*cilium.KafkaNetworkPolicyRule{
+		{ApiKey: -1, ApiVersion: 2, ClientId: "client"},
+	})
+
+	rule = api.PortRuleKafka{APIKey: "produce"}
+	c.Assert(rule.Sanitize(), IsNil)
+	c.Assert(getKafkaRules(&rule), checker.DeepEquals, []*cilium.KafkaNetworkPolicyRule{
+		{ApiKey: int32(api.ProduceKey), ApiVersion: -1},
+	})
+}
+
 func (s *ServerSuite) TestGetPortNetworkPolicyRule(c *C) {
 	obtained := getPortNetworkPolicyRule(EndpointSelector1, policy.ParserTypeHTTP, L7Rules1,
 		IdentityCache, DeniedIdentitiesNone)
@@ -179,8 +179,84 @@ func HTTPNetworkPolicyRuleLess(r1, r2 *cilium.HttpNetworkPolicyRule) bool {
 		}
 	}
 
-	// Elements are equal.
-	return false
+	matches1, matches2 := r1.HeaderMatches, r2.HeaderMatches
+	switch {
+	case len(matches1) < len(matches2):
+		return true
+	case len(matches1) > len(matches2):
+		return false
+	}
+	// Header matches are ordered, compare them in order.
+	for idx := range matches1 {
+		match1, match2 := matches1[idx], matches2[idx]
+		switch {
+		case HeaderMatchLess(match1, match2):
+			return true
+		case HeaderMatchLess(match2, match1):
+			return false
+		}
+	}
+
+	limit1, limit2 := r1.RateLimit, r2.RateLimit
+	switch {
+	case limit1 == nil && limit2 != nil:
+		return true
+	case limit1 == nil || limit2 == nil:
+		return false
+	}
+	return HTTPRateLimitLess(limit1, limit2)
+}
+
+// HTTPRateLimitLess reports whether the l1 rate limit should sort before the
+// l2 rate limit.
+func HTTPRateLimitLess(l1, l2 *cilium.HttpRateLimit) bool {
+	switch {
+	case l1.Requests < l2.Requests:
+		return true
+	case l1.Requests > l2.Requests:
+		return false
+	}
+
+	interval1, interval2 := l1.GetInterval(), l2.GetInterval()
+	switch {
+	case interval1.GetSeconds() < interval2.GetSeconds():
+		return true
+	case interval1.GetSeconds() > interval2.GetSeconds():
+		return false
+	case interval1.GetNanos() < interval2.GetNanos():
+		return true
+	case interval1.GetNanos() > interval2.GetNanos():
+		return false
+	}
+
+	switch {
+	case l1.Burst < l2.Burst:
+		return true
+	case l1.Burst > l2.Burst:
+		return false
+	}
+
+	return l1.Scope < l2.Scope
+}
+
+// HeaderMatchLess reports whether the m1 header match should sort before the
+// m2 header match.
+func HeaderMatchLess(m1, m2 *cilium.HeaderMatch) bool {
+	switch {
+	case HeaderMatcherLess(m1.Header, m2.Header):
+		return true
+	case HeaderMatcherLess(m2.Header, m1.Header):
+		return false
+	}
+
+	switch {
+	case m1.MismatchAction < m2.MismatchAction:
+		return true
+	case m1.MismatchAction > m2.MismatchAction:
+		return false
+	}
+
+	return m1.Value < m2.Value
 }
 
 func (s HTTPNetworkPolicyRuleSlice) Len() int {
@@ -21,6 +21,7 @@ import (
 	envoy_api_v2_core "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/core"
 	envoy_api_v2_route "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/route"
 
+	"github.com/golang/protobuf/ptypes/duration"
 	. "gopkg.in/check.v1"
 )
 
@@ -100,6 +101,61 @@ func (s *SortSuite) TestSortHttpNetworkPolicyRules(c *C) {
 	c.Assert(slice, DeepEquals, expected)
 }
 
+func (s *SortSuite) TestSortHttpNetworkPolicyRulesHeaderMatches(c *C) {
+	rule1 := &cilium.HttpNetworkPolicyRule{
+		Headers: []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1},
+	}
+	rule2 := &cilium.HttpNetworkPolicyRule{
+		Headers: []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1},
+		HeaderMatches: []*cilium.HeaderMatch{
+			{Header: HeaderMatcher2, MismatchAction: cilium.HeaderMatch_CONTINUE_ON_MISMATCH},
+		},
+	}
+	rule3 := &cilium.HttpNetworkPolicyRule{
+		Headers: []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1},
+		HeaderMatches: []*cilium.HeaderMatch{
+			{Header: HeaderMatcher2, MismatchAction: cilium.HeaderMatch_ADD_ON_MISMATCH, Value: "a"},
+		},
+	}
+	rule4 := &cilium.HttpNetworkPolicyRule{
+		Headers: []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1},
+		HeaderMatches: []*cilium.HeaderMatch{
+			{Header: HeaderMatcher2, MismatchAction: cilium.HeaderMatch_ADD_ON_MISMATCH, Value: "b"},
+		},
+	}
+
+	slice := []*cilium.HttpNetworkPolicyRule{rule4, rule3, rule2, rule1}
+	SortHTTPNetworkPolicyRules(slice)
+	c.Assert(slice, DeepEquals, []*cilium.HttpNetworkPolicyRule{rule1, rule2, rule3, rule4})
+}
+
+func (s *SortSuite) TestSortHttpNetworkPolicyRulesRateLimit(c *C) {
+	rule1 := &cilium.HttpNetworkPolicyRule{
+		Headers: []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1},
+	}
+	rule2 := &cilium.HttpNetworkPolicyRule{
+		Headers:   []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1},
+		RateLimit: &cilium.HttpRateLimit{Requests: 10, Interval: &duration.Duration{Seconds: 1}},
+	}
+	rule3 := &cilium.HttpNetworkPolicyRule{
+		Headers:   []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1},
+		RateLimit: &cilium.HttpRateLimit{Requests: 10, Interval: &duration.Duration{Seconds: 60}},
+	}
+	rule4 := &cilium.HttpNetworkPolicyRule{
+		Headers: []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1},
+		RateLimit: &cilium.HttpRateLimit{Requests: 10, Interval: &duration.Duration{Seconds: 60},
+			Scope: cilium.HttpRateLimit_PER_SOURCE_ADDRESS},
+	}
+	rule5 := &cilium.HttpNetworkPolicyRule{
+		Headers:   []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1},
+		RateLimit: &cilium.HttpRateLimit{Requests: 100, Interval: &duration.Duration{Seconds: 1}},
+	}
+
+	slice := []*cilium.HttpNetworkPolicyRule{rule5, rule4, rule3, rule2, rule1}
+	SortHTTPNetworkPolicyRules(slice)
+	c.Assert(slice, DeepEquals, []*cilium.HttpNetworkPolicyRule{rule1, rule2, rule3, rule4, rule5})
+}
+
 var PortNetworkPolicyRule1 = &cilium.PortNetworkPolicyRule{
 	RemotePolicies: nil,
 	L7:             nil,
@@ -0,0 +1,252 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+)
+
+// ErrUnsupportedType is returned when decoding a monitor event which does not
+// describe a flow
+var ErrUnsupportedType = errors.New("monitor event does not describe a flow")
+
+// Decode decodes the data of a monitor event sample into a flow. Events which
+// do not carry a timestamp are assumed to be observed at ts.
+func Decode(data []byte, ts time.Time) (*Flow, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty monitor event")
+	}
+
+	switch data[0] {
+	case monitor.MessageTypeDrop:
+		return decodeDrop(data, ts)
+	case monitor.MessageTypeTrace:
+		return decodeTrace(data, ts)
+	case monitor.MessageTypePolicyVerdict:
+		return decodePolicyVerdict(data, ts)
+	case monitor.MessageTypeAccessLog:
+		return decodeLogRecord(data, ts)
+	default:
+		return nil, ErrUnsupportedType
+	}
+}
+
+// applyPacket fills the flow with the addressing information and summary of
+// the packet data captured by a datapath notification
+func (f *Flow) applyPacket(data []byte) {
+	tuple := monitor.GetConnectionTuple(data)
+	f.Protocol = tuple.Protocol
+	f.Source.IP, f.Source.Port = tuple.SrcIP, tuple.SrcPort
+	f.Destination.IP, f.Destination.Port = tuple.DstIP, tuple.DstPort
+	f.Summary = monitor.GetConnectionSummary(data)
+}
+
+func decodeDrop(data []byte, ts time.Time) (*Flow, error) {
+	dn := monitor.DropNotify{}
+	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dn); err != nil {
+		return nil, fmt.Errorf("unable to parse drop notification: %s", err)
+	}
+
+	f := &Flow{
+		Time:    ts,
+		Type:    monitor.MessageTypeName(monitor.MessageTypeDrop),
+		Verdict: VerdictDropped,
+		Reason:  monitor.DropReason(dn.SubType),
+		Source: Endpoint{
+			ID:       uint64(dn.Source),
+			Identity: uint64(dn.SrcLabel),
+		},
+		Destination: Endpoint{
+			ID:       uint64(dn.DstID),
+			Identity: uint64(dn.DstLabel),
+		},
+	}
+
+	if dn.CapLen > 0 && len(data) > monitor.DropNotifyLen {
+		f.applyPacket(data[monitor.DropNotifyLen:])
+	}
+
+	return f, nil
+}
+
+func decodeTrace(data []byte, ts time.Time) (*Flow, error) {
+	tn := monitor.TraceNotify{}
+	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &tn); err != nil {
+		return nil, fmt.Errorf("unable to parse trace notification: %s", err)
+	}
+
+	f := &Flow{
+		Time:             ts,
+		Type:             monitor.MessageTypeName(monitor.MessageTypeTrace),
+		Verdict:          VerdictForwarded,
+		Reason:           monitor.TraceReason(tn.Reason),
+		ObservationPoint: monitor.TraceObservationPoint(tn.ObsPoint),
+		Source: Endpoint{
+			ID:       uint64(tn.Source),
+			Identity: uint64(tn.SrcLabel),
+		},
+		Destination: Endpoint{
+			ID:       uint64(tn.DstID),
+			Identity: uint64(tn.DstLabel),
+		},
+	}
+
+	if tn.CapLen > 0 && len(data) > monitor.TraceNotifyLen {
+		f.applyPacket(data[monitor.TraceNotifyLen:])
+	}
+
+	return f, nil
+}
+
+func decodePolicyVerdict(data []byte, ts time.Time) (*Flow, error) {
+	pn := monitor.PolicyVerdictNotify{}
+	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &pn); err != nil {
+		return nil, fmt.Errorf("unable to parse policy verdict notification: %s", err)
+	}
+
+	f := &Flow{
+		Time:             ts,
+		Type:             monitor.MessageTypeName(monitor.MessageTypePolicyVerdict),
+		Verdict:          VerdictForwarded,
+		Reason:           monitor.PolicyMatchType(pn.MatchType),
+		ObservationPoint: pn.Direction(),
+		Source: Endpoint{
+			ID:       uint64(pn.SourceEndpoint()),
+			Identity: uint64(pn.SourceIdentity()),
+		},
+		Destination: Endpoint{
+			ID:       uint64(pn.DestinationEndpoint()),
+			Identity: uint64(pn.DestinationIdentity()),
+			Port:     pn.DstPort,
+		},
+	}
+
+	if pn.CapLen > 0 && len(data) > monitor.PolicyVerdictNotifyLen {
+		f.applyPacket(data[monitor.PolicyVerdictNotifyLen:])
+	}
+	if pn.ProxyPort != 0 {
+		redirect := fmt.Sprintf("redirected to proxy port %d", pn.ProxyPort)
+		if f.Summary != "" {
+			redirect = f.Summary + ", " + redirect
+		}
+		f.Summary = redirect
+	}
+
+	return f, nil
+}
+
+func logRecordEndpoint(ep accesslog.EndpointInfo, version accesslog.IPVersion) Endpoint {
+	ip := ep.IPv4
+	if version == accesslog.VersionIPV6 {
+		ip = ep.IPv6
+	}
+
+	return Endpoint{
+		ID:       ep.ID,
+		Identity: ep.Identity,
+		Labels:   ep.Labels,
+		IP:       net.ParseIP(ip),
+		Port:     ep.Port,
+	}
+}
+
+func logRecordVerdict(verdict accesslog.FlowVerdict) Verdict {
+	switch verdict {
+	case accesslog.VerdictForwarded:
+		return VerdictForwarded
+	case accesslog.VerdictDenied:
+		return VerdictDenied
+	case accesslog.VerdictRateLimited:
+		return VerdictRateLimited
+	default:
+		return VerdictError
+	}
+}
+
+// logRecordSummary returns the protocol and a summary of the L7 information
+// of an access log record
+func logRecordSummary(lr *accesslog.LogRecord) (string, string) {
+	switch {
+	case lr.HTTP != nil && lr.HTTP.GRPC != nil:
+		call := fmt.Sprintf("%s/%s", lr.HTTP.GRPC.Service, lr.HTTP.GRPC.Method)
+		if lr.HTTP.GRPC.Status == nil {
+			return "grpc", call
+		}
+		return "grpc", fmt.Sprintf("%s => %d", call, *lr.HTTP.GRPC.Status)
+
+	case lr.HTTP != nil:
+		url := ""
+		if lr.HTTP.URL != nil {
+			url = lr.HTTP.URL.String()
+		}
+		return "http", fmt.Sprintf("%s %s => %d", lr.HTTP.Method, url, lr.HTTP.Code)
+
+	case lr.Kafka != nil:
+		return "kafka", fmt.Sprintf("%s topic %s => %d", lr.Kafka.APIKey, lr.Kafka.Topic.Topic, lr.Kafka.ErrorCode)
+
+	case lr.L7 != nil:
+		fields := make([]string, 0, len(lr.L7.Fields))
+		for k, v := range lr.L7.Fields {
+			fields = append(fields, k+":"+v)
+		}
+		sort.Strings(fields)
+		return lr.L7.Proto, strings.Join(fields, " ")
+	}
+
+	return "unknown-l7", ""
+}
+
+func decodeLogRecord(data []byte, ts time.Time) (*Flow, error) {
+	lr := monitor.LogRecordNotify{}
+	if err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&lr); err != nil {
+		return nil, fmt.Errorf("unable to decode access log record: %s", err)
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, lr.Timestamp); err == nil {
+		ts = t
+	}
+
+	f := &Flow{
+		Time:             ts,
+		Type:             monitor.MessageTypeName(monitor.MessageTypeAccessLog),
+		Verdict:          logRecordVerdict(lr.Verdict),
+		Reason:           string(lr.Type),
+		ObservationPoint: string(lr.ObservationPoint),
+		Source:           logRecordEndpoint(lr.SourceEndpoint, lr.IPVersion),
+		Destination:      logRecordEndpoint(lr.DestinationEndpoint, lr.IPVersion),
+	}
+	f.Protocol, f.Summary = logRecordSummary(&lr.LogRecord)
+
+	switch {
+	case lr.HTTP != nil:
+		f.Code = lr.HTTP.Code
+	case lr.Kafka != nil:
+		f.Code = lr.Kafka.ErrorCode
+	}
+
+	return f, nil
+}
@@ -0,0 +1,225 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package flow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	. "gopkg.in/check.v1"
+)
+
+func testPacket(c *C) []byte {
+	buf := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{},
+		&layers.Ethernet{
+			SrcMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 6},
+			DstMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 7},
+			EthernetType: layers.EthernetTypeIPv4,
+		},
+		&layers.IPv4{
+			Version:  4,
+			IHL:      5,
+			TTL:      64,
+			Protocol: layers.IPProtocolTCP,
+			SrcIP:    net.ParseIP("10.0.0.1").To4(),
+			DstIP:    net.ParseIP("10.0.0.2").To4(),
+		},
+		&layers.TCP{SrcPort: 34567, DstPort: 80, SYN: true, DataOffset: 5},
+	)
+	c.Assert(err, IsNil)
+	return buf.Bytes()
+}
+
+func (s *FlowSuite) TestDecodeDrop(c *C) {
+	pkt := testPacket(c)
+	buf := &bytes.Buffer{}
+	err := binary.Write(buf, byteorder.Native, monitor.DropNotify{
+		Type:     monitor.MessageTypeDrop,
+		SubType:  133,
+		Source:   10,
+		CapLen:   uint32(len(pkt)),
+		SrcLabel: 100,
+		DstLabel: 200,
+		DstID:    20,
+	})
+	c.Assert(err, IsNil)
+	buf.Write(pkt)
+
+	f, err := Decode(buf.Bytes(), baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(f.Time, Equals, baseTime)
+	c.Assert(f.Type, Equals, "drop")
+	c.Assert(f.Verdict, Equals, VerdictDropped)
+	c.Assert(f.Reason, Equals, "Policy denied (L3)")
+	c.Assert(f.Protocol, Equals, "tcp")
+	c.Assert(f.Source.ID, Equals, uint64(10))
+	c.Assert(f.Source.Identity, Equals, uint64(100))
+	c.Assert(f.Source.IP.Equal(net.ParseIP("10.0.0.1")), Equals, true)
+	c.Assert(f.Source.Port, Equals, uint16(34567))
+	c.Assert(f.Destination.ID, Equals, uint64(20))
+	c.Assert(f.Destination.Identity, Equals, uint64(200))
+	c.Assert(f.Destination.IP.Equal(net.ParseIP("10.0.0.2")), Equals, true)
+	c.Assert(f.Destination.Port, Equals, uint16(80))
+	c.Assert(f.Summary, Equals, "10.0.0.1:34567 -> 10.0.0.2:80 tcp SYN")
+}
+
+func (s *FlowSuite) TestDecodeTrace(c *C) {
+	buf := &bytes.Buffer{}
+	err := binary.Write(buf, byteorder.Native, monitor.TraceNotify{
+		Type:     monitor.MessageTypeTrace,
+		ObsPoint: monitor.TraceToLxc,
+		Reason:   monitor.TraceReasonCtEstablished,
+		SrcLabel: 100,
+		DstLabel: 200,
+		DstID:    20,
+	})
+	c.Assert(err, IsNil)
+
+	f, err := Decode(buf.Bytes(), baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(f.Type, Equals, "trace")
+	c.Assert(f.Verdict, Equals, VerdictForwarded)
+	c.Assert(f.Reason, Equals, "established")
+	c.Assert(f.ObservationPoint, Equals, "to-endpoint")
+	c.Assert(f.Destination.ID, Equals, uint64(20))
+	c.Assert(f.Source.IP, IsNil)
+}
+
+func (s *FlowSuite) TestDecodePolicyVerdict(c *C) {
+	pkt := testPacket(c)
+	buf := &bytes.Buffer{}
+	err := binary.Write(buf, byteorder.Native, monitor.PolicyVerdictNotify{
+		Type:        monitor.MessageTypePolicyVerdict,
+		MatchType:   monitor.PolicyMatchL3L4,
+		Source:      20,
+		CapLen:      uint32(len(pkt)),
+		RemoteLabel: 100,
+		DstPort:     80,
+		Proto:       6,
+	})
+	c.Assert(err, IsNil)
+	buf.Write(pkt)
+
+	f, err := Decode(buf.Bytes(), baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(f.Time, Equals, baseTime)
+	c.Assert(f.Type, Equals, "policy-verdict")
+	c.Assert(f.Verdict, Equals, VerdictForwarded)
+	c.Assert(f.Reason, Equals, "L3-L4")
+	c.Assert(f.ObservationPoint, Equals, "ingress")
+	c.Assert(f.Protocol, Equals, "tcp")
+	c.Assert(f.Source.ID, Equals, uint64(0))
+	c.Assert(f.Source.Identity, Equals, uint64(100))
+	c.Assert(f.Source.IP.Equal(net.ParseIP("10.0.0.1")), Equals, true)
+	c.Assert(f.Destination.ID, Equals, uint64(20))
+	c.Assert(f.Destination.Identity, Equals, uint64(0))
+	c.Assert(f.Destination.Port, Equals, uint16(80))
+	c.Assert(f.Summary, Equals, "10.0.0.1:34567 -> 10.0.0.2:80 tcp SYN")
+
+	// Egress verdicts redirected to the proxy
+	buf.Reset()
+	err = binary.Write(buf, byteorder.Native, monitor.PolicyVerdictNotify{
+		Type:        monitor.MessageTypePolicyVerdict,
+		MatchType:   monitor.PolicyMatchL4Only,
+		Source:      20,
+		RemoteLabel: 200,
+		DstPort:     80,
+		ProxyPort:   12345,
+		Proto:       6,
+		Egress:      1,
+	})
+	c.Assert(err, IsNil)
+
+	f, err = Decode(buf.Bytes(), baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(f.Reason, Equals, "L4-Only")
+	c.Assert(f.ObservationPoint, Equals, "egress")
+	c.Assert(f.Source.ID, Equals, uint64(20))
+	c.Assert(f.Destination.Identity, Equals, uint64(200))
+	c.Assert(f.Source.IP, IsNil)
+	c.Assert(f.Summary, Equals, "redirected to proxy port 12345")
+}
+
+func (s *FlowSuite) TestDecodeLogRecord(c *C) {
+	u, err := url.Parse("http://10.0.0.2/public")
+	c.Assert(err, IsNil)
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte(monitor.MessageTypeAccessLog)
+	err = gob.NewEncoder(buf).Encode(accesslog.LogRecord{
+		Type:             accesslog.TypeRequest,
+		Timestamp:        baseTime.Add(time.Second).Format(time.RFC3339Nano),
+		ObservationPoint: accesslog.Ingress,
+		SourceEndpoint: accesslog.EndpointInfo{
+			Identity: 100,
+			IPv4:     "10.0.0.1",
+			Port:     34567,
+			Labels:   []string{"k8s:app=foo"},
+		},
+		DestinationEndpoint: accesslog.EndpointInfo{
+			ID:       20,
+			Identity: 200,
+			IPv4:     "10.0.0.2",
+			Port:     80,
+		},
+		Verdict: accesslog.VerdictDenied,
+		HTTP: &accesslog.LogRecordHTTP{
+			Code:   403,
+			Method: "GET",
+			URL:    u,
+		},
+	})
+	c.Assert(err, IsNil)
+
+	f, err := Decode(buf.Bytes(), baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(f.Time.Equal(baseTime.Add(time.Second)), Equals, true)
+	c.Assert(f.Type, Equals, "l7")
+	c.Assert(f.Verdict, Equals, VerdictDenied)
+	c.Assert(f.Protocol, Equals, "http")
+	c.Assert(f.Source.Labels, DeepEquals, []string{"k8s:app=foo"})
+	c.Assert(f.Source.IP.Equal(net.ParseIP("10.0.0.1")), Equals, true)
+	c.Assert(f.Destination.Port, Equals, uint16(80))
+	c.Assert(f.Summary, Equals, "GET http://10.0.0.2/public => 403")
+	c.Assert(f.Code, Equals, 403)
+}
+
+func (s *FlowSuite) TestLogRecordVerdict(c *C) {
+	c.Assert(logRecordVerdict(accesslog.VerdictForwarded), Equals, VerdictForwarded)
+	c.Assert(logRecordVerdict(accesslog.VerdictDenied), Equals, VerdictDenied)
+	c.Assert(logRecordVerdict(accesslog.VerdictRateLimited), Equals, VerdictRateLimited)
+	c.Assert(logRecordVerdict(accesslog.VerdictError), Equals, VerdictError)
+}
+
+func (s *FlowSuite) TestDecodeUnsupported(c *C) {
+	_, err := Decode([]byte{monitor.MessageTypeDebug}, baseTime)
+	c.Assert(err, Equals, ErrUnsupportedType)
+
+	_, err = Decode([]byte{}, baseTime)
+	c.Assert(err, Not(IsNil))
+}
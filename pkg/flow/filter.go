@@ -0,0 +1,206 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/labels"
+)
+
+// Filter selects flows. All conditions which are set must be met by a flow to
+// be selected. Conditions on endpoints are met if either the source or the
+// destination of the flow meets them.
+type Filter struct {
+	// Since selects flows observed at or after this time if not zero
+	Since time.Time
+
+	// Until selects flows observed before this time if not zero
+	Until time.Time
+
+	// Types selects flows derived from one of the monitor message types
+	Types []string
+
+	// EndpointIDs selects flows from or to one of the local endpoints
+	EndpointIDs []uint64
+
+	// Identities selects flows from or to one of the security identities
+	Identities []uint64
+
+	// Labels selects flows whose source or destination has all labels
+	Labels labels.LabelArray
+
+	// IPs selects flows from or to one of the IP addresses
+	IPs []net.IP
+
+	// Ports selects flows from or to one of the L4 ports
+	Ports []uint16
+
+	// Verdicts selects flows with one of the verdicts
+	Verdicts []Verdict
+
+	// Limit is the maximum number of flows to select. If more flows
+	// match, the most recent flows are selected.
+	Limit int
+}
+
+// ParseTime parses a point in time which is either specified in RFC3339
+// format or as a duration such as "5m" which is subtracted from now
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("negative duration %s", value)
+		}
+		return now.Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s is neither a duration nor a time in RFC3339 format", value)
+	}
+
+	return t, nil
+}
+
+// NewFilterFromModel returns a filter from its API model representation.
+// Relative points in time are interpreted relative to now.
+func NewFilterFromModel(m *models.FlowFilter, now time.Time) (*Filter, error) {
+	var err error
+
+	f := &Filter{
+		Types:  m.Types,
+		Labels: labels.ParseSelectLabelArrayFromArray(m.Labels),
+		Limit:  int(m.Limit),
+	}
+
+	if m.Since != "" {
+		if f.Since, err = ParseTime(m.Since, now); err != nil {
+			return nil, fmt.Errorf("invalid since: %s", err)
+		}
+	}
+
+	if m.Until != "" {
+		if f.Until, err = ParseTime(m.Until, now); err != nil {
+			return nil, fmt.Errorf("invalid until: %s", err)
+		}
+	}
+
+	for _, id := range m.Endpoints {
+		f.EndpointIDs = append(f.EndpointIDs, uint64(id))
+	}
+
+	for _, id := range m.Identities {
+		f.Identities = append(f.Identities, uint64(id))
+	}
+
+	for _, s := range m.Ips {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %s", s)
+		}
+		f.IPs = append(f.IPs, ip)
+	}
+
+	for _, p := range m.Ports {
+		if p < 0 || p > 65535 {
+			return nil, fmt.Errorf("invalid port %d", p)
+		}
+		f.Ports = append(f.Ports, uint16(p))
+	}
+
+	for _, v := range m.Verdicts {
+		f.Verdicts = append(f.Verdicts, Verdict(v))
+	}
+
+	return f, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint64(values []uint64, a, b uint64) bool {
+	for _, v := range values {
+		if v == a || v == b {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPort(ports []uint16, a, b uint16) bool {
+	for _, p := range ports {
+		if p == a || p == b {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIP(ips []net.IP, a, b net.IP) bool {
+	for _, ip := range ips {
+		if ip.Equal(a) || ip.Equal(b) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsVerdict(verdicts []Verdict, verdict Verdict) bool {
+	for _, v := range verdicts {
+		if v == verdict {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns true if the flow meets all conditions of the filter
+func (f *Filter) Match(flow *Flow) bool {
+	switch {
+	case !f.Since.IsZero() && flow.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !flow.Time.Before(f.Until):
+		return false
+	case len(f.Types) > 0 && !containsString(f.Types, flow.Type):
+		return false
+	case len(f.EndpointIDs) > 0 && !containsUint64(f.EndpointIDs, flow.Source.ID, flow.Destination.ID):
+		return false
+	case len(f.Identities) > 0 && !containsUint64(f.Identities, flow.Source.Identity, flow.Destination.Identity):
+		return false
+	case len(f.IPs) > 0 && !containsIP(f.IPs, flow.Source.IP, flow.Destination.IP):
+		return false
+	case len(f.Ports) > 0 && !containsPort(f.Ports, flow.Source.Port, flow.Destination.Port):
+		return false
+	case len(f.Verdicts) > 0 && !containsVerdict(f.Verdicts, flow.Verdict):
+		return false
+	}
+
+	if len(f.Labels) > 0 &&
+		!labels.ParseLabelArrayFromArray(flow.Source.Labels).Contains(f.Labels) &&
+		!labels.ParseLabelArrayFromArray(flow.Destination.Labels).Contains(f.Labels) {
+		return false
+	}
+
+	return true
+}
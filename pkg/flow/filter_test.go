@@ -0,0 +1,127 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package flow
+
+import (
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/labels"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *FlowSuite) TestParseTime(c *C) {
+	t, err := ParseTime("5m", baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(t, Equals, baseTime.Add(-5*time.Minute))
+
+	t, err = ParseTime("2018-10-01T11:00:00Z", baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(t.Equal(baseTime.Add(-time.Hour)), Equals, true)
+
+	_, err = ParseTime("-5m", baseTime)
+	c.Assert(err, Not(IsNil))
+
+	_, err = ParseTime("yesterday", baseTime)
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *FlowSuite) TestNewFilterFromModel(c *C) {
+	f, err := NewFilterFromModel(&models.FlowFilter{
+		Since:      "1m",
+		Endpoints:  []int64{10},
+		Identities: []int64{100},
+		Ips:        []string{"10.0.0.1"},
+		Ports:      []int64{80},
+		Verdicts:   []string{"dropped"},
+		Labels:     []string{"app=foo"},
+		Limit:      5,
+	}, baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(f.Since, Equals, baseTime.Add(-time.Minute))
+	c.Assert(f.EndpointIDs, DeepEquals, []uint64{10})
+	c.Assert(f.Identities, DeepEquals, []uint64{100})
+	c.Assert(f.IPs[0].Equal(net.ParseIP("10.0.0.1")), Equals, true)
+	c.Assert(f.Ports, DeepEquals, []uint16{80})
+	c.Assert(f.Verdicts, DeepEquals, []Verdict{VerdictDropped})
+	c.Assert(f.Labels, DeepEquals, labels.ParseSelectLabelArray("app=foo"))
+	c.Assert(f.Limit, Equals, 5)
+
+	_, err = NewFilterFromModel(&models.FlowFilter{Until: "later"}, baseTime)
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewFilterFromModel(&models.FlowFilter{Ips: []string{"foo"}}, baseTime)
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewFilterFromModel(&models.FlowFilter{Ports: []int64{70000}}, baseTime)
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *FlowSuite) TestFilterMatch(c *C) {
+	f := &Flow{
+		Time:    baseTime,
+		Type:    "drop",
+		Verdict: VerdictDropped,
+		Source: Endpoint{
+			ID:       10,
+			Identity: 100,
+			Labels:   []string{"k8s:app=foo"},
+			IP:       net.ParseIP("10.0.0.1"),
+			Port:     34567,
+		},
+		Destination: Endpoint{
+			Identity: 200,
+			Labels:   []string{"k8s:app=bar"},
+			IP:       net.ParseIP("10.0.0.2"),
+			Port:     80,
+		},
+	}
+
+	c.Assert((&Filter{}).Match(f), Equals, true)
+
+	c.Assert((&Filter{Since: baseTime}).Match(f), Equals, true)
+	c.Assert((&Filter{Since: baseTime.Add(time.Second)}).Match(f), Equals, false)
+	c.Assert((&Filter{Until: baseTime.Add(time.Second)}).Match(f), Equals, true)
+	c.Assert((&Filter{Until: baseTime}).Match(f), Equals, false)
+
+	c.Assert((&Filter{Types: []string{"drop"}}).Match(f), Equals, true)
+	c.Assert((&Filter{Types: []string{"trace", "l7"}}).Match(f), Equals, false)
+
+	c.Assert((&Filter{EndpointIDs: []uint64{10}}).Match(f), Equals, true)
+	c.Assert((&Filter{EndpointIDs: []uint64{11}}).Match(f), Equals, false)
+
+	c.Assert((&Filter{Identities: []uint64{200}}).Match(f), Equals, true)
+	c.Assert((&Filter{Identities: []uint64{300}}).Match(f), Equals, false)
+
+	c.Assert((&Filter{IPs: []net.IP{net.ParseIP("10.0.0.2")}}).Match(f), Equals, true)
+	c.Assert((&Filter{IPs: []net.IP{net.ParseIP("10.0.0.3")}}).Match(f), Equals, false)
+
+	c.Assert((&Filter{Ports: []uint16{80}}).Match(f), Equals, true)
+	c.Assert((&Filter{Ports: []uint16{443}}).Match(f), Equals, false)
+
+	c.Assert((&Filter{Verdicts: []Verdict{VerdictDropped}}).Match(f), Equals, true)
+	c.Assert((&Filter{Verdicts: []Verdict{VerdictForwarded}}).Match(f), Equals, false)
+
+	c.Assert((&Filter{Labels: labels.ParseSelectLabelArray("app=bar")}).Match(f), Equals, true)
+	c.Assert((&Filter{Labels: labels.ParseSelectLabelArray("k8s:app=foo")}).Match(f), Equals, true)
+	c.Assert((&Filter{Labels: labels.ParseSelectLabelArray("app=baz")}).Match(f), Equals, false)
+
+	// All conditions must be met
+	c.Assert((&Filter{Types: []string{"drop"}, Ports: []uint16{443}}).Match(f), Equals, false)
+}
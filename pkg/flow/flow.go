@@ -0,0 +1,126 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+)
+
+// Verdict is the verdict of a flow
+type Verdict string
+
+const (
+	// VerdictForwarded is the verdict of flows forwarded by the datapath
+	// or the proxy
+	VerdictForwarded Verdict = "forwarded"
+
+	// VerdictDropped is the verdict of flows dropped by the datapath
+	VerdictDropped Verdict = "dropped"
+
+	// VerdictDenied is the verdict of flows denied by the proxy
+	VerdictDenied Verdict = "denied"
+
+	// VerdictError is the verdict of flows which failed in the proxy
+	VerdictError Verdict = "error"
+
+	// VerdictRateLimited is the verdict of flows denied by the proxy due
+	// to a rate limit
+	VerdictRateLimited Verdict = "rate-limited"
+)
+
+// Endpoint is the source or destination of a flow
+type Endpoint struct {
+	// ID is the local endpoint ID, 0 if unknown
+	ID uint64
+
+	// Identity is the security identity, 0 if unknown
+	Identity uint64
+
+	// Labels are the labels of the security identity
+	Labels []string
+
+	// IP is the IP address, nil if unknown
+	IP net.IP
+
+	// Port is the L4 port, 0 if unknown
+	Port uint16
+}
+
+func (e *Endpoint) getModel() *models.FlowEndpoint {
+	m := &models.FlowEndpoint{
+		ID:       int64(e.ID),
+		Identity: int64(e.Identity),
+		Labels:   e.Labels,
+		Port:     int64(e.Port),
+	}
+
+	if e.IP != nil {
+		m.IP = e.IP.String()
+	}
+
+	return m
+}
+
+// Flow is a flow observed by the datapath or the L7 proxy
+type Flow struct {
+	// Time is the time the flow was observed at
+	Time time.Time
+
+	// Type is the name of the monitor message type the flow was derived
+	// from, e.g. "drop", "trace", "policy-verdict" or "l7"
+	Type string
+
+	// Verdict is the verdict of the flow
+	Verdict Verdict
+
+	// Reason is the drop reason, connection tracking state or policy match
+	// type of the flow
+	Reason string
+
+	// ObservationPoint is the point in the datapath or proxy at which the
+	// flow was observed
+	ObservationPoint string
+
+	// Protocol is the L4 or L7 protocol of the flow
+	Protocol string
+
+	// Code is the L7 status code of the flow, e.g. the HTTP status code
+	// or the Kafka error code, 0 if unknown
+	Code int
+
+	Source      Endpoint
+	Destination Endpoint
+
+	// Summary is a human readable summary of the flow
+	Summary string
+}
+
+// GetModel returns the API model representation of the flow
+func (f *Flow) GetModel() *models.Flow {
+	return &models.Flow{
+		Time:             f.Time.Format(time.RFC3339Nano),
+		Type:             f.Type,
+		Verdict:          string(f.Verdict),
+		Reason:           f.Reason,
+		ObservationPoint: f.ObservationPoint,
+		Protocol:         f.Protocol,
+		Source:           f.Source.getModel(),
+		Destination:      f.Destination.getModel(),
+		Summary:          f.Summary,
+	}
+}
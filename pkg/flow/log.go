@@ -0,0 +1,162 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "flow")
+
+// LogConfig is the configuration of a flow log
+type LogConfig struct {
+	// Path is the path of the log file
+	Path string
+
+	// MaxSize is the size in megabytes at which the log file is rotated
+	MaxSize int
+
+	// MaxBackups is the number of rotated log files to retain, 0 retains
+	// all rotated log files
+	MaxBackups int
+
+	// RotationInterval is the interval in which the log file is rotated
+	// regardless of its size, 0 disables time based rotation
+	RotationInterval time.Duration
+
+	// Compress enables gzip compression of rotated log files
+	Compress bool
+
+	// Namespaces restricts the log to flows from or to pods in one of
+	// these namespaces. All flows are logged if empty.
+	Namespaces []string
+}
+
+// Logger writes flows as JSON lines into a log file. The log file is
+// rotated once it reaches its maximum size and, optionally, in a fixed
+// interval so that it can be tailed by log shippers.
+type Logger struct {
+	mutex      lock.Mutex
+	out        *lumberjack.Logger
+	namespaces map[string]struct{}
+	stop       chan struct{}
+}
+
+// NewLogger returns a new flow logger writing to the log file described by
+// the configuration
+func NewLogger(c LogConfig) (*Logger, error) {
+	switch {
+	case c.Path == "":
+		return nil, fmt.Errorf("path of flow log must not be empty")
+	case c.MaxSize <= 0:
+		return nil, fmt.Errorf("maximum size of flow log must be positive")
+	case c.MaxBackups < 0:
+		return nil, fmt.Errorf("number of rotated flow logs must not be negative")
+	case c.RotationInterval < 0:
+		return nil, fmt.Errorf("rotation interval of flow log must not be negative")
+	}
+
+	l := &Logger{
+		out: &lumberjack.Logger{
+			Filename:   c.Path,
+			MaxSize:    c.MaxSize,
+			MaxBackups: c.MaxBackups,
+			Compress:   c.Compress,
+		},
+		namespaces: make(map[string]struct{}, len(c.Namespaces)),
+		stop:       make(chan struct{}),
+	}
+
+	for _, ns := range c.Namespaces {
+		l.namespaces[ns] = struct{}{}
+	}
+
+	if c.RotationInterval > 0 {
+		go l.rotate(c.RotationInterval)
+	}
+
+	return l, nil
+}
+
+// rotate rotates the log file in the given interval until the logger is
+// closed
+func (l *Logger) rotate(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.mutex.Lock()
+			if err := l.out.Rotate(); err != nil {
+				log.WithError(err).WithField("path", l.out.Filename).Warning("Unable to rotate flow log")
+			}
+			l.mutex.Unlock()
+		}
+	}
+}
+
+// selects returns true if the flow is from or to a pod in one of the
+// namespaces of the logger
+func (l *Logger) selects(f *Flow) bool {
+	if len(l.namespaces) == 0 {
+		return true
+	}
+
+	if _, ok := l.namespaces[namespace(f.Source.Labels)]; ok {
+		return true
+	}
+	_, ok := l.namespaces[namespace(f.Destination.Labels)]
+	return ok
+}
+
+// Log writes the flow to the log file unless it is filtered out
+func (l *Logger) Log(f *Flow) error {
+	if !l.selects(f) {
+		return nil
+	}
+
+	line, err := json.Marshal(f.GetModel())
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	_, err = l.out.Write(line)
+	return err
+}
+
+// Close stops the rotation of the log file and closes it
+func (l *Logger) Close() error {
+	close(l.stop)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.out.Close()
+}
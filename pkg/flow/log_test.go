@@ -0,0 +1,87 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package flow
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *FlowSuite) TestNewLogger(c *C) {
+	_, err := NewLogger(LogConfig{MaxSize: 1})
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewLogger(LogConfig{Path: "flows.log"})
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewLogger(LogConfig{Path: "flows.log", MaxSize: 1, MaxBackups: -1})
+	c.Assert(err, Not(IsNil))
+}
+
+// readFlowLog returns the flows of a flow log
+func readFlowLog(c *C, path string) []*models.Flow {
+	f, err := os.Open(path)
+	c.Assert(err, IsNil)
+	defer f.Close()
+
+	flows := []*models.Flow{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := &models.Flow{}
+		c.Assert(json.Unmarshal(scanner.Bytes(), m), IsNil)
+		flows = append(flows, m)
+	}
+	c.Assert(scanner.Err(), IsNil)
+
+	return flows
+}
+
+func (s *FlowSuite) TestLogger(c *C) {
+	dir, err := ioutil.TempDir("", "flow-log")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "flows.log")
+	l, err := NewLogger(LogConfig{
+		Path:       path,
+		MaxSize:    1,
+		Namespaces: []string{"default"},
+	})
+	c.Assert(err, IsNil)
+
+	inNamespace := newTestFlow(1)
+	inNamespace.Destination.Labels = []string{"k8s:io.kubernetes.pod.namespace=default"}
+	otherNamespace := newTestFlow(2)
+	otherNamespace.Source.Labels = []string{"k8s:io.kubernetes.pod.namespace=kube-system"}
+
+	c.Assert(l.Log(inNamespace), IsNil)
+	c.Assert(l.Log(otherNamespace), IsNil)
+	c.Assert(l.Log(newTestFlow(3)), IsNil)
+	c.Assert(l.Close(), IsNil)
+
+	// Only the flow to a pod in the default namespace is logged
+	flows := readFlowLog(c, path)
+	c.Assert(flows, HasLen, 1)
+	c.Assert(flows[0], DeepEquals, inNamespace.GetModel())
+}
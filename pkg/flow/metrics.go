@@ -0,0 +1,217 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	k8sconst "github.com/cilium/cilium/pkg/k8s/apis/cilium.io"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricLabelSourceNamespace is the namespace of the source pod
+	MetricLabelSourceNamespace = "source_namespace"
+
+	// MetricLabelSourceWorkload is the value of the workload label of the
+	// source security identity
+	MetricLabelSourceWorkload = "source_workload"
+
+	// MetricLabelDestinationNamespace is the namespace of the destination pod
+	MetricLabelDestinationNamespace = "destination_namespace"
+
+	// MetricLabelDestinationWorkload is the value of the workload label of
+	// the destination security identity
+	MetricLabelDestinationWorkload = "destination_workload"
+
+	// MetricLabelReason is the drop reason, connection tracking state or
+	// L7 record type of the flow
+	MetricLabelReason = "reason"
+
+	// MetricLabelProtocol is the L4 or L7 protocol of the flow
+	MetricLabelProtocol = "protocol"
+
+	// MetricLabelCode is the L7 status code of the flow
+	MetricLabelCode = "code"
+
+	// MetricLabelValueOther is the label value reported once a label has
+	// reached maxMetricLabelValues distinct values
+	MetricLabelValueOther = "other"
+
+	// maxMetricLabelValues is the maximum number of distinct values
+	// reported for each label
+	maxMetricLabelValues = 100
+)
+
+// metricLabelFuncs returns the value of each configurable metric label for
+// a flow
+var metricLabelFuncs = map[string]func(e *MetricsExporter, f *Flow) string{
+	MetricLabelSourceNamespace:      func(e *MetricsExporter, f *Flow) string { return namespace(f.Source.Labels) },
+	MetricLabelSourceWorkload:       func(e *MetricsExporter, f *Flow) string { return e.workload(f.Source.Labels) },
+	MetricLabelDestinationNamespace: func(e *MetricsExporter, f *Flow) string { return namespace(f.Destination.Labels) },
+	MetricLabelDestinationWorkload:  func(e *MetricsExporter, f *Flow) string { return e.workload(f.Destination.Labels) },
+	MetricLabelReason:               func(e *MetricsExporter, f *Flow) string { return f.Reason },
+	MetricLabelProtocol:             func(e *MetricsExporter, f *Flow) string { return f.Protocol },
+	MetricLabelCode: func(e *MetricsExporter, f *Flow) string {
+		if f.Code == 0 {
+			return ""
+		}
+		return strconv.Itoa(f.Code)
+	},
+}
+
+// MetricLabels returns the names of all configurable flow metric labels
+func MetricLabels() []string {
+	names := make([]string, 0, len(metricLabelFuncs))
+	for name := range metricLabelFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultMetricLabels is the default set of configurable labels of the flow
+// metrics
+var DefaultMetricLabels = []string{
+	MetricLabelSourceNamespace,
+	MetricLabelDestinationNamespace,
+	MetricLabelReason,
+	MetricLabelCode,
+}
+
+// namespacePrefix is the prefix of the label carrying the namespace of a pod
+var namespacePrefix = labels.LabelSourceK8s + ":" + k8sconst.PodNamespaceLabel + "="
+
+// namespace returns the namespace of the pod with the given labels
+func namespace(lbls []string) string {
+	for _, l := range lbls {
+		if strings.HasPrefix(l, namespacePrefix) {
+			return strings.TrimPrefix(l, namespacePrefix)
+		}
+	}
+	return ""
+}
+
+// MetricsExporter counts flows in a Prometheus counter. The counter is
+// labeled by the type and verdict of the flows and a configurable set of
+// additional labels. The number of distinct values of each configurable
+// label is limited to keep the cardinality of the metric bounded.
+type MetricsExporter struct {
+	labels        []string
+	workloadLabel *labels.Label
+	counter       *prometheus.CounterVec
+
+	mutex  lock.Mutex
+	values map[string]map[string]struct{}
+}
+
+// NewMetricsExporter returns a new flow metrics exporter with the given
+// configurable labels. workloadLabel is the identity label whose value is
+// reported as workload, e.g. "k8s:app". Reserved identities are reported
+// with their reserved label as workload.
+func NewMetricsExporter(metricLabels []string, workloadLabel string) (*MetricsExporter, error) {
+	seen := make(map[string]struct{}, len(metricLabels))
+	for _, l := range metricLabels {
+		if _, ok := metricLabelFuncs[l]; !ok {
+			return nil, fmt.Errorf("unknown flow metric label %q", l)
+		}
+		if _, ok := seen[l]; ok {
+			return nil, fmt.Errorf("duplicate flow metric label %q", l)
+		}
+		seen[l] = struct{}{}
+	}
+
+	if workloadLabel == "" {
+		return nil, fmt.Errorf("workload label must not be empty")
+	}
+
+	return &MetricsExporter{
+		labels:        metricLabels,
+		workloadLabel: labels.ParseSelectLabel(workloadLabel),
+		counter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Name:      "flows_total",
+			Help:      "Number of flows observed by the datapath and the L7 proxy, tagged by type, verdict and the configured flow metric labels",
+		}, append([]string{"type", "verdict"}, metricLabels...)),
+		values: map[string]map[string]struct{}{},
+	}, nil
+}
+
+// workload returns the value of the workload label of an identity
+func (e *MetricsExporter) workload(lbls []string) string {
+	for _, s := range lbls {
+		l := labels.ParseLabel(s)
+		if l.Source == labels.LabelSourceReserved {
+			return s
+		}
+		if l.Key == e.workloadLabel.Key &&
+			(e.workloadLabel.IsAnySource() || l.Source == e.workloadLabel.Source) {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+// limit returns value unless the label has already reached the maximum
+// number of distinct values, in which case MetricLabelValueOther is returned
+func (e *MetricsExporter) limit(label, value string) string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	values, ok := e.values[label]
+	if !ok {
+		values = map[string]struct{}{}
+		e.values[label] = values
+	}
+
+	if _, ok := values[value]; ok {
+		return value
+	}
+
+	if len(values) >= maxMetricLabelValues {
+		return MetricLabelValueOther
+	}
+
+	values[value] = struct{}{}
+	return value
+}
+
+// Export counts the flow
+func (e *MetricsExporter) Export(f *Flow) {
+	values := make([]string, 0, 2+len(e.labels))
+	values = append(values, f.Type, string(f.Verdict))
+	for _, l := range e.labels {
+		values = append(values, e.limit(l, metricLabelFuncs[l](e, f)))
+	}
+
+	e.counter.WithLabelValues(values...).Inc()
+}
+
+// Describe implements prometheus.Collector
+func (e *MetricsExporter) Describe(ch chan<- *prometheus.Desc) {
+	e.counter.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (e *MetricsExporter) Collect(ch chan<- prometheus.Metric) {
+	e.counter.Collect(ch)
+}
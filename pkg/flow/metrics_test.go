@@ -0,0 +1,79 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package flow
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/metrics"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *FlowSuite) TestNewMetricsExporter(c *C) {
+	_, err := NewMetricsExporter([]string{MetricLabelSourceNamespace, "unknown"}, "k8s:app")
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewMetricsExporter([]string{MetricLabelReason, MetricLabelSourceNamespace, MetricLabelReason}, "k8s:app")
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewMetricsExporter(DefaultMetricLabels, "")
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewMetricsExporter(MetricLabels(), "k8s:app")
+	c.Assert(err, IsNil)
+}
+
+func (s *FlowSuite) TestMetricsExporter(c *C) {
+	e, err := NewMetricsExporter([]string{
+		MetricLabelSourceNamespace,
+		MetricLabelSourceWorkload,
+		MetricLabelDestinationWorkload,
+		MetricLabelCode,
+	}, "app")
+	c.Assert(err, IsNil)
+
+	f := &Flow{
+		Type:    "l7",
+		Verdict: VerdictDenied,
+		Code:    403,
+		Source: Endpoint{Labels: []string{
+			"k8s:app=client",
+			"k8s:io.kubernetes.pod.namespace=default",
+		}},
+		Destination: Endpoint{Labels: []string{"reserved:world"}},
+	}
+	e.Export(f)
+	e.Export(f)
+
+	counter := e.counter.WithLabelValues("l7", "denied", "default", "client", "reserved:world", "403")
+	c.Assert(metrics.GetCounterValue(counter), Equals, float64(2))
+
+	// Values beyond the maximum number of distinct values per label are
+	// reported as other. "client" has already been reported, so only the
+	// last flow exceeds the limit.
+	for i := 0; i < maxMetricLabelValues; i++ {
+		e.Export(&Flow{
+			Type:    "drop",
+			Verdict: VerdictDropped,
+			Source:  Endpoint{Labels: []string{fmt.Sprintf("k8s:app=client-%d", i)}},
+		})
+	}
+
+	counter = e.counter.WithLabelValues("drop", "dropped", "", MetricLabelValueOther, "", "")
+	c.Assert(metrics.GetCounterValue(counter), Equals, float64(1))
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"github.com/cilium/cilium/pkg/ring"
+)
+
+// Ring is a buffer of a fixed number of flows. Once the buffer is full, each
+// added flow replaces the oldest flow in the buffer.
+type Ring struct {
+	ring *ring.Ring
+}
+
+// NewRing returns a new ring buffer for size flows
+func NewRing(size int) *Ring {
+	return &Ring{ring: ring.NewRing(size)}
+}
+
+// Add adds a flow to the buffer. The flow must not be modified afterwards.
+func (r *Ring) Add(f *Flow) {
+	r.ring.Add(f)
+}
+
+// Len returns the number of flows in the buffer
+func (r *Ring) Len() int {
+	return r.ring.Len()
+}
+
+// Cap returns the maximum number of flows in the buffer
+func (r *Ring) Cap() int {
+	return r.ring.Cap()
+}
+
+// Query returns all flows in the buffer matching the filter, oldest first. If
+// the filter limits the number of flows, the most recent flows are returned.
+func (r *Ring) Query(filter *Filter) []*Flow {
+	result := []*Flow{}
+
+	// Walk the buffer from the most recent to the oldest flow so the
+	// limit is applied to the most recent flows
+	r.ring.Walk(func(e interface{}) bool {
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			return false
+		}
+		if f := e.(*Flow); filter.Match(f) {
+			result = append(result, f)
+		}
+		return true
+	})
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package flow
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type FlowSuite struct{}
+
+var _ = Suite(&FlowSuite{})
+
+var baseTime = time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestFlow(i int) *Flow {
+	return &Flow{
+		Time:    baseTime.Add(time.Duration(i) * time.Second),
+		Type:    "trace",
+		Verdict: VerdictForwarded,
+		Source:  Endpoint{ID: uint64(i)},
+	}
+}
+
+func flowIDs(flows []*Flow) []uint64 {
+	ids := []uint64{}
+	for _, f := range flows {
+		ids = append(ids, f.Source.ID)
+	}
+	return ids
+}
+
+func (s *FlowSuite) TestRing(c *C) {
+	r := NewRing(3)
+	c.Assert(r.Len(), Equals, 0)
+	c.Assert(r.Cap(), Equals, 3)
+	c.Assert(r.Query(&Filter{}), HasLen, 0)
+
+	r.Add(newTestFlow(1))
+	r.Add(newTestFlow(2))
+	c.Assert(r.Len(), Equals, 2)
+	c.Assert(flowIDs(r.Query(&Filter{})), DeepEquals, []uint64{1, 2})
+
+	// Oldest flows are replaced once the buffer is full
+	r.Add(newTestFlow(3))
+	r.Add(newTestFlow(4))
+	r.Add(newTestFlow(5))
+	c.Assert(r.Len(), Equals, 3)
+	c.Assert(flowIDs(r.Query(&Filter{})), DeepEquals, []uint64{3, 4, 5})
+
+	// The limit selects the most recent flows
+	c.Assert(flowIDs(r.Query(&Filter{Limit: 2})), DeepEquals, []uint64{4, 5})
+
+	c.Assert(flowIDs(r.Query(&Filter{Since: baseTime.Add(4 * time.Second)})), DeepEquals, []uint64{4, 5})
+}
+
+func (s *FlowSuite) TestRingEmpty(c *C) {
+	r := NewRing(0)
+	r.Add(newTestFlow(1))
+	c.Assert(r.Len(), Equals, 0)
+	c.Assert(r.Query(&Filter{}), HasLen, 0)
+}
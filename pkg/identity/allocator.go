@@ -18,7 +18,9 @@ import (
 	"fmt"
 	"path"
 	"sync"
+	"time"
 
+	"github.com/cilium/cilium/pkg/backoff"
 	"github.com/cilium/cilium/pkg/idpool"
 	"github.com/cilium/cilium/pkg/kvstore"
 	"github.com/cilium/cilium/pkg/kvstore/allocator"
@@ -91,7 +93,14 @@ func InitIdentityAllocator(owner IdentityAllocatorOwner) {
 			allocator.WithSuffix(owner.GetNodeSuffix()),
 			allocator.WithEvents(events),
 			allocator.WithMasterKeyProtection(),
-			allocator.WithPrefixMask(idpool.ID(option.Config.ClusterID<<option.ClusterIDShift)))
+			allocator.WithPrefixMask(idpool.ID(option.Config.ClusterID<<option.ClusterIDShift)),
+			allocator.WithMaxConcurrency(option.Config.IdentityAllocationConcurrency),
+			allocator.WithBackoff(backoff.Exponential{
+				Min:    20 * time.Millisecond,
+				Max:    option.Config.IdentityAllocationBackoffMax,
+				Factor: 2.0,
+				Jitter: true,
+			}))
 		if err != nil {
 			log.WithError(err).Fatal("Unable to initialize identity allocator")
 		}
@@ -24,10 +24,23 @@ import (
 	"github.com/cilium/cilium/pkg/kvstore/allocator"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/trigger"
+
+	"github.com/hashicorp/golang-lru"
+)
+
+const (
+	// releasedIdentityCacheSize is the number of released identities for
+	// which the labels are retained
+	releasedIdentityCacheSize = 1024
 )
 
 var (
 	reservedIdentityCache = map[NumericIdentity]*Identity{}
+
+	// releasedIdentities retains the labels of recently released
+	// identities so that events referring to an identity which has been
+	// released in the meantime can still be resolved
+	releasedIdentities *lru.Cache
 )
 
 // IdentityCache is a cache of identity to labels mapping
@@ -100,7 +113,16 @@ func identityWatcher(owner IdentityAllocatorOwner, events allocator.AllocatorEve
 		event := <-events
 
 		switch event.Typ {
-		case kvstore.EventTypeCreate, kvstore.EventTypeDelete:
+		case kvstore.EventTypeCreate:
+			// The ID may have been reused, the labels of the
+			// previous owner are no longer relevant
+			releasedIdentities.Remove(NumericIdentity(event.ID))
+			policyTrigger.Trigger()
+
+		case kvstore.EventTypeDelete:
+			if gi, ok := event.Key.(globalIdentity); ok {
+				releasedIdentities.Add(NumericIdentity(event.ID), gi.Labels)
+			}
 			policyTrigger.Trigger()
 
 		case kvstore.EventTypeModify:
@@ -204,6 +226,17 @@ func LookupIdentityByID(id NumericIdentity) *Identity {
 	return nil
 }
 
+// LookupReleasedIdentityByID returns a recently released identity by ID.
+// Returns nil if the identity has not been released by the allocator or has
+// been released too long ago.
+func LookupReleasedIdentityByID(id NumericIdentity) *Identity {
+	if lbls, ok := releasedIdentities.Get(id); ok {
+		return NewIdentity(id, lbls.(labels.Labels))
+	}
+
+	return nil
+}
+
 // AddReservedIdentity adds the reserved numeric identity with the respective
 // label into the map of reserved identity cache.
 func AddReservedIdentity(ni NumericIdentity, lbl string) {
@@ -220,6 +253,9 @@ func init() {
 		identity.GetLabelsSHA256()
 		reservedIdentityCache[ni] = identity
 	})
+
+	// lru.New only fails for a non-positive size
+	releasedIdentities, _ = lru.New(releasedIdentityCacheSize)
 }
 
 // AddUserDefinedNumericIdentitySet adds all key-value pairs from the given map
@@ -127,3 +127,18 @@ func (s *IdentityTestSuite) TestLookupReservedIdentityByLabels(c *C) {
 		}
 	}
 }
+
+func (s *IdentityTestSuite) TestLookupReleasedIdentity(c *C) {
+	id := NumericIdentity(54321)
+	lbls := labels.NewLabelsFromModel([]string{"k8s:app=released"})
+
+	c.Assert(LookupReleasedIdentityByID(id), IsNil)
+
+	releasedIdentities.Add(id, lbls)
+	defer releasedIdentities.Remove(id)
+
+	identity := LookupReleasedIdentityByID(id)
+	c.Assert(identity, Not(IsNil))
+	c.Assert(identity.ID, Equals, id)
+	c.Assert(identity.Labels, DeepEquals, lbls)
+}
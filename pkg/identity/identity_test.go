@@ -72,6 +72,7 @@ func (s *IdentityTestSuite) TestIsReservedIdentity(c *C) {
 	c.Assert(ReservedIdentityWorld.IsReservedIdentity(), Equals, true)
 	c.Assert(ReservedIdentityInit.IsReservedIdentity(), Equals, true)
 	c.Assert(ReservedIdentityUnmanaged.IsReservedIdentity(), Equals, true)
+	c.Assert(ReservedIdentityRemoteNode.IsReservedIdentity(), Equals, true)
 
 	c.Assert(NumericIdentity(123456).IsReservedIdentity(), Equals, false)
 }
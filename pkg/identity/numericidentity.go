@@ -58,6 +58,10 @@ const (
 	// received any labels yet.
 	ReservedIdentityInit
 
+	// ReservedIdentityRemoteNode represents all nodes of the cluster
+	// except the local node
+	ReservedIdentityRemoteNode
+
 	// --------------------------------------------------------------
 	// Special identities for well-known cluster components
 
@@ -192,18 +196,20 @@ func initWellKnownIdentities() {
 
 var (
 	reservedIdentities = map[string]NumericIdentity{
-		labels.IDNameHost:      ReservedIdentityHost,
-		labels.IDNameWorld:     ReservedIdentityWorld,
-		labels.IDNameUnmanaged: ReservedIdentityUnmanaged,
-		labels.IDNameHealth:    ReservedIdentityHealth,
-		labels.IDNameInit:      ReservedIdentityInit,
+		labels.IDNameHost:       ReservedIdentityHost,
+		labels.IDNameWorld:      ReservedIdentityWorld,
+		labels.IDNameUnmanaged:  ReservedIdentityUnmanaged,
+		labels.IDNameHealth:     ReservedIdentityHealth,
+		labels.IDNameInit:       ReservedIdentityInit,
+		labels.IDNameRemoteNode: ReservedIdentityRemoteNode,
 	}
 	reservedIdentityNames = map[NumericIdentity]string{
-		ReservedIdentityHost:      labels.IDNameHost,
-		ReservedIdentityWorld:     labels.IDNameWorld,
-		ReservedIdentityUnmanaged: labels.IDNameUnmanaged,
-		ReservedIdentityHealth:    labels.IDNameHealth,
-		ReservedIdentityInit:      labels.IDNameInit,
+		ReservedIdentityHost:       labels.IDNameHost,
+		ReservedIdentityWorld:      labels.IDNameWorld,
+		ReservedIdentityUnmanaged:  labels.IDNameUnmanaged,
+		ReservedIdentityHealth:     labels.IDNameHealth,
+		ReservedIdentityInit:       labels.IDNameInit,
+		ReservedIdentityRemoteNode: labels.IDNameRemoteNode,
 	}
 
 	wellKnown = wellKnownIdentities{}
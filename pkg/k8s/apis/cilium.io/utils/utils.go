@@ -99,8 +99,7 @@ func parseToCiliumIngressRule(namespace string, inRule, retRule *api.Rule) {
 			}
 
 			if ing.ToPorts != nil {
-				retRule.Ingress[i].ToPorts = make([]api.PortRule, len(ing.ToPorts))
-				copy(retRule.Ingress[i].ToPorts, ing.ToPorts)
+				retRule.Ingress[i].ToPorts = parseToCiliumPortRules(namespace, ing.ToPorts)
 			}
 			if ing.FromCIDR != nil {
 				retRule.Ingress[i].FromCIDR = make([]api.CIDR, len(ing.FromCIDR))
@@ -142,8 +141,7 @@ func parseToCiliumEgressRule(namespace string, inRule, retRule *api.Rule) {
 			}
 
 			if egr.ToPorts != nil {
-				retRule.Egress[i].ToPorts = make([]api.PortRule, len(egr.ToPorts))
-				copy(retRule.Egress[i].ToPorts, egr.ToPorts)
+				retRule.Egress[i].ToPorts = parseToCiliumPortRules(namespace, egr.ToPorts)
 			}
 			if egr.ToCIDR != nil {
 				retRule.Egress[i].ToCIDR = make([]api.CIDR, len(egr.ToCIDR))
@@ -180,6 +178,35 @@ func parseToCiliumEgressRule(namespace string, inRule, retRule *api.Rule) {
 	}
 }
 
+// parseToCiliumPortRules returns a copy of the given port rules in which all
+// secrets referred to by HTTP header matches are in the given namespace, so
+// that a policy cannot refer to secrets outside of its own namespace.
+func parseToCiliumPortRules(namespace string, portRules []api.PortRule) []api.PortRule {
+	retRules := make([]api.PortRule, len(portRules))
+	for i := range portRules {
+		portRules[i].DeepCopyInto(&retRules[i])
+		if retRules[i].Rules == nil {
+			continue
+		}
+		for _, h := range retRules[i].Rules.HTTP {
+			for _, hm := range h.HeaderMatches {
+				if hm.Secret == nil || hm.Secret.Namespace == namespace {
+					continue
+				}
+				if hm.Secret.Namespace != "" {
+					log.WithFields(logrus.Fields{
+						logfields.K8sNamespace:              namespace,
+						logfields.K8sNamespace + ".illegal": hm.Secret.Namespace,
+					}).Warn("CiliumNetworkPolicy refers to a secret in another namespace," +
+						" using the namespace of the policy instead.")
+				}
+				hm.Secret.Namespace = namespace
+			}
+		}
+	}
+	return retRules
+}
+
 // namespacesAreValid checks the set of namespaces from a rule returns true if
 // they are not specified, or if they are specified and match the namespace
 // where the rule is being inserted.
@@ -319,3 +319,34 @@ func (s *CiliumUtilsSuite) TestParseToCiliumLabels(c *C) {
 		c.Assert(got, DeepEquals, tt.want, Commentf("Test Name: %s", tt.name))
 	}
 }
+
+func (s *CiliumUtilsSuite) TestParseToCiliumPortRules(c *C) {
+	portRules := []api.PortRule{
+		{
+			Ports: []api.PortProtocol{{Port: "80", Protocol: api.ProtoTCP}},
+			Rules: &api.L7Rules{
+				HTTP: []api.PortRuleHTTP{
+					{
+						HeaderMatches: []api.HeaderMatch{
+							{Name: "x-api-key", Secret: &api.Secret{Name: "key"}},
+							{Name: "x-other-key", Secret: &api.Secret{Namespace: "kube-system", Name: "key"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			Ports: []api.PortProtocol{{Port: "53", Protocol: api.ProtoUDP}},
+		},
+	}
+
+	parsed := parseToCiliumPortRules("default", portRules)
+	c.Assert(parsed, HasLen, 2)
+	c.Assert(parsed[0].Rules.HTTP[0].HeaderMatches[0].Secret.Namespace, Equals, "default")
+	c.Assert(parsed[0].Rules.HTTP[0].HeaderMatches[1].Secret.Namespace, Equals, "default")
+	c.Assert(parsed[1], checker.DeepEquals, portRules[1])
+
+	// The original rules are left untouched
+	c.Assert(portRules[0].Rules.HTTP[0].HeaderMatches[0].Secret.Namespace, Equals, "")
+	c.Assert(portRules[0].Rules.HTTP[0].HeaderMatches[1].Secret.Namespace, Equals, "kube-system")
+}
@@ -41,7 +41,7 @@ const (
 
 	// CustomResourceDefinitionSchemaVersion is semver-conformant version of CRD schema
 	// Used to determine if CRD needs to be updated in cluster
-	CustomResourceDefinitionSchemaVersion = "1.10"
+	CustomResourceDefinitionSchemaVersion = "1.15"
 
 	// CustomResourceDefinitionSchemaVersionKey is key to label which holds the CRD schema version
 	CustomResourceDefinitionSchemaVersionKey = "io.cilium.k8s.crd.schema.version"
@@ -376,6 +376,7 @@ var (
 		"CIDRRule":                 CIDRRule,
 		"EgressRule":               EgressRule,
 		"EndpointSelector":         EndpointSelector,
+		"HeaderMatch":              HeaderMatch,
 		"IngressRule":              IngressRule,
 		"K8sServiceNamespace":      K8sServiceNamespace,
 		"L7Rules":                  L7Rules,
@@ -384,10 +385,13 @@ var (
 		"LabelSelectorRequirement": LabelSelectorRequirement,
 		"PortProtocol":             PortProtocol,
 		"PortRule":                 PortRule,
+		"PortRuleGRPC":             PortRuleGRPC,
 		"PortRuleHTTP":             PortRuleHTTP,
 		"PortRuleKafka":            PortRuleKafka,
 		"PortRuleL7":               PortRuleL7,
+		"RateLimit":                RateLimit,
 		"Rule":                     Rule,
+		"Secret":                   Secret,
 		"Service":                  Service,
 		"ServiceSelector":          ServiceSelector,
 		"spec":                     spec,
@@ -542,6 +546,54 @@ var (
 
 	EndpointSelector = *LabelSelector.DeepCopy()
 
+	HeaderMatch = apiextensionsv1beta1.JSONSchemaProps{
+		Description: "HeaderMatch is a match on the value of an HTTP request header. At most " +
+			"one of Value, Regex, Secret and Absent may be set. If none is set, the header " +
+			"must be present with any value.",
+		Required: []string{
+			"name",
+		},
+		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+			"absent": {
+				Description: "Absent requires the header not to be present in the request.",
+				Type:        "boolean",
+			},
+			"mismatch": {
+				Description: "Mismatch is the action taken when the header does not match, one " +
+					"of \"DENY\", \"LOG\", \"ADD\" or \"REPLACE\". \"ADD\" and \"REPLACE\" " +
+					"require Value or Secret. If omitted, the request is denied.",
+				Type: "string",
+				Enum: []apiextensionsv1beta1.JSON{
+					{
+						Raw: []byte(`"DENY"`),
+					},
+					{
+						Raw: []byte(`"LOG"`),
+					},
+					{
+						Raw: []byte(`"ADD"`),
+					},
+					{
+						Raw: []byte(`"REPLACE"`),
+					},
+				},
+			},
+			"name": {
+				Description: "Name is the name of the header.",
+				Type:        "string",
+			},
+			"regex": {
+				Description: "Regex is an extended POSIX regex the value of the header must match.",
+				Type:        "string",
+			},
+			"secret": Secret,
+			"value": {
+				Description: "Value is the exact value the header must have.",
+				Type:        "string",
+			},
+		},
+	}
+
 	IngressRule = apiextensionsv1beta1.JSONSchemaProps{
 		Description: "IngressRule contains all rule types which can be applied at ingress, " +
 			"i.e. network traffic that originates outside of the endpoint and is entering " +
@@ -665,6 +717,13 @@ var (
 					Schema: &PortRuleKafka,
 				},
 			},
+			"grpc": {
+				Description: "gRPC-specific rules.",
+				Type:        "array",
+				Items: &apiextensionsv1beta1.JSONSchemaPropsOrArray{
+					Schema: &PortRuleGRPC,
+				},
+			},
 			"l7proto": {
 				Description: "Parser type name that uses Key-Value pair rules.",
 				Type:        "string",
@@ -822,6 +881,50 @@ var (
 				Format: "uint16",
 			},
 			"rules": L7Rules,
+			"serverNames": {
+				Description: "ServerNames is a list of allowed TLS server names, matched " +
+					"against the Server Name Indication (SNI) of the TLS ClientHello. A name " +
+					"may begin with \"*.\" to match any single DNS label in its place. If " +
+					"omitted or empty, the server name is not enforced. ServerNames cannot " +
+					"be combined with Rules.",
+				Type: "array",
+				Items: &apiextensionsv1beta1.JSONSchemaPropsOrArray{
+					Schema: &apiextensionsv1beta1.JSONSchemaProps{
+						Type: "string",
+					},
+				},
+			},
+		},
+	}
+
+	PortRuleGRPC = apiextensionsv1beta1.JSONSchemaProps{
+		Description: "PortRuleGRPC is a list of gRPC protocol constraints. All fields are " +
+			"optional, if all fields are empty or missing, the rule matches all gRPC calls.",
+		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+			"headers": {
+				Description: "Headers is a list of gRPC metadata headers which must be present " +
+					"in the request. If omitted or empty, calls are allowed regardless of " +
+					"metadata present.",
+				Type: "array",
+				Items: &apiextensionsv1beta1.JSONSchemaPropsOrArray{
+					Schema: &apiextensionsv1beta1.JSONSchemaProps{
+						Type: "string",
+					},
+				},
+			},
+			"method": {
+				Description: "Method is the name of a method of the service, e.g. \"SayHello\".\n\n" +
+					"If omitted or empty, calls to all methods are allowed.",
+				Type:    "string",
+				Pattern: `^[a-zA-Z_][a-zA-Z0-9_]*$`,
+			},
+			"service": {
+				Description: "Service is the fully qualified name of a gRPC service, including " +
+					"the package name, e.g. \"helloworld.Greeter\".\n\nIf omitted or empty, " +
+					"calls to all services are allowed.",
+				Type:    "string",
+				Pattern: `^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`,
+			},
 		},
 	}
 
@@ -845,6 +948,16 @@ var (
 					},
 				},
 			},
+			"headerMatches": {
+				Description: "HeaderMatches is a list of structured HTTP header matches. Unlike " +
+					"Headers, the value of a header may be matched against a regex or a " +
+					"Kubernetes secret, a header may be required to be absent, and a mismatch " +
+					"may be logged or fixed up instead of denying the request.",
+				Type: "array",
+				Items: &apiextensionsv1beta1.JSONSchemaPropsOrArray{
+					Schema: &HeaderMatch,
+				},
+			},
 			"host": {
 				Description: "Host is an extended POSIX regex matched against the host header " +
 					"of a request, e.g. \"foo.com\"\n\nIf omitted or empty, the value of the " +
@@ -865,6 +978,7 @@ var (
 					"If omitted or empty, all paths are all allowed.",
 				Type: "string",
 			},
+			"rateLimit": RateLimit,
 		},
 	}
 
@@ -932,6 +1046,15 @@ var (
 				Type:      "string",
 				MaxLength: getInt64(255),
 			},
+			"principal": {
+				Description: "Principal is the user name the client authenticated as with " +
+					"SASL, using the PLAIN or SCRAM mechanisms. Requests sent before " +
+					"authentication, or on connections using other mechanisms, do not match " +
+					"a rule with a principal. The SaslHandshake and SaslAuthenticate requests " +
+					"necessary to authenticate are always allowed by a rule with a principal." +
+					"\n\nIf omitted or empty, all principals are allowed.",
+				Type: "string",
+			},
 		},
 	}
 
@@ -955,6 +1078,47 @@ var (
 		//},
 	}
 
+	RateLimit = apiextensionsv1beta1.JSONSchemaProps{
+		Description: "RateLimit limits the rate of requests allowed by this rule. Requests " +
+			"in excess of the limit are answered with status 429 (Too Many Requests) " +
+			"unless they are allowed by another rule.",
+		Required: []string{
+			"requests",
+		},
+		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+			"burst": {
+				Description: "Burst is the number of requests allowed in a burst. If omitted " +
+					"or less than Requests, bursts are limited to Requests requests.",
+				Type:   "integer",
+				Format: "uint32",
+			},
+			"interval": {
+				Description: "Interval is the interval in which Requests requests are allowed, " +
+					"e.g. \"1s\" or \"1m\". If omitted, the interval is one second.",
+				Type: "string",
+			},
+			"per": {
+				Description: "Per is the scope of the limit, either \"identity\" or " +
+					"\"endpoint\". If omitted, the requests of all sources with the same " +
+					"identity are limited together.",
+				Type: "string",
+				Enum: []apiextensionsv1beta1.JSON{
+					{
+						Raw: []byte(`"identity"`),
+					},
+					{
+						Raw: []byte(`"endpoint"`),
+					},
+				},
+			},
+			"requests": {
+				Description: "Requests is the number of requests allowed per Interval.",
+				Type:        "integer",
+				Format:      "uint32",
+			},
+		},
+	}
+
 	Rule = apiextensionsv1beta1.JSONSchemaProps{
 		Description: "Rule is a policy rule which must be applied to all endpoints which match " +
 			"the labels contained in the endpointSelector\n\nEach rule is split into an " +
@@ -1004,6 +1168,29 @@ var (
 		},
 	}
 
+	Secret = apiextensionsv1beta1.JSONSchemaProps{
+		Description: "Secret is a reference to a value stored in a Kubernetes secret.",
+		Required: []string{
+			"name",
+		},
+		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+			"key": {
+				Description: "Key is the key in the data of the secret holding the value. If " +
+					"omitted, the secret must hold exactly one key.",
+				Type: "string",
+			},
+			"name": {
+				Description: "Name is the name of the secret.",
+				Type:        "string",
+			},
+			"namespace": {
+				Description: "Namespace is the namespace of the secret. For policies imported " +
+					"from Kubernetes, this is always the namespace of the policy.",
+				Type: "string",
+			},
+		},
+	}
+
 	Service = apiextensionsv1beta1.JSONSchemaProps{
 		Description: "Service wraps around selectors for services",
 		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
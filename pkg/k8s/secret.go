@@ -0,0 +1,167 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/lock"
+
+	"k8s.io/api/core/v1"
+)
+
+// secretCache holds the Kubernetes secrets received by the secret watchers,
+// so that policies can refer to secrets without querying the apiserver. Only
+// the secrets referenced by policies are watched.
+type secretCache struct {
+	mutex lock.RWMutex
+
+	// secrets maps "<namespace>/<name>" to the secret
+	secrets map[string]*v1.Secret
+
+	// referenced is the set of "<namespace>/<name>" of the secrets that
+	// have been looked up, whether they existed or not
+	referenced map[string]struct{}
+
+	// watch starts watching the secret with the given namespace and name
+	watch func(namespace, name string)
+}
+
+var secrets = newSecretCache()
+
+func newSecretCache() *secretCache {
+	return &secretCache{
+		secrets:    map[string]*v1.Secret{},
+		referenced: map[string]struct{}{},
+	}
+}
+
+func secretKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// GetSecretValue returns the value stored under key in the Kubernetes secret
+// with the given namespace and name. If key is empty, the secret must hold
+// exactly one key. The secret is looked up in the cache filled by the secret
+// watchers, the first lookup of a secret starts watching it. A change of the
+// secret is reported by UpdateSecret and DeleteSecret.
+func GetSecretValue(namespace, name, key string) (string, error) {
+	return secrets.getValue(namespace, name, key)
+}
+
+// SetSecretWatcher sets the function called to start watching a secret when
+// it is looked up for the first time. The function is called right away for
+// the secrets that have already been looked up.
+func SetSecretWatcher(watch func(namespace, name string)) {
+	secrets.setWatcher(watch)
+}
+
+// UpdateSecret adds or updates secret in the secret cache. Returns true if
+// the secret has been looked up before, in which case the policies referring
+// to it need to be regenerated.
+func UpdateSecret(secret *v1.Secret) bool {
+	return secrets.update(secret)
+}
+
+// DeleteSecret removes secret from the secret cache. Returns true if the
+// secret has been looked up before.
+func DeleteSecret(secret *v1.Secret) bool {
+	return secrets.delete(secret)
+}
+
+func (s *secretCache) getValue(namespace, name, key string) (string, error) {
+	k := secretKey(namespace, name)
+
+	s.mutex.Lock()
+	_, referenced := s.referenced[k]
+	s.referenced[k] = struct{}{}
+	secret, ok := s.secrets[k]
+	watch := s.watch
+	s.mutex.Unlock()
+
+	if !referenced && watch != nil {
+		watch(namespace, name)
+	}
+
+	if !ok {
+		return "", fmt.Errorf("unable to get secret %s/%s: secret not found", namespace, name)
+	}
+	return secretValue(secret, key)
+}
+
+func (s *secretCache) setWatcher(watch func(namespace, name string)) {
+	s.mutex.Lock()
+	s.watch = watch
+	referenced := make([]string, 0, len(s.referenced))
+	for k := range s.referenced {
+		referenced = append(referenced, k)
+	}
+	s.mutex.Unlock()
+
+	for _, k := range referenced {
+		nsName := strings.SplitN(k, "/", 2)
+		watch(nsName[0], nsName[1])
+	}
+}
+
+func (s *secretCache) update(secret *v1.Secret) bool {
+	k := secretKey(secret.Namespace, secret.Name)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if old, ok := s.secrets[k]; ok && reflect.DeepEqual(old.Data, secret.Data) {
+		return false
+	}
+	s.secrets[k] = secret
+	_, ok := s.referenced[k]
+	return ok
+}
+
+func (s *secretCache) delete(secret *v1.Secret) bool {
+	k := secretKey(secret.Namespace, secret.Name)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.secrets[k]; !ok {
+		return false
+	}
+	delete(s.secrets, k)
+	_, ok := s.referenced[k]
+	return ok
+}
+
+// secretValue returns the value stored under key in secret. If key is empty,
+// the secret must hold exactly one key.
+func secretValue(secret *v1.Secret, key string) (string, error) {
+	if key == "" {
+		if len(secret.Data) != 1 {
+			return "", fmt.Errorf("secret %s/%s must hold exactly one key if no key is specified, it holds %d",
+				secret.Namespace, secret.Name, len(secret.Data))
+		}
+		for k := range secret.Data {
+			key = k
+		}
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no key %q", secret.Namespace, secret.Name, key)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package k8s
+
+import (
+	. "gopkg.in/check.v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *K8sSuite) TestSecretValue(c *C) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-key"},
+		Data:       map[string][]byte{"key": []byte("secret")},
+	}
+
+	value, err := secretValue(secret, "")
+	c.Assert(err, IsNil)
+	c.Assert(value, Equals, "secret")
+
+	value, err = secretValue(secret, "key")
+	c.Assert(err, IsNil)
+	c.Assert(value, Equals, "secret")
+
+	_, err = secretValue(secret, "other")
+	c.Assert(err, Not(IsNil))
+
+	secret.Data["other"] = []byte("other")
+	_, err = secretValue(secret, "")
+	c.Assert(err, Not(IsNil))
+
+	value, err = secretValue(secret, "other")
+	c.Assert(err, IsNil)
+	c.Assert(value, Equals, "other")
+}
+
+func (s *K8sSuite) TestSecretCache(c *C) {
+	cache := newSecretCache()
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-key"},
+		Data:       map[string][]byte{"key": []byte("secret")},
+	}
+
+	// A secret that has not been looked up does not affect any policy
+	c.Assert(cache.update(secret), Equals, false)
+
+	value, err := cache.getValue("default", "api-key", "key")
+	c.Assert(err, IsNil)
+	c.Assert(value, Equals, "secret")
+
+	_, err = cache.getValue("default", "missing", "")
+	c.Assert(err, Not(IsNil))
+
+	// Unchanged data does not require the policies to be regenerated
+	c.Assert(cache.update(secret.DeepCopy()), Equals, false)
+
+	updated := secret.DeepCopy()
+	updated.Data["key"] = []byte("updated")
+	c.Assert(cache.update(updated), Equals, true)
+	value, err = cache.getValue("default", "api-key", "")
+	c.Assert(err, IsNil)
+	c.Assert(value, Equals, "updated")
+
+	c.Assert(cache.delete(updated), Equals, true)
+	c.Assert(cache.delete(updated), Equals, false)
+	_, err = cache.getValue("default", "api-key", "key")
+	c.Assert(err, Not(IsNil))
+
+	// A secret looked up before it was created is referenced
+	c.Assert(cache.update(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "missing"},
+		Data:       map[string][]byte{"key": []byte("secret")},
+	}), Equals, true)
+}
+
+func (s *K8sSuite) TestSecretCacheWatch(c *C) {
+	cache := newSecretCache()
+	watched := []string{}
+	watch := func(namespace, name string) {
+		watched = append(watched, secretKey(namespace, name))
+	}
+
+	// Secrets looked up before the watcher is set are watched when it is set
+	_, err := cache.getValue("default", "api-key", "")
+	c.Assert(err, Not(IsNil))
+	cache.setWatcher(watch)
+	c.Assert(watched, DeepEquals, []string{"default/api-key"})
+
+	// Each secret is watched once, when it is looked up for the first time
+	_, err = cache.getValue("default", "api-key", "")
+	c.Assert(err, Not(IsNil))
+	_, err = cache.getValue("other", "api-key", "")
+	c.Assert(err, Not(IsNil))
+	_, err = cache.getValue("other", "api-key", "")
+	c.Assert(err, Not(IsNil))
+	c.Assert(watched, DeepEquals, []string{"default/api-key", "other/api-key"})
+}
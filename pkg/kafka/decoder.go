@@ -0,0 +1,553 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// requestHeaderLen is the length of the request header up to and including
+// the correlation ID, preceded by the request size
+const requestHeaderLen = 12
+
+// configResourceTopic is the type of config resources named by a topic
+const configResourceTopic = 2
+
+// requestDecoder decodes the fields of a request body as defined in
+// https://kafka.apache.org/protocol. Flexible versions (KIP-482) encode
+// strings, bytes and arrays with compact lengths and end each structure
+// with tagged fields. Once an error occurs all reads return zero values.
+type requestDecoder struct {
+	data     []byte
+	flexible bool
+	err      error
+
+	// topics are the topic names read by topic()
+	topics []string
+	// unknownTopics is set if the request acts on topics which are not
+	// named: topics identified by their ID only, all topics for null
+	// topic arrays, or config resources other than topics
+	unknownTopics bool
+}
+
+func (d *requestDecoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.data) {
+		d.err = fmt.Errorf("unexpected end of request")
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *requestDecoder) skip(n int) {
+	d.bytes(n)
+}
+
+func (d *requestDecoder) int8() int8 {
+	b := d.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return int8(b[0])
+}
+
+func (d *requestDecoder) int16() int16 {
+	b := d.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(b))
+}
+
+func (d *requestDecoder) int32() int32 {
+	b := d.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (d *requestDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = fmt.Errorf("invalid varint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// compactLen reads the length of a compact field, which is encoded as
+// the length plus one, zero being null. Returns -1 for null.
+func (d *requestDecoder) compactLen() int {
+	n := d.uvarint()
+	if n > uint64(len(d.data))+1 {
+		d.err = fmt.Errorf("invalid length")
+		return 0
+	}
+	return int(n) - 1
+}
+
+// arrayLen returns the number of elements of an array, or -1 if null
+func (d *requestDecoder) arrayLen() int {
+	if d.flexible {
+		return d.compactLen()
+	}
+	n := int(d.int32())
+	if n > len(d.data) {
+		d.err = fmt.Errorf("invalid array length")
+		return 0
+	}
+	return n
+}
+
+// string reads a (nullable) string, returning false if it is null
+func (d *requestDecoder) string() (string, bool) {
+	var n int
+	if d.flexible {
+		n = d.compactLen()
+	} else {
+		n = int(d.int16())
+	}
+	if n < 0 {
+		return "", false
+	}
+	return string(d.bytes(n)), d.err == nil
+}
+
+func (d *requestDecoder) skipString() {
+	d.string()
+}
+
+// skipBytes skips a (nullable) bytes or records field
+func (d *requestDecoder) skipBytes() {
+	var n int
+	if d.flexible {
+		n = d.compactLen()
+	} else {
+		n = int(d.int32())
+	}
+	if n > 0 {
+		d.skip(n)
+	}
+}
+
+// skipTaggedFields skips the tagged fields ending a structure in flexible
+// versions
+func (d *requestDecoder) skipTaggedFields() {
+	if !d.flexible {
+		return
+	}
+	for n := d.uvarint(); n > 0 && d.err == nil; n-- {
+		d.uvarint() // tag
+		d.skip(int(d.uvarint()))
+	}
+}
+
+// structs calls 'f' for each element of an array of structures
+func (d *requestDecoder) structs(f func()) {
+	for n := d.arrayLen(); n > 0 && d.err == nil; n-- {
+		f()
+		d.skipTaggedFields()
+	}
+}
+
+// skipStrings skips an array of strings
+func (d *requestDecoder) skipStrings() {
+	for n := d.arrayLen(); n > 0 && d.err == nil; n-- {
+		d.skipString()
+	}
+}
+
+// skipInt32s skips an array of int32 values
+func (d *requestDecoder) skipInt32s() {
+	if n := d.arrayLen(); n > 0 {
+		d.skip(4 * n)
+	}
+}
+
+// topic reads a (nullable) topic name
+func (d *requestDecoder) topic() {
+	name, ok := d.string()
+	if ok {
+		d.topics = append(d.topics, name)
+	} else {
+		d.unknownTopics = true
+	}
+}
+
+// topicPartitions reads a (nullable) array of topics with an array of int32
+// partition indexes each. A null array requests all topics.
+func (d *requestDecoder) topicPartitions() {
+	n := d.arrayLen()
+	if n < 0 && d.err == nil {
+		d.unknownTopics = true
+	}
+	for ; n > 0 && d.err == nil; n-- {
+		d.topic()
+		d.skipInt32s()
+		d.skipTaggedFields()
+	}
+}
+
+// configResource reads the type and name of a config resource
+func (d *requestDecoder) configResource() {
+	if d.int8() == configResourceTopic {
+		d.topic()
+	} else {
+		d.skipString() // resource_name
+		d.unknownTopics = true
+	}
+}
+
+// topicsParser describes how to read the topics of the versions of a
+// request with topic names up to 'maxVersion'. Newer versions identify
+// topics by IDs or have not been seen yet.
+type topicsParser struct {
+	maxVersion int16
+	// flexibleVersion is the first flexible version, or -1 if none
+	flexibleVersion int16
+	parse           func(d *requestDecoder, version int16)
+}
+
+var topicsParsers = map[int16]topicsParser{
+	api.ProduceKey: {12, 9, func(d *requestDecoder, v int16) {
+		if v >= 3 {
+			d.skipString() // transactional_id
+		}
+		d.skip(2 + 4) // acks, timeout_ms
+		d.structs(func() {
+			d.topic()
+			d.structs(func() {
+				d.skip(4) // index
+				d.skipBytes()
+			})
+		})
+	}},
+	api.FetchKey: {12, 12, func(d *requestDecoder, v int16) {
+		d.skip(4 + 4 + 4) // replica_id, max_wait_ms, min_bytes
+		if v >= 3 {
+			d.skip(4) // max_bytes
+		}
+		if v >= 4 {
+			d.skip(1) // isolation_level
+		}
+		if v >= 7 {
+			d.skip(4 + 4) // session_id, session_epoch
+		}
+		d.structs(func() {
+			d.topic()
+			d.structs(func() {
+				d.skip(4) // partition
+				if v >= 9 {
+					d.skip(4) // current_leader_epoch
+				}
+				d.skip(8) // fetch_offset
+				if v >= 12 {
+					d.skip(4) // last_fetched_epoch
+				}
+				if v >= 5 {
+					d.skip(8) // log_start_offset
+				}
+				d.skip(4) // partition_max_bytes
+			})
+		})
+	}},
+	api.OffsetsKey: {9, 6, func(d *requestDecoder, v int16) {
+		d.skip(4) // replica_id
+		if v >= 2 {
+			d.skip(1) // isolation_level
+		}
+		d.structs(func() {
+			d.topic()
+			d.structs(func() {
+				d.skip(4) // partition_index
+				if v >= 4 {
+					d.skip(4) // current_leader_epoch
+				}
+				d.skip(8) // timestamp
+				if v == 0 {
+					d.skip(4) // max_num_offsets
+				}
+			})
+		})
+	}},
+	api.MetadataKey: {12, 9, func(d *requestDecoder, v int16) {
+		d.structs(func() {
+			if v >= 10 {
+				d.skip(16) // topic_id
+			}
+			d.topic()
+		})
+	}},
+	api.OffsetCommitKey: {9, 8, func(d *requestDecoder, v int16) {
+		d.skipString() // group_id
+		if v >= 1 {
+			d.skip(4)      // generation_id
+			d.skipString() // member_id
+		}
+		if v >= 7 {
+			d.skipString() // group_instance_id
+		}
+		if v >= 2 && v <= 4 {
+			d.skip(8) // retention_time_ms
+		}
+		d.structs(func() {
+			d.topic()
+			d.structs(func() {
+				d.skip(4 + 8) // partition_index, committed_offset
+				if v >= 6 {
+					d.skip(4) // committed_leader_epoch
+				}
+				if v == 1 {
+					d.skip(8) // commit_timestamp
+				}
+				d.skipString() // committed_metadata
+			})
+		})
+	}},
+	api.OffsetFetchKey: {9, 6, func(d *requestDecoder, v int16) {
+		if v <= 7 {
+			d.skipString() // group_id
+			d.topicPartitions()
+			return
+		}
+		d.structs(func() {
+			d.skipString() // group_id
+			if v >= 9 {
+				d.skipString() // member_id
+				d.skip(4)      // member_epoch
+			}
+			d.topicPartitions()
+		})
+	}},
+	api.CreateTopicsKey: {7, 5, func(d *requestDecoder, v int16) {
+		d.structs(func() {
+			d.topic()
+			d.skip(4 + 2) // num_partitions, replication_factor
+			d.structs(func() {
+				d.skip(4) // partition_index
+				d.skipInt32s()
+			})
+			d.structs(func() {
+				d.skipString() // name
+				d.skipString() // value
+			})
+		})
+	}},
+	api.DeleteTopicsKey: {6, 4, func(d *requestDecoder, v int16) {
+		if v <= 5 {
+			for n := d.arrayLen(); n > 0 && d.err == nil; n-- {
+				d.topic()
+			}
+			return
+		}
+		d.structs(func() {
+			d.topic()
+			d.skip(16) // topic_id
+		})
+	}},
+	api.DeleteRecordsKey: {2, 2, func(d *requestDecoder, v int16) {
+		d.structs(func() {
+			d.topic()
+			d.structs(func() {
+				d.skip(4 + 8) // partition_index, offset
+			})
+		})
+	}},
+	api.OffsetForLeaderEpochKey: {4, 4, func(d *requestDecoder, v int16) {
+		if v >= 3 {
+			d.skip(4) // replica_id
+		}
+		d.structs(func() {
+			d.topic()
+			d.structs(func() {
+				d.skip(4) // partition
+				if v >= 2 {
+					d.skip(4) // current_leader_epoch
+				}
+				d.skip(4) // leader_epoch
+			})
+		})
+	}},
+	api.AddPartitionsToTxnKey: {5, 3, func(d *requestDecoder, v int16) {
+		if v <= 3 {
+			d.skipString() // transactional_id
+			d.skip(8 + 2)  // producer_id, producer_epoch
+			d.topicPartitions()
+			return
+		}
+		d.structs(func() {
+			d.skipString()    // transactional_id
+			d.skip(8 + 2 + 1) // producer_id, producer_epoch, verify_only
+			d.topicPartitions()
+		})
+	}},
+	api.WriteTxnMarkersKey: {1, 1, func(d *requestDecoder, v int16) {
+		d.structs(func() {
+			d.skip(8 + 2 + 1) // producer_id, producer_epoch, transaction_result
+			d.topicPartitions()
+			d.skip(4) // coordinator_epoch
+		})
+	}},
+	api.TxnOffsetCommitKey: {4, 3, func(d *requestDecoder, v int16) {
+		d.skipString() // transactional_id
+		d.skipString() // group_id
+		d.skip(8 + 2)  // producer_id, producer_epoch
+		if v >= 3 {
+			d.skip(4)      // generation_id
+			d.skipString() // member_id
+			d.skipString() // group_instance_id
+		}
+		d.structs(func() {
+			d.topic()
+			d.structs(func() {
+				d.skip(4 + 8) // partition_index, committed_offset
+				if v >= 2 {
+					d.skip(4) // committed_leader_epoch
+				}
+				d.skipString() // committed_metadata
+			})
+		})
+	}},
+	api.AlterReplicaLogDirsKey: {2, 2, func(d *requestDecoder, v int16) {
+		d.structs(func() {
+			d.skipString() // path
+			d.topicPartitions()
+		})
+	}},
+	api.DescribeConfigsKey: {4, 4, func(d *requestDecoder, v int16) {
+		d.structs(func() {
+			d.configResource()
+			d.skipStrings() // configuration_keys
+		})
+	}},
+	api.AlterConfigsKey: {2, 2, func(d *requestDecoder, v int16) {
+		d.structs(func() {
+			d.configResource()
+			d.structs(func() {
+				d.skipString() // name
+				d.skipString() // value
+			})
+		})
+	}},
+	api.DescribeLogDirsKey: {4, 2, func(d *requestDecoder, v int16) {
+		d.topicPartitions()
+	}},
+	api.CreatePartitionsKey: {3, 2, func(d *requestDecoder, v int16) {
+		d.structs(func() {
+			d.topic()
+			d.skip(4) // count
+			d.structs(func() {
+				d.skipInt32s() // broker_ids
+			})
+		})
+	}},
+	api.IncrementalAlterConfigsKey: {1, 1, func(d *requestDecoder, v int16) {
+		d.structs(func() {
+			d.configResource()
+			d.structs(func() {
+				d.skipString() // name
+				d.skip(1)      // config_operation
+				d.skipString() // value
+			})
+		})
+	}},
+	api.ElectLeadersKey: {2, 2, func(d *requestDecoder, v int16) {
+		if v >= 1 {
+			d.skip(1) // election_type
+		}
+		d.topicPartitions()
+	}},
+	api.AlterPartitionReassignmentsKey: {0, 0, func(d *requestDecoder, v int16) {
+		d.skip(4) // timeout_ms
+		d.structs(func() {
+			d.topic()
+			d.structs(func() {
+				d.skip(4)      // partition_index
+				d.skipInt32s() // replicas
+			})
+		})
+	}},
+	api.ListPartitionReassignmentsKey: {0, 0, func(d *requestDecoder, v int16) {
+		d.skip(4) // timeout_ms
+		d.topicPartitions()
+	}},
+	api.OffsetDeleteKey: {0, -1, func(d *requestDecoder, v int16) {
+		d.skipString() // group_id
+		d.structs(func() {
+			d.topic()
+			d.structs(func() {
+				d.skip(4) // partition_index
+			})
+		})
+	}},
+	api.DescribeProducersKey: {0, 0, func(d *requestDecoder, v int16) {
+		d.topicPartitions()
+	}},
+}
+
+// isFlexible returns true if 'version' of the request with the API key
+// 'kind' is a flexible version. Only known for the APIs with topics and
+// the APIs relevant for SASL.
+func isFlexible(kind, version int16) bool {
+	if kind == api.SaslAuthenticateKey {
+		return version >= 2
+	}
+	if p, ok := topicsParsers[kind]; ok {
+		return p.flexibleVersion >= 0 && version >= p.flexibleVersion
+	}
+	return false
+}
+
+// newRequestDecoder returns a decoder for the body of the raw request
+// 'rawMsg', which must be at least requestHeaderLen bytes long, and the
+// client ID from its header
+func newRequestDecoder(kind, version int16, rawMsg []byte) (*requestDecoder, string) {
+	d := &requestDecoder{data: rawMsg[requestHeaderLen:]}
+	// The client ID is never a compact string
+	clientID, _ := d.string()
+	d.flexible = isFlexible(kind, version)
+	d.skipTaggedFields()
+	return d, clientID
+}
+
+// parseTopics returns the topics of the request with the API key 'kind'
+// and the body decoded by 'd'. Returns false if the request has topics,
+// but they could not be determined.
+func parseTopics(kind, version int16, d *requestDecoder) ([]string, bool) {
+	p, ok := topicsParsers[kind]
+	if !ok {
+		return nil, !isTopicAPIKey(kind)
+	}
+	if version > p.maxVersion {
+		return nil, false
+	}
+	p.parse(d, version)
+	if d.err != nil || d.unknownTopics {
+		return nil, false
+	}
+	return d.topics, true
+}
@@ -0,0 +1,470 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cilium/cilium/pkg/checker"
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	"github.com/optiopay/kafka/proto"
+	. "gopkg.in/check.v1"
+)
+
+// testEncoder encodes requests in the wire format of the given version
+type testEncoder struct {
+	buf      bytes.Buffer
+	flexible bool
+}
+
+func (e *testEncoder) int8(v int8)   { e.buf.WriteByte(byte(v)) }
+func (e *testEncoder) int16(v int16) { binary.Write(&e.buf, binary.BigEndian, v) }
+func (e *testEncoder) int32(v int32) { binary.Write(&e.buf, binary.BigEndian, v) }
+func (e *testEncoder) int64(v int64) { binary.Write(&e.buf, binary.BigEndian, v) }
+
+func (e *testEncoder) uvarint(v uint64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	e.buf.Write(b[:binary.PutUvarint(b, v)])
+}
+
+func (e *testEncoder) length(n int, compact bool) {
+	if compact {
+		e.uvarint(uint64(n + 1))
+	} else {
+		e.int32(int32(n))
+	}
+}
+
+func (e *testEncoder) string(s string) {
+	if e.flexible {
+		e.uvarint(uint64(len(s) + 1))
+	} else {
+		e.int16(int16(len(s)))
+	}
+	e.buf.WriteString(s)
+}
+
+func (e *testEncoder) nullString() {
+	if e.flexible {
+		e.uvarint(0)
+	} else {
+		e.int16(-1)
+	}
+}
+
+func (e *testEncoder) bytes(b []byte) {
+	e.length(len(b), e.flexible)
+	e.buf.Write(b)
+}
+
+func (e *testEncoder) array(n int) {
+	e.length(n, e.flexible)
+}
+
+// tags writes an empty set of tagged fields, or one tagged field if
+// 'tagged' is set
+func (e *testEncoder) tags(tagged ...byte) {
+	if !e.flexible {
+		return
+	}
+	if len(tagged) == 0 {
+		e.uvarint(0)
+		return
+	}
+	e.uvarint(1)
+	e.uvarint(0)
+	e.uvarint(uint64(len(tagged)))
+	e.buf.Write(tagged)
+}
+
+// int32s writes an array of int32 values
+func (e *testEncoder) int32s(values ...int32) {
+	e.array(len(values))
+	for _, v := range values {
+		e.int32(v)
+	}
+}
+
+// topicPartitions writes an array of topics with partition indexes
+func (e *testEncoder) topicPartitions(topics ...string) {
+	e.array(len(topics))
+	for _, t := range topics {
+		e.string(t)
+		e.int32s(0, 1)
+		e.tags()
+	}
+}
+
+// newTestRequest returns a raw request with the body written by 'body'
+func newTestRequest(kind, version int16, clientID string, body func(e *testEncoder)) []byte {
+	e := &testEncoder{}
+	e.int16(kind)
+	e.int16(version)
+	e.int32(42) // correlation ID
+	e.string(clientID)
+	e.flexible = isFlexible(kind, version)
+	e.tags(1, 2, 3)
+	body(e)
+
+	raw := make([]byte, 4, 4+e.buf.Len())
+	binary.BigEndian.PutUint32(raw, uint32(e.buf.Len()))
+	return append(raw, e.buf.Bytes()...)
+}
+
+func readTestRequest(c *C, raw []byte) *RequestMessage {
+	req, err := ReadRequest(bytes.NewReader(raw))
+	c.Assert(err, IsNil)
+	return req
+}
+
+func (k *kafkaTestSuite) TestReadRequestOptiopay(c *C) {
+	produce := &proto.ProduceReq{
+		CorrelationID: 241,
+		ClientID:      "test",
+		RequiredAcks:  proto.RequiredAcksAll,
+		Topics: []proto.ProduceReqTopic{
+			{Name: "foo", Partitions: []proto.ProduceReqPartition{{ID: 0, Messages: messages}}},
+			{Name: "bar", Partitions: []proto.ProduceReqPartition{{ID: 1, Messages: messages}}},
+		},
+	}
+	raw, err := produce.Bytes(proto.KafkaV0)
+	c.Assert(err, IsNil)
+
+	req := readTestRequest(c, raw)
+	c.Assert(req.request, Not(IsNil))
+	c.Assert(req.topicsParsed, Equals, true)
+	c.Assert(req.GetTopics(), checker.DeepEquals, []string{"foo", "bar"})
+	c.Assert(req.GetClientID(), Equals, "test")
+
+	metadata := &proto.MetadataReq{CorrelationID: 1, ClientID: "test", Topics: []string{"foo"}}
+	raw, err = metadata.Bytes(proto.KafkaV1)
+	c.Assert(err, IsNil)
+
+	req = readTestRequest(c, raw)
+	c.Assert(req.request, Not(IsNil))
+	c.Assert(req.GetTopics(), checker.DeepEquals, []string{"foo"})
+}
+
+func (k *kafkaTestSuite) TestReadRequestTopics(c *C) {
+	records := []byte("records")
+	testCases := []struct {
+		kind    int16
+		version int16
+		body    func(e *testEncoder)
+		topics  []string
+	}{
+		{api.ProduceKey, 9, func(e *testEncoder) {
+			e.nullString() // transactional_id
+			e.int16(-1)
+			e.int32(1000)
+			e.array(2)
+			for _, t := range []string{"foo", "bar"} {
+				e.string(t)
+				e.array(1)
+				e.int32(0)
+				e.bytes(records)
+				e.tags()
+				e.tags(4)
+			}
+		}, []string{"foo", "bar"}},
+		{api.FetchKey, 12, func(e *testEncoder) {
+			e.int32(-1)
+			e.int32(500)
+			e.int32(1)
+			e.int32(1 << 20)
+			e.int8(0)
+			e.int32(0)
+			e.int32(-1)
+			e.array(1)
+			e.string("foo")
+			e.array(2)
+			for p := int32(0); p < 2; p++ {
+				e.int32(p)
+				e.int32(-1)
+				e.int64(100)
+				e.int32(-1)
+				e.int64(-1)
+				e.int32(1 << 20)
+				e.tags()
+			}
+			e.tags()
+		}, []string{"foo"}},
+		{api.OffsetsKey, 7, func(e *testEncoder) {
+			e.int32(-1)
+			e.int8(0)
+			e.array(1)
+			e.string("foo")
+			e.array(1)
+			e.int32(0)
+			e.int32(-1)
+			e.int64(-1)
+			e.tags()
+			e.tags()
+		}, []string{"foo"}},
+		{api.MetadataKey, 12, func(e *testEncoder) {
+			e.array(1)
+			e.buf.Write(make([]byte, 16))
+			e.string("foo")
+			e.tags()
+		}, []string{"foo"}},
+		{api.MetadataKey, 9, func(e *testEncoder) {
+			e.array(-1)
+		}, nil},
+		{api.OffsetCommitKey, 8, func(e *testEncoder) {
+			e.string("group")
+			e.int32(1)
+			e.string("member")
+			e.nullString()
+			e.array(1)
+			e.string("foo")
+			e.array(1)
+			e.int32(0)
+			e.int64(100)
+			e.int32(-1)
+			e.string("metadata")
+			e.tags()
+			e.tags()
+		}, []string{"foo"}},
+		{api.OffsetFetchKey, 9, func(e *testEncoder) {
+			e.array(2)
+			e.string("group1")
+			e.string("member")
+			e.int32(1)
+			e.topicPartitions("foo")
+			e.tags()
+			e.string("group2")
+			e.nullString()
+			e.int32(-1)
+			e.topicPartitions("bar")
+			e.tags()
+		}, []string{"foo", "bar"}},
+		{api.CreateTopicsKey, 5, func(e *testEncoder) {
+			e.array(1)
+			e.string("foo")
+			e.int32(-1)
+			e.int16(-1)
+			e.array(1)
+			e.int32(0)
+			e.int32s(1, 2, 3)
+			e.tags()
+			e.array(1)
+			e.string("cleanup.policy")
+			e.string("compact")
+			e.tags()
+			e.tags()
+		}, []string{"foo"}},
+		{api.DeleteTopicsKey, 4, func(e *testEncoder) {
+			e.array(2)
+			e.string("foo")
+			e.string("bar")
+		}, []string{"foo", "bar"}},
+		{api.DeleteRecordsKey, 2, func(e *testEncoder) {
+			e.array(1)
+			e.string("foo")
+			e.array(1)
+			e.int32(0)
+			e.int64(100)
+			e.tags()
+			e.tags()
+		}, []string{"foo"}},
+		{api.AddPartitionsToTxnKey, 3, func(e *testEncoder) {
+			e.string("txn")
+			e.int64(1)
+			e.int16(0)
+			e.topicPartitions("foo", "bar")
+		}, []string{"foo", "bar"}},
+		{api.AddPartitionsToTxnKey, 4, func(e *testEncoder) {
+			e.array(1)
+			e.string("txn")
+			e.int64(1)
+			e.int16(0)
+			e.int8(1)
+			e.topicPartitions("foo")
+			e.tags()
+		}, []string{"foo"}},
+		{api.TxnOffsetCommitKey, 3, func(e *testEncoder) {
+			e.string("txn")
+			e.string("group")
+			e.int64(1)
+			e.int16(0)
+			e.int32(1)
+			e.string("member")
+			e.nullString()
+			e.array(1)
+			e.string("foo")
+			e.array(1)
+			e.int32(0)
+			e.int64(100)
+			e.int32(-1)
+			e.nullString()
+			e.tags()
+			e.tags()
+		}, []string{"foo"}},
+		{api.OffsetDeleteKey, 0, func(e *testEncoder) {
+			e.string("group")
+			e.array(1)
+			e.string("foo")
+			e.array(1)
+			e.int32(0)
+		}, []string{"foo"}},
+		{api.DescribeConfigsKey, 4, func(e *testEncoder) {
+			e.array(2)
+			for _, t := range []string{"foo", "bar"} {
+				e.int8(configResourceTopic)
+				e.string(t)
+				e.array(1)
+				e.string("retention.ms")
+				e.tags()
+			}
+			e.int8(1)
+			e.int8(0)
+			e.tags()
+		}, []string{"foo", "bar"}},
+		{api.AlterConfigsKey, 1, func(e *testEncoder) {
+			e.array(1)
+			e.int8(configResourceTopic)
+			e.string("foo")
+			e.array(1)
+			e.string("retention.ms")
+			e.nullString()
+			e.int8(0)
+		}, []string{"foo"}},
+		{api.IncrementalAlterConfigsKey, 1, func(e *testEncoder) {
+			e.array(1)
+			e.int8(configResourceTopic)
+			e.string("foo")
+			e.array(1)
+			e.string("retention.ms")
+			e.int8(0)
+			e.string("1000")
+			e.tags()
+			e.tags()
+			e.int8(0)
+			e.tags()
+		}, []string{"foo"}},
+	}
+
+	for _, tc := range testCases {
+		raw := newTestRequest(tc.kind, tc.version, "client", tc.body)
+		req := readTestRequest(c, raw)
+		comment := Commentf("API key %d version %d", tc.kind, tc.version)
+		c.Assert(req.GetAPIKey(), Equals, tc.kind, comment)
+		c.Assert(req.GetVersion(), Equals, tc.version, comment)
+		c.Assert(req.GetCorrelationID(), Equals, CorrelationID(42), comment)
+		c.Assert(req.GetClientID(), Equals, "client", comment)
+		c.Assert(req.topicsParsed, Equals, true, comment)
+		c.Assert(req.GetTopics(), checker.DeepEquals, tc.topics, comment)
+	}
+}
+
+func (k *kafkaTestSuite) TestReadRequestUnknownTopics(c *C) {
+	rules := []api.PortRuleKafka{{Topic: "foo"}}
+	c.Assert(rules[0].Sanitize(), IsNil)
+
+	// Fetch version 13 identifies topics by their IDs
+	raw := newTestRequest(api.FetchKey, 13, "client", func(e *testEncoder) {
+		e.buf.Write(make([]byte, 32))
+	})
+	req := readTestRequest(c, raw)
+	c.Assert(req.topicsParsed, Equals, false)
+	c.Assert(req.MatchesRule(rules), Equals, false)
+	c.Assert(req.MatchesRule([]api.PortRuleKafka{{}}), Equals, true)
+
+	// Metadata requests by topic ID
+	raw = newTestRequest(api.MetadataKey, 10, "client", func(e *testEncoder) {
+		e.array(1)
+		e.buf.Write(make([]byte, 16))
+		e.nullString()
+		e.tags()
+	})
+	req = readTestRequest(c, raw)
+	c.Assert(req.topicsParsed, Equals, false)
+	c.Assert(req.MatchesRule(rules), Equals, false)
+
+	// Truncated body
+	raw = newTestRequest(api.DeleteRecordsKey, 1, "client", func(e *testEncoder) {
+		e.array(1)
+		e.int16(3)
+		e.buf.WriteString("fo")
+	})
+	req = readTestRequest(c, raw)
+	c.Assert(req.topicsParsed, Equals, false)
+	c.Assert(req.MatchesRule(rules), Equals, false)
+
+	// Requests for all topics with null topic arrays
+	for _, tc := range []struct {
+		kind    int16
+		version int16
+		body    func(e *testEncoder)
+	}{
+		{api.ElectLeadersKey, 0, func(e *testEncoder) {
+			e.array(-1)
+			e.int32(1000)
+		}},
+		{api.DescribeLogDirsKey, 0, func(e *testEncoder) {
+			e.array(-1)
+		}},
+		{api.ListPartitionReassignmentsKey, 0, func(e *testEncoder) {
+			e.int32(1000)
+			e.array(-1)
+			e.tags()
+		}},
+		{api.OffsetFetchKey, 2, func(e *testEncoder) {
+			e.string("group")
+			e.array(-1)
+		}},
+	} {
+		comment := Commentf("API key %d version %d", tc.kind, tc.version)
+		req = readTestRequest(c, newTestRequest(tc.kind, tc.version, "client", tc.body))
+		c.Assert(req.topicsParsed, Equals, false, comment)
+		c.Assert(req.MatchesRule(rules), Equals, false, comment)
+		c.Assert(req.MatchesRule([]api.PortRuleKafka{{}}), Equals, true, comment)
+	}
+
+	// Configs of a broker
+	raw = newTestRequest(api.AlterConfigsKey, 0, "client", func(e *testEncoder) {
+		e.array(1)
+		e.int8(4)
+		e.string("1")
+		e.array(0)
+		e.int8(0)
+	})
+	req = readTestRequest(c, raw)
+	c.Assert(req.topicsParsed, Equals, false)
+	c.Assert(req.MatchesRule(rules), Equals, false)
+
+	// Requests without topics
+	raw = newTestRequest(api.HeartbeatKey, 4, "client", func(e *testEncoder) {})
+	req = readTestRequest(c, raw)
+	c.Assert(req.topicsParsed, Equals, true)
+	c.Assert(req.MatchesRule(rules), Equals, true)
+}
+
+func (k *kafkaTestSuite) TestCreateResponseVersions(c *C) {
+	raw := newTestRequest(api.ProduceKey, 3, "client", func(e *testEncoder) {
+		e.nullString()
+		e.int16(-1)
+		e.int32(1000)
+		e.array(0)
+	})
+	req := readTestRequest(c, raw)
+	c.Assert(req.request, IsNil)
+	_, err := req.CreateResponse(proto.ErrTopicAuthorizationFailed)
+	c.Assert(err, Not(IsNil))
+}
@@ -18,7 +18,6 @@ import (
 	"github.com/cilium/cilium/pkg/flowdebug"
 	"github.com/cilium/cilium/pkg/policy/api"
 
-	"github.com/optiopay/kafka/proto"
 	"github.com/sirupsen/logrus"
 )
 
@@ -42,103 +41,34 @@ func isTopicAPIKey(kind int16) bool {
 		api.AddPartitionsToTxnKey,
 		api.WriteTxnMarkersKey,
 		api.TxnOffsetCommitKey,
+		api.DescribeConfigsKey,
+		api.AlterConfigsKey,
 		api.AlterReplicaLogDirsKey,
 		api.DescribeLogDirsKey,
-		api.CreatePartitionsKey:
+		api.CreatePartitionsKey,
+		api.ElectLeadersKey,
+		api.IncrementalAlterConfigsKey,
+		api.AlterPartitionReassignmentsKey,
+		api.ListPartitionReassignmentsKey,
+		api.OffsetDeleteKey,
+		api.DescribeProducersKey:
 
 		return true
 	}
 	return false
 }
 
-func matchNonTopicRequests(req *RequestMessage, rule api.PortRuleKafka) bool {
-	// matchNonTopicRequests() is called when
-	// the kafka parser was not able to parse beyond the generic header.
-	// This could be due to 2 sceanrios:
-	// 1. It was a non-topic request
-	// 2. The parser could not parse further even if there was a topic present.
-	// For scenario 2, if topic is present, we need to return
-	// false since topic can never be associated with this request kind.
-	if rule.Topic != "" && isTopicAPIKey(req.kind) {
-		return false
-	}
-	// TODO add functionality for parsing clientID GH-3097
-	//if rule.ClientID != "" && rule.ClientID != req.GetClientID() {
-	//	return false
-	//}
-	return true
+// isSASLAPIKey returns true if kind is the apiKey of a message type
+// necessary to authenticate with SASL.
+func isSASLAPIKey(kind int16) bool {
+	return kind == api.SaslHandshakeKey || kind == api.SaslAuthenticateKey
 }
 
-func matchProduceReq(req *proto.ProduceReq, rule api.PortRuleKafka) bool {
-	if req == nil {
-		return false
-	}
-
-	if rule.ClientID != "" && rule.ClientID != req.ClientID {
-		return false
-	}
-
-	return true
-}
-
-func matchFetchReq(req *proto.FetchReq, rule api.PortRuleKafka) bool {
-	if req == nil {
-		return false
-	}
-
-	if rule.ClientID != "" && rule.ClientID != req.ClientID {
-		return false
-	}
-
-	return true
-}
-
-func matchOffsetReq(req *proto.OffsetReq, rule api.PortRuleKafka) bool {
-	if req == nil {
-		return false
-	}
-
-	if rule.ClientID != "" && rule.ClientID != req.ClientID {
-		return false
-	}
-
-	return true
-}
-
-func matchMetadataReq(req *proto.MetadataReq, rule api.PortRuleKafka) bool {
-	if req == nil {
-		return false
-	}
-
-	if rule.ClientID != "" && rule.ClientID != req.ClientID {
-		return false
-	}
-
-	return true
-}
-
-func matchOffsetCommitReq(req *proto.OffsetCommitReq, rule api.PortRuleKafka) bool {
-	if req == nil {
-		return false
-	}
-
-	if rule.ClientID != "" && rule.ClientID != req.ClientID {
-		return false
-	}
-
-	return true
-}
-
-func matchOffsetFetchReq(req *proto.OffsetFetchReq, rule api.PortRuleKafka) bool {
-	if req == nil {
-		return false
-	}
-
-	if rule.ClientID != "" && rule.ClientID != req.ClientID {
-		return false
-	}
-
-	return true
+// hasTopics returns true if the topics of the request are known. The
+// topics of requests which could not be parsed beyond the generic header,
+// or which act on topics that are not named, are unknown.
+func (req *RequestMessage) hasTopics() bool {
+	return !req.topicsUnknown && (req.topicsParsed || req.request != nil)
 }
 
 func (req *RequestMessage) ruleMatches(rule api.PortRuleKafka) bool {
@@ -151,6 +81,16 @@ func (req *RequestMessage) ruleMatches(rule api.PortRuleKafka) bool {
 		fieldRule:    rule,
 	}), "Matching Kafka rule")
 
+	if rule.Principal != "" {
+		// The principal is only known after authenticating
+		if isSASLAPIKey(req.kind) {
+			return true
+		}
+		if rule.Principal != req.principal {
+			return false
+		}
+	}
+
 	if !rule.CheckAPIKeyRole(req.kind) {
 		return false
 	}
@@ -166,32 +106,17 @@ func (req *RequestMessage) ruleMatches(rule api.PortRuleKafka) bool {
 		return true
 	}
 
-	switch val := req.request.(type) {
-	case *proto.ProduceReq:
-		return matchProduceReq(val, rule)
-	case *proto.FetchReq:
-		return matchFetchReq(val, rule)
-	case *proto.OffsetReq:
-		return matchOffsetReq(val, rule)
-	case *proto.MetadataReq:
-		return matchMetadataReq(val, rule)
-	case *proto.OffsetCommitReq:
-		return matchOffsetCommitReq(val, rule)
-	case *proto.OffsetFetchReq:
-		return matchOffsetFetchReq(val, rule)
-	case *proto.ConsumerMetadataReq:
-		return true
-	case nil:
-		// This is the case when requests like
-		// heartbeat,findcordinator, et al
-		// are specified. They are not
-		// associated with a topic, but we should
-		// still check for ClientID present in request header.
-		return matchNonTopicRequests(req, rule)
-	default:
-		// If all conditions have been met, allow the request
-		return true
+	if rule.ClientID != "" && rule.ClientID != req.GetClientID() {
+		return false
 	}
+
+	// If the topics of a request which may contain topics are unknown,
+	// the request can never be associated with the topic of the rule.
+	if rule.Topic != "" && isTopicAPIKey(req.kind) && !req.hasTopics() {
+		return false
+	}
+
+	return true
 }
 
 // MatchesRule validates the Kafka request message against the provided list of
@@ -223,3 +148,15 @@ func (req *RequestMessage) MatchesRule(rules []api.PortRuleKafka) bool {
 	}
 	return false
 }
+
+// MatchesTopicRule returns true if 'rule' allows the request for 'topic',
+// which must be one of the topics of the request, or an empty string for
+// requests without topics. The request is allowed by a set of rules if each
+// of its topics is allowed by at least one of the rules, so that the rules
+// can be evaluated independently of each other.
+func (req *RequestMessage) MatchesTopicRule(topic string, rule api.PortRuleKafka) bool {
+	if rule.Topic != "" && topic != "" && rule.Topic != topic {
+		return false
+	}
+	return req.ruleMatches(rule)
+}
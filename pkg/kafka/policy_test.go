@@ -127,3 +127,48 @@ func (k *kafkaTestSuite) TestUnknownRequest(c *C) {
 	reqMsg = RequestMessage{kind: 19}
 	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule1, rule2}), Equals, false)
 }
+
+func (k *kafkaTestSuite) TestPrincipalRule(c *C) {
+	rule := api.PortRuleKafka{Principal: "alice", Role: "consume"}
+	c.Assert(rule.Sanitize(), IsNil)
+	rules := []api.PortRuleKafka{rule}
+
+	// SASL authentication is allowed before the principal is known
+	for _, kind := range []int16{api.SaslHandshakeKey, api.SaslAuthenticateKey} {
+		reqMsg := RequestMessage{kind: kind}
+		c.Assert(reqMsg.MatchesRule(rules), Equals, true)
+	}
+
+	reqMsg := RequestMessage{kind: api.HeartbeatKey}
+	c.Assert(reqMsg.MatchesRule(rules), Equals, false)
+
+	reqMsg.SetPrincipal("bob")
+	c.Assert(reqMsg.MatchesRule(rules), Equals, false)
+
+	reqMsg.SetPrincipal("alice")
+	c.Assert(reqMsg.MatchesRule(rules), Equals, true)
+
+	// The principal does not extend the allowed API keys
+	reqMsg = RequestMessage{kind: api.ProduceKey, principal: "alice"}
+	c.Assert(reqMsg.MatchesRule(rules), Equals, false)
+}
+
+func (k *kafkaTestSuite) TestClientIDRule(c *C) {
+	raw := newTestRequest(api.FetchKey, 12, "client", func(e *testEncoder) {
+		e.int32(-1)
+		e.int32(500)
+		e.int32(1)
+		e.int32(1 << 20)
+		e.int8(0)
+		e.int32(0)
+		e.int32(-1)
+		e.topicPartitions()
+		e.array(0)
+		e.string("")
+		e.tags()
+	})
+	reqMsg := readTestRequest(c, raw)
+
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{{ClientID: "client"}}), Equals, true)
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{{ClientID: "other"}}), Equals, false)
+}
@@ -22,6 +22,7 @@ import (
 	"io"
 
 	"github.com/cilium/cilium/pkg/flowdebug"
+	"github.com/cilium/cilium/pkg/policy/api"
 
 	"github.com/optiopay/kafka/proto"
 )
@@ -32,6 +33,31 @@ type RequestMessage struct {
 	version int16
 	rawMsg  []byte
 	request interface{}
+
+	// clientID is the client ID from the request header
+	clientID string
+
+	// topics are the topics of the request if topicsParsed is true
+	topics       []string
+	topicsParsed bool
+	// topicsUnknown is set if the request acts on topics which are not
+	// named, e.g. all topics
+	topicsUnknown bool
+
+	// principal is the SASL principal the request has been sent by
+	principal string
+}
+
+// maxResponseVersions are the highest versions of the requests for which
+// a response can be created
+var maxResponseVersions = map[int16]int16{
+	proto.ProduceReqKind:          2,
+	proto.FetchReqKind:            6,
+	proto.OffsetReqKind:           2,
+	proto.MetadataReqKind:         4,
+	proto.ConsumerMetadataReqKind: 1,
+	proto.OffsetCommitReqKind:     2,
+	proto.OffsetFetchReqKind:      1,
 }
 
 // CorrelationID represents the correlation id as defined in the Kafka protocol
@@ -53,6 +79,47 @@ func (req *RequestMessage) GetVersion() int16 {
 	return req.version
 }
 
+// APIKeyToString returns the name of the Kafka API key 'apiKey', or its
+// number if it is not known
+func APIKeyToString(apiKey int16) string {
+	if key, ok := api.KafkaReverseAPIKeyMap[apiKey]; ok {
+		return key
+	}
+	return fmt.Sprintf("%d", apiKey)
+}
+
+// GetClientID returns the client ID of the Kafka request
+func (req *RequestMessage) GetClientID() string {
+	switch val := req.request.(type) {
+	case *proto.ProduceReq:
+		return val.ClientID
+	case *proto.FetchReq:
+		return val.ClientID
+	case *proto.OffsetReq:
+		return val.ClientID
+	case *proto.MetadataReq:
+		return val.ClientID
+	case *proto.ConsumerMetadataReq:
+		return val.ClientID
+	case *proto.OffsetCommitReq:
+		return val.ClientID
+	case *proto.OffsetFetchReq:
+		return val.ClientID
+	}
+	return req.clientID
+}
+
+// GetPrincipal returns the SASL principal the Kafka request has been sent
+// by, or an empty string if the client has not authenticated
+func (req *RequestMessage) GetPrincipal() string {
+	return req.principal
+}
+
+// SetPrincipal sets the SASL principal the Kafka request has been sent by
+func (req *RequestMessage) SetPrincipal(principal string) {
+	req.principal = principal
+}
+
 // GetCorrelationID returns the Kafka request correlationID
 func (req *RequestMessage) GetCorrelationID() CorrelationID {
 	if len(req.rawMsg) >= 12 {
@@ -86,6 +153,9 @@ func (req *RequestMessage) String() string {
 
 // GetTopics returns the Kafka request list of topics
 func (req *RequestMessage) GetTopics() []string {
+	if req.topicsParsed {
+		return req.topics
+	}
 	if req.request == nil {
 		return nil
 	}
@@ -172,7 +242,7 @@ func (req *RequestMessage) CreateResponse(err error) (*ResponseMessage, error) {
 	case *proto.OffsetFetchReq:
 		return createOffsetFetchResponse(val, err)
 	case nil:
-		return nil, fmt.Errorf("unsupported request API key %d", req.kind)
+		return nil, fmt.Errorf("unsupported request API key %d version %d", req.kind, req.version)
 	default:
 		// The switch cases above must correspond exactly to the switch cases
 		// in ReadRequest.
@@ -198,6 +268,23 @@ func ReadRequest(reader io.Reader) (*RequestMessage, error) {
 	}
 	req.version = req.extractVersion()
 
+	d, clientID := newRequestDecoder(req.kind, req.version, req.rawMsg)
+	if d.err != nil {
+		return nil, fmt.Errorf("unable to parse request header: %s", d.err)
+	}
+	req.clientID = clientID
+	req.topics, req.topicsParsed = parseTopics(req.kind, req.version, d)
+	req.topicsUnknown = d.unknownTopics
+	if !req.topicsParsed {
+		flowdebug.Log(log.WithField(fieldRequest, req.String()), "Unable to parse topics of Kafka request")
+	}
+
+	// Requests are only parsed into messages for which a response can be
+	// created if their topics are denied
+	if maxVersion, ok := maxResponseVersions[req.kind]; !ok || req.version > maxVersion {
+		return req, nil
+	}
+
 	var nilSlice []byte
 	buf := bytes.NewBuffer(append(nilSlice, req.rawMsg...))
 
@@ -216,8 +303,6 @@ func ReadRequest(reader io.Reader) (*RequestMessage, error) {
 		req.request, err = proto.ReadOffsetCommitReq(buf)
 	case proto.OffsetFetchReqKind:
 		req.request, err = proto.ReadOffsetFetchReq(buf)
-	default:
-		log.WithField(fieldRequest, req.String()).Debugf("Unknown Kafka request API key: %d", req.kind)
 	}
 
 	if err != nil {
@@ -0,0 +1,103 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// Session tracks the SASL authentication of the client of a Kafka
+// connection. Only the PLAIN and SCRAM mechanisms negotiated with
+// SaslHandshake version 1 or later and SaslAuthenticate requests are
+// supported, as the user name of other mechanisms is not visible to the
+// proxy.
+//
+// The principal is taken from the authentication requests forwarded to the
+// broker. The broker closes the connection if the authentication fails, so
+// that further requests on the connection are only processed for the
+// authenticated principal.
+type Session struct {
+	mechanism string
+	principal string
+}
+
+// Principal returns the user name the client has authenticated as, or an
+// empty string if it has not authenticated
+func (s *Session) Principal() string {
+	return s.principal
+}
+
+// HandleRequest must be called for each request forwarded to the broker
+func (s *Session) HandleRequest(req *RequestMessage) {
+	switch req.kind {
+	case api.SaslHandshakeKey:
+		if req.version < 1 {
+			// Version 0 is followed by raw SASL tokens
+			s.mechanism = ""
+			return
+		}
+		d, _ := newRequestDecoder(req.kind, req.version, req.rawMsg)
+		mechanism, _ := d.string()
+		if d.err != nil {
+			mechanism = ""
+		}
+		s.mechanism = strings.ToUpper(mechanism)
+
+	case api.SaslAuthenticateKey:
+		d, _ := newRequestDecoder(req.kind, req.version, req.rawMsg)
+		var n int
+		if d.flexible {
+			n = d.compactLen()
+		} else {
+			n = int(d.int32())
+		}
+		authBytes := d.bytes(n)
+		if d.err != nil {
+			return
+		}
+		if user, ok := saslUser(s.mechanism, authBytes); ok {
+			s.principal = user
+		}
+	}
+}
+
+// saslUser returns the user name contained in the SASL authentication
+// message 'msg' of 'mechanism', or false if the message does not contain
+// a user name
+func saslUser(mechanism string, msg []byte) (string, bool) {
+	switch {
+	case mechanism == "PLAIN":
+		// [authzid] NUL authcid NUL passwd (RFC 4616)
+		fields := bytes.Split(msg, []byte{0})
+		if len(fields) != 3 || len(fields[1]) == 0 {
+			return "", false
+		}
+		return string(fields[1]), true
+
+	case strings.HasPrefix(mechanism, "SCRAM-"):
+		// client-first-message: gs2-header "n=" saslname ",r=" nonce
+		// (RFC 5802). Later messages do not contain a user name.
+		attrs := strings.Split(string(msg), ",")
+		if len(attrs) < 4 || !strings.HasPrefix(attrs[2], "n=") {
+			return "", false
+		}
+		user := strings.NewReplacer("=2C", ",", "=3D", "=").Replace(attrs[2][2:])
+		return user, user != ""
+	}
+	return "", false
+}
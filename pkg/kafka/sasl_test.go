@@ -0,0 +1,80 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package kafka
+
+import (
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	. "gopkg.in/check.v1"
+)
+
+func saslHandshake(c *C, version int16, mechanism string) *RequestMessage {
+	return readTestRequest(c, newTestRequest(api.SaslHandshakeKey, version, "client", func(e *testEncoder) {
+		e.string(mechanism)
+	}))
+}
+
+func saslAuthenticate(c *C, version int16, authBytes string) *RequestMessage {
+	return readTestRequest(c, newTestRequest(api.SaslAuthenticateKey, version, "client", func(e *testEncoder) {
+		e.bytes([]byte(authBytes))
+		e.tags()
+	}))
+}
+
+func (k *kafkaTestSuite) TestSessionPlain(c *C) {
+	s := &Session{}
+	s.HandleRequest(saslHandshake(c, 1, "plain"))
+	c.Assert(s.Principal(), Equals, "")
+
+	s.HandleRequest(saslAuthenticate(c, 0, "\x00alice\x00secret"))
+	c.Assert(s.Principal(), Equals, "alice")
+
+	// Flexible version
+	s = &Session{}
+	s.HandleRequest(saslHandshake(c, 1, "PLAIN"))
+	s.HandleRequest(saslAuthenticate(c, 2, "admin\x00bob\x00secret"))
+	c.Assert(s.Principal(), Equals, "bob")
+
+	// Malformed message
+	s = &Session{}
+	s.HandleRequest(saslHandshake(c, 1, "PLAIN"))
+	s.HandleRequest(saslAuthenticate(c, 1, "alice"))
+	c.Assert(s.Principal(), Equals, "")
+}
+
+func (k *kafkaTestSuite) TestSessionScram(c *C) {
+	s := &Session{}
+	s.HandleRequest(saslHandshake(c, 1, "SCRAM-SHA-256"))
+	s.HandleRequest(saslAuthenticate(c, 1, "n,,n=user=2Cname=3D,r=fyko+d2lbbFgONRv9qkxdawL"))
+	c.Assert(s.Principal(), Equals, "user,name=")
+
+	// The client-final-message does not change the principal
+	s.HandleRequest(saslAuthenticate(c, 1, "c=biws,r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,p=v0X8v3Bz2T0CJGbJQyF0X+HI4Ts="))
+	c.Assert(s.Principal(), Equals, "user,name=")
+}
+
+func (k *kafkaTestSuite) TestSessionUnsupported(c *C) {
+	// Version 0 handshakes are followed by raw SASL tokens
+	s := &Session{}
+	s.HandleRequest(saslHandshake(c, 0, "PLAIN"))
+	s.HandleRequest(saslAuthenticate(c, 0, "\x00alice\x00secret"))
+	c.Assert(s.Principal(), Equals, "")
+
+	s.HandleRequest(saslHandshake(c, 1, "GSSAPI"))
+	s.HandleRequest(saslAuthenticate(c, 0, "\x00alice\x00secret"))
+	c.Assert(s.Principal(), Equals, "")
+}
@@ -202,6 +202,14 @@ type Allocator struct {
 
 	// disableGC disables the garbage collector
 	disableGC bool
+
+	// maxConcurrency is the maximum number of allocations performed
+	// against the kvstore in parallel. If 0, no limit is applied.
+	maxConcurrency int
+
+	// queue limits parallel kvstore allocations and batches concurrent
+	// allocation requests for the same key
+	queue *allocationQueue
 }
 
 func locklessCapability() bool {
@@ -223,6 +231,8 @@ type AllocatorOption func(*Allocator)
 //  - WithSuffix(string) - customize the node specifix suffix to attach to keys
 //  - WithMin(id) - minimum ID to allocate (default: 1)
 //  - WithMax(id) - maximum ID to allocate (default max(uint64))
+//  - WithMaxConcurrency(n) - maximum number of parallel kvstore allocations
+//  - WithBackoff(backoff) - backoff configuration between allocation attempts
 //
 // After creation, IDs can be allocated with Allocate() and released with
 // Release()
@@ -271,7 +281,12 @@ func NewAllocator(basePath string, typ AllocatorKey, opts ...AllocatorOption) (*
 		return nil, errors.New("Maximum ID must be greater than minimum ID")
 	}
 
+	if a.maxConcurrency < 0 {
+		return nil, errors.New("maximum allocation concurrency must be >= 0")
+	}
+
 	a.idPool = idpool.NewIDPool(a.min, a.max)
+	a.queue = newAllocationQueue(a.maxConcurrency)
 
 	a.initialListDone = a.mainCache.start(a)
 	if !a.disableGC {
@@ -332,6 +347,19 @@ func WithoutGC() AllocatorOption {
 	return func(a *Allocator) { a.disableGC = true }
 }
 
+// WithMaxConcurrency limits the number of allocations performed against the
+// kvstore in parallel. Additional allocation requests are queued until a slot
+// becomes available. A value of 0 disables the limit.
+func WithMaxConcurrency(n int) AllocatorOption {
+	return func(a *Allocator) { a.maxConcurrency = n }
+}
+
+// WithBackoff sets the backoff configuration used between failed allocation
+// attempts
+func WithBackoff(b backoff.Exponential) AllocatorOption {
+	return func(a *Allocator) { a.backoffTemplate = b }
+}
+
 // Delete deletes an allocator and stops the garbage collector
 func (a *Allocator) Delete() {
 	close(a.stopGC)
@@ -523,27 +551,55 @@ func (a *Allocator) lockedAllocate(key AllocatorKey) (idpool.ID, bool, error) {
 // most likely due to a parallel allocation of the same ID by another user,
 // allocation is re-attempted for maxAllocAttempts times.
 //
+// Concurrent requests for the same key are batched: only the first request
+// performs the allocation against the kvstore while all others wait for it
+// to complete and then reuse the locally allocated ID.
+//
 // Returns the ID allocated to the key, if the ID had to be allocated, then
 // true is returned. An error is returned in case of failure.
 func (a *Allocator) Allocate(key AllocatorKey) (idpool.ID, bool, error) {
+	k := key.GetKey()
+
+	kvstore.Trace("Allocating key", nil, logrus.Fields{fieldKey: key})
+
+	for {
+		// Check our list of local keys already in use and increment
+		// the refcnt. The returned key must be released afterwards. No
+		// kvstore operation was performed for this allocation
+		if val := a.localKeys.use(k); val != idpool.NoID {
+			kvstore.Trace("Reusing local id", nil, logrus.Fields{fieldID: val, fieldKey: key})
+			a.mainCache.insert(key, val)
+			return val, false, nil
+		}
+
+		p, owner := a.queue.join(k)
+		if owner {
+			value, isNew, err := a.allocate(key)
+			a.queue.complete(k, p, err)
+			return value, isNew, err
+		}
+
+		kvstore.Trace("Waiting for pending allocation", nil, logrus.Fields{fieldKey: key})
+		<-p.done
+		if p.err != nil {
+			return 0, false, p.err
+		}
+
+		// The pending allocation succeeded, the next iteration will
+		// find the key in the list of local keys unless it has been
+		// released again in the meantime.
+	}
+}
+
+// allocate allocates the key in the kvstore while respecting the concurrency
+// limit of the allocator and backing off between failed attempts
+func (a *Allocator) allocate(key AllocatorKey) (idpool.ID, bool, error) {
 	var (
 		err   error
 		value idpool.ID
 		isNew bool
-		k     = key.GetKey()
 	)
 
-	kvstore.Trace("Allocating key", nil, logrus.Fields{fieldKey: key})
-
-	// Check our list of local keys already in use and increment the
-	// refcnt. The returned key must be released afterwards. No kvstore
-	// operation was performed for this allocation
-	if val := a.localKeys.use(k); val != idpool.NoID {
-		kvstore.Trace("Reusing local id", nil, logrus.Fields{fieldID: val, fieldKey: key})
-		a.mainCache.insert(key, val)
-		return val, false, nil
-	}
-
 	kvstore.Trace("Allocating from kvstore", nil, logrus.Fields{fieldKey: key})
 
 	// make a copy of the template and customize it
@@ -552,7 +608,9 @@ func (a *Allocator) Allocate(key AllocatorKey) (idpool.ID, bool, error) {
 
 	for attempt := 0; attempt < maxAllocAttempts; attempt++ {
 		// FIXME: Add non-locking variant
+		a.queue.acquire()
 		value, isNew, err = a.lockedAllocate(key)
+		a.queue.release()
 		if err == nil {
 			a.mainCache.insert(key, value)
 			return value, isNew, nil
@@ -208,6 +208,12 @@ func (c *cache) start(a *Allocator) waitChan {
 
 						if k, ok := c.nextCache[id]; ok && k != nil {
 							delete(c.nextKeyCache, k.GetKey())
+							// Deletion events do not carry the key,
+							// provide the key of the released ID
+							// to the event consumer
+							if key == nil {
+								key = k
+							}
 						}
 
 						delete(c.nextCache, id)
@@ -0,0 +1,125 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package allocator
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/metrics"
+)
+
+// pendingAllocation is an allocation of a key which is currently being
+// performed against the kvstore. Additional requests for the same key wait
+// for the pending allocation to complete instead of contending for the same
+// kvstore lock.
+type pendingAllocation struct {
+	// done is closed when the allocation has completed
+	done chan struct{}
+
+	// err is the result of the allocation, it may only be accessed after
+	// done has been closed
+	err error
+}
+
+// allocationQueue limits the number of allocations performed against the
+// kvstore in parallel and batches requests for identical keys.
+type allocationQueue struct {
+	mutex lock.Mutex
+
+	// slots is a semaphore limiting the number of parallel kvstore
+	// allocations. If nil, the number of allocations is not limited.
+	slots chan struct{}
+
+	// pending is the map of all allocations currently queued or in
+	// progress indexed by key
+	pending map[string]*pendingAllocation
+
+	// waiting is the number of allocations waiting for a free slot
+	waiting int
+}
+
+func newAllocationQueue(maxConcurrency int) *allocationQueue {
+	q := &allocationQueue{
+		pending: map[string]*pendingAllocation{},
+	}
+
+	if maxConcurrency > 0 {
+		q.slots = make(chan struct{}, maxConcurrency)
+	}
+
+	return q
+}
+
+// join registers an allocation request for key. If an allocation for the key
+// is already pending, the pending allocation is returned and owner is false.
+// Otherwise, a new pending allocation is created and the caller becomes the
+// owner. The owner is responsible for calling complete() when done.
+func (q *allocationQueue) join(key string) (p *pendingAllocation, owner bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if p, ok := q.pending[key]; ok {
+		metrics.AllocatorBatchedRequests.Inc()
+		return p, false
+	}
+
+	p = &pendingAllocation{done: make(chan struct{})}
+	q.pending[key] = p
+	return p, true
+}
+
+// complete marks the pending allocation of key as completed with the given
+// result and wakes up all requests waiting for it
+func (q *allocationQueue) complete(key string, p *pendingAllocation, err error) {
+	q.mutex.Lock()
+	delete(q.pending, key)
+	q.mutex.Unlock()
+
+	p.err = err
+	close(p.done)
+}
+
+// acquire blocks until an allocation slot is available
+func (q *allocationQueue) acquire() {
+	if q.slots == nil {
+		return
+	}
+
+	start := time.Now()
+
+	q.mutex.Lock()
+	q.waiting++
+	metrics.AllocatorQueueDepth.Set(float64(q.waiting))
+	q.mutex.Unlock()
+
+	q.slots <- struct{}{}
+
+	q.mutex.Lock()
+	q.waiting--
+	metrics.AllocatorQueueDepth.Set(float64(q.waiting))
+	q.mutex.Unlock()
+
+	metrics.AllocatorQueueWaitDuration.Observe(time.Since(start).Seconds())
+}
+
+// release returns an allocation slot acquired with acquire()
+func (q *allocationQueue) release() {
+	if q.slots == nil {
+		return
+	}
+
+	<-q.slots
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package allocator
+
+import (
+	"fmt"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type QueueSuite struct{}
+
+var _ = Suite(&QueueSuite{})
+
+func (s *QueueSuite) TestJoin(c *C) {
+	q := newAllocationQueue(0)
+
+	p1, owner := q.join("foo")
+	c.Assert(owner, Equals, true)
+
+	p2, owner := q.join("foo")
+	c.Assert(owner, Equals, false)
+	c.Assert(p2, Equals, p1)
+
+	p3, owner := q.join("bar")
+	c.Assert(owner, Equals, true)
+	c.Assert(p3, Not(Equals), p1)
+
+	err := fmt.Errorf("failed")
+	q.complete("foo", p1, err)
+
+	select {
+	case <-p2.done:
+		c.Assert(p2.err, Equals, err)
+	default:
+		c.Fatal("pending allocation not completed")
+	}
+
+	// a new request after completion must become the owner again
+	p4, owner := q.join("foo")
+	c.Assert(owner, Equals, true)
+	c.Assert(p4, Not(Equals), p1)
+}
+
+func (s *QueueSuite) TestConcurrencyLimit(c *C) {
+	q := newAllocationQueue(1)
+
+	q.acquire()
+
+	acquired := make(chan struct{})
+	go func() {
+		q.acquire()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		c.Fatal("slot acquired while limit reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	q.mutex.Lock()
+	c.Assert(q.waiting, Equals, 1)
+	q.mutex.Unlock()
+
+	q.release()
+
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		c.Fatal("slot not acquired after release")
+	}
+
+	q.release()
+}
+
+func (s *QueueSuite) TestUnlimited(c *C) {
+	q := newAllocationQueue(0)
+
+	for i := 0; i < 100; i++ {
+		q.acquire()
+	}
+	for i := 0; i < 100; i++ {
+		q.release()
+	}
+}
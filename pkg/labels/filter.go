@@ -30,7 +30,7 @@ import (
 var (
 	log                  = logging.DefaultLogger.WithField(logfields.LogSubsys, "labels-filter")
 	validLabelPrefixesMU lock.RWMutex
-	validLabelPrefixes   *labelPrefixCfg // Label prefixes used to filter from all labels
+	validLabelPrefixes   *LabelPrefixCfg // Label prefixes used to filter from all labels
 )
 
 const (
@@ -59,6 +59,20 @@ func (p LabelPrefix) String() string {
 	return s
 }
 
+// expression returns the LabelPrefix in the format accepted by
+// parseLabelPrefix
+func (p LabelPrefix) expression() string {
+	s := p.Prefix
+	if p.Ignore {
+		s = "!" + s
+	}
+	if p.Source != "" {
+		s = p.Source + ":" + s
+	}
+
+	return s
+}
+
 // matches returns true and the length of the matched section if the label is
 // matched by the LabelPrefix. The Ignore flag has no effect at this point.
 func (p LabelPrefix) matches(l *Label) (bool, int) {
@@ -93,6 +107,10 @@ func parseLabelPrefix(label string) (*LabelPrefix, error) {
 		labelPrefix.Prefix = label
 	}
 
+	if labelPrefix.Prefix == "" {
+		return nil, fmt.Errorf("label prefix must not be empty")
+	}
+
 	if labelPrefix.Prefix[0] == '!' {
 		labelPrefix.Ignore = true
 		labelPrefix.Prefix = labelPrefix.Prefix[1:]
@@ -116,33 +134,57 @@ func ParseLabelPrefixCfg(prefixes []string, file string) error {
 		return fmt.Errorf("Unable to read label prefix file: %s", err)
 	}
 
-	for _, label := range prefixes {
-		p, err := parseLabelPrefix(label)
-		if err != nil {
-			return err
-		}
+	if err := cfg.appendPrefixes(prefixes); err != nil {
+		return err
+	}
 
-		if !p.Ignore {
-			cfg.whitelist = true
-		}
+	SetLabelPrefixCfg(cfg)
 
-		cfg.LabelPrefixes = append(cfg.LabelPrefixes, p)
+	return nil
+}
+
+// NewLabelPrefixCfg returns a label prefix configuration consisting of
+// exactly the given list of prefixes. Unlike ParseLabelPrefixCfg, the default
+// prefixes are not included.
+func NewLabelPrefixCfg(prefixes []string) (*LabelPrefixCfg, error) {
+	cfg := &LabelPrefixCfg{
+		Version:       LPCfgFileVersion,
+		LabelPrefixes: []*LabelPrefix{},
 	}
 
+	if err := cfg.appendPrefixes(prefixes); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// GetLabelPrefixCfg returns the label prefix configuration currently used to
+// filter labels. The returned configuration must not be modified.
+func GetLabelPrefixCfg() *LabelPrefixCfg {
+	validLabelPrefixesMU.RLock()
+	defer validLabelPrefixesMU.RUnlock()
+	return validLabelPrefixes
+}
+
+// SetLabelPrefixCfg replaces the label prefix configuration used to filter
+// labels. The configuration must not be modified after it has been set.
+func SetLabelPrefixCfg(cfg *LabelPrefixCfg) {
+	validLabelPrefixesMU.Lock()
 	validLabelPrefixes = cfg
+	validLabelPrefixesMU.Unlock()
 
 	log.Info("Valid label prefix configuration:")
-	for _, l := range validLabelPrefixes.LabelPrefixes {
+	for _, l := range cfg.LabelPrefixes {
 		log.Infof(" - %s", l)
 	}
-
-	return nil
 }
 
-// labelPrefixCfg is the label prefix configuration to filter labels of started
+// LabelPrefixCfg is the label prefix configuration to filter labels of started
 // containers.
+// +k8s:deepcopy-gen=false
 // +k8s:openapi-gen=false
-type labelPrefixCfg struct {
+type LabelPrefixCfg struct {
 	Version       int            `json:"version"`
 	LabelPrefixes []*LabelPrefix `json:"valid-prefixes"`
 	// whitelist if true, indicates that an inclusive rule has to match
@@ -150,10 +192,39 @@ type labelPrefixCfg struct {
 	whitelist bool
 }
 
+// appendPrefixes parses all prefixes and appends them to the configuration
+func (cfg *LabelPrefixCfg) appendPrefixes(prefixes []string) error {
+	for _, label := range prefixes {
+		p, err := parseLabelPrefix(label)
+		if err != nil {
+			return err
+		}
+
+		if !p.Ignore {
+			cfg.whitelist = true
+		}
+
+		cfg.LabelPrefixes = append(cfg.LabelPrefixes, p)
+	}
+
+	return nil
+}
+
+// Prefixes returns all label prefixes of the configuration in the format
+// accepted by NewLabelPrefixCfg
+func (cfg *LabelPrefixCfg) Prefixes() []string {
+	prefixes := make([]string, 0, len(cfg.LabelPrefixes))
+	for _, p := range cfg.LabelPrefixes {
+		prefixes = append(prefixes, p.expression())
+	}
+
+	return prefixes
+}
+
 // defaultLabelPrefixCfg returns a default LabelPrefixCfg using the latest
 // LPCfgFileVersion
-func defaultLabelPrefixCfg() *labelPrefixCfg {
-	cfg := &labelPrefixCfg{
+func defaultLabelPrefixCfg() *LabelPrefixCfg {
+	cfg := &LabelPrefixCfg{
 		Version:       LPCfgFileVersion,
 		LabelPrefixes: []*LabelPrefix{},
 	}
@@ -187,7 +258,7 @@ func defaultLabelPrefixCfg() *labelPrefixCfg {
 
 // readLabelPrefixCfgFrom reads a label prefix configuration file from fileName. If the
 // version is not supported by us it returns an error.
-func readLabelPrefixCfgFrom(fileName string) (*labelPrefixCfg, error) {
+func readLabelPrefixCfgFrom(fileName string) (*LabelPrefixCfg, error) {
 	// if not file is specified, the default is empty
 	if fileName == "" {
 		return defaultLabelPrefixCfg(), nil
@@ -198,7 +269,7 @@ func readLabelPrefixCfgFrom(fileName string) (*labelPrefixCfg, error) {
 		return nil, err
 	}
 	defer f.Close()
-	lpc := labelPrefixCfg{}
+	lpc := LabelPrefixCfg{}
 	err = json.NewDecoder(f).Decode(&lpc)
 	if err != nil {
 		return nil, err
@@ -220,14 +291,14 @@ func readLabelPrefixCfgFrom(fileName string) (*labelPrefixCfg, error) {
 	return &lpc, nil
 }
 
-func (cfg *labelPrefixCfg) filterLabels(lbls Labels) (identityLabels, informationLabels Labels) {
+// FilterLabels returns Labels from the given labels that have the same source
+// and the same prefix as one of the valid prefixes of the configuration, as
+// well as labels that do not match the aforementioned filtering criteria.
+func (cfg *LabelPrefixCfg) FilterLabels(lbls Labels) (identityLabels, informationLabels Labels) {
 	if lbls == nil {
 		return nil, nil
 	}
 
-	validLabelPrefixesMU.RLock()
-	defer validLabelPrefixesMU.RUnlock()
-
 	identityLabels = Labels{}
 	informationLabels = Labels{}
 	for k, v := range lbls {
@@ -274,5 +345,5 @@ func (cfg *labelPrefixCfg) filterLabels(lbls Labels) (identityLabels, informatio
 // same prefix as one of lpc valid prefixes, as well as labels that do not match
 // the aforementioned filtering criteria.
 func FilterLabels(lbls Labels) (identityLabels, informationLabels Labels) {
-	return validLabelPrefixes.filterLabels(lbls)
+	return GetLabelPrefixCfg().FilterLabels(lbls)
 }
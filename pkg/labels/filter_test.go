@@ -59,11 +59,11 @@ func (s *LabelsPrefCfgSuite) TestFilterLabels(c *C) {
 		"controller-revision-hash":                       "123456",
 	}
 	allLabels := Map2Labels(allNormalLabels, LabelSourceContainer)
-	filtered, _ := dlpcfg.filterLabels(allLabels)
+	filtered, _ := dlpcfg.FilterLabels(allLabels)
 	c.Assert(len(filtered), Equals, 1)
 	allLabels["id.lizards"] = NewLabel("id.lizards", "web", LabelSourceContainer)
 	allLabels["id.lizards.k8s"] = NewLabel("id.lizards.k8s", "web", LabelSourceK8s)
-	filtered, _ = dlpcfg.filterLabels(allLabels)
+	filtered, _ = dlpcfg.FilterLabels(allLabels)
 	c.Assert(len(filtered), Equals, 3)
 	c.Assert(filtered, checker.DeepEquals, wanted)
 
@@ -71,3 +71,48 @@ func (s *LabelsPrefCfgSuite) TestFilterLabels(c *C) {
 	allLabels["id.lizards"].Source = "I can change this and doesn't affect any one"
 	c.Assert(filtered, checker.DeepEquals, wanted)
 }
+
+func (s *LabelsPrefCfgSuite) TestNewLabelPrefixCfg(c *C) {
+	cfg, err := NewLabelPrefixCfg([]string{"k8s:app", "!io.kubernetes", ":!ignore"})
+	c.Assert(err, IsNil)
+	c.Assert(cfg.whitelist, Equals, true)
+	c.Assert(cfg.Prefixes(), checker.DeepEquals, []string{"k8s:app", "!io.kubernetes", "!ignore"})
+
+	// The string representation must be accepted again
+	cfg2, err := NewLabelPrefixCfg(cfg.Prefixes())
+	c.Assert(err, IsNil)
+	c.Assert(cfg2.Prefixes(), checker.DeepEquals, cfg.Prefixes())
+
+	lbls := Labels{
+		"app":  NewLabel("app", "web", LabelSourceK8s),
+		"role": NewLabel("role", "db", LabelSourceK8s),
+	}
+	identityLabels, informationLabels := cfg.FilterLabels(lbls)
+	c.Assert(identityLabels, checker.DeepEquals, Labels{"app": lbls["app"]})
+	c.Assert(informationLabels, checker.DeepEquals, Labels{"role": lbls["role"]})
+
+	_, err = NewLabelPrefixCfg([]string{"k8s:"})
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewLabelPrefixCfg([]string{"k8s:[invalid"})
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *LabelsPrefCfgSuite) TestSetLabelPrefixCfg(c *C) {
+	old := GetLabelPrefixCfg()
+	defer func() {
+		validLabelPrefixes = old
+	}()
+
+	cfg, err := NewLabelPrefixCfg([]string{"role"})
+	c.Assert(err, IsNil)
+	SetLabelPrefixCfg(cfg)
+	c.Assert(GetLabelPrefixCfg(), Equals, cfg)
+
+	lbls := Labels{
+		"app":  NewLabel("app", "web", LabelSourceContainer),
+		"role": NewLabel("role", "db", LabelSourceContainer),
+	}
+	identityLabels, _ := FilterLabels(lbls)
+	c.Assert(identityLabels, checker.DeepEquals, Labels{"role": lbls["role"]})
+}
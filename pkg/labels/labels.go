@@ -41,6 +41,10 @@ const (
 	// inside the cluster
 	IDNameCluster = "cluster"
 
+	// IDNameRemoteNode is the label used to identify all nodes of the
+	// cluster except the local node
+	IDNameRemoteNode = "remote-node"
+
 	// IDNameHealth is the label used for the local cilium-health endpoint
 	IDNameHealth = "health"
 
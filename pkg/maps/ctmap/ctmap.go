@@ -25,6 +25,7 @@ import (
 	"unsafe"
 
 	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/metrics"
@@ -88,15 +89,17 @@ const (
 
 type mapAttributes struct {
 	keySize    int
+	valueSize  int
 	maxEntries int
 	parser     bpf.DumpParser
 	bpfDefine  string
 }
 
-func setupMapInfo(mapType MapType, define string, keySize, maxEntries int, parser bpf.DumpParser) {
+func setupMapInfo(mapType MapType, define string, keySize, valueSize, maxEntries int, parser bpf.DumpParser) {
 	mapInfo[mapType] = mapAttributes{
 		bpfDefine:  define,
 		keySize:    keySize,
+		valueSize:  valueSize,
 		maxEntries: maxEntries,
 		parser:     parser,
 	}
@@ -104,31 +107,37 @@ func setupMapInfo(mapType MapType, define string, keySize, maxEntries int, parse
 
 // InitMapInfo builds the information about different CT maps for the
 // combination of L3/L4 protocols, using the specified limits on TCP vs non-TCP
-// maps.
-func InitMapInfo(tcpMaxEntries, anyMaxEntries int) {
+// maps. If proxyPort is true, the CT entries record the proxy port of the
+// connections, which grows them to the full size of CtEntry.
+func InitMapInfo(tcpMaxEntries, anyMaxEntries int, proxyPort bool) {
 	mapInfo = make(map[MapType]mapAttributes)
 
+	valueSize := entrySizeNoProxyPort
+	if proxyPort {
+		valueSize = entrySize
+	}
+
 	mapType := MapTypeIPv4TCPLocal
 	for _, maxEntries := range []int{MapNumEntriesLocal, tcpMaxEntries} {
 		setupMapInfo(MapType(mapType), "CT_MAP_TCP4",
-			int(unsafe.Sizeof(CtKey4{})), maxEntries, ct4DumpParser)
+			int(unsafe.Sizeof(CtKey4{})), valueSize, maxEntries, ct4DumpParser)
 		mapType++
 		setupMapInfo(MapType(mapType), "CT_MAP_TCP6",
-			int(unsafe.Sizeof(CtKey6{})), maxEntries, ct6DumpParser)
+			int(unsafe.Sizeof(CtKey6{})), valueSize, maxEntries, ct6DumpParser)
 		mapType++
 	}
 	for _, maxEntries := range []int{MapNumEntriesLocal, anyMaxEntries} {
 		setupMapInfo(MapType(mapType), "CT_MAP_ANY4",
-			int(unsafe.Sizeof(CtKey4{})), maxEntries, ct4DumpParser)
+			int(unsafe.Sizeof(CtKey4{})), valueSize, maxEntries, ct4DumpParser)
 		mapType++
 		setupMapInfo(MapType(mapType), "CT_MAP_ANY6",
-			int(unsafe.Sizeof(CtKey6{})), maxEntries, ct6DumpParser)
+			int(unsafe.Sizeof(CtKey6{})), valueSize, maxEntries, ct6DumpParser)
 		mapType++
 	}
 }
 
 func init() {
-	InitMapInfo(option.CTMapEntriesGlobalTCPDefault, option.CTMapEntriesGlobalAnyDefault)
+	InitMapInfo(option.CTMapEntriesGlobalTCPDefault, option.CTMapEntriesGlobalAnyDefault, false)
 }
 
 // CtEndpoint represents an endpoint for the functions required to manage
@@ -180,6 +189,10 @@ type GCFilter struct {
 
 	// MatchIPs is the list of IPs to remove from the conntrack table
 	MatchIPs map[string]struct{}
+
+	// ProxyPort, if not zero, removes all entries of connections which
+	// were redirected to the proxy port
+	ProxyPort uint16
 }
 
 // ToString iterates through Map m and writes the values of the ct entries in m
@@ -200,10 +213,19 @@ func (m *Map) DumpEntries() (string, error) {
 	return buffer.String(), err
 }
 
+// padEntry extends the value of a CT entry which does not record the proxy
+// port to the full size of CtEntry.
+func padEntry(value []byte) []byte {
+	if len(value) > 0 && len(value) < entrySize {
+		value = append(value, make([]byte, entrySize-len(value))...)
+	}
+	return value
+}
+
 func ct4DumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
 	k, v := CtKey4Global{}, CtEntry{}
 
-	if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+	if err := bpf.ConvertKeyValue(key, padEntry(value), &k, &v); err != nil {
 		return nil, nil, err
 	}
 	return &k, &v, nil
@@ -212,7 +234,7 @@ func ct4DumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
 func ct6DumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
 	k, v := CtKey6Global{}, CtEntry{}
 
-	if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+	if err := bpf.ConvertKeyValue(key, padEntry(value), &k, &v); err != nil {
 		return nil, nil, err
 	}
 	return &k, &v, nil
@@ -224,7 +246,7 @@ func NewMap(mapName string, mapType MapType) *Map {
 		Map: *bpf.NewMap(mapName,
 			bpf.GetLRUMapType(),
 			mapInfo[mapType].keySize,
-			int(unsafe.Sizeof(CtEntry{})),
+			mapInfo[mapType].valueSize,
 			mapInfo[mapType].maxEntries,
 			0, 0,
 			mapInfo[mapType].parser,
@@ -235,6 +257,28 @@ func NewMap(mapName string, mapType MapType) *Map {
 	return result
 }
 
+// Open opens the map pinned at the path of m. The size of the entries is
+// taken from the pinned map, as it depends on whether the agent which
+// created the map records the proxy port of connections.
+func (m *Map) Open() error {
+	if err := m.Map.Open(); err != nil {
+		return err
+	}
+
+	info, err := bpf.GetMapInfo(os.Getpid(), m.GetFd())
+	if err != nil {
+		m.Close()
+		return fmt.Errorf("unable to get info of CT map: %s", err)
+	}
+	if info.ValueSize != uint32(entrySizeNoProxyPort) && info.ValueSize != uint32(entrySize) {
+		m.Close()
+		return fmt.Errorf("unexpected CT entry size %d", info.ValueSize)
+	}
+	m.MapInfo.ValueSize = info.ValueSize
+
+	return nil
+}
+
 // doGC6 iterates through a CTv6 map and drops entries based on the given
 // filter.
 func doGC6(m *Map, filter *GCFilter) gcStats {
@@ -322,6 +366,10 @@ func (f *GCFilter) doFiltering(srcIP net.IP, dstIP net.IP, dstPort uint16, nextH
 		}
 	}
 
+	if f.ProxyPort != 0 && byteorder.NetworkToHost(entry.ProxyPort).(uint16) == f.ProxyPort {
+		return deleteEntry
+	}
+
 	return noAction
 }
 
@@ -17,10 +17,12 @@
 package ctmap
 
 import (
+	"net"
 	"strings"
 	"testing"
 	"unsafe"
 
+	"github.com/cilium/cilium/pkg/byteorder"
 	"github.com/cilium/cilium/pkg/option"
 
 	. "gopkg.in/check.v1"
@@ -36,9 +38,10 @@ func Test(t *testing.T) {
 }
 
 func (t *CTMapTestSuite) TestInit(c *C) {
-	InitMapInfo(option.CTMapEntriesGlobalTCPDefault, option.CTMapEntriesGlobalAnyDefault)
+	InitMapInfo(option.CTMapEntriesGlobalTCPDefault, option.CTMapEntriesGlobalAnyDefault, false)
 	for mapType := MapType(0); mapType < MapTypeMax; mapType++ {
 		info := mapInfo[mapType]
+		c.Assert(info.valueSize, Equals, 56)
 		if mapType.isIPv6() {
 			c.Assert(info.keySize, Equals, int(unsafe.Sizeof(CtKey6{})))
 			c.Assert(strings.Contains(info.bpfDefine, "6"), Equals, true)
@@ -64,3 +67,38 @@ func (t *CTMapTestSuite) TestInit(c *C) {
 		}
 	}
 }
+
+func (t *CTMapTestSuite) TestInitProxyPort(c *C) {
+	InitMapInfo(option.CTMapEntriesGlobalTCPDefault, option.CTMapEntriesGlobalAnyDefault, true)
+	defer InitMapInfo(option.CTMapEntriesGlobalTCPDefault, option.CTMapEntriesGlobalAnyDefault, false)
+
+	for mapType := MapType(0); mapType < MapTypeMax; mapType++ {
+		c.Assert(mapInfo[mapType].valueSize, Equals, int(unsafe.Sizeof(CtEntry{})))
+	}
+}
+
+func (t *CTMapTestSuite) TestDumpParserShortEntry(c *C) {
+	key := make([]byte, unsafe.Sizeof(CtKey4Global{}))
+	value := make([]byte, entrySizeNoProxyPort)
+	value[0] = 1
+
+	_, v, err := ct4DumpParser(key, value)
+	c.Assert(err, IsNil)
+	c.Assert(v.(*CtEntry).RxPackets, Equals, uint64(1))
+	c.Assert(v.(*CtEntry).ProxyPort, Equals, uint16(0))
+}
+
+func (t *CTMapTestSuite) TestFilterProxyPort(c *C) {
+	srcIP, dstIP := net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")
+	filter := &GCFilter{ProxyPort: 10001}
+
+	entry := &CtEntry{ProxyPort: byteorder.HostToNetwork(uint16(10001)).(uint16)}
+	c.Assert(filter.doFiltering(srcIP, dstIP, 80, 6, 0, entry), Equals, deleteEntry)
+
+	entry = &CtEntry{ProxyPort: byteorder.HostToNetwork(uint16(10002)).(uint16)}
+	c.Assert(filter.doFiltering(srcIP, dstIP, 80, 6, 0, entry), Equals, noAction)
+
+	entry = &CtEntry{}
+	c.Assert(filter.doFiltering(srcIP, dstIP, 80, 6, 0, entry), Equals, noAction)
+	c.Assert((&GCFilter{}).doFiltering(srcIP, dstIP, 80, 6, 0, entry), Equals, noAction)
+}
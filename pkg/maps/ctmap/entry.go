@@ -37,14 +37,29 @@ type CtEntry struct {
 	SourceSecurityID uint32
 	LastTxReport     uint32
 	LastRxReport     uint32
+	// ProxyPort is in network byte order. The datapath only records it
+	// in the CT entries if proxy redirects are drained, the entries end
+	// before it otherwise.
+	ProxyPort uint16
+	Pad1      uint16
+	Pad2      uint32
 }
 
+const (
+	// entrySize is the size of the CT entries which record the proxy port
+	entrySize = int(unsafe.Sizeof(CtEntry{}))
+
+	// entrySizeNoProxyPort is the size of the CT entries which don't
+	// record the proxy port
+	entrySizeNoProxyPort = int(unsafe.Offsetof(CtEntry{}.ProxyPort))
+)
+
 // GetValuePtr returns the unsafe.Pointer for s.
 func (c *CtEntry) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(c) }
 
 // String returns the readable format
 func (c *CtEntry) String() string {
-	return fmt.Sprintf("expires=%d RxPackets=%d RxBytes=%d TxPackets=%d TxBytes=%d Flags=%x RevNAT=%d SourceSecurityID=%d\n",
+	return fmt.Sprintf("expires=%d RxPackets=%d RxBytes=%d TxPackets=%d TxBytes=%d Flags=%x RevNAT=%d SourceSecurityID=%d ProxyPort=%d\n",
 		c.Lifetime,
 		c.RxPackets,
 		c.RxBytes,
@@ -52,7 +67,8 @@ func (c *CtEntry) String() string {
 		c.TxBytes,
 		c.Flags,
 		byteorder.NetworkToHost(c.RevNAT),
-		c.SourceSecurityID)
+		c.SourceSecurityID,
+		byteorder.NetworkToHost(c.ProxyPort))
 }
 
 // CtEntryDump represents the key and value contained in the conntrack map.
@@ -113,6 +113,9 @@ var (
 	// LabelAction is the label used to defined what kind of action was performed in a metric
 	LabelAction = "action"
 
+	// LabelCPU is the label used to refer to a CPU
+	LabelCPU = "cpu"
+
 	// LabelSubsystem is the label used to refer to any of the child process
 	// started by cilium (Envoy, monitor, etc..)
 	LabelSubsystem = "subsystem"
@@ -427,6 +430,50 @@ var (
 		Name:      "ipam_events_total",
 		Help:      "Number of IPAM events received labeled by action and datapath family type",
 	}, []string{LabelAction, LabelDatapathFamily})
+
+	// Allocator
+
+	// AllocatorQueueDepth is the number of kvstore allocation requests
+	// waiting for an allocation slot
+	AllocatorQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "allocator_queue_depth",
+		Help:      "Number of kvstore allocation requests waiting for an allocation slot",
+	})
+
+	// AllocatorQueueWaitDuration is the time spent by allocation requests
+	// waiting for an allocation slot
+	AllocatorQueueWaitDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "allocator_queue_wait_duration_seconds",
+		Help:      "Duration in seconds allocation requests waited for an allocation slot",
+	})
+
+	// AllocatorBatchedRequests is the number of allocation requests which
+	// were served by an in-flight allocation of the same key
+	AllocatorBatchedRequests = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "allocator_batched_requests_total",
+		Help:      "Number of allocation requests served by an in-flight allocation of the same key",
+	})
+
+	// Node monitor
+
+	// MonitorPerfLostEvents is the number of events lost in the perf ring
+	// buffer of a CPU before they could be read by the node monitor
+	MonitorPerfLostEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "monitor_perf_lost_events_total",
+		Help:      "Number of events lost in the perf ring buffer before they could be read by the node monitor, labeled by CPU",
+	}, []string{LabelCPU})
+
+	// MonitorListenerQueueLostEvents is the number of events dropped by the
+	// node monitor because the queue of a listener was full
+	MonitorListenerQueueLostEvents = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "monitor_listener_queue_lost_events_total",
+		Help:      "Number of events dropped by the node monitor because the queue of a listener was full",
+	})
 )
 
 func init() {
@@ -484,6 +531,13 @@ func init() {
 	MustRegister(KubernetesEvent)
 
 	MustRegister(IpamEvent)
+
+	MustRegister(AllocatorQueueDepth)
+	MustRegister(AllocatorQueueWaitDuration)
+	MustRegister(AllocatorBatchedRequests)
+
+	MustRegister(MonitorPerfLostEvents)
+	MustRegister(MonitorListenerQueueLostEvents)
 }
 
 // MustRegister adds the collector to the registry, exposing this metric to
@@ -40,6 +40,8 @@ const (
 	AgentNotifyEndpointRegenerateFail
 	AgentNotifyPolicyUpdated
 	AgentNotifyPolicyDeleted
+	AgentNotifyNodeConflict
+	AgentNotifyNodeConflictResolved
 )
 
 var notifyTable = map[AgentNotification]string{
@@ -50,6 +52,8 @@ var notifyTable = map[AgentNotification]string{
 	AgentNotifyEndpointRegenerateFail:    "Failed endpoint regeneration",
 	AgentNotifyPolicyUpdated:             "Policy updated",
 	AgentNotifyPolicyDeleted:             "Policy deleted",
+	AgentNotifyNodeConflict:              "Node conflict",
+	AgentNotifyNodeConflictResolved:      "Node conflict resolved",
 }
 
 func resolveAgentType(t AgentNotification) string {
@@ -60,6 +64,12 @@ func resolveAgentType(t AgentNotification) string {
 	return fmt.Sprintf("%d", t)
 }
 
+// AgentNotificationName returns the human readable name of an agent
+// notification type
+func AgentNotificationName(t AgentNotification) string {
+	return resolveAgentType(t)
+}
+
 // DumpInfo dumps an agent notification
 func (n *AgentNotify) DumpInfo() {
 	fmt.Printf(">> %s: %s\n", resolveAgentType(n.Type), n.Text)
@@ -147,3 +157,19 @@ func TimeRepr(t time.Time) (string, error) {
 	repr, err := json.Marshal(notification)
 	return string(repr), err
 }
+
+// NodeConflictNotification structures node conflict notification
+type NodeConflictNotification struct {
+	Name      string   `json:"name"`
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// NodeConflictRepr returns string representation of monitor notification
+func NodeConflictRepr(name string, conflicts []string) (string, error) {
+	notification := NodeConflictNotification{
+		Name:      name,
+		Conflicts: conflicts,
+	}
+	repr, err := json.Marshal(notification)
+	return string(repr), err
+}
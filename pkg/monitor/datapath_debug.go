@@ -230,6 +230,11 @@ func (n *DebugMsg) Dump(prefix string) {
 	fmt.Printf("%s MARK %#x FROM %d DEBUG: %s\n", prefix, n.Hash, n.Source, n.subTypeString())
 }
 
+// Message returns the human readable representation of the debug message
+func (n *DebugMsg) Message() string {
+	return n.subTypeString()
+}
+
 func (n *DebugMsg) subTypeString() string {
 	switch n.SubType {
 	case DbgGeneric:
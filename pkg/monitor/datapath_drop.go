@@ -79,6 +79,24 @@ var errors = map[uint8]string{
 	164: "Local host is unreachable",
 }
 
+// Drop reasons of packets denied by policy. Must be synchronized with
+// <bpf/lib/common.h>
+const (
+	DropReasonPolicy     = 133
+	DropReasonPolicyL4   = 159
+	DropReasonPolicyCIDR = 162
+)
+
+// IsPolicyDrop returns true if the drop reason indicates that the packet was
+// denied by policy
+func IsPolicyDrop(reason uint8) bool {
+	switch reason {
+	case DropReasonPolicy, DropReasonPolicyL4, DropReasonPolicyCIDR:
+		return true
+	}
+	return false
+}
+
 // DropReason prints the drop reason in a human readable string
 func DropReason(reason uint8) string {
 	if err, ok := errors[reason]; ok {
@@ -94,8 +112,9 @@ func (n *DropNotify) DumpInfo(data []byte) {
 		GetConnectionSummary(data[DropNotifyLen:]))
 }
 
-// DumpVerbose prints the drop notification in human readable form
-func (n *DropNotify) DumpVerbose(dissect bool, data []byte, prefix string) {
+// DumpVerbose prints the drop notification in human readable form. The
+// resolved source and destination are printed as well if e is not nil.
+func (n *DropNotify) DumpVerbose(dissect bool, data []byte, prefix string, e *Enrichment) {
 	fmt.Printf("%s MARK %#x FROM %d DROP: %d bytes, reason %s, to ifindex %s",
 		prefix, n.Hash, n.Source, n.OrigLen, DropReason(n.SubType), ifname(int(n.Ifindex)))
 
@@ -109,15 +128,18 @@ func (n *DropNotify) DumpVerbose(dissect bool, data []byte, prefix string) {
 		fmt.Printf("\n")
 	}
 
+	e.DumpVerbose()
+
 	if n.CapLen > 0 && len(data) > DropNotifyLen {
 		Dissect(dissect, data[DropNotifyLen:])
 	}
 }
 
-func (n *DropNotify) getJSON(data []byte, cpuPrefix string) (string, error) {
+func (n *DropNotify) getJSON(data []byte, cpuPrefix string, e *Enrichment) (string, error) {
 
 	v := DropNotifyToVerbose(n)
 	v.CPUPrefix = cpuPrefix
+	v.Enrichment = e
 	if n.CapLen > 0 && len(data) > DropNotifyLen {
 		v.Summary = GetDissectSummary(data[DropNotifyLen:])
 	}
@@ -126,9 +148,10 @@ func (n *DropNotify) getJSON(data []byte, cpuPrefix string) (string, error) {
 	return string(ret), err
 }
 
-// DumpJSON prints notification in json format
-func (n *DropNotify) DumpJSON(data []byte, cpuPrefix string) {
-	resp, err := n.getJSON(data, cpuPrefix)
+// DumpJSON prints notification in json format. The resolved source and
+// destination are included if e is not nil.
+func (n *DropNotify) DumpJSON(data []byte, cpuPrefix string, e *Enrichment) {
+	resp, err := n.getJSON(data, cpuPrefix, e)
 	if err == nil {
 		fmt.Println(resp)
 	}
@@ -148,7 +171,8 @@ type DropNotifyVerbose struct {
 	DstLabel uint32 `json:"dstLabel"`
 	DstID    uint32 `json:"dstID"`
 
-	Summary *DissectSummary `json:"summary,omitempty"`
+	Summary    *DissectSummary `json:"summary,omitempty"`
+	Enrichment *Enrichment     `json:"resolved,omitempty"`
 }
 
 //DropNotifyToVerbose creates verbose notification from DropNotify
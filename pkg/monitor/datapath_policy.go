@@ -0,0 +1,223 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+const (
+	// PolicyVerdictNotifyLen is the amount of packet data provided in a
+	// policy verdict notification
+	PolicyVerdictNotifyLen = 28
+)
+
+// PolicyVerdictNotify is the message format of a policy verdict notification
+// in the BPF ring buffer. It is emitted on the first packet of each new
+// connection allowed by policy.
+type PolicyVerdictNotify struct {
+	Type        uint8
+	MatchType   uint8
+	Source      uint16
+	Hash        uint32
+	OrigLen     uint32
+	CapLen      uint32
+	RemoteLabel uint32
+	DstPort     uint16
+	ProxyPort   uint16
+	Proto       uint8
+	Egress      uint8
+	Pad         uint16
+	// data
+}
+
+// Policy map entries which allowed a new connection.
+const (
+	PolicyMatchNone = iota
+	PolicyMatchL3L4
+	PolicyMatchL3Only
+	PolicyMatchL4Only
+	PolicyMatchAll
+)
+
+var policyMatchTypes = map[uint8]string{
+	PolicyMatchNone:   "none",
+	PolicyMatchL3L4:   "L3-L4",
+	PolicyMatchL3Only: "L3-Only",
+	PolicyMatchL4Only: "L4-Only",
+	PolicyMatchAll:    "all",
+}
+
+// PolicyMatchType returns the name of the policy map entry which allowed a
+// connection
+func PolicyMatchType(match uint8) string {
+	if str, ok := policyMatchTypes[match]; ok {
+		return str
+	}
+	return fmt.Sprintf("%d", match)
+}
+
+// IsEgress returns true if the connection was allowed by egress policy
+func (n *PolicyVerdictNotify) IsEgress() bool {
+	return n.Egress != 0
+}
+
+// IsDefaultAllow returns true if the connection was not allowed by a
+// specific policy rule but because policy enforcement was skipped
+func (n *PolicyVerdictNotify) IsDefaultAllow() bool {
+	return n.MatchType == PolicyMatchAll
+}
+
+// Direction returns the traffic direction the policy verdict applies to
+func (n *PolicyVerdictNotify) Direction() string {
+	if n.IsEgress() {
+		return "egress"
+	}
+	return "ingress"
+}
+
+// Protocol returns the name of the L4 protocol of the connection
+func (n *PolicyVerdictNotify) Protocol() string {
+	return u8proto.U8proto(n.Proto).String()
+}
+
+// SourceEndpoint returns the ID of the local endpoint if it is the source of
+// the connection, 0 otherwise
+func (n *PolicyVerdictNotify) SourceEndpoint() uint16 {
+	if n.IsEgress() {
+		return n.Source
+	}
+	return 0
+}
+
+// DestinationEndpoint returns the ID of the local endpoint if it is the
+// destination of the connection, 0 otherwise
+func (n *PolicyVerdictNotify) DestinationEndpoint() uint16 {
+	if n.IsEgress() {
+		return 0
+	}
+	return n.Source
+}
+
+// SourceIdentity returns the identity of the source of the connection. The
+// identity of the local endpoint is not part of the notification and is
+// returned as 0.
+func (n *PolicyVerdictNotify) SourceIdentity() uint32 {
+	if n.IsEgress() {
+		return 0
+	}
+	return n.RemoteLabel
+}
+
+// DestinationIdentity returns the identity of the destination of the
+// connection. The identity of the local endpoint is not part of the
+// notification and is returned as 0.
+func (n *PolicyVerdictNotify) DestinationIdentity() uint32 {
+	if n.IsEgress() {
+		return n.RemoteLabel
+	}
+	return 0
+}
+
+func (n *PolicyVerdictNotify) verdictSummary() string {
+	action := "allow"
+	if n.ProxyPort != 0 {
+		action = fmt.Sprintf("redirect to proxy port %d", n.ProxyPort)
+	}
+
+	return fmt.Sprintf("%s %s identity %d port %d/%s match %s",
+		action, n.Direction(), n.RemoteLabel, n.DstPort, n.Protocol(),
+		PolicyMatchType(n.MatchType))
+}
+
+// DumpInfo prints a summary of the policy verdict notification.
+func (n *PolicyVerdictNotify) DumpInfo(data []byte) {
+	fmt.Printf("Policy verdict endpoint %d: %s: %s\n",
+		n.Source, n.verdictSummary(), GetConnectionSummary(data[PolicyVerdictNotifyLen:]))
+}
+
+// DumpVerbose prints the policy verdict notification in human readable
+// form. The resolved source and destination are printed as well if e is not
+// nil.
+func (n *PolicyVerdictNotify) DumpVerbose(dissect bool, data []byte, prefix string, e *Enrichment) {
+	fmt.Printf("%s MARK %#x FROM %d Policy verdict: %d bytes (%d captured), %s\n",
+		prefix, n.Hash, n.Source, n.OrigLen, n.CapLen, n.verdictSummary())
+
+	e.DumpVerbose()
+
+	if n.CapLen > 0 && len(data) > PolicyVerdictNotifyLen {
+		Dissect(dissect, data[PolicyVerdictNotifyLen:])
+	}
+}
+
+func (n *PolicyVerdictNotify) getJSON(data []byte, cpuPrefix string, e *Enrichment) (string, error) {
+	v := PolicyVerdictNotifyToVerbose(n)
+	v.CPUPrefix = cpuPrefix
+	v.Enrichment = e
+	if n.CapLen > 0 && len(data) > PolicyVerdictNotifyLen {
+		v.Summary = GetDissectSummary(data[PolicyVerdictNotifyLen:])
+	}
+
+	ret, err := json.Marshal(v)
+	return string(ret), err
+}
+
+// DumpJSON prints notification in json format. The resolved source and
+// destination are included if e is not nil.
+func (n *PolicyVerdictNotify) DumpJSON(data []byte, cpuPrefix string, e *Enrichment) {
+	resp, err := n.getJSON(data, cpuPrefix, e)
+	if err == nil {
+		fmt.Println(resp)
+	}
+}
+
+// PolicyVerdictNotifyVerbose represents a json notification printed by monitor
+type PolicyVerdictNotifyVerbose struct {
+	CPUPrefix string `json:"cpu,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Mark      string `json:"mark,omitempty"`
+	Direction string `json:"direction"`
+	Match     string `json:"match"`
+	Protocol  string `json:"protocol"`
+
+	Source      uint16 `json:"source"`
+	Bytes       uint32 `json:"bytes"`
+	RemoteLabel uint32 `json:"remoteLabel"`
+	DstPort     uint16 `json:"dstPort"`
+	ProxyPort   uint16 `json:"proxyPort,omitempty"`
+
+	Summary    *DissectSummary `json:"summary,omitempty"`
+	Enrichment *Enrichment     `json:"resolved,omitempty"`
+}
+
+// PolicyVerdictNotifyToVerbose creates verbose notification from base
+// PolicyVerdictNotify
+func PolicyVerdictNotifyToVerbose(n *PolicyVerdictNotify) PolicyVerdictNotifyVerbose {
+	return PolicyVerdictNotifyVerbose{
+		Type:        "policy-verdict",
+		Mark:        fmt.Sprintf("%#x", n.Hash),
+		Direction:   n.Direction(),
+		Match:       PolicyMatchType(n.MatchType),
+		Protocol:    n.Protocol(),
+		Source:      n.Source,
+		Bytes:       n.OrigLen,
+		RemoteLabel: n.RemoteLabel,
+		DstPort:     n.DstPort,
+		ProxyPort:   n.ProxyPort,
+	}
+}
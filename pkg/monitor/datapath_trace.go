@@ -97,6 +97,18 @@ func connState(reason uint8) string {
 	return fmt.Sprintf("%d", reason)
 }
 
+// TraceObservationPoint returns the name of the observation point of a trace
+// notification
+func TraceObservationPoint(point uint8) string {
+	return obsPoint(point)
+}
+
+// TraceReason returns the connection tracking state for the reason of a trace
+// notification
+func TraceReason(reason uint8) string {
+	return connState(reason)
+}
+
 func (n *TraceNotify) traceSummary() string {
 	switch n.ObsPoint {
 	case TraceToLxc:
@@ -131,8 +143,9 @@ func (n *TraceNotify) DumpInfo(data []byte) {
 		connState(n.Reason), ifname(int(n.Ifindex)), GetConnectionSummary(data[TraceNotifyLen:]))
 }
 
-// DumpVerbose prints the trace notification in human readable form
-func (n *TraceNotify) DumpVerbose(dissect bool, data []byte, prefix string) {
+// DumpVerbose prints the trace notification in human readable form. The
+// resolved source and destination are printed as well if e is not nil.
+func (n *TraceNotify) DumpVerbose(dissect bool, data []byte, prefix string, e *Enrichment) {
 	fmt.Printf("%s MARK %#x FROM %d %s: %d bytes (%d captured), state %s",
 		prefix, n.Hash, n.Source, obsPoint(n.ObsPoint), n.OrigLen, n.CapLen, connState(n.Reason))
 
@@ -150,14 +163,17 @@ func (n *TraceNotify) DumpVerbose(dissect bool, data []byte, prefix string) {
 		fmt.Printf("\n")
 	}
 
+	e.DumpVerbose()
+
 	if n.CapLen > 0 && len(data) > TraceNotifyLen {
 		Dissect(dissect, data[TraceNotifyLen:])
 	}
 }
 
-func (n *TraceNotify) getJSON(data []byte, cpuPrefix string) (string, error) {
+func (n *TraceNotify) getJSON(data []byte, cpuPrefix string, e *Enrichment) (string, error) {
 	v := TraceNotifyToVerbose(n)
 	v.CPUPrefix = cpuPrefix
+	v.Enrichment = e
 	if n.CapLen > 0 && len(data) > TraceNotifyLen {
 		v.Summary = GetDissectSummary(data[TraceNotifyLen:])
 	}
@@ -166,9 +182,10 @@ func (n *TraceNotify) getJSON(data []byte, cpuPrefix string) (string, error) {
 	return string(ret), err
 }
 
-// DumpJSON prints notification in json format
-func (n *TraceNotify) DumpJSON(data []byte, cpuPrefix string) {
-	resp, err := n.getJSON(data, cpuPrefix)
+// DumpJSON prints notification in json format. The resolved source and
+// destination are included if e is not nil.
+func (n *TraceNotify) DumpJSON(data []byte, cpuPrefix string, e *Enrichment) {
+	resp, err := n.getJSON(data, cpuPrefix, e)
 	if err == nil {
 		fmt.Println(resp)
 	}
@@ -190,7 +207,8 @@ type TraceNotifyVerbose struct {
 	DstLabel uint32 `json:"dstLabel"`
 	DstID    uint16 `json:"dstID"`
 
-	Summary *DissectSummary `json:"summary,omitempty"`
+	Summary    *DissectSummary `json:"summary,omitempty"`
+	Enrichment *Enrichment     `json:"resolved,omitempty"`
 }
 
 // TraceNotifyToVerbose creates verbose notification from base TraceNotify
@@ -131,6 +131,48 @@ func GetConnectionSummary(data []byte) string {
 	return "[unknown]"
 }
 
+// ConnectionTuple contains the addressing information of a packet
+type ConnectionTuple struct {
+	SrcIP    net.IP
+	DstIP    net.IP
+	SrcPort  uint16
+	DstPort  uint16
+	Protocol string
+}
+
+// GetConnectionTuple decodes the data into layers and returns the addressing
+// information of the packet. Fields which cannot be decoded are left empty.
+func GetConnectionTuple(data []byte) ConnectionTuple {
+	dissectLock.Lock()
+	defer dissectLock.Unlock()
+
+	parser.DecodeLayers(data, &decoded)
+
+	t := ConnectionTuple{}
+	for _, typ := range decoded {
+		switch typ {
+		case layers.LayerTypeIPv4:
+			t.SrcIP = append(net.IP(nil), ip4.SrcIP...)
+			t.DstIP = append(net.IP(nil), ip4.DstIP...)
+		case layers.LayerTypeIPv6:
+			t.SrcIP = append(net.IP(nil), ip6.SrcIP...)
+			t.DstIP = append(net.IP(nil), ip6.DstIP...)
+		case layers.LayerTypeTCP:
+			t.Protocol = "tcp"
+			t.SrcPort, t.DstPort = uint16(tcp.SrcPort), uint16(tcp.DstPort)
+		case layers.LayerTypeUDP:
+			t.Protocol = "udp"
+			t.SrcPort, t.DstPort = uint16(udp.SrcPort), uint16(udp.DstPort)
+		case layers.LayerTypeICMPv4:
+			t.Protocol = "icmp"
+		case layers.LayerTypeICMPv6:
+			t.Protocol = "icmpv6"
+		}
+	}
+
+	return t
+}
+
 // Dissect parses and prints the provided data if dissect is set to true,
 // otherwise the data is printed as HEX output
 func Dissect(dissect bool, data []byte) {
@@ -189,6 +231,28 @@ type DissectSummary struct {
 	L4       *Flow  `json:"l4,omitempty"`
 }
 
+// GetIPs returns the source and destination IP addresses of the packet in
+// data. Both addresses are nil if the packet does not contain an IP header.
+func GetIPs(data []byte) (src, dst net.IP) {
+	dissectLock.Lock()
+	defer dissectLock.Unlock()
+
+	parser.DecodeLayers(data, &decoded)
+
+	for _, typ := range decoded {
+		switch typ {
+		case layers.LayerTypeIPv4:
+			src = append(net.IP(nil), ip4.SrcIP...)
+			dst = append(net.IP(nil), ip4.DstIP...)
+		case layers.LayerTypeIPv6:
+			src = append(net.IP(nil), ip6.SrcIP...)
+			dst = append(net.IP(nil), ip6.DstIP...)
+		}
+	}
+
+	return src, dst
+}
+
 // GetDissectSummary returns DissectSummary created from data
 func GetDissectSummary(data []byte) *DissectSummary {
 	dissectLock.Lock()
@@ -0,0 +1,137 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Resolver resolves the numeric identifiers carried by datapath
+// notifications into information which is meaningful to operators
+type Resolver interface {
+	// ResolveIdentity returns the labels of the security identity or nil
+	// if the identity is unknown
+	ResolveIdentity(id uint32) []string
+
+	// ResolveEndpoint returns the namespace and pod name of the local
+	// endpoint or empty strings if the endpoint is unknown or is not
+	// associated with a pod
+	ResolveEndpoint(id uint16) (namespace, pod string)
+
+	// ResolveIP returns the security identity associated with the IP in the
+	// ipcache
+	ResolveIP(ip net.IP) (uint32, bool)
+}
+
+// PeerInfo is the resolved information about the source or destination of
+// a datapath notification
+type PeerInfo struct {
+	IP        string   `json:"ip,omitempty"`
+	Identity  uint32   `json:"identity,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Namespace string   `json:"namespace,omitempty"`
+	Pod       string   `json:"pod,omitempty"`
+}
+
+// String returns the peer information in human readable form
+func (p *PeerInfo) String() string {
+	if p == nil {
+		return ""
+	}
+
+	parts := []string{}
+	if p.IP != "" {
+		parts = append(parts, p.IP)
+	}
+	if p.Identity != 0 {
+		parts = append(parts, fmt.Sprintf("identity %d", p.Identity))
+	}
+	if len(p.Labels) > 0 {
+		parts = append(parts, fmt.Sprintf("labels %s", strings.Join(p.Labels, ",")))
+	}
+	if p.Pod != "" {
+		parts = append(parts, fmt.Sprintf("pod %s/%s", p.Namespace, p.Pod))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Enrichment is the resolved information about both sides of a datapath
+// notification
+type Enrichment struct {
+	Source      *PeerInfo `json:"source,omitempty"`
+	Destination *PeerInfo `json:"destination,omitempty"`
+}
+
+// resolvePeer resolves the endpoint ID, identity and IP of one side of a
+// notification. If the datapath did not provide an identity, the identity
+// is looked up in the ipcache by IP.
+func resolvePeer(r Resolver, epID uint16, id uint32, ip net.IP) *PeerInfo {
+	p := &PeerInfo{Identity: id}
+
+	if ip != nil {
+		p.IP = ip.String()
+		if p.Identity == 0 {
+			if ipID, ok := r.ResolveIP(ip); ok {
+				p.Identity = ipID
+			}
+		}
+	}
+
+	if p.Identity != 0 {
+		p.Labels = r.ResolveIdentity(p.Identity)
+	}
+
+	if epID != 0 {
+		p.Namespace, p.Pod = r.ResolveEndpoint(epID)
+	}
+
+	return p
+}
+
+// Enrich resolves the source and destination of a datapath notification.
+// srcEP and dstEP are the local endpoint IDs, srcID and dstID the security
+// identities as reported by the datapath and data the captured packet. nil
+// is returned if no resolver is provided.
+func Enrich(r Resolver, srcEP, dstEP uint16, srcID, dstID uint32, data []byte) *Enrichment {
+	if r == nil {
+		return nil
+	}
+
+	var srcIP, dstIP net.IP
+	if len(data) > 0 {
+		srcIP, dstIP = GetIPs(data)
+	}
+
+	return &Enrichment{
+		Source:      resolvePeer(r, srcEP, srcID, srcIP),
+		Destination: resolvePeer(r, dstEP, dstID, dstIP),
+	}
+}
+
+// DumpVerbose prints the resolved information in human readable form
+func (e *Enrichment) DumpVerbose() {
+	if e == nil {
+		return
+	}
+
+	if s := e.Source.String(); s != "" {
+		fmt.Printf("  Source: %s\n", s)
+	}
+	if d := e.Destination.String(); d != "" {
+		fmt.Printf("  Destination: %s\n", d)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package monitor
+
+import (
+	"net"
+
+	. "gopkg.in/check.v1"
+)
+
+type fakeResolver struct {
+	identities map[uint32][]string
+	endpoints  map[uint16][2]string
+	ips        map[string]uint32
+}
+
+func (f *fakeResolver) ResolveIdentity(id uint32) []string {
+	return f.identities[id]
+}
+
+func (f *fakeResolver) ResolveEndpoint(id uint16) (string, string) {
+	name := f.endpoints[id]
+	return name[0], name[1]
+}
+
+func (f *fakeResolver) ResolveIP(ip net.IP) (uint32, bool) {
+	id, ok := f.ips[ip.String()]
+	return id, ok
+}
+
+func (s *MonitorSuite) TestEnrich(c *C) {
+	// Ether()/IP(src="1.2.3.4",dst="5.6.7.8")/TCP(sport=80,dport=443)
+	packetData := []byte{2, 51, 69, 103, 137, 171, 1, 35, 69, 103, 137, 171, 8, 0, 69, 0, 0, 40, 0, 1, 0, 0, 64, 6, 106, 188, 1, 2, 3, 4, 5, 6, 7, 8, 0, 80, 1, 187, 0, 0, 0, 0, 0, 0, 0, 0, 80, 2, 32, 0, 125, 196, 0, 0}
+
+	r := &fakeResolver{
+		identities: map[uint32][]string{
+			1000: {"k8s:app=client"},
+			2000: {"k8s:app=server"},
+		},
+		endpoints: map[uint16][2]string{
+			10: {"default", "client-1"},
+		},
+		ips: map[string]uint32{
+			"5.6.7.8": 2000,
+		},
+	}
+
+	c.Assert(Enrich(nil, 10, 0, 1000, 0, packetData), IsNil)
+
+	// The destination identity is not provided by the datapath and
+	// resolved via the ipcache
+	e := Enrich(r, 10, 0, 1000, 0, packetData)
+	c.Assert(e, DeepEquals, &Enrichment{
+		Source: &PeerInfo{
+			IP:        "1.2.3.4",
+			Identity:  1000,
+			Labels:    []string{"k8s:app=client"},
+			Namespace: "default",
+			Pod:       "client-1",
+		},
+		Destination: &PeerInfo{
+			IP:       "5.6.7.8",
+			Identity: 2000,
+			Labels:   []string{"k8s:app=server"},
+		},
+	})
+	c.Assert(e.Source.String(), Equals, "1.2.3.4, identity 1000, labels k8s:app=client, pod default/client-1")
+
+	// Without a packet only the identities and endpoints are resolved
+	e = Enrich(r, 0, 10, 2000, 1000, nil)
+	c.Assert(e.Source, DeepEquals, &PeerInfo{Identity: 2000, Labels: []string{"k8s:app=server"}})
+	c.Assert(e.Destination, DeepEquals, &PeerInfo{
+		Identity:  1000,
+		Labels:    []string{"k8s:app=client"},
+		Namespace: "default",
+		Pod:       "client-1",
+	})
+}
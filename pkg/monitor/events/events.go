@@ -0,0 +1,270 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events converts the payloads of the node monitor into the events of
+// the monitor gRPC API.
+package events
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"net"
+	"time"
+
+	monitorAPI "github.com/cilium/cilium/api/v1/monitor"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/golang/protobuf/ptypes"
+)
+
+// NewEvent converts a monitor payload received at ts into an event of the
+// monitor API
+func NewEvent(pl *payload.Payload, ts time.Time) (*monitorAPI.Event, error) {
+	ev := &monitorAPI.Event{
+		Cpu: uint32(pl.CPU),
+	}
+
+	switch pl.Type {
+	case payload.RecordLost:
+		ev.Event = &monitorAPI.Event_Lost{Lost: &monitorAPI.LostEvents{Count: pl.Lost}}
+	case payload.ListenerQueueLost:
+		ev.Event = &monitorAPI.Event_Lost{Lost: &monitorAPI.LostEvents{
+			Count:  pl.Lost,
+			Source: monitorAPI.LostEventsSource_LISTENER_QUEUE,
+		}}
+	case payload.EventSample:
+		if err := decodeSample(ev, pl.Data, &ts); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown payload type %d", pl.Type)
+	}
+
+	t, err := ptypes.TimestampProto(ts)
+	if err != nil {
+		return nil, err
+	}
+	ev.Time = t
+
+	return ev, nil
+}
+
+func decodeSample(ev *monitorAPI.Event, data []byte, ts *time.Time) error {
+	if len(data) == 0 {
+		return fmt.Errorf("empty monitor event")
+	}
+
+	ev.Type = monitorAPI.EventType(data[0])
+
+	switch data[0] {
+	case monitor.MessageTypeDrop:
+		dn := monitor.DropNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dn); err != nil {
+			return fmt.Errorf("unable to parse drop notification: %s", err)
+		}
+		ev.Event = &monitorAPI.Event_Drop{Drop: &monitorAPI.DropNotify{
+			Source:              uint32(dn.Source),
+			Reason:              uint32(dn.SubType),
+			ReasonDescription:   monitor.DropReason(dn.SubType),
+			SourceIdentity:      dn.SrcLabel,
+			DestinationIdentity: dn.DstLabel,
+			DestinationId:       dn.DstID,
+			Ifindex:             dn.Ifindex,
+			Hash:                dn.Hash,
+			Packet:              newPacket(dn.OrigLen, data, monitor.DropNotifyLen),
+		}}
+
+	case monitor.MessageTypeTrace:
+		tn := monitor.TraceNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &tn); err != nil {
+			return fmt.Errorf("unable to parse trace notification: %s", err)
+		}
+		ev.Event = &monitorAPI.Event_Trace{Trace: &monitorAPI.TraceNotify{
+			Source:                      uint32(tn.Source),
+			ObservationPoint:            uint32(tn.ObsPoint),
+			ObservationPointDescription: monitor.TraceObservationPoint(tn.ObsPoint),
+			Reason:                      uint32(tn.Reason),
+			ReasonDescription:           monitor.TraceReason(tn.Reason),
+			SourceIdentity:              tn.SrcLabel,
+			DestinationIdentity:         tn.DstLabel,
+			DestinationId:               uint32(tn.DstID),
+			Ifindex:                     tn.Ifindex,
+			Hash:                        tn.Hash,
+			Packet:                      newPacket(tn.OrigLen, data, monitor.TraceNotifyLen),
+		}}
+
+	case monitor.MessageTypePolicyVerdict:
+		pn := monitor.PolicyVerdictNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &pn); err != nil {
+			return fmt.Errorf("unable to parse policy verdict notification: %s", err)
+		}
+		ev.Event = &monitorAPI.Event_PolicyVerdict{PolicyVerdict: &monitorAPI.PolicyVerdictNotify{
+			Source:               uint32(pn.Source),
+			MatchType:            uint32(pn.MatchType),
+			MatchTypeDescription: monitor.PolicyMatchType(pn.MatchType),
+			Egress:               pn.IsEgress(),
+			DefaultAllow:         pn.IsDefaultAllow(),
+			RemoteIdentity:       pn.RemoteLabel,
+			DestinationPort:      uint32(pn.DstPort),
+			Protocol:             pn.Protocol(),
+			ProxyPort:            uint32(pn.ProxyPort),
+			Hash:                 pn.Hash,
+			Packet:               newPacket(pn.OrigLen, data, monitor.PolicyVerdictNotifyLen),
+		}}
+
+	case monitor.MessageTypeDebug:
+		dm := monitor.DebugMsg{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dm); err != nil {
+			return fmt.Errorf("unable to parse debug message: %s", err)
+		}
+		ev.Event = &monitorAPI.Event_Debug{Debug: &monitorAPI.DebugMessage{
+			Source:  uint32(dm.Source),
+			SubType: uint32(dm.SubType),
+			Hash:    dm.Hash,
+			Arg1:    dm.Arg1,
+			Arg2:    dm.Arg2,
+			Arg3:    dm.Arg3,
+			Message: dm.Message(),
+		}}
+
+	case monitor.MessageTypeCapture:
+		dc := monitor.DebugCapture{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dc); err != nil {
+			return fmt.Errorf("unable to parse debug capture message: %s", err)
+		}
+		ev.Event = &monitorAPI.Event_Capture{Capture: &monitorAPI.DebugCapture{
+			Source:  uint32(dc.Source),
+			SubType: uint32(dc.SubType),
+			Hash:    dc.Hash,
+			Arg1:    dc.Arg1,
+			Arg2:    dc.Arg2,
+			Packet:  newPacket(dc.OrigLen, data, monitor.DebugCaptureLen),
+		}}
+
+	case monitor.MessageTypeAccessLog:
+		lr := monitor.LogRecordNotify{}
+		if err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&lr); err != nil {
+			return fmt.Errorf("unable to decode access log record: %s", err)
+		}
+		if t, err := time.Parse(time.RFC3339Nano, lr.Timestamp); err == nil {
+			*ts = t
+		}
+		ev.Event = &monitorAPI.Event_AccessLog{AccessLog: newAccessLog(&lr.LogRecord)}
+
+	case monitor.MessageTypeAgent:
+		an := monitor.AgentNotify{}
+		if err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&an); err != nil {
+			return fmt.Errorf("unable to decode agent notification: %s", err)
+		}
+		ev.Event = &monitorAPI.Event_Agent{Agent: &monitorAPI.AgentNotify{
+			Type:            uint32(an.Type),
+			TypeDescription: monitor.AgentNotificationName(an.Type),
+			Text:            an.Text,
+		}}
+
+	default:
+		return fmt.Errorf("unknown monitor event type %d", data[0])
+	}
+
+	return nil
+}
+
+// newPacket returns the packet captured by a datapath notification. hdrLen
+// is the length of the notification header preceding the packet data.
+func newPacket(origLen uint32, data []byte, hdrLen int) *monitorAPI.Packet {
+	if len(data) <= hdrLen {
+		return nil
+	}
+
+	data = data[hdrLen:]
+	tuple := monitor.GetConnectionTuple(data)
+
+	return &monitorAPI.Packet{
+		OriginalLength:  origLen,
+		Data:            data,
+		Summary:         monitor.GetConnectionSummary(data),
+		SourceIp:        ipString(tuple.SrcIP),
+		DestinationIp:   ipString(tuple.DstIP),
+		SourcePort:      uint32(tuple.SrcPort),
+		DestinationPort: uint32(tuple.DstPort),
+		Protocol:        tuple.Protocol,
+	}
+}
+
+func ipString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+func newEndpoint(ep accesslog.EndpointInfo, version accesslog.IPVersion) *monitorAPI.Endpoint {
+	ip := ep.IPv4
+	if version == accesslog.VersionIPV6 {
+		ip = ep.IPv6
+	}
+
+	return &monitorAPI.Endpoint{
+		Id:       ep.ID,
+		Identity: ep.Identity,
+		Labels:   ep.Labels,
+		Ip:       ip,
+		Port:     uint32(ep.Port),
+	}
+}
+
+func newAccessLog(lr *accesslog.LogRecord) *monitorAPI.AccessLog {
+	al := &monitorAPI.AccessLog{
+		FlowType:         string(lr.Type),
+		ObservationPoint: string(lr.ObservationPoint),
+		Verdict:          string(lr.Verdict),
+		Info:             lr.Info,
+		Source:           newEndpoint(lr.SourceEndpoint, lr.IPVersion),
+		Destination:      newEndpoint(lr.DestinationEndpoint, lr.IPVersion),
+	}
+
+	switch {
+	case lr.HTTP != nil:
+		al.Protocol = "http"
+		al.Http = &monitorAPI.HTTP{
+			Method:   lr.HTTP.Method,
+			Protocol: lr.HTTP.Protocol,
+			Code:     uint32(lr.HTTP.Code),
+		}
+		if lr.HTTP.URL != nil {
+			al.Http.Url = lr.HTTP.URL.String()
+		}
+
+	case lr.Kafka != nil:
+		al.Protocol = "kafka"
+		al.Kafka = &monitorAPI.Kafka{
+			ApiKey:        lr.Kafka.APIKey,
+			ApiVersion:    uint32(lr.Kafka.APIVersion),
+			CorrelationId: lr.Kafka.CorrelationID,
+			ErrorCode:     int32(lr.Kafka.ErrorCode),
+			Topic:         lr.Kafka.Topic.Topic,
+		}
+
+	case lr.L7 != nil:
+		al.Protocol = lr.L7.Proto
+		al.Fields = lr.L7.Fields
+	}
+
+	return al
+}
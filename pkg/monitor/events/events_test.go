@@ -0,0 +1,243 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package events
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"net"
+	"testing"
+	"time"
+
+	monitorAPI "github.com/cilium/cilium/api/v1/monitor"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type EventsSuite struct{}
+
+var _ = Suite(&EventsSuite{})
+
+var baseTime = time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func testPacket(c *C) []byte {
+	buf := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{},
+		&layers.Ethernet{
+			SrcMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 6},
+			DstMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 7},
+			EthernetType: layers.EthernetTypeIPv4,
+		},
+		&layers.IPv4{
+			Version:  4,
+			IHL:      5,
+			TTL:      64,
+			Protocol: layers.IPProtocolUDP,
+			SrcIP:    net.ParseIP("10.0.0.1").To4(),
+			DstIP:    net.ParseIP("10.0.0.2").To4(),
+		},
+		&layers.UDP{SrcPort: 34567, DstPort: 53},
+	)
+	c.Assert(err, IsNil)
+	return buf.Bytes()
+}
+
+func testSample(c *C, hdr interface{}, data []byte) *payload.Payload {
+	buf := &bytes.Buffer{}
+	c.Assert(binary.Write(buf, byteorder.Native, hdr), IsNil)
+	buf.Write(data)
+	return &payload.Payload{Data: buf.Bytes(), CPU: 2, Type: payload.EventSample}
+}
+
+func (s *EventsSuite) TestNewEventDrop(c *C) {
+	pkt := testPacket(c)
+	pl := testSample(c, monitor.DropNotify{
+		Type:     monitor.MessageTypeDrop,
+		SubType:  133,
+		Source:   10,
+		OrigLen:  uint32(len(pkt)),
+		CapLen:   uint32(len(pkt)),
+		SrcLabel: 100,
+		DstLabel: 200,
+		DstID:    20,
+	}, pkt)
+
+	ev, err := NewEvent(pl, baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(ev.Cpu, Equals, uint32(2))
+	c.Assert(ev.Type, Equals, monitorAPI.EventType_DROP)
+
+	ts, err := ptypes.Timestamp(ev.Time)
+	c.Assert(err, IsNil)
+	c.Assert(ts.Equal(baseTime), Equals, true)
+
+	drop := ev.GetDrop()
+	c.Assert(drop, Not(IsNil))
+	c.Assert(drop.Source, Equals, uint32(10))
+	c.Assert(drop.ReasonDescription, Equals, "Policy denied (L3)")
+	c.Assert(drop.SourceIdentity, Equals, uint32(100))
+	c.Assert(drop.DestinationIdentity, Equals, uint32(200))
+	c.Assert(drop.DestinationId, Equals, uint32(20))
+	c.Assert(drop.Packet.Data, DeepEquals, pkt)
+	c.Assert(drop.Packet.SourceIp, Equals, "10.0.0.1")
+	c.Assert(drop.Packet.DestinationIp, Equals, "10.0.0.2")
+	c.Assert(drop.Packet.SourcePort, Equals, uint32(34567))
+	c.Assert(drop.Packet.DestinationPort, Equals, uint32(53))
+	c.Assert(drop.Packet.Protocol, Equals, "udp")
+}
+
+func (s *EventsSuite) TestNewEventTrace(c *C) {
+	pl := testSample(c, monitor.TraceNotify{
+		Type:     monitor.MessageTypeTrace,
+		ObsPoint: monitor.TraceToLxc,
+		Reason:   monitor.TraceReasonCtEstablished,
+		Source:   10,
+		DstID:    20,
+	}, nil)
+
+	ev, err := NewEvent(pl, baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(ev.Type, Equals, monitorAPI.EventType_TRACE)
+
+	trace := ev.GetTrace()
+	c.Assert(trace, Not(IsNil))
+	c.Assert(trace.ObservationPointDescription, Equals, "to-endpoint")
+	c.Assert(trace.ReasonDescription, Equals, "established")
+	c.Assert(trace.DestinationId, Equals, uint32(20))
+	c.Assert(trace.Packet, IsNil)
+}
+
+func (s *EventsSuite) TestNewEventPolicyVerdict(c *C) {
+	pkt := testPacket(c)
+	pl := testSample(c, monitor.PolicyVerdictNotify{
+		Type:        monitor.MessageTypePolicyVerdict,
+		MatchType:   monitor.PolicyMatchL3L4,
+		Source:      10,
+		OrigLen:     uint32(len(pkt)),
+		CapLen:      uint32(len(pkt)),
+		RemoteLabel: 100,
+		DstPort:     53,
+		Proto:       17,
+		Egress:      1,
+	}, pkt)
+
+	ev, err := NewEvent(pl, baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(ev.Type, Equals, monitorAPI.EventType_POLICY_VERDICT)
+
+	verdict := ev.GetPolicyVerdict()
+	c.Assert(verdict, Not(IsNil))
+	c.Assert(verdict.Source, Equals, uint32(10))
+	c.Assert(verdict.MatchTypeDescription, Equals, "L3-L4")
+	c.Assert(verdict.Egress, Equals, true)
+	c.Assert(verdict.DefaultAllow, Equals, false)
+	c.Assert(verdict.RemoteIdentity, Equals, uint32(100))
+	c.Assert(verdict.DestinationPort, Equals, uint32(53))
+	c.Assert(verdict.Protocol, Equals, "UDP")
+	c.Assert(verdict.ProxyPort, Equals, uint32(0))
+	c.Assert(verdict.Packet.DestinationIp, Equals, "10.0.0.2")
+}
+
+func (s *EventsSuite) TestNewEventAccessLog(c *C) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(monitor.MessageTypeAccessLog)
+	err := gob.NewEncoder(buf).Encode(accesslog.LogRecord{
+		Type:             accesslog.TypeRequest,
+		Timestamp:        baseTime.Add(time.Second).Format(time.RFC3339Nano),
+		ObservationPoint: accesslog.Ingress,
+		SourceEndpoint:   accesslog.EndpointInfo{ID: 10, Identity: 100, IPv4: "10.0.0.1"},
+		DestinationEndpoint: accesslog.EndpointInfo{
+			ID:       20,
+			Identity: 200,
+			IPv4:     "10.0.0.2",
+			Port:     6379,
+		},
+		Verdict: accesslog.VerdictForwarded,
+		L7: &accesslog.LogRecordL7{
+			Proto:  "redis",
+			Fields: map[string]string{"cmd": "GET"},
+		},
+	})
+	c.Assert(err, IsNil)
+
+	ev, err := NewEvent(&payload.Payload{Data: buf.Bytes(), Type: payload.EventSample}, baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(ev.Type, Equals, monitorAPI.EventType_ACCESS_LOG)
+
+	ts, err := ptypes.Timestamp(ev.Time)
+	c.Assert(err, IsNil)
+	c.Assert(ts.Equal(baseTime.Add(time.Second)), Equals, true)
+
+	al := ev.GetAccessLog()
+	c.Assert(al, Not(IsNil))
+	c.Assert(al.FlowType, Equals, "Request")
+	c.Assert(al.Verdict, Equals, "Forwarded")
+	c.Assert(al.Protocol, Equals, "redis")
+	c.Assert(al.Fields, DeepEquals, map[string]string{"cmd": "GET"})
+	c.Assert(al.Destination.Ip, Equals, "10.0.0.2")
+	c.Assert(al.Destination.Port, Equals, uint32(6379))
+}
+
+func (s *EventsSuite) TestNewEventAgent(c *C) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(monitor.MessageTypeAgent)
+	err := gob.NewEncoder(buf).Encode(monitor.AgentNotify{
+		Type: monitor.AgentNotifyStart,
+		Text: "{}",
+	})
+	c.Assert(err, IsNil)
+
+	ev, err := NewEvent(&payload.Payload{Data: buf.Bytes(), Type: payload.EventSample}, baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(ev.GetAgent(), DeepEquals, &monitorAPI.AgentNotify{
+		Type:            uint32(monitor.AgentNotifyStart),
+		TypeDescription: "Cilium agent started",
+		Text:            "{}",
+	})
+}
+
+func (s *EventsSuite) TestNewEventLost(c *C) {
+	ev, err := NewEvent(&payload.Payload{CPU: 3, Lost: 42, Type: payload.RecordLost}, baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(ev.Cpu, Equals, uint32(3))
+	c.Assert(ev.GetLost().Count, Equals, uint64(42))
+	c.Assert(ev.GetLost().Source, Equals, monitorAPI.LostEventsSource_PERF_RING_BUFFER)
+
+	ev, err = NewEvent(&payload.Payload{Lost: 7, Type: payload.ListenerQueueLost}, baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(ev.GetLost().Count, Equals, uint64(7))
+	c.Assert(ev.GetLost().Source, Equals, monitorAPI.LostEventsSource_LISTENER_QUEUE)
+
+	_, err = NewEvent(&payload.Payload{Type: payload.EventSample}, baseTime)
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewEvent(&payload.Payload{Type: 100}, baseTime)
+	c.Assert(err, Not(IsNil))
+}
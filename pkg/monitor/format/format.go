@@ -58,6 +58,10 @@ type MonitorFormatter struct {
 	Hex        bool
 	JSONOutput bool
 	Verbosity  Verbosity
+
+	// Resolver, if set, is used to resolve the identities, endpoints and
+	// IPs of drop and trace notifications in verbose and JSON output
+	Resolver monitor.Resolver
 }
 
 // NewMonitorFormatter returns a new formatter with default configuration.
@@ -92,6 +96,22 @@ func (m *MonitorFormatter) match(messageType int, src uint16, dst uint16) bool {
 	return true
 }
 
+// enrich resolves the source and destination of a datapath notification if
+// a resolver is configured. hdrLen is the length of the notification header
+// preceding the captured packet in data.
+func (m *MonitorFormatter) enrich(srcEP, dstEP uint16, srcID, dstID uint32, data []byte, hdrLen int) *monitor.Enrichment {
+	if m.Resolver == nil {
+		return nil
+	}
+
+	var packet []byte
+	if len(data) > hdrLen {
+		packet = data[hdrLen:]
+	}
+
+	return monitor.Enrich(m.Resolver, srcEP, dstEP, srcID, dstID, packet)
+}
+
 // dropEvents prints out all the received drop notifications.
 func (m *MonitorFormatter) dropEvents(prefix string, data []byte) {
 	dn := monitor.DropNotify{}
@@ -104,10 +124,10 @@ func (m *MonitorFormatter) dropEvents(prefix string, data []byte) {
 		case INFO:
 			dn.DumpInfo(data)
 		case JSON:
-			dn.DumpJSON(data, prefix)
+			dn.DumpJSON(data, prefix, m.enrich(dn.Source, uint16(dn.DstID), dn.SrcLabel, dn.DstLabel, data, monitor.DropNotifyLen))
 		default:
 			fmt.Println(msgSeparator)
-			dn.DumpVerbose(!m.Hex, data, prefix)
+			dn.DumpVerbose(!m.Hex, data, prefix, m.enrich(dn.Source, uint16(dn.DstID), dn.SrcLabel, dn.DstLabel, data, monitor.DropNotifyLen))
 		}
 	}
 }
@@ -124,10 +144,33 @@ func (m *MonitorFormatter) traceEvents(prefix string, data []byte) {
 		case INFO:
 			tn.DumpInfo(data)
 		case JSON:
-			tn.DumpJSON(data, prefix)
+			tn.DumpJSON(data, prefix, m.enrich(tn.Source, tn.DstID, tn.SrcLabel, tn.DstLabel, data, monitor.TraceNotifyLen))
+		default:
+			fmt.Println(msgSeparator)
+			tn.DumpVerbose(!m.Hex, data, prefix, m.enrich(tn.Source, tn.DstID, tn.SrcLabel, tn.DstLabel, data, monitor.TraceNotifyLen))
+		}
+	}
+}
+
+// policyVerdictEvents prints out all the received policy verdict
+// notifications.
+func (m *MonitorFormatter) policyVerdictEvents(prefix string, data []byte) {
+	pn := monitor.PolicyVerdictNotify{}
+
+	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &pn); err != nil {
+		fmt.Printf("Error while parsing policy verdict notification message: %s\n", err)
+	}
+	srcEP, dstEP := pn.SourceEndpoint(), pn.DestinationEndpoint()
+	if m.match(monitor.MessageTypePolicyVerdict, srcEP, dstEP) {
+		e := m.enrich(srcEP, dstEP, pn.SourceIdentity(), pn.DestinationIdentity(), data, monitor.PolicyVerdictNotifyLen)
+		switch m.Verbosity {
+		case INFO:
+			pn.DumpInfo(data)
+		case JSON:
+			pn.DumpJSON(data, prefix, e)
 		default:
 			fmt.Println(msgSeparator)
-			tn.DumpVerbose(!m.Hex, data, prefix)
+			pn.DumpVerbose(!m.Hex, data, prefix, e)
 		}
 	}
 }
@@ -227,6 +270,8 @@ func (m *MonitorFormatter) FormatSample(data []byte, cpu int) {
 		m.captureEvents(prefix, data)
 	case monitor.MessageTypeTrace:
 		m.traceEvents(prefix, data)
+	case monitor.MessageTypePolicyVerdict:
+		m.policyVerdictEvents(prefix, data)
 	case monitor.MessageTypeAccessLog:
 		m.logRecordEvents(prefix, data)
 	case monitor.MessageTypeAgent:
@@ -241,6 +286,12 @@ func LostEvent(lost uint64, cpu int) {
 	fmt.Printf("CPU %02d: Lost %d events\n", cpu, lost)
 }
 
+// QueueLostEvent formats an event reporting events dropped by the node
+// monitor because the queue of the listener was full.
+func QueueLostEvent(lost uint64) {
+	fmt.Printf("Listener queue: Lost %d events\n", lost)
+}
+
 // FormatEvent formats an event from the specified payload to stdout.
 //
 // Returns true if the event was successfully printed, false otherwise.
@@ -250,6 +301,8 @@ func (m *MonitorFormatter) FormatEvent(pl *payload.Payload) bool {
 		m.FormatSample(pl.Data, pl.CPU)
 	case payload.RecordLost:
 		LostEvent(pl.Lost, pl.CPU)
+	case payload.ListenerQueueLost:
+		QueueLostEvent(pl.Lost)
 	default:
 		return false
 	}
@@ -39,6 +39,9 @@ func (l *LogRecordNotify) direction() string {
 
 func (l *LogRecordNotify) l7Proto() string {
 	if l.HTTP != nil {
+		if l.HTTP.GRPC != nil {
+			return "grpc"
+		}
 		return "http"
 	}
 
@@ -67,7 +70,11 @@ func (l *LogRecordNotify) DumpInfo() {
 			url = http.URL.String()
 		}
 
-		fmt.Printf(" %s %s => %d\n", http.Method, url, http.Code)
+		if grpc := http.GRPC; grpc != nil && grpc.Status != nil {
+			fmt.Printf(" %s %s => %d grpc-status:%d\n", http.Method, url, http.Code, *grpc.Status)
+		} else {
+			fmt.Printf(" %s %s => %d\n", http.Method, url, http.Code)
+		}
 	}
 
 	if kafka := l.Kafka; kafka != nil {
@@ -30,6 +30,10 @@ const (
 	EventSample = 9
 	// RecordLost is equivalent to PERF_RECORD_LOST
 	RecordLost = 2
+	// ListenerQueueLost has no perf equivalent, it reports events which
+	// were dropped by the node monitor because the queue of the listener
+	// was full
+	ListenerQueueLost = 1000
 )
 
 // Meta is used by readers to get information about the payload.
@@ -0,0 +1,156 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type PcapSuite struct{}
+
+var _ = Suite(&PcapSuite{})
+
+var (
+	baseTime   = time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	testPacket = []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}
+)
+
+type block struct {
+	typ  uint32
+	body []byte
+}
+
+// readBlocks splits a pcapng file into its blocks
+func readBlocks(c *C, data []byte) []block {
+	blocks := []block{}
+	for len(data) > 0 {
+		c.Assert(len(data) >= 12, Equals, true)
+		length := byteOrder.Uint32(data[4:])
+		c.Assert(length%4, Equals, uint32(0))
+		c.Assert(byteOrder.Uint32(data[length-4:]), Equals, length)
+		blocks = append(blocks, block{
+			typ:  byteOrder.Uint32(data[0:]),
+			body: data[8 : length-4],
+		})
+		data = data[length:]
+	}
+	return blocks
+}
+
+func dropNotification(c *C, capLen uint32) []byte {
+	buf := &bytes.Buffer{}
+	err := binary.Write(buf, byteorder.Native, monitor.DropNotify{
+		Type:     monitor.MessageTypeDrop,
+		SubType:  133,
+		Source:   10,
+		OrigLen:  64,
+		CapLen:   capLen,
+		SrcLabel: 100,
+		DstLabel: 200,
+		DstID:    20,
+	})
+	c.Assert(err, IsNil)
+	buf.Write(testPacket)
+	return buf.Bytes()
+}
+
+func (s *PcapSuite) TestWriter(c *C) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf)
+	c.Assert(err, IsNil)
+
+	ts := baseTime.Add(time.Nanosecond)
+	c.Assert(w.WritePacket(ts, testPacket, 64, "dropped"), IsNil)
+	c.Assert(w.WritePacket(ts, testPacket[:4], 4, ""), IsNil)
+
+	blocks := readBlocks(c, buf.Bytes())
+	c.Assert(blocks, HasLen, 4)
+	c.Assert(blocks[0].typ, Equals, uint32(blockTypeSectionHeader))
+	c.Assert(byteOrder.Uint32(blocks[0].body), Equals, uint32(byteOrderMagic))
+	c.Assert(blocks[1].typ, Equals, uint32(blockTypeInterfaceDescription))
+	c.Assert(byteOrder.Uint16(blocks[1].body), Equals, uint16(linkTypeEther))
+
+	epb := blocks[2].body
+	c.Assert(blocks[2].typ, Equals, uint32(blockTypeEnhancedPacket))
+	nsec := uint64(byteOrder.Uint32(epb[4:]))<<32 | uint64(byteOrder.Uint32(epb[8:]))
+	c.Assert(nsec, Equals, uint64(ts.UnixNano()))
+	c.Assert(byteOrder.Uint32(epb[12:]), Equals, uint32(len(testPacket)))
+	c.Assert(byteOrder.Uint32(epb[16:]), Equals, uint32(64))
+	c.Assert(epb[20:20+len(testPacket)], DeepEquals, testPacket)
+
+	// The comment option follows the packet data padded to 32 bits
+	opt := epb[20+len(testPacket)+padding(len(testPacket)):]
+	c.Assert(byteOrder.Uint16(opt[0:]), Equals, uint16(optComment))
+	c.Assert(byteOrder.Uint16(opt[2:]), Equals, uint16(len("dropped")))
+	c.Assert(string(opt[4:4+len("dropped")]), Equals, "dropped")
+
+	// Packets without comment have no options
+	c.Assert(blocks[3].body, HasLen, 24)
+}
+
+func (s *PcapSuite) TestNewSample(c *C) {
+	sample, err := NewSample(dropNotification(c, uint32(len(testPacket))), baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(sample, DeepEquals, &Sample{
+		Time:    baseTime,
+		Data:    testPacket,
+		OrigLen: 64,
+		Comment: "drop: Policy denied (L3), identity 100->200, endpoint 10->20",
+	})
+
+	// The captured length limits the packet data
+	sample, err = NewSample(dropNotification(c, 4), baseTime)
+	c.Assert(err, IsNil)
+	c.Assert(sample.Data, DeepEquals, testPacket[:4])
+
+	_, err = NewSample(dropNotification(c, 0), baseTime)
+	c.Assert(err, Equals, ErrNoSample)
+
+	_, err = NewSample([]byte{monitor.MessageTypeDebug}, baseTime)
+	c.Assert(err, Equals, ErrNoSample)
+}
+
+func (s *PcapSuite) TestRing(c *C) {
+	r := NewRing(2)
+	c.Assert(r.Samples(), HasLen, 0)
+
+	for i := 0; i < 3; i++ {
+		r.Add(&Sample{Time: baseTime.Add(time.Duration(i) * time.Second), Data: testPacket})
+	}
+
+	samples := r.Samples()
+	c.Assert(samples, HasLen, 2)
+	c.Assert(samples[0].Time, Equals, baseTime.Add(time.Second))
+	c.Assert(samples[1].Time, Equals, baseTime.Add(2*time.Second))
+
+	buf := &bytes.Buffer{}
+	c.Assert(r.Dump(buf), IsNil)
+	c.Assert(readBlocks(c, buf.Bytes()), HasLen, 4)
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcap
+
+import (
+	"io"
+
+	"github.com/cilium/cilium/pkg/ring"
+)
+
+// Ring is a buffer of a fixed number of packet samples. Once the buffer is
+// full, each added sample replaces the oldest sample in the buffer.
+type Ring struct {
+	ring *ring.Ring
+}
+
+// NewRing returns a new ring buffer for size packet samples
+func NewRing(size int) *Ring {
+	return &Ring{ring: ring.NewRing(size)}
+}
+
+// Add adds a sample to the buffer. The sample must not be modified
+// afterwards.
+func (r *Ring) Add(s *Sample) {
+	r.ring.Add(s)
+}
+
+// Samples returns all samples in the buffer, oldest first
+func (r *Ring) Samples() []*Sample {
+	entries := r.ring.Entries()
+	result := make([]*Sample, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.(*Sample))
+	}
+	return result
+}
+// Dump writes all samples in the buffer, oldest first, to w in pcapng format
+func (r *Ring) Dump(w io.Writer) error {
+	writer, err := NewWriter(w)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range r.Samples() {
+		if err := writer.WriteSample(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
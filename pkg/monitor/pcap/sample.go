@@ -0,0 +1,122 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+)
+
+// ErrNoSample is returned when creating a sample from a monitor event which
+// does not carry a packet sample
+var ErrNoSample = errors.New("monitor event does not carry a packet sample")
+
+// Sample is a packet sample of a drop or trace notification
+type Sample struct {
+	// Time is the time the packet was observed at
+	Time time.Time
+
+	// Data is the captured part of the packet
+	Data []byte
+
+	// OrigLen is the length of the packet on the wire
+	OrigLen int
+
+	// Comment describes the notification carrying the sample, e.g. the
+	// drop reason, identities and observation point
+	Comment string
+}
+
+// packetData returns the captured packet following a notification header of
+// hdrLen bytes in data
+func packetData(data []byte, hdrLen int, capLen uint32) ([]byte, error) {
+	if capLen == 0 || len(data) <= hdrLen {
+		return nil, ErrNoSample
+	}
+
+	packet := data[hdrLen:]
+	if int(capLen) < len(packet) {
+		packet = packet[:capLen]
+	}
+
+	// Copy the packet so the sample does not refer to the event buffer
+	return append([]byte(nil), packet...), nil
+}
+
+func newDropSample(data []byte, ts time.Time) (*Sample, error) {
+	dn := monitor.DropNotify{}
+	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dn); err != nil {
+		return nil, fmt.Errorf("unable to parse drop notification: %s", err)
+	}
+
+	packet, err := packetData(data, monitor.DropNotifyLen, dn.CapLen)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sample{
+		Time:    ts,
+		Data:    packet,
+		OrigLen: int(dn.OrigLen),
+		Comment: fmt.Sprintf("drop: %s, identity %d->%d, endpoint %d->%d",
+			monitor.DropReason(dn.SubType), dn.SrcLabel, dn.DstLabel, dn.Source, dn.DstID),
+	}, nil
+}
+
+func newTraceSample(data []byte, ts time.Time) (*Sample, error) {
+	tn := monitor.TraceNotify{}
+	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &tn); err != nil {
+		return nil, fmt.Errorf("unable to parse trace notification: %s", err)
+	}
+
+	packet, err := packetData(data, monitor.TraceNotifyLen, tn.CapLen)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sample{
+		Time:    ts,
+		Data:    packet,
+		OrigLen: int(tn.OrigLen),
+		Comment: fmt.Sprintf("trace: %s, state %s, identity %d->%d, endpoint %d->%d",
+			monitor.TraceObservationPoint(tn.ObsPoint), monitor.TraceReason(tn.Reason),
+			tn.SrcLabel, tn.DstLabel, tn.Source, tn.DstID),
+	}, nil
+}
+
+// NewSample returns the packet sample carried by the data of a monitor event
+// sample. The packet is assumed to be observed at ts. ErrNoSample is
+// returned for events other than drop and trace notifications and for
+// notifications without captured packet data.
+func NewSample(data []byte, ts time.Time) (*Sample, error) {
+	if len(data) == 0 {
+		return nil, ErrNoSample
+	}
+
+	switch data[0] {
+	case monitor.MessageTypeDrop:
+		return newDropSample(data, ts)
+	case monitor.MessageTypeTrace:
+		return newTraceSample(data, ts)
+	default:
+		return nil, ErrNoSample
+	}
+}
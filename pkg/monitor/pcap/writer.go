@@ -0,0 +1,141 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pcap
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// Block types, option codes and constants of the pcapng format as specified
+// in https://github.com/pcapng/pcapng
+const (
+	blockTypeSectionHeader        = 0x0A0D0D0A
+	blockTypeInterfaceDescription = 0x00000001
+	blockTypeEnhancedPacket       = 0x00000006
+
+	byteOrderMagic = 0x1A2B3C4D
+
+	optEndOfOpt   = 0
+	optComment    = 1
+	optUserAppl   = 4
+	optIfTsResol  = 9
+	linkTypeEther = 1
+
+	// tsResolNanoseconds is the value of the if_tsresol option for
+	// timestamps in nanoseconds
+	tsResolNanoseconds = 9
+)
+
+var byteOrder = binary.LittleEndian
+
+// padding returns the number of bytes required to pad n to 32 bits
+func padding(n int) int {
+	return (4 - n%4) % 4
+}
+
+// appendOption appends an option with the given code and value to b
+func appendOption(b []byte, code uint16, value []byte) []byte {
+	hdr := make([]byte, 4)
+	byteOrder.PutUint16(hdr[0:], code)
+	byteOrder.PutUint16(hdr[2:], uint16(len(value)))
+	b = append(b, hdr...)
+	b = append(b, value...)
+	return append(b, make([]byte, padding(len(value)))...)
+}
+
+// appendEndOfOpt terminates the list of options in b
+func appendEndOfOpt(b []byte) []byte {
+	return append(b, make([]byte, 4)...)
+}
+
+// writeBlock writes a block of the given type with the given body. The body
+// must be padded to 32 bits.
+func writeBlock(w io.Writer, typ uint32, body []byte) error {
+	length := uint32(12 + len(body))
+	b := make([]byte, 8, length)
+	byteOrder.PutUint32(b[0:], typ)
+	byteOrder.PutUint32(b[4:], length)
+	b = append(b, body...)
+	b = append(b, make([]byte, 4)...)
+	byteOrder.PutUint32(b[length-4:], length)
+
+	_, err := w.Write(b)
+	return err
+}
+
+// Writer writes packets in pcapng format. All packets are written for a
+// single Ethernet interface with timestamps in nanoseconds. Each packet is
+// written with a single call to Write of the underlying writer.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter writes the section header and the interface description to w
+// and returns a writer for packets
+func NewWriter(w io.Writer) (*Writer, error) {
+	shb := make([]byte, 16)
+	byteOrder.PutUint32(shb[0:], byteOrderMagic)
+	byteOrder.PutUint16(shb[4:], 1) // major version
+	byteOrder.PutUint16(shb[6:], 0) // minor version
+	// The section length is unspecified
+	byteOrder.PutUint64(shb[8:], 0xFFFFFFFFFFFFFFFF)
+	shb = appendOption(shb, optUserAppl, []byte("cilium"))
+	shb = appendEndOfOpt(shb)
+
+	if err := writeBlock(w, blockTypeSectionHeader, shb); err != nil {
+		return nil, err
+	}
+
+	idb := make([]byte, 8)
+	byteOrder.PutUint16(idb[0:], linkTypeEther)
+	// The snap length is unlimited
+	byteOrder.PutUint32(idb[4:], 0)
+	idb = appendOption(idb, optIfTsResol, []byte{tsResolNanoseconds})
+	idb = appendEndOfOpt(idb)
+
+	if err := writeBlock(w, blockTypeInterfaceDescription, idb); err != nil {
+		return nil, err
+	}
+
+	return &Writer{w: w}, nil
+}
+
+// WritePacket writes a packet observed at ts. origLen is the length of the
+// packet on the wire, data may only contain the first bytes of the packet.
+// The comment is omitted if empty.
+func (w *Writer) WritePacket(ts time.Time, data []byte, origLen int, comment string) error {
+	epb := make([]byte, 20, 20+len(data)+len(comment)+16)
+	nsec := uint64(ts.UnixNano())
+	byteOrder.PutUint32(epb[0:], 0) // interface ID
+	byteOrder.PutUint32(epb[4:], uint32(nsec>>32))
+	byteOrder.PutUint32(epb[8:], uint32(nsec))
+	byteOrder.PutUint32(epb[12:], uint32(len(data)))
+	byteOrder.PutUint32(epb[16:], uint32(origLen))
+	epb = append(epb, data...)
+	epb = append(epb, make([]byte, padding(len(data)))...)
+	if comment != "" {
+		epb = appendOption(epb, optComment, []byte(comment))
+		epb = appendEndOfOpt(epb)
+	}
+
+	return writeBlock(w.w, blockTypeEnhancedPacket, epb)
+}
+
+// WriteSample writes a packet sample
+func (w *Writer) WriteSample(s *Sample) error {
+	return w.WritePacket(s.Time, s.Data, s.OrigLen, s.Comment)
+}
@@ -31,6 +31,7 @@ const (
 	MessageTypeDebug
 	MessageTypeCapture
 	MessageTypeTrace
+	MessageTypePolicyVerdict
 
 	// 129-255 are reserved for agent level events
 
@@ -43,12 +44,13 @@ const (
 
 var (
 	names = map[string]int{
-		"drop":    MessageTypeDrop,
-		"debug":   MessageTypeDebug,
-		"capture": MessageTypeCapture,
-		"trace":   MessageTypeTrace,
-		"l7":      MessageTypeAccessLog,
-		"agent":   MessageTypeAgent,
+		"drop":           MessageTypeDrop,
+		"debug":          MessageTypeDebug,
+		"capture":        MessageTypeCapture,
+		"trace":          MessageTypeTrace,
+		"policy-verdict": MessageTypePolicyVerdict,
+		"l7":             MessageTypeAccessLog,
+		"agent":          MessageTypeAgent,
 	}
 )
 
@@ -62,6 +64,12 @@ func type2name(typ int) string {
 	return strconv.Itoa(typ)
 }
 
+// MessageTypeName returns the name of a message type as used by
+// MessageTypeFilter
+func MessageTypeName(typ int) string {
+	return type2name(typ)
+}
+
 type MessageTypeFilter []int
 
 var _ pflag.Value = &MessageTypeFilter{}
@@ -0,0 +1,228 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/monitor"
+)
+
+// sourcePrecedence defines which source is used if several sources announce
+// the same node. The node information of the source with the highest
+// precedence is put into effect, the information of all other sources is
+// only retained to detect conflicts:
+//  1. FromAgentLocal: the local node as configured by the agent itself
+//  2. FromKVStore: nodes registered by other agents in the kvstore
+//  3. FromKubernetes: nodes derived from Kubernetes node resources
+//
+// Nodes from an unspecified source are treated like nodes from the kvstore.
+func sourcePrecedence(s Source) int {
+	switch s {
+	case FromAgentLocal:
+		return 3
+	case FromKubernetes:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Conflict describes a disagreement between a source announcing a node and the
+// node information currently in effect
+type Conflict struct {
+	// Source is the source announcing the conflicting value
+	Source Source
+
+	// Field is the name of the conflicting node attribute
+	Field string
+
+	// Value is the value announced by Source
+	Value string
+
+	// EffectiveValue is the value currently in effect
+	EffectiveValue string
+}
+
+// String returns a human readable representation of the conflict
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: %s=%s (effective: %s)", c.Source, c.Field, c.Value, c.EffectiveValue)
+}
+
+// GetModel returns the API model representation of the conflict
+func (c Conflict) GetModel() *models.NodeConflict {
+	return &models.NodeConflict{
+		Source:         string(c.Source),
+		Field:          c.Field,
+		Value:          c.Value,
+		EffectiveValue: c.EffectiveValue,
+	}
+}
+
+func cidrString(cidr *net.IPNet) string {
+	if cidr == nil {
+		return ""
+	}
+	return cidr.String()
+}
+
+func ipString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+// conflictFields returns the attributes of a node which must be identical
+// across all sources announcing the node
+func conflictFields(n *Node) map[string]string {
+	return map[string]string{
+		"ipv4-alloc-cidr": cidrString(n.IPv4AllocCIDR),
+		"ipv6-alloc-cidr": cidrString(n.IPv6AllocCIDR),
+		"ipv4-address":    ipString(n.GetNodeIP(false)),
+		"ipv6-address":    ipString(n.GetNodeIP(true)),
+	}
+}
+
+// findConflicts compares the node information of all sources with the
+// effective node. Attributes not announced by a source are not considered a
+// conflict.
+func findConflicts(effective *Node, views map[Source]*Node) []Conflict {
+	conflicts := []Conflict{}
+	effectiveFields := conflictFields(effective)
+
+	for source, n := range views {
+		if n == effective {
+			continue
+		}
+
+		for field, value := range conflictFields(n) {
+			if value == "" || effectiveFields[field] == "" {
+				continue
+			}
+
+			if value != effectiveFields[field] {
+				conflicts = append(conflicts, Conflict{
+					Source:         source,
+					Field:          field,
+					Value:          value,
+					EffectiveValue: effectiveFields[field],
+				})
+			}
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Source != conflicts[j].Source {
+			return conflicts[i].Source < conflicts[j].Source
+		}
+		return conflicts[i].Field < conflicts[j].Field
+	})
+
+	return conflicts
+}
+
+func conflictsEqual(a, b []Conflict) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Notifier is used by the node manager to emit agent notifications
+type Notifier interface {
+	// SendNotification is called to emit an agent notification
+	SendNotification(typ monitor.AgentNotification, text string) error
+}
+
+var (
+	notifierMutex lock.RWMutex
+	notifier      Notifier
+)
+
+// SetNotifier sets the notifier used to report conflicts between the sources
+// announcing a node
+func SetNotifier(n Notifier) {
+	notifierMutex.Lock()
+	notifier = n
+	notifierMutex.Unlock()
+}
+
+// conflictReport is a copy of the conflicts of a node taken while the cluster
+// configuration is locked, so that they can be reported after unlocking it
+type conflictReport struct {
+	node      Node
+	conflicts []Conflict
+}
+
+func newConflictReport(n *Node, conflicts []Conflict) *conflictReport {
+	return &conflictReport{
+		node:      *n,
+		conflicts: append([]Conflict(nil), conflicts...),
+	}
+}
+
+// send reports the conflicts, it must not be called with the cluster
+// configuration locked. A nil report is not sent.
+func (r *conflictReport) send() {
+	if r != nil {
+		reportConflicts(&r.node, r.conflicts)
+	}
+}
+
+// reportConflicts logs and emits an agent notification about the conflicts
+// of a node. An empty list of conflicts reports that all previous conflicts
+// have been resolved.
+func reportConflicts(n *Node, conflicts []Conflict) {
+	strs := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		strs = append(strs, c.String())
+	}
+
+	scopedLog := n.getLogger()
+	typ := monitor.AgentNotifyNodeConflict
+	if len(conflicts) == 0 {
+		typ = monitor.AgentNotifyNodeConflictResolved
+		scopedLog.Info("Sources announcing node agree again")
+	} else {
+		scopedLog.WithField("conflicts", strs).Warning("Sources announcing node disagree")
+	}
+
+	notifierMutex.RLock()
+	defer notifierMutex.RUnlock()
+
+	if notifier == nil {
+		return
+	}
+
+	repr, err := monitor.NodeConflictRepr(n.Fullname(), strs)
+	if err != nil {
+		scopedLog.WithError(err).Warning("Unable to encode node conflict notification")
+		return
+	}
+
+	notifier.SendNotification(typ, repr)
+}
@@ -0,0 +1,118 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package node
+
+import (
+	"net"
+
+	"github.com/cilium/cilium/pkg/monitor"
+
+	. "gopkg.in/check.v1"
+)
+
+type fakeNotifier struct {
+	notifications []monitor.AgentNotification
+	conflicts     []int
+}
+
+func (f *fakeNotifier) SendNotification(typ monitor.AgentNotification, text string) error {
+	f.notifications = append(f.notifications, typ)
+	// Blocks if the notification is sent with the cluster configuration
+	// locked
+	f.conflicts = append(f.conflicts, len(GetNodeConflicts()))
+	return nil
+}
+
+func newConflictTestNode(source Source, cidr string) *Node {
+	_, ipv4CIDR, _ := net.ParseCIDR(cidr)
+	return &Node{
+		Name:          "conflict-test",
+		Cluster:       "default",
+		IPAddresses:   []Address{{IP: net.ParseIP("192.0.2.10")}},
+		IPv4AllocCIDR: ipv4CIDR,
+		Source:        source,
+	}
+}
+
+func (s *NodeSuite) TestFindConflicts(c *C) {
+	kvstore := newConflictTestNode(FromKVStore, "10.1.0.0/16")
+	k8s := newConflictTestNode(FromKubernetes, "10.2.0.0/16")
+
+	conflicts := findConflicts(kvstore, map[Source]*Node{
+		FromKVStore:    kvstore,
+		FromKubernetes: k8s,
+	})
+	c.Assert(conflicts, DeepEquals, []Conflict{{
+		Source:         FromKubernetes,
+		Field:          "ipv4-alloc-cidr",
+		Value:          "10.2.0.0/16",
+		EffectiveValue: "10.1.0.0/16",
+	}})
+
+	// Attributes not announced by a source do not conflict
+	k8s.IPv4AllocCIDR = nil
+	conflicts = findConflicts(kvstore, map[Source]*Node{
+		FromKVStore:    kvstore,
+		FromKubernetes: k8s,
+	})
+	c.Assert(conflicts, HasLen, 0)
+}
+
+func (s *NodeSuite) TestSourcePrecedence(c *C) {
+	DeleteAllNodes()
+	defer DeleteAllNodes()
+
+	notifier := &fakeNotifier{}
+	SetNotifier(notifier)
+	defer SetNotifier(nil)
+
+	kvstore := newConflictTestNode(FromKVStore, "10.1.0.0/16")
+	k8s := newConflictTestNode(FromKubernetes, "10.2.0.0/16")
+	ni := kvstore.Identity()
+
+	UpdateNode(kvstore, 0, nil)
+	c.Assert(GetNode(ni), Equals, kvstore)
+
+	// A source with lower precedence must not overwrite the node but
+	// causes a conflict to be reported
+	UpdateNode(k8s, 0, nil)
+	c.Assert(GetNode(ni), Equals, kvstore)
+	c.Assert(GetNodeConflicts()[ni], HasLen, 1)
+	c.Assert(notifier.notifications, DeepEquals, []monitor.AgentNotification{monitor.AgentNotifyNodeConflict})
+
+	// Repeated identical updates are not reported again
+	UpdateNode(k8s, 0, nil)
+	c.Assert(notifier.notifications, HasLen, 1)
+
+	// Sources agree again
+	k8sAgree := newConflictTestNode(FromKubernetes, "10.1.0.0/16")
+	UpdateNode(k8sAgree, 0, nil)
+	c.Assert(GetNodeConflicts()[ni], HasLen, 0)
+	c.Assert(notifier.notifications, DeepEquals, []monitor.AgentNotification{
+		monitor.AgentNotifyNodeConflict,
+		monitor.AgentNotifyNodeConflictResolved,
+	})
+	c.Assert(notifier.conflicts, DeepEquals, []int{1, 0})
+
+	// Removal of the effective source falls back to the remaining source
+	DeleteNode(ni, FromKVStore, 0)
+	c.Assert(GetNode(ni), Equals, k8sAgree)
+
+	DeleteNode(ni, FromKubernetes, 0)
+	c.Assert(GetNode(ni), IsNil)
+	c.Assert(GetNodeConflicts(), HasLen, 0)
+}
@@ -15,14 +15,14 @@
 package node
 
 import (
-	"bytes"
 	"fmt"
 	"net"
-	"os/exec"
-	"strings"
+	"time"
 
 	routeUtils "github.com/cilium/cilium/pkg/datapath/route"
 	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/ipcache"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/maps/tunnel"
@@ -59,16 +59,43 @@ const (
 	FromAgentLocal Source = "agent-local"
 )
 
+// nodeView is the node information announced by a single source
+type nodeView struct {
+	node        *Node
+	routesTypes RouteType
+	ownAddr     net.IP
+}
+
 type clusterConfiguation struct {
 	lock.RWMutex
 
-	nodes                 map[Identity]*Node
+	// nodes contains the node information in effect for each node
+	nodes map[Identity]*Node
+
+	// sources contains the node information of all sources announcing a
+	// node, see sourcePrecedence()
+	sources map[Identity]map[Source]*nodeView
+
+	// conflicts contains the last reported conflicts of each node
+	conflicts map[Identity][]Conflict
+
 	ciliumHostInitialized bool
 	auxPrefixes           []*net.IPNet
 }
 
+// routeReconcileInterval is the interval in which the node routes are
+// compared with the routing table of the kernel
+const routeReconcileInterval = time.Minute
+
+// nodeRoutes contains all routes installed to reach the local and remote
+// nodes. Once the host device has been initialized, the routes are
+// reconciled periodically to repair routes removed or modified by others.
+var nodeRoutes = routeUtils.NewReconciler("node-routes")
+
 var clusterConf = &clusterConfiguation{
 	nodes:       map[Identity]*Node{},
+	sources:     map[Identity]map[Source]*nodeView{},
+	conflicts:   map[Identity][]Conflict{},
 	auxPrefixes: []*net.IPNet{},
 }
 
@@ -129,7 +156,7 @@ func replaceNodeRoute(ip *net.IPNet) {
 		return
 	}
 
-	routeUtils.ReplaceRoute(createNodeRoute(ip))
+	nodeRoutes.Upsert(createNodeRoute(ip))
 }
 
 // deleteNodeRoute removes a node route of a particular CIDR
@@ -138,7 +165,7 @@ func deleteNodeRoute(ip *net.IPNet) {
 		return
 	}
 
-	routeUtils.DeleteRoute(createNodeRoute(ip))
+	nodeRoutes.Delete(createNodeRoute(ip))
 }
 
 func (cc *clusterConfiguation) replaceHostRoutes() {
@@ -183,6 +210,8 @@ func (cc *clusterConfiguation) installHostRoutes() {
 	cc.ciliumHostInitialized = true
 	cc.replaceHostRoutes()
 	cc.Unlock()
+
+	nodeRoutes.Start(routeReconcileInterval)
 }
 
 // InstallHostRoutes installs all required routes to make the following IP
@@ -225,28 +254,143 @@ func updateTunnelMapping(n *Node, ip *net.IPNet) {
 	}
 }
 
+// remoteNodeIPs returns the set of all addresses of the node if n is a remote
+// node. Returns nil for the local node.
+func remoteNodeIPs(n *Node) map[string]struct{} {
+	if n == nil || n.IsLocal() {
+		return nil
+	}
+
+	ips := make(map[string]struct{}, len(n.IPAddresses))
+	for _, addr := range n.IPAddresses {
+		if addr.IP != nil {
+			ips[addr.IP.String()] = struct{}{}
+		}
+	}
+
+	return ips
+}
+
+// deleteRemoteNodeIP removes the ipcache entry of ip if it is still
+// associated with the remote-node identity
+func deleteRemoteNodeIP(ip string) {
+	if id, ok := ipcache.IPIdentityCache.LookupByIP(ip); ok && id.ID == identity.ReservedIdentityRemoteNode {
+		ipcache.IPIdentityCache.Delete(ip)
+	}
+}
+
+// updateRemoteNodeIdentity associates all addresses of a remote node with the
+// reserved remote-node identity in the ipcache and removes addresses which
+// are no longer announced by the node.
+func updateRemoteNodeIdentity(oldNode, n *Node) {
+	newIPs := remoteNodeIPs(n)
+
+	for ip := range remoteNodeIPs(oldNode) {
+		if _, ok := newIPs[ip]; !ok {
+			deleteRemoteNodeIP(ip)
+		}
+	}
+
+	for ip := range newIPs {
+		ipcache.IPIdentityCache.Upsert(ip, nil, ipcache.Identity{
+			ID:     identity.ReservedIdentityRemoteNode,
+			Source: ipcache.FromAgentLocal,
+		})
+	}
+}
+
+// effectiveView returns the node information of the source with the highest
+// precedence announcing the node. Returns nil if no source announces the node.
+func (cc *clusterConfiguation) effectiveView(ni Identity) *nodeView {
+	var effective *nodeView
+	for source, v := range cc.sources[ni] {
+		if effective == nil {
+			effective = v
+			continue
+		}
+
+		p, effectiveP := sourcePrecedence(source), sourcePrecedence(effective.node.Source)
+		if p > effectiveP || (p == effectiveP && source > effective.node.Source) {
+			effective = v
+		}
+	}
+
+	return effective
+}
+
+// updateConflicts compares the node information of all sources announcing
+// the node with the node information in effect and returns the report of
+// any change in the resulting conflicts, or nil. The report must be sent
+// after cc has been unlocked.
+func (cc *clusterConfiguation) updateConflicts(ni Identity) *conflictReport {
+	effective, ok := cc.nodes[ni]
+	if !ok {
+		delete(cc.conflicts, ni)
+		return nil
+	}
+
+	views := make(map[Source]*Node, len(cc.sources[ni]))
+	for source, v := range cc.sources[ni] {
+		views[source] = v.node
+	}
+
+	conflicts := findConflicts(effective, views)
+	if conflictsEqual(cc.conflicts[ni], conflicts) {
+		return nil
+	}
+
+	if len(conflicts) == 0 {
+		delete(cc.conflicts, ni)
+	} else {
+		cc.conflicts[ni] = conflicts
+	}
+
+	return newConflictReport(effective, conflicts)
+}
+
 // UpdateNode updates the new node in the nodes' map with the given identity.
 // When using DirectRoute RouteType the field ownAddr should contain the IPv6
 // address of the interface that can reach the other nodes.
+//
+// The node information is retained per source. Only the node information of
+// the source with the highest precedence is put into effect, see
+// sourcePrecedence(). Updates from other sources never modify the routes or
+// tunnel mappings of the node but are compared against the node information
+// in effect to report conflicts.
 func UpdateNode(n *Node, routesTypes RouteType, ownAddr net.IP) {
+	var report *conflictReport
+
 	clusterConf.Lock()
-	defer clusterConf.Unlock()
+	defer func() {
+		clusterConf.Unlock()
+		report.send()
+	}()
 
 	ni := n.Identity()
 
-	oldNode, oldNodeExists := clusterConf.nodes[ni]
-	// Ignore kubernetes updates if the node already exists and its source
-	// was not from kubernetes. Also ignore any updates if the oldNode was
-	// updated by the local agent.
-	if oldNodeExists {
-		switch {
-		case oldNode.Source != FromKubernetes && n.Source == FromKubernetes:
-			return
-		case oldNode.Source == FromAgentLocal && n.Source != FromAgentLocal:
-			return
-		}
+	if _, ok := clusterConf.sources[ni]; !ok {
+		clusterConf.sources[ni] = map[Source]*nodeView{}
+	}
+	clusterConf.sources[ni][n.Source] = &nodeView{
+		node:        n,
+		routesTypes: routesTypes,
+		ownAddr:     ownAddr,
 	}
 
+	if effective := clusterConf.effectiveView(ni); effective.node == n {
+		clusterConf.applyNode(n, routesTypes, ownAddr)
+	}
+
+	report = clusterConf.updateConflicts(ni)
+}
+
+// applyNode puts the node information into effect by updating the tunnel
+// mappings and routes of the node. Must be called with cc locked.
+func (cc *clusterConfiguation) applyNode(n *Node, routesTypes RouteType, ownAddr net.IP) {
+	ni := n.Identity()
+
+	oldNode, oldNodeExists := cc.nodes[ni]
+
 	if (routesTypes & TunnelRoute) != 0 {
 		// FIXME if PodCIDR is empty retrieve the CIDR from the KVStore
 		log.WithFields(logrus.Fields{
@@ -280,15 +424,40 @@ func UpdateNode(n *Node, routesTypes RouteType, ownAddr net.IP) {
 		updateIPRoute(oldNode, n, ownAddr)
 	}
 
-	clusterConf.nodes[ni] = n
-	clusterConf.replaceHostRoutes()
+	updateRemoteNodeIdentity(oldNode, n)
+
+	cc.nodes[ni] = n
+	cc.replaceHostRoutes()
 }
 
-// DeleteNode remove the node from the nodes' maps and / or the L3 routes to
-// reach that node.
-func DeleteNode(ni Identity, routesTypes RouteType) {
+// DeleteNode removes the node information announced by the given source. If
+// other sources still announce the node, the node information of the source
+// with the next highest precedence is put into effect without tearing down
+// the routes to the node. Otherwise the node is removed from the nodes' maps
+// and / or the L3 routes to reach that node are removed.
+func DeleteNode(ni Identity, source Source, routesTypes RouteType) {
+	var report *conflictReport
+
 	clusterConf.Lock()
-	defer clusterConf.Unlock()
+	defer func() {
+		clusterConf.Unlock()
+		report.send()
+	}()
+
+	delete(clusterConf.sources[ni], source)
+
+	if effective := clusterConf.effectiveView(ni); effective != nil {
+		if clusterConf.nodes[ni] != effective.node {
+			effective.node.getLogger().WithField("source", effective.node.Source).
+				Info("Source of node removed, falling back to next source")
+			clusterConf.applyNode(effective.node, effective.routesTypes, effective.ownAddr)
+		}
+		report = clusterConf.updateConflicts(ni)
+		return
+	}
+
+	delete(clusterConf.sources, ni)
+	delete(clusterConf.conflicts, ni)
 
 	if n, ok := clusterConf.nodes[ni]; ok {
 		if (routesTypes & TunnelRoute) != 0 {
@@ -308,6 +477,7 @@ func DeleteNode(ni Identity, routesTypes RouteType) {
 		if (routesTypes & DirectRoute) != 0 {
 			deleteIPRoute(n)
 		}
+		updateRemoteNodeIdentity(n, nil)
 		delete(clusterConf.nodes, ni)
 		clusterConf.replaceHostRoutes()
 	}
@@ -326,11 +496,49 @@ func GetNodes() map[Identity]Node {
 	return nodes
 }
 
-// DeleteAllNodes deletes all nodes from the node maanger.
-func DeleteAllNodes() {
+// GetNodeConflicts returns a copy of the conflicts of all nodes for which the
+// announcing sources disagree
+func GetNodeConflicts() map[Identity][]Conflict {
 	clusterConf.RLock()
 	defer clusterConf.RUnlock()
+
+	conflicts := make(map[Identity][]Conflict, len(clusterConf.conflicts))
+	for id, c := range clusterConf.conflicts {
+		conflicts[id] = append([]Conflict(nil), c...)
+	}
+
+	return conflicts
+}
+
+// DeleteAllNodes deletes all nodes from the node maanger.
+func DeleteAllNodes() {
+	clusterConf.Lock()
+	defer clusterConf.Unlock()
 	clusterConf.nodes = map[Identity]*Node{}
+	clusterConf.sources = map[Identity]map[Source]*nodeView{}
+	clusterConf.conflicts = map[Identity][]Conflict{}
+}
+
+// createDirectRoute returns the route to reach the IPv6 pod CIDR of node n via
+// the IPv6 address of the node on the given device
+func createDirectRoute(n *Node, dev string) routeUtils.Route {
+	nexthop := n.GetNodeIP(true)
+	return routeUtils.Route{
+		Nexthop: &nexthop,
+		Device:  dev,
+		Prefix:  *n.IPv6AllocCIDR,
+	}
+}
+
+// deleteDirectRoute removes the route to the pod CIDR of node n as well as the
+// L2 route to the IPv6 address of the node
+func deleteDirectRoute(n *Node, dev string) error {
+	route := createDirectRoute(n, dev)
+	if err := nodeRoutes.Delete(route); err != nil {
+		return err
+	}
+
+	return routeUtils.DeleteNexthopRoute(route)
 }
 
 // updateIPRoute updates the IP routing entry for the given node n via the
@@ -353,16 +561,15 @@ func updateIPRoute(oldNode, n *Node, ownAddr net.IP) {
 		scopedLog.WithField(logfields.IPAddr, ownAddr).Error("iproute: Unable to get v6 interface for address: empty interface name")
 		return
 	}
+	n.dev = dev
 
-	if oldNode != nil {
+	if oldNode != nil && oldNode.IPv6AllocCIDR != nil {
 		oldNodeIPv6 := oldNode.GetNodeIP(true)
 		if oldNode.IPv6AllocCIDR.String() != n.IPv6AllocCIDR.String() ||
 			!oldNodeIPv6.Equal(nodeIPv6) ||
 			oldNode.dev != n.dev {
 			// If any of the routing components changed, then remove the old entries
-
-			err = routeDel(oldNodeIPv6.String(), oldNode.IPv6AllocCIDR.String(), oldNode.dev)
-			if err != nil {
+			if err := deleteDirectRoute(oldNode, oldNode.dev); err != nil {
 				log.WithError(err).WithFields(logrus.Fields{
 					logfields.IPAddr:   oldNodeIPv6,
 					logfields.V6Prefix: oldNode.IPv6AllocCIDR,
@@ -370,13 +577,10 @@ func updateIPRoute(oldNode, n *Node, ownAddr net.IP) {
 				}).Warn("Cannot delete old route during update")
 			}
 		}
-	} else {
-		n.dev = dev
 	}
 
 	// Always re add
-	err = routeAdd(nodeIPv6.String(), n.IPv6AllocCIDR.String(), dev)
-	if err != nil {
+	if err := nodeRoutes.Upsert(createDirectRoute(n, dev)); err != nil {
 		log.WithError(err).WithFields(logrus.Fields{
 			logfields.IPAddr:   nodeIPv6,
 			logfields.V6Prefix: n.IPv6AllocCIDR,
@@ -391,12 +595,11 @@ func updateIPRoute(oldNode, n *Node, ownAddr net.IP) {
 func deleteIPRoute(node *Node) {
 	oldNodeIPv6 := node.GetNodeIP(true)
 
-	if oldNodeIPv6 == nil {
+	if oldNodeIPv6 == nil || node.IPv6AllocCIDR == nil {
 		return
 	}
 
-	err := routeDel(oldNodeIPv6.String(), node.IPv6AllocCIDR.String(), node.dev)
-	if err != nil {
+	if err := deleteDirectRoute(node, node.dev); err != nil {
 		log.WithError(err).WithFields(logrus.Fields{
 			logfields.IPAddr:   oldNodeIPv6,
 			logfields.V6Prefix: node.IPv6AllocCIDR,
@@ -423,48 +626,3 @@ func firstLinkWithv6(ip net.IP) (netlink.Link, error) {
 
 	return nil, fmt.Errorf("No address found")
 }
-
-func routeAdd(dstNode, podCIDR, dev string) error {
-	prog := "ip"
-
-	// for example: ip -6 r a fd00::b dev eth0
-	// TODO: don't add direct route if a subnet of that IP is already present
-	// in the routing table
-	args := []string{"-6", "route", "add", dstNode, "dev", dev}
-	out, err := exec.Command(prog, args...).CombinedOutput()
-	// Ignore file exists in case the route already exists
-	if err != nil && !bytes.Contains(out, []byte("File exists")) {
-		return fmt.Errorf("unable to add routing entry, command %s %s failed: %s: %s", prog,
-			strings.Join(args, " "), err, out)
-	}
-
-	// now we can add the pods cidr route via the other's node IP
-	// for example: ip -6 r a f00d::ac1f:32:0:0/96 via fd00::b
-	args = []string{"-6", "route", "add", podCIDR, "via", dstNode}
-	out, err = exec.Command(prog, args...).CombinedOutput()
-	// Ignore file exists in case the route already exists
-	if err != nil && !bytes.Contains(out, []byte("File exists")) {
-		return fmt.Errorf("unable to add routing entry, command %s %s failed: %s: %s", prog,
-			strings.Join(args, " "), err, out)
-	}
-	return nil
-}
-
-func routeDel(dstNode, podCIDR, dev string) error {
-	prog := "ip"
-
-	args := []string{"-6", "route", "del", podCIDR, "via", dstNode}
-	out, err := exec.Command(prog, args...).CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("unable to clean up old routing entry, command %s %s failed: %s: %s", prog,
-			strings.Join(args, " "), err, out)
-	}
-
-	args = []string{"-6", "route", "del", dstNode, "dev", dev}
-	out, err = exec.Command(prog, args...).CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("unable to clean up old routing entry, command %s %s failed: %s: %s", prog,
-			strings.Join(args, " "), err, out)
-	}
-	return nil
-}
@@ -19,6 +19,9 @@ package node
 import (
 	"net"
 
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/ipcache"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -45,3 +48,49 @@ func (s *NodeSuite) TestTunnelCIDRDeletionRequired(c *C) {
 	c.Assert(tunnelCIDRDeletionRequired(c1, c2), Equals, true)    // c1 -> c2
 	c.Assert(tunnelCIDRDeletionRequired(c2, nil), Equals, true)   // c2 -> disabled
 }
+
+func (s *NodeSuite) TestUpdateRemoteNodeIdentity(c *C) {
+	n1 := &Node{
+		Name: "remote-node-identity-test",
+		IPAddresses: []Address{
+			{IP: net.ParseIP("192.0.2.1")},
+			{IP: net.ParseIP("2001:db8::1")},
+		},
+	}
+
+	updateRemoteNodeIdentity(nil, n1)
+	for _, ip := range []string{"192.0.2.1", "2001:db8::1"} {
+		id, ok := ipcache.IPIdentityCache.LookupByIP(ip)
+		c.Assert(ok, Equals, true)
+		c.Assert(id.ID, Equals, identity.ReservedIdentityRemoteNode)
+	}
+
+	// Addresses no longer announced must be removed
+	n2 := &Node{
+		Name:        n1.Name,
+		IPAddresses: []Address{{IP: net.ParseIP("192.0.2.1")}},
+	}
+	updateRemoteNodeIdentity(n1, n2)
+	_, ok := ipcache.IPIdentityCache.LookupByIP("2001:db8::1")
+	c.Assert(ok, Equals, false)
+
+	// Entries which have been taken over by another identity are retained
+	ipcache.IPIdentityCache.Upsert("192.0.2.1", nil, ipcache.Identity{
+		ID:     identity.ReservedIdentityHost,
+		Source: ipcache.FromAgentLocal,
+	})
+	updateRemoteNodeIdentity(n2, nil)
+	id, ok := ipcache.IPIdentityCache.LookupByIP("192.0.2.1")
+	c.Assert(ok, Equals, true)
+	c.Assert(id.ID, Equals, identity.ReservedIdentityHost)
+	ipcache.IPIdentityCache.Delete("192.0.2.1")
+
+	// The local node is never associated with the remote-node identity
+	local := &Node{
+		Name:        GetName(),
+		IPAddresses: []Address{{IP: net.ParseIP("192.0.2.2")}},
+	}
+	updateRemoteNodeIdentity(nil, local)
+	_, ok = ipcache.IPIdentityCache.LookupByIP("192.0.2.2")
+	c.Assert(ok, Equals, false)
+}
@@ -201,6 +201,7 @@ func (n *Node) GetModel(ipv4 bool) *models.NodeElement {
 		PrimaryAddress:        n.getPrimaryAddress(ipv4),
 		SecondaryAddresses:    n.getSecondaryAddresses(ipv4),
 		HealthEndpointAddress: n.getHealthAddresses(ipv4),
+		Source:                string(n.Source),
 	}
 }
 
@@ -241,7 +242,7 @@ func (n *Node) OnUpdate() {
 
 // OnDelete is called when a node has been deleted from the cluster
 func (n *Node) OnDelete() {
-	DeleteNode(n.Identity(), TunnelRoute|DirectRoute)
+	DeleteNode(n.Identity(), FromKVStore, TunnelRoute|DirectRoute)
 }
 
 // IsLocal returns true if this is the node on which the agent itself is
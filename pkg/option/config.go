@@ -20,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common"
@@ -148,8 +149,102 @@ const (
 
 	// SockopsEnableName is the name of the option to enable sockops
 	SockopsEnableName = "sockops-enable"
+
+	// IdentityAllocationConcurrencyName is the name of the option to limit
+	// the number of parallel identity allocations in the kvstore
+	IdentityAllocationConcurrencyName = "identity-allocation-concurrency"
+
+	// IdentityAllocationBackoffMaxName is the name of the option to limit
+	// the backoff time between failed identity allocation attempts
+	IdentityAllocationBackoffMaxName = "identity-allocation-backoff-max"
+
+	// FlowBufferSizeName is the name of the option to configure the
+	// number of flows retained in the flow buffer
+	FlowBufferSizeName = "flow-buffer-size"
+
+	// MonitorGRPCAddressName is the name of the option to configure an
+	// additional TCP address of the monitor gRPC API
+	MonitorGRPCAddressName = "monitor-grpc-address"
+
+	// MonitorGRPCTLSCertFileName is the name of the option to configure
+	// the TLS certificate of the monitor gRPC API served on a TCP address
+	MonitorGRPCTLSCertFileName = "monitor-grpc-tls-cert-file"
+
+	// MonitorGRPCTLSKeyFileName is the name of the option to configure the
+	// TLS private key of the monitor gRPC API served on a TCP address
+	MonitorGRPCTLSKeyFileName = "monitor-grpc-tls-key-file"
+
+	// MonitorGRPCTLSClientCAFileName is the name of the option to configure
+	// the CA certificates verifying the clients of the monitor gRPC API
+	// served on a TCP address
+	MonitorGRPCTLSClientCAFileName = "monitor-grpc-tls-client-ca-file"
+
+	// FlowMetricsName is the name of the option to enable the export of
+	// flow metrics
+	FlowMetricsName = "flow-metrics"
+
+	// FlowMetricsLabelsName is the name of the option to configure the
+	// labels of the flow metrics
+	FlowMetricsLabelsName = "flow-metrics-labels"
+
+	// FlowMetricsWorkloadLabelName is the name of the option to configure
+	// the identity label reported as workload by the flow metrics
+	FlowMetricsWorkloadLabelName = "flow-metrics-workload-label"
+
+	// PcapBufferSizeName is the name of the option to configure the
+	// number of packet samples retained in the packet sample buffer
+	PcapBufferSizeName = "pcap-buffer-size"
+
+	// FlowLogFileName is the name of the option to enable the flow log
+	FlowLogFileName = "flow-log-file"
+
+	// FlowLogMaxSizeName is the name of the option to configure the size
+	// at which the flow log is rotated
+	FlowLogMaxSizeName = "flow-log-max-size"
+
+	// FlowLogMaxBackupsName is the name of the option to configure the
+	// number of rotated flow logs to retain
+	FlowLogMaxBackupsName = "flow-log-max-backups"
+
+	// FlowLogRotationIntervalName is the name of the option to configure
+	// the interval in which the flow log is rotated
+	FlowLogRotationIntervalName = "flow-log-rotation-interval"
+
+	// FlowLogCompressName is the name of the option to enable compression
+	// of rotated flow logs
+	FlowLogCompressName = "flow-log-compress"
+
+	// FlowLogNamespacesName is the name of the option to restrict the flow
+	// log to flows of pods in particular namespaces
+	FlowLogNamespacesName = "flow-log-namespaces"
+
+	// KafkaProxyName is the name of the option to select the proxy
+	// enforcing Kafka policies
+	KafkaProxyName = "kafka-proxy"
+
+	// KafkaProxyNameEnv is the name of the environment variable of the
+	// KafkaProxy option
+	KafkaProxyNameEnv = "CILIUM_KAFKA_PROXY"
+
+	// ProxyRedirectDrainPeriodName is the name of the option to configure
+	// the period in which removed proxy redirects are drained
+	ProxyRedirectDrainPeriodName = "proxy-redirect-drain-period"
+)
+
+// Available option for daemonConfig.KafkaProxy
+const (
+	// KafkaProxyAgent enforces Kafka policies in the proxy of the agent
+	KafkaProxyAgent = "agent"
+
+	// KafkaProxyEnvoy enforces Kafka policies in Envoy
+	KafkaProxyEnvoy = "envoy"
 )
 
+// GetKafkaProxyModes returns the list of all Kafka proxy modes
+func GetKafkaProxyModes() string {
+	return fmt.Sprintf("%s, %s", KafkaProxyAgent, KafkaProxyEnvoy)
+}
+
 // Available option for daemonConfig.Tunnel
 const (
 	// TunnelVXLAN specifies VXLAN encapsulation
@@ -273,6 +368,85 @@ type daemonConfig struct {
 	// MaxControllerInterval is the maximum value for a controller's
 	// RunInterval. Zero means unlimited.
 	MaxControllerInterval int
+
+	// IdentityAllocationConcurrency is the maximum number of identity
+	// allocations performed against the kvstore in parallel. Zero means
+	// unlimited.
+	IdentityAllocationConcurrency int
+
+	// IdentityAllocationBackoffMax is the maximum time to back off between
+	// failed identity allocation attempts
+	IdentityAllocationBackoffMax time.Duration
+
+	// FlowBufferSize is the number of recent flows retained in the flow
+	// buffer. Zero disables the flow buffer.
+	FlowBufferSize int
+
+	// MonitorGRPCAddress is an additional TCP address on which the node
+	// monitor serves its gRPC API. The API is always served on
+	// defaults.MonitorGRPCSockPath. Must be a loopback address unless
+	// MonitorGRPCTLSCertFile and MonitorGRPCTLSKeyFile are set.
+	MonitorGRPCAddress string
+
+	// MonitorGRPCTLSCertFile and MonitorGRPCTLSKeyFile are the TLS
+	// certificate and private key of the monitor gRPC API served on
+	// MonitorGRPCAddress
+	MonitorGRPCTLSCertFile string
+	MonitorGRPCTLSKeyFile  string
+
+	// MonitorGRPCTLSClientCAFile is the file with the CA certificates
+	// verifying the client certificates required by the monitor gRPC API
+	// served on MonitorGRPCAddress. Clients are not authenticated if empty.
+	MonitorGRPCTLSClientCAFile string
+
+	// FlowMetrics enables the export of the flows observed by the node
+	// monitor as Prometheus metrics
+	FlowMetrics bool
+
+	// FlowMetricsLabels is the set of configurable labels of the flow
+	// metrics
+	FlowMetricsLabels []string
+
+	// FlowMetricsWorkloadLabel is the identity label reported as workload
+	// by the flow metrics
+	FlowMetricsWorkloadLabel string
+
+	// PcapBufferSize is the number of packet samples of recent drop and
+	// trace notifications retained in the packet sample buffer. Zero
+	// disables the packet sample buffer.
+	PcapBufferSize int
+
+	// FlowLogFile is the path of the file the flows observed by the node
+	// monitor are logged to as JSON lines. Empty disables the flow log.
+	FlowLogFile string
+
+	// FlowLogMaxSize is the size in megabytes at which the flow log is
+	// rotated
+	FlowLogMaxSize int
+
+	// FlowLogMaxBackups is the number of rotated flow logs to retain
+	FlowLogMaxBackups int
+
+	// FlowLogRotationInterval is the interval in which the flow log is
+	// rotated regardless of its size. Zero disables time based rotation.
+	FlowLogRotationInterval time.Duration
+
+	// FlowLogCompress enables gzip compression of rotated flow logs
+	FlowLogCompress bool
+
+	// FlowLogNamespaces restricts the flow log to flows from or to pods in
+	// one of these namespaces. All flows are logged if empty.
+	FlowLogNamespaces []string
+
+	// KafkaProxy is the proxy enforcing Kafka policies, either the proxy
+	// of the agent or Envoy. Changing it takes effect for new Kafka
+	// redirects only.
+	KafkaProxy string
+
+	// ProxyRedirectDrainPeriod is the period in which a removed proxy
+	// redirect keeps serving the connections established through it
+	// before it is closed. Zero closes removed redirects immediately.
+	ProxyRedirectDrainPeriod time.Duration
 }
 
 var (
@@ -282,6 +456,15 @@ var (
 		IPv6ClusterAllocCIDR:     defaults.IPv6ClusterAllocCIDR,
 		IPv6ClusterAllocCIDRBase: defaults.IPv6ClusterAllocCIDRBase,
 		EnableHostIPRestore:      defaults.EnableHostIPRestore,
+
+		IdentityAllocationConcurrency: defaults.IdentityAllocationConcurrency,
+		IdentityAllocationBackoffMax:  defaults.IdentityAllocationBackoffMax,
+		FlowBufferSize:                defaults.FlowBufferSize,
+		FlowMetricsWorkloadLabel:      defaults.FlowMetricsWorkloadLabel,
+		PcapBufferSize:                defaults.PcapBufferSize,
+		FlowLogMaxSize:                defaults.FlowLogMaxSize,
+		FlowLogMaxBackups:             defaults.FlowLogMaxBackups,
+		FlowLogCompress:               true,
 	}
 )
 
@@ -337,6 +520,49 @@ func (c *daemonConfig) validateIPv6ClusterAllocCIDR() error {
 	return nil
 }
 
+// ValidateMonitorGRPCAddress returns an error if the monitor gRPC API would be
+// served on a TCP address reachable from other hosts without TLS, or if the
+// TLS configuration is incomplete
+func ValidateMonitorGRPCAddress(address, certFile, keyFile, clientCAFile string) error {
+	if (certFile == "") != (keyFile == "") {
+		return fmt.Errorf("options --%s and --%s must be set together",
+			MonitorGRPCTLSCertFileName, MonitorGRPCTLSKeyFileName)
+	}
+	if clientCAFile != "" && certFile == "" {
+		return fmt.Errorf("option --%s requires --%s and --%s",
+			MonitorGRPCTLSClientCAFileName, MonitorGRPCTLSCertFileName, MonitorGRPCTLSKeyFileName)
+	}
+	if address == "" || certFile != "" {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address '%s' of option --%s: %s", address, MonitorGRPCAddressName, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("address '%s' of option --%s must be a loopback address unless --%s and --%s are set",
+			address, MonitorGRPCAddressName, MonitorGRPCTLSCertFileName, MonitorGRPCTLSKeyFileName)
+	}
+
+	return nil
+}
+
+// ValidateMonitorNumPages returns an error if numPages is not a valid number
+// of pages of the perf ring buffer of each CPU of the node monitor
+func ValidateMonitorNumPages(numPages int) error {
+	if numPages <= 0 || numPages&(numPages-1) != 0 {
+		return fmt.Errorf("number of pages must be a power of 2")
+	}
+	if numPages > defaults.MonitorMaxNumPages {
+		return fmt.Errorf("number of pages must not exceed %d", defaults.MonitorMaxNumPages)
+	}
+	return nil
+}
+
 // Validate validates the daemon configuration
 func (c *daemonConfig) Validate() error {
 	if err := c.validateIPv6ClusterAllocCIDR(); err != nil {
@@ -360,6 +586,22 @@ func (c *daemonConfig) Validate() error {
 		return fmt.Errorf("invalid tunnel mode '%s', valid modes = {%s}", c.Tunnel, GetTunnelModes())
 	}
 
+	c.KafkaProxy = viper.GetString(KafkaProxyName)
+	switch c.KafkaProxy {
+	case KafkaProxyAgent, KafkaProxyEnvoy:
+	default:
+		return fmt.Errorf("invalid Kafka proxy '%s', valid proxies = {%s}", c.KafkaProxy, GetKafkaProxyModes())
+	}
+
+	if err := ValidateMonitorGRPCAddress(c.MonitorGRPCAddress, c.MonitorGRPCTLSCertFile,
+		c.MonitorGRPCTLSKeyFile, c.MonitorGRPCTLSClientCAFile); err != nil {
+		return err
+	}
+
+	if c.ProxyRedirectDrainPeriod < 0 {
+		return fmt.Errorf("option --%s cannot be negative", ProxyRedirectDrainPeriodName)
+	}
+
 	c.ClusterName = viper.GetString(ClusterName)
 	c.ClusterID = viper.GetInt(ClusterIDName)
 	c.ClusterMeshConfig = viper.GetString(ClusterMeshConfigName)
@@ -389,5 +631,10 @@ func (c *daemonConfig) Validate() error {
 			c.CTMapEntriesGlobalTCP, c.CTMapEntriesGlobalAny, ctTableMax)
 	}
 
+	if c.IdentityAllocationConcurrency < 0 {
+		return fmt.Errorf("invalid value %d for option --%s: must be >= 0",
+			c.IdentityAllocationConcurrency, IdentityAllocationConcurrencyName)
+	}
+
 	return nil
 }
@@ -17,6 +17,8 @@
 package option
 
 import (
+	"github.com/cilium/cilium/pkg/defaults"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -41,3 +43,33 @@ func (s *OptionSuite) TestValidateIPv6ClusterAllocCIDR(c *C) {
 	invalid4 := &daemonConfig{}
 	c.Assert(invalid4.validateIPv6ClusterAllocCIDR(), Not(IsNil))
 }
+
+func (s *OptionSuite) TestValidateMonitorGRPCAddress(c *C) {
+	c.Assert(ValidateMonitorGRPCAddress("", "", "", ""), IsNil)
+	c.Assert(ValidateMonitorGRPCAddress("localhost:4244", "", "", ""), IsNil)
+	c.Assert(ValidateMonitorGRPCAddress("127.0.0.1:4244", "", "", ""), IsNil)
+	c.Assert(ValidateMonitorGRPCAddress("[::1]:4244", "", "", ""), IsNil)
+	c.Assert(ValidateMonitorGRPCAddress("0.0.0.0:4244", "cert.pem", "key.pem", ""), IsNil)
+	c.Assert(ValidateMonitorGRPCAddress(":4244", "cert.pem", "key.pem", "ca.pem"), IsNil)
+
+	c.Assert(ValidateMonitorGRPCAddress(":4244", "", "", ""), Not(IsNil))
+	c.Assert(ValidateMonitorGRPCAddress("0.0.0.0:4244", "", "", ""), Not(IsNil))
+	c.Assert(ValidateMonitorGRPCAddress("10.0.0.1:4244", "", "", ""), Not(IsNil))
+	c.Assert(ValidateMonitorGRPCAddress("example.com:4244", "", "", ""), Not(IsNil))
+	c.Assert(ValidateMonitorGRPCAddress("localhost", "", "", ""), Not(IsNil))
+	c.Assert(ValidateMonitorGRPCAddress("localhost:4244", "cert.pem", "", ""), Not(IsNil))
+	c.Assert(ValidateMonitorGRPCAddress("localhost:4244", "", "key.pem", ""), Not(IsNil))
+	c.Assert(ValidateMonitorGRPCAddress("localhost:4244", "", "", "ca.pem"), Not(IsNil))
+}
+
+func (s *OptionSuite) TestValidateMonitorNumPages(c *C) {
+	c.Assert(ValidateMonitorNumPages(1), IsNil)
+	c.Assert(ValidateMonitorNumPages(64), IsNil)
+	c.Assert(ValidateMonitorNumPages(defaults.MonitorMaxNumPages), IsNil)
+
+	c.Assert(ValidateMonitorNumPages(0), Not(IsNil))
+	c.Assert(ValidateMonitorNumPages(-64), Not(IsNil))
+	c.Assert(ValidateMonitorNumPages(96), Not(IsNil))
+	c.Assert(ValidateMonitorNumPages(2*defaults.MonitorMaxNumPages), Not(IsNil))
+	c.Assert(ValidateMonitorNumPages(1<<20), Not(IsNil))
+}
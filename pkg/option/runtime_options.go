@@ -27,6 +27,7 @@ const (
 	DebugLB             = "DebugLB"
 	DropNotify          = "DropNotification"
 	TraceNotify         = "TraceNotification"
+	PolicyVerdictNotify = "PolicyVerdictNotification"
 	MonitorAggregation  = "MonitorAggregationLevel"
 	NAT46               = "NAT46"
 	AlwaysEnforce       = "always"
@@ -77,6 +78,11 @@ var (
 		Description: "Enable trace notifications",
 	}
 
+	specPolicyVerdictNotify = Option{
+		Define:      "POLICY_VERDICT_NOTIFY",
+		Description: "Enable policy verdict notifications for new connections",
+	}
+
 	specMonitorAggregation = Option{
 		Define:      "MONITOR_AGGREGATION",
 		Description: "Set the level of aggregation for monitor events in the datapath",
@@ -41,6 +41,9 @@ const (
 
 	// EntityInit is an entity that represents an initializing endpoint
 	EntityInit Entity = "init"
+
+	// EntityRemoteNode is an entity that represents all remote nodes
+	EntityRemoteNode Entity = "remote-node"
 )
 
 var (
@@ -62,6 +65,12 @@ var (
 		Source: labels.LabelSourceReserved,
 	})
 
+	endpointSelectorRemoteNode = NewESFromLabels(&labels.Label{
+		Key:    labels.IDNameRemoteNode,
+		Value:  "",
+		Source: labels.LabelSourceReserved,
+	})
+
 	endpointSelectorUnmanaged = NewESFromLabels(&labels.Label{
 		Key:    labels.IDNameUnmanaged,
 		Value:  "",
@@ -71,10 +80,11 @@ var (
 	// EntitySelectorMapping maps special entity names that come in
 	// policies to selectors
 	EntitySelectorMapping = map[Entity]EndpointSelectorSlice{
-		EntityAll:   {WildcardEndpointSelector},
-		EntityWorld: {endpointSelectorWorld},
-		EntityHost:  {endpointSelectorHost},
-		EntityInit:  {endpointSelectorInit},
+		EntityAll:        {WildcardEndpointSelector},
+		EntityWorld:      {endpointSelectorWorld},
+		EntityHost:       {endpointSelectorHost, endpointSelectorRemoteNode},
+		EntityInit:       {endpointSelectorInit},
+		EntityRemoteNode: {endpointSelectorRemoteNode},
 
 		// EntityCluster is populated with an empty entry to allow the
 		// cilium client importing this package to perform basic rule
@@ -128,6 +138,7 @@ func (s EntitySlice) GetAsEndpointSelectors() EndpointSelectorSlice {
 func InitEntities(clusterName string) {
 	EntitySelectorMapping[EntityCluster] = EndpointSelectorSlice{
 		endpointSelectorHost,
+		endpointSelectorRemoteNode,
 		endpointSelectorInit,
 		endpointSelectorUnmanaged,
 		NewESFromLabels(&labels.Label{
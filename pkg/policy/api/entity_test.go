@@ -30,15 +30,21 @@ func (s *PolicyAPITestSuite) TestEntityMatches(c *C) {
 
 	c.Assert(EntityHost.Matches(labels.ParseLabelArray("reserved:host")), Equals, true)
 	c.Assert(EntityHost.Matches(labels.ParseLabelArray("reserved:host", "id:foo")), Equals, true)
+	c.Assert(EntityHost.Matches(labels.ParseLabelArray("reserved:remote-node")), Equals, true)
 	c.Assert(EntityHost.Matches(labels.ParseLabelArray("reserved:world")), Equals, false)
 	c.Assert(EntityHost.Matches(labels.ParseLabelArray("id=foo")), Equals, false)
 
+	c.Assert(EntityRemoteNode.Matches(labels.ParseLabelArray("reserved:remote-node")), Equals, true)
+	c.Assert(EntityRemoteNode.Matches(labels.ParseLabelArray("reserved:host")), Equals, false)
+	c.Assert(EntityRemoteNode.Matches(labels.ParseLabelArray("reserved:world")), Equals, false)
+
 	c.Assert(EntityAll.Matches(labels.ParseLabelArray("reserved:host")), Equals, true)
 	c.Assert(EntityAll.Matches(labels.ParseLabelArray("reserved:world")), Equals, true)
 	c.Assert(EntityAll.Matches(labels.ParseLabelArray("id=foo")), Equals, true)
 
 	c.Assert(EntityCluster.Matches(labels.ParseLabelArray("reserved:host")), Equals, true)
 	c.Assert(EntityCluster.Matches(labels.ParseLabelArray("reserved:init")), Equals, true)
+	c.Assert(EntityCluster.Matches(labels.ParseLabelArray("reserved:remote-node")), Equals, true)
 	c.Assert(EntityCluster.Matches(labels.ParseLabelArray("reserved:world")), Equals, false)
 
 	clusterLabel := fmt.Sprintf("k8s:%s=%s", k8sapi.PolicyLabelCluster, "cluster1")
@@ -0,0 +1,74 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	// GRPCServiceValidChar matches a fully qualified gRPC service name,
+	// e.g. "helloworld.Greeter".
+	GRPCServiceValidChar = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+
+	// GRPCMethodValidChar matches a gRPC method name, e.g. "SayHello".
+	GRPCMethodValidChar = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// PortRuleGRPC is a list of gRPC protocol constraints. All fields are
+// optional, if all fields are empty or missing, the rule matches all gRPC
+// calls.
+//
+// gRPC calls are carried as HTTP/2 POST requests to the path
+// "/<service>/<method>", so these rules are enforced by the HTTP proxy.
+type PortRuleGRPC struct {
+	// Service is the fully qualified name of a gRPC service, including the
+	// package name, e.g. "helloworld.Greeter".
+	//
+	// If omitted or empty, calls to all services are allowed.
+	//
+	// +optional
+	Service string `json:"service,omitempty"`
+
+	// Method is the name of a method of the service, e.g. "SayHello".
+	//
+	// If omitted or empty, calls to all methods are allowed.
+	//
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Headers is a list of gRPC metadata headers which must be present in
+	// the request. If omitted or empty, calls are allowed regardless of
+	// metadata present.
+	//
+	// +optional
+	Headers []string `json:"headers,omitempty"`
+}
+
+// Sanitize sanitizes gRPC rules. It ensures that the service and method
+// fields are valid gRPC names. If the rule is invalid, returns an error.
+func (g *PortRuleGRPC) Sanitize() error {
+	if g.Service != "" && !GRPCServiceValidChar.MatchString(g.Service) {
+		return fmt.Errorf("invalid gRPC service name %q", g.Service)
+	}
+
+	if g.Method != "" && !GRPCMethodValidChar.MatchString(g.Method) {
+		return fmt.Errorf("invalid gRPC method name %q", g.Method)
+	}
+
+	// Headers are not sanitized.
+	return nil
+}
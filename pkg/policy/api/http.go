@@ -14,7 +14,11 @@
 
 package api
 
-import "regexp"
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
 
 // PortRuleHTTP is a list of HTTP protocol constraints. All fields are
 // optional, if all fields are empty or missing, the rule does not have any
@@ -57,6 +61,211 @@ type PortRuleHTTP struct {
 	//
 	// +optional
 	Headers []string `json:"headers,omitempty"`
+
+	// HeaderMatches is a list of structured HTTP header matches. Unlike
+	// Headers, the value of a header may be matched against a regex or a
+	// Kubernetes secret, a header may be required to be absent, and a
+	// mismatch may be logged or fixed up instead of denying the request.
+	//
+	// +optional
+	HeaderMatches []HeaderMatch `json:"headerMatches,omitempty"`
+
+	// RateLimit limits the rate of requests allowed by this rule. Requests
+	// in excess of the limit are answered with status 429 (Too Many
+	// Requests) unless they are allowed by another rule.
+	//
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+}
+
+// RateLimitScope specifies which requests share a rate limit.
+type RateLimitScope string
+
+const (
+	// RateLimitPerIdentity limits the requests of all source endpoints
+	// with the same security identity together. This is the default.
+	RateLimitPerIdentity RateLimitScope = "identity"
+
+	// RateLimitPerEndpoint limits the requests of each source endpoint
+	// separately.
+	RateLimitPerEndpoint RateLimitScope = "endpoint"
+)
+
+// RateLimit is a limit on the rate of requests, e.g. 100 requests per second
+// with bursts of up to 200 requests.
+type RateLimit struct {
+	// Requests is the number of requests allowed per Interval.
+	Requests uint32 `json:"requests"`
+
+	// Interval is the interval in which Requests requests are allowed,
+	// e.g. "1s" or "1m". If omitted, the interval is one second.
+	//
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Burst is the number of requests allowed in a burst. If omitted or
+	// less than Requests, bursts are limited to Requests requests.
+	//
+	// +optional
+	Burst uint32 `json:"burst,omitempty"`
+
+	// Per is the scope of the limit, either "identity" or "endpoint". If
+	// omitted, the requests of all sources with the same identity are
+	// limited together.
+	//
+	// +optional
+	Per RateLimitScope `json:"per,omitempty"`
+}
+
+// GetInterval returns the interval of the rate limit. Must only be called
+// on a sanitized rate limit.
+func (rl *RateLimit) GetInterval() time.Duration {
+	if rl.Interval == "" {
+		return time.Second
+	}
+	interval, _ := time.ParseDuration(rl.Interval)
+	return interval
+}
+
+// Sanitize validates a rate limit. If the limit is invalid, returns an error.
+func (rl *RateLimit) Sanitize() error {
+	if rl.Requests == 0 {
+		return fmt.Errorf("rate limit must allow at least one request")
+	}
+
+	if rl.Interval != "" {
+		interval, err := time.ParseDuration(rl.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid rate limit interval %q: %s", rl.Interval, err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("rate limit interval %q must be positive", rl.Interval)
+		}
+	}
+
+	switch rl.Per {
+	case "", RateLimitPerIdentity, RateLimitPerEndpoint:
+	default:
+		return fmt.Errorf("invalid rate limit scope %q", rl.Per)
+	}
+	return nil
+}
+
+// MismatchAction specifies what to do when a request does not match a
+// HeaderMatch.
+type MismatchAction string
+
+const (
+	// MismatchActionDeny denies the request. This is the default.
+	MismatchActionDeny MismatchAction = "DENY"
+
+	// MismatchActionLog allows the request and logs the mismatch in the
+	// access log.
+	MismatchActionLog MismatchAction = "LOG"
+
+	// MismatchActionAdd adds the value to the header, keeping any
+	// existing values.
+	MismatchActionAdd MismatchAction = "ADD"
+
+	// MismatchActionReplace replaces all values of the header with the
+	// value, or adds the header if it is missing.
+	MismatchActionReplace MismatchAction = "REPLACE"
+)
+
+// Secret is a reference to a value stored in a Kubernetes secret.
+type Secret struct {
+	// Namespace is the namespace of the secret. For policies imported from
+	// Kubernetes, this is always the namespace of the policy.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the secret.
+	Name string `json:"name"`
+
+	// Key is the key in the data of the secret holding the value. If
+	// omitted, the secret must hold exactly one key.
+	//
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// HeaderMatch is a match on the value of an HTTP request header. At most one
+// of Value, Regex, Secret and Absent may be set. If none is set, the header
+// must be present with any value.
+type HeaderMatch struct {
+	// Name is the name of the header.
+	Name string `json:"name"`
+
+	// Value is the exact value the header must have.
+	//
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Regex is an extended POSIX regex the value of the header must match.
+	//
+	// +optional
+	Regex string `json:"regex,omitempty"`
+
+	// Secret refers to a Kubernetes secret holding the exact value the
+	// header must have, so that the value need not be part of the policy.
+	//
+	// +optional
+	Secret *Secret `json:"secret,omitempty"`
+
+	// Absent requires the header not to be present in the request.
+	//
+	// +optional
+	Absent bool `json:"absent,omitempty"`
+
+	// Mismatch is the action taken when the header does not match, one of
+	// "DENY", "LOG", "ADD" or "REPLACE". "ADD" and "REPLACE" require Value
+	// or Secret. If omitted, the request is denied.
+	//
+	// +optional
+	Mismatch MismatchAction `json:"mismatch,omitempty"`
+}
+
+// Sanitize validates a header match. If the match is invalid, returns an
+// error.
+func (hm *HeaderMatch) Sanitize() error {
+	if hm.Name == "" {
+		return fmt.Errorf("header match without a header name")
+	}
+
+	n := 0
+	if hm.Value != "" {
+		n++
+	}
+	if hm.Regex != "" {
+		n++
+		if _, err := regexp.Compile(hm.Regex); err != nil {
+			return err
+		}
+	}
+	if hm.Secret != nil {
+		n++
+		if hm.Secret.Name == "" {
+			return fmt.Errorf("header match %q: secret without a name", hm.Name)
+		}
+	}
+	if hm.Absent {
+		n++
+	}
+	if n > 1 {
+		return fmt.Errorf("header match %q: only one of value, regex, secret and absent may be specified", hm.Name)
+	}
+
+	switch hm.Mismatch {
+	case "", MismatchActionDeny, MismatchActionLog:
+	case MismatchActionAdd, MismatchActionReplace:
+		if hm.Value == "" && hm.Secret == nil {
+			return fmt.Errorf("header match %q: mismatch action %s requires a value or secret", hm.Name, hm.Mismatch)
+		}
+	default:
+		return fmt.Errorf("header match %q: invalid mismatch action %q", hm.Name, hm.Mismatch)
+	}
+	return nil
 }
 
 // Sanitize sanitizes HTTP rules. It ensures that the path and method fields
@@ -80,5 +289,17 @@ func (h *PortRuleHTTP) Sanitize() error {
 	}
 
 	// Headers are not sanitized.
+
+	for i := range h.HeaderMatches {
+		if err := h.HeaderMatches[i].Sanitize(); err != nil {
+			return err
+		}
+	}
+
+	if h.RateLimit != nil {
+		if err := h.RateLimit.Sanitize(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
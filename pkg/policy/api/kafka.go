@@ -93,6 +93,18 @@ type PortRuleKafka struct {
 	// +optional
 	Topic string `json:"topic,omitempty"`
 
+	// Principal is the user name the client authenticated as with SASL,
+	// using the PLAIN or SCRAM mechanisms. Requests sent before
+	// authentication, or on connections using other mechanisms, do not
+	// match a rule with a principal. The SaslHandshake and SaslAuthenticate
+	// requests necessary to authenticate are always allowed by a rule with
+	// a principal.
+	//
+	// If omitted or empty, all principals are allowed.
+	//
+	// +optional
+	Principal string `json:"principal,omitempty"`
+
 	// --------------------------------------------------------------------
 	// Private fields. These fields are used internally and are not exposed
 	// via the API.
@@ -127,18 +139,33 @@ const (
 	AddPartitionsToTxnKey   = 24
 	WriteTxnMarkersKey      = 27
 	TxnOffsetCommitKey      = 28
+	DescribeConfigsKey      = 32
+	AlterConfigsKey         = 33
 	AlterReplicaLogDirsKey  = 34
 	DescribeLogDirsKey      = 35
 	CreatePartitionsKey     = 37
+
+	ElectLeadersKey                = 43
+	IncrementalAlterConfigsKey     = 44
+	AlterPartitionReassignmentsKey = 45
+	ListPartitionReassignmentsKey  = 46
+	OffsetDeleteKey                = 47
+	DescribeProducersKey           = 61
 )
 
 // List of Kafka apiKey which are not associated with
 // any topic
 const (
-	HeartbeatKey   = 12
-	LeaveGroupKey  = 13
-	SyncgroupKey   = 14
-	APIVersionsKey = 18
+	HeartbeatKey              = 12
+	LeaveGroupKey             = 13
+	SyncgroupKey              = 14
+	SaslHandshakeKey          = 17
+	APIVersionsKey            = 18
+	InitProducerIDKey         = 22
+	AddOffsetsToTxnKey        = 25
+	EndTxnKey                 = 26
+	SaslAuthenticateKey       = 36
+	ConsumerGroupHeartbeatKey = 68
 )
 
 // List of Kafka Roles
@@ -151,80 +178,128 @@ const (
 // with the key values.
 // Reference: https://kafka.apache.org/protocol#protocol_api_keys
 var KafkaAPIKeyMap = map[string]int16{
-	"produce":              0,  /* Produce */
-	"fetch":                1,  /* Fetch */
-	"offsets":              2,  /* Offsets */
-	"metadata":             3,  /* Metadata */
-	"leaderandisr":         4,  /* LeaderAndIsr */
-	"stopreplica":          5,  /* StopReplica */
-	"updatemetadata":       6,  /* UpdateMetadata */
-	"controlledshutdown":   7,  /* ControlledShutdown */
-	"offsetcommit":         8,  /* OffsetCommit */
-	"offsetfetch":          9,  /* OffsetFetch */
-	"findcoordinator":      10, /* FindCoordinator */
-	"joingroup":            11, /* JoinGroup */
-	"heartbeat":            12, /* Heartbeat */
-	"leavegroup":           13, /* LeaveGroup */
-	"syncgroup":            14, /* SyncGroup */
-	"describegroups":       15, /* DescribeGroups */
-	"listgroups":           16, /* ListGroups */
-	"saslhandshake":        17, /* SaslHandshake */
-	"apiversions":          18, /* ApiVersions */
-	"createtopics":         19, /* CreateTopics */
-	"deletetopics":         20, /* DeleteTopics */
-	"deleterecords":        21, /* DeleteRecords */
-	"initproducerid":       22, /* InitProducerId */
-	"offsetforleaderepoch": 23, /* OffsetForLeaderEpoch */
-	"addpartitionstotxn":   24, /* AddPartitionsToTxn */
-	"addoffsetstotxn":      25, /* AddOffsetsToTxn */
-	"endtxn":               26, /* EndTxn */
-	"writetxnmarkers":      27, /* WriteTxnMarkers */
-	"txnoffsetcommit":      28, /* TxnOffsetCommit */
-	"describeacls":         29, /* DescribeAcls */
-	"createacls":           30, /* CreateAcls */
-	"deleteacls":           31, /* DeleteAcls */
-	"describeconfigs":      32, /* DescribeConfigs */
-	"alterconfigs":         33, /* AlterConfigs */
+	"produce":                      0,  /* Produce */
+	"fetch":                        1,  /* Fetch */
+	"offsets":                      2,  /* Offsets */
+	"metadata":                     3,  /* Metadata */
+	"leaderandisr":                 4,  /* LeaderAndIsr */
+	"stopreplica":                  5,  /* StopReplica */
+	"updatemetadata":               6,  /* UpdateMetadata */
+	"controlledshutdown":           7,  /* ControlledShutdown */
+	"offsetcommit":                 8,  /* OffsetCommit */
+	"offsetfetch":                  9,  /* OffsetFetch */
+	"findcoordinator":              10, /* FindCoordinator */
+	"joingroup":                    11, /* JoinGroup */
+	"heartbeat":                    12, /* Heartbeat */
+	"leavegroup":                   13, /* LeaveGroup */
+	"syncgroup":                    14, /* SyncGroup */
+	"describegroups":               15, /* DescribeGroups */
+	"listgroups":                   16, /* ListGroups */
+	"saslhandshake":                17, /* SaslHandshake */
+	"apiversions":                  18, /* ApiVersions */
+	"createtopics":                 19, /* CreateTopics */
+	"deletetopics":                 20, /* DeleteTopics */
+	"deleterecords":                21, /* DeleteRecords */
+	"initproducerid":               22, /* InitProducerId */
+	"offsetforleaderepoch":         23, /* OffsetForLeaderEpoch */
+	"addpartitionstotxn":           24, /* AddPartitionsToTxn */
+	"addoffsetstotxn":              25, /* AddOffsetsToTxn */
+	"endtxn":                       26, /* EndTxn */
+	"writetxnmarkers":              27, /* WriteTxnMarkers */
+	"txnoffsetcommit":              28, /* TxnOffsetCommit */
+	"describeacls":                 29, /* DescribeAcls */
+	"createacls":                   30, /* CreateAcls */
+	"deleteacls":                   31, /* DeleteAcls */
+	"describeconfigs":              32, /* DescribeConfigs */
+	"alterconfigs":                 33, /* AlterConfigs */
+	"alterreplicalogdirs":          34, /* AlterReplicaLogDirs */
+	"describelogdirs":              35, /* DescribeLogDirs */
+	"saslauthenticate":             36, /* SaslAuthenticate */
+	"createpartitions":             37, /* CreatePartitions */
+	"createdelegationtoken":        38, /* CreateDelegationToken */
+	"renewdelegationtoken":         39, /* RenewDelegationToken */
+	"expiredelegationtoken":        40, /* ExpireDelegationToken */
+	"describedelegationtoken":      41, /* DescribeDelegationToken */
+	"deletegroups":                 42, /* DeleteGroups */
+	"electleaders":                 43, /* ElectLeaders */
+	"incrementalalterconfigs":      44, /* IncrementalAlterConfigs */
+	"alterpartitionreassignments":  45, /* AlterPartitionReassignments */
+	"listpartitionreassignments":   46, /* ListPartitionReassignments */
+	"offsetdelete":                 47, /* OffsetDelete */
+	"describeclientquotas":         48, /* DescribeClientQuotas */
+	"alterclientquotas":            49, /* AlterClientQuotas */
+	"describeuserscramcredentials": 50, /* DescribeUserScramCredentials */
+	"alteruserscramcredentials":    51, /* AlterUserScramCredentials */
+	"describecluster":              60, /* DescribeCluster */
+	"describeproducers":            61, /* DescribeProducers */
+	"describetransactions":         65, /* DescribeTransactions */
+	"listtransactions":             66, /* ListTransactions */
+	"consumergroupheartbeat":       68, /* ConsumerGroupHeartbeat */
+	"consumergroupdescribe":        69, /* ConsumerGroupDescribe */
 }
 
 // KafkaReverseApiKeyMap is the map of all allowed kafka API keys
 // with the key values.
 // Reference: https://kafka.apache.org/protocol#protocol_api_keys
 var KafkaReverseAPIKeyMap = map[int16]string{
-	0:  "produce",              /* Produce */
-	1:  "fetch",                /* Fetch */
-	2:  "offsets",              /* Offsets */
-	3:  "metadata",             /* Metadata */
-	4:  "leaderandisr",         /* LeaderAndIsr */
-	5:  "stopreplica",          /* StopReplica */
-	6:  "updatemetadata",       /* UpdateMetadata */
-	7:  "controlledshutdown",   /* ControlledShutdown */
-	8:  "offsetcommit",         /* OffsetCommit */
-	9:  "offsetfetch",          /* OffsetFetch */
-	10: "findcoordinator",      /* FindCoordinator */
-	11: "joingroup",            /* JoinGroup */
-	12: "heartbeat",            /* Heartbeat */
-	13: "leavegroup",           /* LeaveGroup */
-	14: "syncgroup",            /* SyncGroup */
-	15: "describegroups",       /* DescribeGroups */
-	16: "listgroups",           /* ListGroups */
-	17: "saslhandshake",        /* SaslHandshake */
-	18: "apiversions",          /* ApiVersions */
-	19: "createtopics",         /* CreateTopics */
-	20: "deletetopics",         /* DeleteTopics */
-	21: "deleterecords",        /* DeleteRecords */
-	22: "initproducerid",       /* InitProducerId */
-	23: "offsetforleaderepoch", /* OffsetForLeaderEpoch */
-	24: "addpartitionstotxn",   /* AddPartitionsToTxn */
-	25: "addoffsetstotxn",      /* AddOffsetsToTxn */
-	26: "endtxn",               /* EndTxn */
-	27: "writetxnmarkers",      /* WriteTxnMarkers */
-	28: "txnoffsetcommit",      /* TxnOffsetCommit */
-	29: "describeacls",         /* DescribeAcls */
-	30: "createacls",           /* CreateAcls */
-	31: "deleteacls",           /* DeleteAcls */
-	32: "describeconfigs",      /* DescribeConfigs */
-	33: "alterconfigs",         /* AlterConfigs */
+	0:  "produce",                      /* Produce */
+	1:  "fetch",                        /* Fetch */
+	2:  "offsets",                      /* Offsets */
+	3:  "metadata",                     /* Metadata */
+	4:  "leaderandisr",                 /* LeaderAndIsr */
+	5:  "stopreplica",                  /* StopReplica */
+	6:  "updatemetadata",               /* UpdateMetadata */
+	7:  "controlledshutdown",           /* ControlledShutdown */
+	8:  "offsetcommit",                 /* OffsetCommit */
+	9:  "offsetfetch",                  /* OffsetFetch */
+	10: "findcoordinator",              /* FindCoordinator */
+	11: "joingroup",                    /* JoinGroup */
+	12: "heartbeat",                    /* Heartbeat */
+	13: "leavegroup",                   /* LeaveGroup */
+	14: "syncgroup",                    /* SyncGroup */
+	15: "describegroups",               /* DescribeGroups */
+	16: "listgroups",                   /* ListGroups */
+	17: "saslhandshake",                /* SaslHandshake */
+	18: "apiversions",                  /* ApiVersions */
+	19: "createtopics",                 /* CreateTopics */
+	20: "deletetopics",                 /* DeleteTopics */
+	21: "deleterecords",                /* DeleteRecords */
+	22: "initproducerid",               /* InitProducerId */
+	23: "offsetforleaderepoch",         /* OffsetForLeaderEpoch */
+	24: "addpartitionstotxn",           /* AddPartitionsToTxn */
+	25: "addoffsetstotxn",              /* AddOffsetsToTxn */
+	26: "endtxn",                       /* EndTxn */
+	27: "writetxnmarkers",              /* WriteTxnMarkers */
+	28: "txnoffsetcommit",              /* TxnOffsetCommit */
+	29: "describeacls",                 /* DescribeAcls */
+	30: "createacls",                   /* CreateAcls */
+	31: "deleteacls",                   /* DeleteAcls */
+	32: "describeconfigs",              /* DescribeConfigs */
+	33: "alterconfigs",                 /* AlterConfigs */
+	34: "alterreplicalogdirs",          /* AlterReplicaLogDirs */
+	35: "describelogdirs",              /* DescribeLogDirs */
+	36: "saslauthenticate",             /* SaslAuthenticate */
+	37: "createpartitions",             /* CreatePartitions */
+	38: "createdelegationtoken",        /* CreateDelegationToken */
+	39: "renewdelegationtoken",         /* RenewDelegationToken */
+	40: "expiredelegationtoken",        /* ExpireDelegationToken */
+	41: "describedelegationtoken",      /* DescribeDelegationToken */
+	42: "deletegroups",                 /* DeleteGroups */
+	43: "electleaders",                 /* ElectLeaders */
+	44: "incrementalalterconfigs",      /* IncrementalAlterConfigs */
+	45: "alterpartitionreassignments",  /* AlterPartitionReassignments */
+	46: "listpartitionreassignments",   /* ListPartitionReassignments */
+	47: "offsetdelete",                 /* OffsetDelete */
+	48: "describeclientquotas",         /* DescribeClientQuotas */
+	49: "alterclientquotas",            /* AlterClientQuotas */
+	50: "describeuserscramcredentials", /* DescribeUserScramCredentials */
+	51: "alteruserscramcredentials",    /* AlterUserScramCredentials */
+	60: "describecluster",              /* DescribeCluster */
+	61: "describeproducers",            /* DescribeProducers */
+	65: "describetransactions",         /* DescribeTransactions */
+	66: "listtransactions",             /* ListTransactions */
+	68: "consumergroupheartbeat",       /* ConsumerGroupHeartbeat */
+	69: "consumergroupdescribe",        /* ConsumerGroupDescribe */
 }
 
 // KafkaRole is the list of all low-level apiKeys to
@@ -260,6 +335,12 @@ func (kr *PortRuleKafka) CheckAPIKeyRole(kind int16) bool {
 	return false
 }
 
+// GetAPIKeys returns the API keys allowed by the APIKey or the expanded Role
+// of the rule, or nil if any API key is allowed
+func (kr *PortRuleKafka) GetAPIKeys() []int16 {
+	return kr.apiKeyInt
+}
+
 // GetAPIVersion returns the APIVersion as integer or the bool set to true if
 // any API version is allowed
 func (kr *PortRuleKafka) GetAPIVersion() (int16, bool) {
@@ -274,18 +355,24 @@ func (kr *PortRuleKafka) GetAPIVersion() (int16, bool) {
 func (kr *PortRuleKafka) MapRoleToAPIKey() error {
 	// Expand the kr.apiKeyInt array based on the Role.
 	// For produce role, we need to add mandatory apiKeys produce, metadata and
-	// apiversions. While for consume, we need to add mandatory apiKeys like
-	// fetch, offsets, offsetcommit, offsetfetch, apiversions, metadata,
-	// findcoordinator, joingroup, heartbeat,
-	// leavegroup and syncgroup.
+	// apiversions, as well as initproducerid used by idempotent producers
+	// and findcoordinator, addpartitionstotxn, addoffsetstotxn, endtxn and
+	// txnoffsetcommit used by transactional producers. While for consume,
+	// we need to add mandatory apiKeys like fetch, offsets, offsetcommit,
+	// offsetfetch, apiversions, metadata, findcoordinator, joingroup,
+	// heartbeat, leavegroup, syncgroup, offsetforleaderepoch and
+	// consumergroupheartbeat.
 	switch strings.ToLower(kr.Role) {
 	case ProduceRole:
-		kr.apiKeyInt = KafkaRole{ProduceKey, MetadataKey, APIVersionsKey}
+		kr.apiKeyInt = KafkaRole{ProduceKey, MetadataKey, APIVersionsKey,
+			InitProducerIDKey, FindCoordinatorKey, AddPartitionsToTxnKey,
+			AddOffsetsToTxnKey, EndTxnKey, TxnOffsetCommitKey}
 		return nil
 	case ConsumeRole:
 		kr.apiKeyInt = KafkaRole{FetchKey, OffsetsKey, MetadataKey,
 			OffsetCommitKey, OffsetFetchKey, FindCoordinatorKey,
-			JoinGroupKey, HeartbeatKey, LeaveGroupKey, SyncgroupKey, APIVersionsKey}
+			JoinGroupKey, HeartbeatKey, LeaveGroupKey, SyncgroupKey, APIVersionsKey,
+			OffsetForLeaderEpochKey, ConsumerGroupHeartbeatKey}
 		return nil
 	default:
 		return fmt.Errorf("Invalid Kafka Role %s", kr.Role)
@@ -57,6 +57,16 @@ type PortRule struct {
 	//
 	// +optional
 	Rules *L7Rules `json:"rules,omitempty"`
+
+	// ServerNames is a list of allowed TLS server names, matched against
+	// the Server Name Indication (SNI) of the TLS ClientHello. A name may
+	// begin with "*." to match any single DNS label in its place, e.g.,
+	// "*.example.com" matches "api.example.com", but not "example.com".
+	// If omitted or empty, the server name is not enforced. ServerNames
+	// cannot be combined with Rules.
+	//
+	// +optional
+	ServerNames []string `json:"serverNames,omitempty"`
 }
 
 // L7Rules is a union of port level rule types. Mixing of different port
@@ -73,6 +83,11 @@ type L7Rules struct {
 	// +optional
 	Kafka []PortRuleKafka `json:"kafka,omitempty"`
 
+	// gRPC-specific rules.
+	//
+	// +optional
+	GRPC []PortRuleGRPC `json:"grpc,omitempty"`
+
 	// Name of the L7 protocol for which the Key-value pair rules apply
 	//
 	// +optional
@@ -90,10 +105,10 @@ func (rules *L7Rules) Len() int {
 	if rules == nil {
 		return 0
 	}
-	return len(rules.HTTP) + len(rules.Kafka) + len(rules.L7)
+	return len(rules.HTTP) + len(rules.Kafka) + len(rules.GRPC) + len(rules.L7)
 }
 
 // IsEmpty returns whether the `L7Rules` is nil or contains nil rules.
 func (rules *L7Rules) IsEmpty() bool {
-	return rules == nil || (rules.HTTP == nil && rules.Kafka == nil && rules.L7 == nil)
+	return rules == nil || (rules.HTTP == nil && rules.Kafka == nil && rules.GRPC == nil && rules.L7 == nil)
 }
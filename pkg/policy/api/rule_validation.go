@@ -295,6 +295,15 @@ func (pr *L7Rules) sanitize() error {
 		}
 	}
 
+	if pr.GRPC != nil {
+		nTypes++
+		for i := range pr.GRPC {
+			if err := pr.GRPC[i].Sanitize(); err != nil {
+				return err
+			}
+		}
+	}
+
 	if pr.L7 != nil && pr.L7Proto == "" {
 		return fmt.Errorf("'l7' may only be specified when a 'l7proto' is also specified")
 	}
@@ -324,6 +333,26 @@ func (pr *PortRule) sanitize() error {
 		if !pr.Rules.IsEmpty() && pr.Ports[i].Protocol != ProtoTCP {
 			return fmt.Errorf("L7 rules can only apply exclusively to TCP, not %s", pr.Ports[i].Protocol)
 		}
+		if len(pr.ServerNames) > 0 && pr.Ports[i].Protocol != ProtoTCP {
+			return fmt.Errorf("server names can only apply exclusively to TCP, not %s", pr.Ports[i].Protocol)
+		}
+	}
+
+	// Sanitize TLS server names
+	if len(pr.ServerNames) > 0 {
+		if pr.Rules != nil && (!pr.Rules.IsEmpty() || pr.Rules.L7Proto != "") {
+			return fmt.Errorf("server names cannot be combined with L7 rules")
+		}
+		if len(pr.ServerNames) > maxServerNames {
+			return fmt.Errorf("too many server names, the max is %d", maxServerNames)
+		}
+		for i := range pr.ServerNames {
+			name, err := sanitizeServerName(pr.ServerNames[i])
+			if err != nil {
+				return err
+			}
+			pr.ServerNames[i] = name
+		}
 	}
 
 	// Sanitize L7 rules
@@ -17,6 +17,10 @@
 package api
 
 import (
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/checker"
 	"github.com/cilium/cilium/pkg/labels"
 
 	. "gopkg.in/check.v1"
@@ -276,6 +280,86 @@ func (s *PolicyAPITestSuite) TestToServicesSanitize(c *C) {
 
 }
 
+func (s *PolicyAPITestSuite) TestHTTPHeaderMatches(c *C) {
+	valid := []HeaderMatch{
+		{Name: "x-present"},
+		{Name: "x-value", Value: "foo"},
+		{Name: "x-regex", Regex: "^v[0-9]+$", Mismatch: MismatchActionLog},
+		{Name: "x-absent", Absent: true, Mismatch: MismatchActionDeny},
+		{Name: "x-secret", Secret: &Secret{Name: "api-key"}, Mismatch: MismatchActionReplace},
+		{Name: "x-add", Value: "bar", Mismatch: MismatchActionAdd},
+	}
+	for _, hm := range valid {
+		rule := PortRuleHTTP{HeaderMatches: []HeaderMatch{hm}}
+		c.Assert(rule.Sanitize(), IsNil, Commentf("%+v", hm))
+	}
+
+	invalid := []HeaderMatch{
+		{Value: "no-name"},
+		{Name: "x-regex", Regex: "*"},
+		{Name: "x-both", Value: "foo", Regex: "foo"},
+		{Name: "x-both", Value: "foo", Absent: true},
+		{Name: "x-secret", Secret: &Secret{Namespace: "default"}},
+		{Name: "x-add", Mismatch: MismatchActionAdd},
+		{Name: "x-replace", Regex: "foo", Mismatch: MismatchActionReplace},
+		{Name: "x-action", Value: "foo", Mismatch: "DROP"},
+	}
+	for _, hm := range invalid {
+		rule := PortRuleHTTP{HeaderMatches: []HeaderMatch{hm}}
+		c.Assert(rule.Sanitize(), Not(IsNil), Commentf("%+v", hm))
+	}
+}
+
+func (s *PolicyAPITestSuite) TestHTTPRateLimit(c *C) {
+	valid := []RateLimit{
+		{Requests: 100},
+		{Requests: 10, Interval: "1m", Burst: 20},
+		{Requests: 1, Interval: "500ms", Per: RateLimitPerEndpoint},
+		{Requests: 1, Per: RateLimitPerIdentity},
+	}
+	for i := range valid {
+		rule := PortRuleHTTP{RateLimit: &valid[i]}
+		c.Assert(rule.Sanitize(), IsNil, Commentf("%+v", valid[i]))
+	}
+	c.Assert(valid[0].GetInterval(), Equals, time.Second)
+	c.Assert(valid[1].GetInterval(), Equals, time.Minute)
+
+	invalid := []RateLimit{
+		{},
+		{Requests: 10, Interval: "1"},
+		{Requests: 10, Interval: "-1s"},
+		{Requests: 10, Interval: "0s"},
+		{Requests: 10, Per: "pod"},
+	}
+	for i := range invalid {
+		rule := PortRuleHTTP{RateLimit: &invalid[i]}
+		c.Assert(rule.Sanitize(), Not(IsNil), Commentf("%+v", invalid[i]))
+	}
+}
+
+func (s *PolicyAPITestSuite) TestServerNames(c *C) {
+	tcp := []PortProtocol{{Port: "443", Protocol: ProtoTCP}}
+
+	rule := PortRule{Ports: tcp, ServerNames: []string{"API.github.com.", "*.example.com"}}
+	c.Assert(rule.sanitize(), IsNil)
+	c.Assert(rule.ServerNames, checker.DeepEquals, []string{"api.github.com", "*.example.com"})
+
+	invalid := []PortRule{
+		{Ports: tcp, ServerNames: []string{""}},
+		{Ports: tcp, ServerNames: []string{"*"}},
+		{Ports: tcp, ServerNames: []string{"api.*.com"}},
+		{Ports: tcp, ServerNames: []string{"-api.github.com"}},
+		{Ports: tcp, ServerNames: []string{"api github.com"}},
+		{Ports: tcp, ServerNames: []string{strings.Repeat("a", 64) + ".com"}},
+		{Ports: []PortProtocol{{Port: "443", Protocol: ProtoUDP}}, ServerNames: []string{"api.github.com"}},
+		{Ports: tcp, ServerNames: []string{"api.github.com"}, Rules: &L7Rules{HTTP: []PortRuleHTTP{{Method: "GET"}}}},
+		{Ports: tcp, ServerNames: []string{"api.github.com"}, Rules: &L7Rules{L7Proto: "test.lineparser"}},
+	}
+	for _, r := range invalid {
+		c.Assert(r.sanitize(), Not(IsNil), Commentf("%+v", r))
+	}
+}
+
 // This test ensures that PortRules using key-value pairs do not have empty keys
 func (s *PolicyAPITestSuite) TestL7Rules(c *C) {
 
@@ -353,6 +437,61 @@ func (s *PolicyAPITestSuite) TestL7Rules(c *C) {
 	c.Assert(err, Not(IsNil))
 }
 
+func (s *PolicyAPITestSuite) TestGRPCRules(c *C) {
+	grpcRule := func(rules *L7Rules) Rule {
+		return Rule{
+			EndpointSelector: WildcardEndpointSelector,
+			Ingress: []IngressRule{
+				{
+					FromEndpoints: []EndpointSelector{WildcardEndpointSelector},
+					ToPorts: []PortRule{{
+						Ports: []PortProtocol{
+							{Port: "50051", Protocol: ProtoTCP},
+						},
+						Rules: rules,
+					}},
+				},
+			},
+		}
+	}
+
+	validGRPCRule := grpcRule(&L7Rules{
+		GRPC: []PortRuleGRPC{
+			{Service: "helloworld.Greeter", Method: "SayHello"},
+			{Service: "grpc.health.v1.Health"},
+			{Method: "Check", Headers: []string{"x-token: secret"}},
+		},
+	})
+	err := validGRPCRule.Sanitize()
+	c.Assert(err, IsNil)
+
+	invalidServiceRule := grpcRule(&L7Rules{
+		GRPC: []PortRuleGRPC{{Service: "helloworld/Greeter"}},
+	})
+	err = invalidServiceRule.Sanitize()
+	c.Assert(err, Not(IsNil))
+
+	invalidMethodRule := grpcRule(&L7Rules{
+		GRPC: []PortRuleGRPC{{Service: "helloworld.Greeter", Method: "Say.Hello"}},
+	})
+	err = invalidMethodRule.Sanitize()
+	c.Assert(err, Not(IsNil))
+
+	mixedHTTPRule := grpcRule(&L7Rules{
+		HTTP: []PortRuleHTTP{{Path: "/"}},
+		GRPC: []PortRuleGRPC{{Service: "helloworld.Greeter"}},
+	})
+	err = mixedHTTPRule.Sanitize()
+	c.Assert(err, Not(IsNil))
+
+	mixedL7Rule := grpcRule(&L7Rules{
+		L7Proto: "test.lineparser",
+		GRPC:    []PortRuleGRPC{{Service: "helloworld.Greeter"}},
+	})
+	err = mixedL7Rule.Sanitize()
+	c.Assert(err, Not(IsNil))
+}
+
 func (s *PolicyAPITestSuite) TestInvalidEndpointSelectors(c *C) {
 
 	// Operator in MatchExpressions is invalid, so sanitization should fail.
@@ -0,0 +1,48 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxServerNameLen is the maximum length of a DNS name
+	maxServerNameLen = 253
+	// maxServerNames is the maximum number of server names in a PortRule
+	maxServerNames = 40
+)
+
+// ServerNameValidChar matches valid lowercase TLS server names, optionally
+// prefixed with a "*." wildcard label
+var ServerNameValidChar = regexp.MustCompile(`^(\*\.)?([a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?\.)*[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// sanitizeServerName validates 'name' and returns it in the canonical form,
+// which is lowercase without a trailing dot
+func sanitizeServerName(name string) (string, error) {
+	canonical := strings.TrimSuffix(strings.ToLower(name), ".")
+	if len(canonical) == 0 {
+		return "", fmt.Errorf("empty server name")
+	}
+	if len(canonical) > maxServerNameLen {
+		return "", fmt.Errorf("server name %q is longer than %d characters", name, maxServerNameLen)
+	}
+	if !ServerNameValidChar.MatchString(canonical) {
+		return "", fmt.Errorf("invalid server name %q", name)
+	}
+	return canonical, nil
+}
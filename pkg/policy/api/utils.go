@@ -35,7 +35,8 @@ func (h *PortRuleHTTP) Equal(o PortRuleHTTP) bool {
 	if h.Path != o.Path ||
 		h.Method != o.Method ||
 		h.Host != o.Host ||
-		len(h.Headers) != len(o.Headers) {
+		len(h.Headers) != len(o.Headers) ||
+		len(h.HeaderMatches) != len(o.HeaderMatches) {
 		return false
 	}
 
@@ -44,7 +45,33 @@ func (h *PortRuleHTTP) Equal(o PortRuleHTTP) bool {
 			return false
 		}
 	}
-	return true
+
+	for i := range h.HeaderMatches {
+		if !h.HeaderMatches[i].Equal(&o.HeaderMatches[i]) {
+			return false
+		}
+	}
+
+	if h.RateLimit == nil || o.RateLimit == nil {
+		return h.RateLimit == o.RateLimit
+	}
+	return *h.RateLimit == *o.RateLimit
+}
+
+// Equal returns true if both header matches are equal
+func (hm *HeaderMatch) Equal(o *HeaderMatch) bool {
+	if hm.Name != o.Name ||
+		hm.Value != o.Value ||
+		hm.Regex != o.Regex ||
+		hm.Absent != o.Absent ||
+		hm.Mismatch != o.Mismatch {
+		return false
+	}
+
+	if hm.Secret == nil || o.Secret == nil {
+		return hm.Secret == o.Secret
+	}
+	return *hm.Secret == *o.Secret
 }
 
 // Exists returns true if the HTTP rule already exists in the list of rules
@@ -61,7 +88,35 @@ func (k *PortRuleKafka) Exists(rules L7Rules) bool {
 // Equal returns true if both rules are equal
 func (k *PortRuleKafka) Equal(o PortRuleKafka) bool {
 	return k.APIVersion == o.APIVersion && k.APIKey == o.APIKey &&
-		k.Topic == o.Topic && k.ClientID == o.ClientID && k.Role == o.Role
+		k.Topic == o.Topic && k.ClientID == o.ClientID && k.Role == o.Role &&
+		k.Principal == o.Principal
+}
+
+// Exists returns true if the gRPC rule already exists in the list of rules
+func (g *PortRuleGRPC) Exists(rules L7Rules) bool {
+	for _, existingRule := range rules.GRPC {
+		if g.Equal(existingRule) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Equal returns true if both gRPC rules are equal
+func (g *PortRuleGRPC) Equal(o PortRuleGRPC) bool {
+	if g.Service != o.Service ||
+		g.Method != o.Method ||
+		len(g.Headers) != len(o.Headers) {
+		return false
+	}
+
+	for i, value := range g.Headers {
+		if o.Headers[i] != value {
+			return false
+		}
+	}
+	return true
 }
 
 // Exists returns true if the L7 rule already exists in the list of rules
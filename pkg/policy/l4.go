@@ -84,8 +84,23 @@ const (
 	ParserTypeHTTP L7ParserType = "http"
 	// ParserTypeKafka specifies a Kafka parser type
 	ParserTypeKafka L7ParserType = "kafka"
+	// ParserTypeTLS specifies the TLS server name parser type
+	ParserTypeTLS L7ParserType = "tls"
 )
 
+// serverNameRules returns the key-value pair rules enforcing the TLS
+// server names 'serverNames' by the TLS parser.
+func serverNameRules(serverNames []string) *api.L7Rules {
+	rules := &api.L7Rules{
+		L7Proto: ParserTypeTLS.String(),
+		L7:      make([]api.PortRuleL7, 0, len(serverNames)),
+	}
+	for _, name := range serverNames {
+		rules.L7 = append(rules.L7, api.PortRuleL7{"serverName": name})
+	}
+	return rules
+}
+
 type L4Filter struct {
 	// Port is the destination port to allow
 	Port int `json:"port"`
@@ -123,6 +138,7 @@ func (l7 L7DataMap) GetRelevantRules(identity *identity.Identity) api.L7Rules {
 			if selector.Matches(identity.Labels.LabelArray()) {
 				rules.HTTP = append(rules.HTTP, endpointRules.HTTP...)
 				rules.Kafka = append(rules.Kafka, endpointRules.Kafka...)
+				rules.GRPC = append(rules.GRPC, endpointRules.GRPC...)
 				rules.L7Proto = endpointRules.L7Proto
 				rules.L7 = append(rules.L7, endpointRules.L7...)
 			}
@@ -133,6 +149,7 @@ func (l7 L7DataMap) GetRelevantRules(identity *identity.Identity) api.L7Rules {
 	if r, ok := l7[api.WildcardEndpointSelector]; ok {
 		rules.HTTP = append(rules.HTTP, r.HTTP...)
 		rules.Kafka = append(rules.Kafka, r.Kafka...)
+		rules.GRPC = append(rules.GRPC, r.GRPC...)
 		rules.L7Proto = r.L7Proto // XXX
 		rules.L7 = append(rules.L7, r.L7...)
 	}
@@ -182,9 +199,16 @@ func CreateL4Filter(peerEndpoints api.EndpointSelectorSlice, rule api.PortRule,
 		Ingress:          ingress,
 	}
 
+	// TLS server names are enforced as L7 rules of the TLS parser.
+	// Sanitize ensures they are not combined with other L7 rules.
+	if len(rule.ServerNames) > 0 {
+		rule.Rules = serverNameRules(rule.ServerNames)
+	}
+
 	if protocol == api.ProtoTCP && rule.Rules != nil {
 		switch {
-		case len(rule.Rules.HTTP) > 0:
+		case len(rule.Rules.HTTP) > 0, len(rule.Rules.GRPC) > 0:
+			// gRPC is carried over HTTP/2 and enforced by the HTTP proxy.
 			l4.L7Parser = ParserTypeHTTP
 		case len(rule.Rules.Kafka) > 0:
 			l4.L7Parser = ParserTypeKafka
@@ -213,7 +237,7 @@ func CreateL4IngressFilter(fromEndpoints api.EndpointSelectorSlice, endpointsWit
 
 	// If the filter would apply L7 rules for endpointsWithL3Override,
 	// then wildcard those specific endpoints at L7.
-	if !rule.Rules.IsEmpty() {
+	if !rule.Rules.IsEmpty() || len(rule.ServerNames) > 0 {
 		for _, selector := range endpointsWithL3Override {
 			filter.L7RulesPerEp[selector] = api.L7Rules{}
 		}
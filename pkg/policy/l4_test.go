@@ -85,6 +85,36 @@ func (s *PolicyTestSuite) TestCreateL4Filter(c *C) {
 	}
 }
 
+func (s *PolicyTestSuite) TestCreateL4FilterServerNames(c *C) {
+	tuple := api.PortProtocol{Port: "443", Protocol: api.ProtoTCP}
+	portrule := api.PortRule{
+		Ports:       []api.PortProtocol{tuple},
+		ServerNames: []string{"api.github.com", "*.example.com"},
+	}
+	expected := api.L7Rules{
+		L7Proto: "tls",
+		L7: []api.PortRuleL7{
+			{"serverName": "api.github.com"},
+			{"serverName": "*.example.com"},
+		},
+	}
+
+	filter := CreateL4EgressFilter(nil, portrule, tuple, tuple.Protocol, nil)
+	c.Assert(filter.L7Parser, Equals, ParserTypeTLS)
+	c.Assert(filter.IsRedirect(), Equals, true)
+	c.Assert(filter.L7RulesPerEp, checker.DeepEquals, L7DataMap{
+		api.WildcardEndpointSelector: expected,
+	})
+
+	host := api.ReservedEndpointSelectors[labels.IDNameHost]
+	filter = CreateL4IngressFilter(nil, []api.EndpointSelector{host}, portrule, tuple, tuple.Protocol, nil)
+	c.Assert(filter.L7Parser, Equals, ParserTypeTLS)
+	c.Assert(filter.L7RulesPerEp, checker.DeepEquals, L7DataMap{
+		api.WildcardEndpointSelector: expected,
+		host:                         api.L7Rules{},
+	})
+}
+
 type SortablePolicyRules []*models.PolicyRule
 
 func (a SortablePolicyRules) Len() int           { return len(a) }
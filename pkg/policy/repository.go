@@ -642,6 +642,22 @@ func (p *Repository) GetRulesMatching(labels labels.LabelArray) (ingressMatch bo
 	return
 }
 
+// GetIngressRulesSelectingRLocked returns all rules with ingress sections
+// which select an endpoint with the provided labels.
+//
+// Must be called with p.Mutex held
+func (p *Repository) GetIngressRulesSelectingRLocked(labels labels.LabelArray) api.Rules {
+	result := api.Rules{}
+
+	for _, r := range p.rules {
+		if len(r.Ingress) > 0 && r.EndpointSelector.Matches(labels) {
+			result = append(result, &r.Rule)
+		}
+	}
+
+	return result
+}
+
 // NumRules returns the amount of rules in the policy repository.
 //
 // Must be called with p.Mutex held
@@ -192,6 +192,40 @@ func (ds *PolicyTestSuite) TestContainsAllRLocked(c *C) {
 	c.Assert(repoEmpty.ContainsAllRLocked(a), Equals, false)    // a is NOT in empty
 }
 
+func (ds *PolicyTestSuite) TestGetIngressRulesSelectingRLocked(c *C) {
+	fooSelector := api.NewESFromLabels(labels.ParseSelectLabel("foo"))
+	barSelector := api.NewESFromLabels(labels.ParseSelectLabel("bar"))
+
+	ingressRule := api.Rule{
+		EndpointSelector: fooSelector,
+		Ingress: []api.IngressRule{
+			{FromEndpoints: []api.EndpointSelector{barSelector}},
+		},
+		Labels: labels.LabelArray{labels.NewLabel("1", "1", "1")},
+	}
+	egressRule := api.Rule{
+		EndpointSelector: fooSelector,
+		Egress: []api.EgressRule{
+			{ToEndpoints: []api.EndpointSelector{barSelector}},
+		},
+		Labels: labels.LabelArray{labels.NewLabel("2", "2", "1")},
+	}
+	otherRule := api.Rule{
+		EndpointSelector: barSelector,
+		Ingress: []api.IngressRule{
+			{FromEndpoints: []api.EndpointSelector{fooSelector}},
+		},
+		Labels: labels.LabelArray{labels.NewLabel("3", "3", "1")},
+	}
+
+	repo := NewPolicyRepository()
+	repo.AddList(api.Rules{&ingressRule, &egressRule, &otherRule})
+
+	fooLabels := labels.ParseSelectLabelArray("foo")
+	c.Assert(repo.GetIngressRulesSelectingRLocked(fooLabels), checker.DeepEquals, api.Rules{&ingressRule})
+	c.Assert(repo.GetIngressRulesSelectingRLocked(labels.ParseSelectLabelArray("baz")), checker.DeepEquals, api.Rules{})
+}
+
 func (ds *PolicyTestSuite) TestCanReachIngress(c *C) {
 	repo := NewPolicyRepository()
 
@@ -72,8 +72,19 @@ func mergeL4Port(ctx *SearchContext, endpoints []api.EndpointSelector, existingF
 						ep.HTTP = append(ep.HTTP, newRule)
 					}
 				}
+			case len(newL7Rules.GRPC) > 0:
+				if len(ep.Kafka) > 0 || ep.L7Proto != "" {
+					ctx.PolicyTrace("   Merge conflict: mismatching L7 rule types.\n")
+					return fmt.Errorf("Cannot merge conflicting L7 rule types")
+				}
+
+				for _, newRule := range newL7Rules.GRPC {
+					if !newRule.Exists(ep) {
+						ep.GRPC = append(ep.GRPC, newRule)
+					}
+				}
 			case len(newL7Rules.Kafka) > 0:
-				if len(ep.HTTP) > 0 || ep.L7Proto != "" {
+				if len(ep.HTTP) > 0 || len(ep.GRPC) > 0 || ep.L7Proto != "" {
 					ctx.PolicyTrace("   Merge conflict: mismatching L7 rule types.\n")
 					return fmt.Errorf("Cannot merge conflicting L7 rule types")
 				}
@@ -84,7 +95,7 @@ func mergeL4Port(ctx *SearchContext, endpoints []api.EndpointSelector, existingF
 					}
 				}
 			case newL7Rules.L7Proto != "":
-				if len(ep.Kafka) > 0 || len(ep.HTTP) > 0 || (ep.L7Proto != "" && ep.L7Proto != newL7Rules.L7Proto) {
+				if len(ep.Kafka) > 0 || len(ep.HTTP) > 0 || len(ep.GRPC) > 0 || (ep.L7Proto != "" && ep.L7Proto != newL7Rules.L7Proto) {
 					ctx.PolicyTrace("   Merge conflict: mismatching L7 rule types.\n")
 					return fmt.Errorf("Cannot merge conflicting L7 rule types")
 				}
@@ -176,6 +187,9 @@ func mergeL4Ingress(ctx *SearchContext, rule api.IngressRule, ruleLabels labels.
 		if r.Rules != nil && r.Rules.L7Proto != "" {
 			ctx.PolicyTrace("      l7proto: \"%s\"\n", r.Rules.L7Proto)
 		}
+		if len(r.ServerNames) > 0 {
+			ctx.PolicyTrace("      serverNames: %v\n", r.ServerNames)
+		}
 		if !r.Rules.IsEmpty() {
 			for _, l7 := range r.Rules.HTTP {
 				ctx.PolicyTrace("        %+v\n", l7)
@@ -183,6 +197,9 @@ func mergeL4Ingress(ctx *SearchContext, rule api.IngressRule, ruleLabels labels.
 			for _, l7 := range r.Rules.Kafka {
 				ctx.PolicyTrace("        %+v\n", l7)
 			}
+			for _, l7 := range r.Rules.GRPC {
+				ctx.PolicyTrace("        %+v\n", l7)
+			}
 			for _, l7 := range r.Rules.L7 {
 				ctx.PolicyTrace("        %+v\n", l7)
 			}
@@ -453,6 +470,9 @@ func mergeL4Egress(ctx *SearchContext, rule api.EgressRule, ruleLabels labels.La
 		if r.Rules != nil && r.Rules.L7Proto != "" {
 			ctx.PolicyTrace("      l7proto: \"%s\"\n", r.Rules.L7Proto)
 		}
+		if len(r.ServerNames) > 0 {
+			ctx.PolicyTrace("      serverNames: %v\n", r.ServerNames)
+		}
 		if !r.Rules.IsEmpty() {
 			for _, l7 := range r.Rules.HTTP {
 				ctx.PolicyTrace("        %+v\n", l7)
@@ -460,6 +480,9 @@ func mergeL4Egress(ctx *SearchContext, rule api.EgressRule, ruleLabels labels.La
 			for _, l7 := range r.Rules.Kafka {
 				ctx.PolicyTrace("        %+v\n", l7)
 			}
+			for _, l7 := range r.Rules.GRPC {
+				ctx.PolicyTrace("        %+v\n", l7)
+			}
 			for _, l7 := range r.Rules.L7 {
 				ctx.PolicyTrace("        %+v\n", l7)
 			}
@@ -45,6 +45,10 @@ const (
 
 	// VerdictError indicates that there was an error processing the flow
 	VerdictError = "Error"
+
+	// VerdictRateLimited indicates that the flow was denied due to a rate
+	// limit of the policy
+	VerdictRateLimited = "RateLimited"
 )
 
 // ObservationPoint is the type used to describe point of observation
@@ -215,6 +219,32 @@ type LogRecordHTTP struct {
 
 	// Headers are all HTTP headers present in the request
 	Headers http.Header
+
+	// MissingHeaders are the names of the request headers that did not
+	// match a header match of the policy which does not deny the request.
+	// The values are not logged, as they may be secret.
+	MissingHeaders http.Header `json:"MissingHeaders,omitempty"`
+
+	// RejectedHeaders are the request headers that have been removed or
+	// replaced due to a header match of the policy
+	RejectedHeaders http.Header `json:"RejectedHeaders,omitempty"`
+
+	// GRPC is set if the request is a gRPC call
+	GRPC *LogRecordGRPC `json:"GRPC,omitempty"`
+}
+
+// LogRecordGRPC contains the gRPC specific portion of an HTTP log record
+type LogRecordGRPC struct {
+	// Service is the fully qualified name of the service being called
+	Service string
+
+	// Method is the name of the method being called
+	Method string
+
+	// Status is the gRPC status code being returned, nil if the response
+	// carries no "grpc-status"
+	// Reference: https://github.com/grpc/grpc/blob/master/doc/statuscodes.md
+	Status *int
 }
 
 // KafkaTopic contains the topic for requests
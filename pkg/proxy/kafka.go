@@ -28,7 +28,6 @@ import (
 	"github.com/cilium/cilium/pkg/kafka"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/policy"
-	"github.com/cilium/cilium/pkg/policy/api"
 	"github.com/cilium/cilium/pkg/proxy/accesslog"
 	"github.com/cilium/cilium/pkg/proxy/logger"
 
@@ -159,20 +158,13 @@ type kafkaLogRecord struct {
 	topics        []string
 }
 
-func apiKeyToString(apiKey int16) string {
-	if key, ok := api.KafkaReverseAPIKeyMap[apiKey]; ok {
-		return key
-	}
-	return fmt.Sprintf("%d", apiKey)
-}
-
 func (k *kafkaRedirect) newLogRecordFromRequest(req *kafka.RequestMessage) kafkaLogRecord {
 	return kafkaLogRecord{
 		LogRecord: logger.NewLogRecord(k.endpointInfoRegistry, k.redirect.localEndpoint,
 			accesslog.TypeRequest, k.redirect.ingress,
 			logger.LogTags.Kafka(&accesslog.LogRecordKafka{
 				APIVersion:    req.GetVersion(),
-				APIKey:        apiKeyToString(req.GetAPIKey()),
+				APIKey:        kafka.APIKeyToString(req.GetAPIKey()),
 				CorrelationID: int32(req.GetCorrelationID()),
 			})),
 		localEndpoint: k.redirect.localEndpoint,
@@ -193,7 +185,7 @@ func (k *kafkaRedirect) newLogRecordFromResponse(res *kafka.ResponseMessage, req
 
 	if req != nil {
 		lr.Kafka.APIVersion = req.GetVersion()
-		lr.Kafka.APIKey = apiKeyToString(req.GetAPIKey())
+		lr.Kafka.APIKey = kafka.APIKeyToString(req.GetAPIKey())
 		lr.topics = req.GetTopics()
 	}
 
@@ -230,8 +222,9 @@ func (l *kafkaLogRecord) log(verdict accesslog.FlowVerdict, code int, info strin
 
 }
 
+// handleRequest returns true if the request has been forwarded to the broker
 func (k *kafkaRedirect) handleRequest(pair *connectionPair, req *kafka.RequestMessage, correlationCache *kafka.CorrelationCache,
-	remoteAddr net.Addr, remoteIdentity uint32, origDstAddr string) {
+	remoteAddr net.Addr, remoteIdentity uint32, origDstAddr string) bool {
 	scopedLog := log.WithField(fieldID, pair.String())
 	flowdebug.Log(scopedLog.WithField(logfields.Request, req.String()), "Handling Kafka request")
 
@@ -250,15 +243,18 @@ func (k *kafkaRedirect) handleRequest(pair *connectionPair, req *kafka.RequestMe
 		if err != nil {
 			record.log(accesslog.VerdictError,
 				kafka.ErrInvalidMessage, fmt.Sprintf("Unable to create response: %s", err))
-			scopedLog.WithError(err).Error("Unable to create Kafka response")
-			return
+			scopedLog.WithError(err).Error("Unable to create Kafka response; closing Kafka request connection")
+			// The client would wait for the response until it times
+			// out, so close the connection to fail the request now
+			pair.Rx.Close()
+			return false
 		}
 
 		record.log(accesslog.VerdictDenied,
 			kafka.ErrTopicAuthorizationFailed, fmt.Sprint("Kafka request is denied by policy"))
 
 		pair.Rx.Enqueue(resp.GetRaw())
-		return
+		return false
 	}
 
 	if pair.Tx.Closed() {
@@ -282,7 +278,7 @@ func (k *kafkaRedirect) handleRequest(pair *connectionPair, req *kafka.RequestMe
 			record.log(accesslog.VerdictError,
 				kafka.ErrNetwork, fmt.Sprintf("Unable to dial original destination: %s", err))
 
-			return
+			return false
 		}
 
 		pair.Tx.SetConnection(txConn)
@@ -304,10 +300,11 @@ func (k *kafkaRedirect) handleRequest(pair *connectionPair, req *kafka.RequestMe
 
 	// Write the entire raw request onto the outgoing connection
 	pair.Tx.Enqueue(req.GetRaw())
+	return true
 }
 
 type kafkaReqMessageHander func(pair *connectionPair, req *kafka.RequestMessage, correlationCache *kafka.CorrelationCache,
-	remoteAddr net.Addr, remoteIdentity uint32, origDstAddr string)
+	remoteAddr net.Addr, remoteIdentity uint32, origDstAddr string) bool
 type kafkaRespMessageHander func(pair *connectionPair, req *kafka.ResponseMessage)
 
 func (k *kafkaRedirect) handleRequests(done <-chan struct{}, pair *connectionPair, c *proxyConnection,
@@ -335,6 +332,9 @@ func (k *kafkaRedirect) handleRequests(done <-chan struct{}, pair *connectionPai
 	correlationCache := kafka.NewCorrelationCache()
 	defer correlationCache.DeleteCache()
 
+	// track the SASL principal of the client
+	session := &kafka.Session{}
+
 	for {
 		req, err := kafka.ReadRequest(c.conn)
 
@@ -354,7 +354,10 @@ func (k *kafkaRedirect) handleRequests(done <-chan struct{}, pair *connectionPai
 			return
 		}
 
-		handler(pair, req, correlationCache, remoteAddr, srcIdentity, dstIPPort)
+		req.SetPrincipal(session.Principal())
+		if handler(pair, req, correlationCache, remoteAddr, srcIdentity, dstIPPort) {
+			session.HandleRequest(req)
+		}
 	}
 }
 
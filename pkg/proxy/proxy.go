@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/cilium/cilium/pkg/maps/proxymap"
 	"github.com/cilium/cilium/pkg/metrics"
 	"github.com/cilium/cilium/pkg/node"
+	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/proxy/logger"
 	"github.com/cilium/cilium/pkg/revert"
@@ -81,12 +83,23 @@ type Proxy struct {
 	// the redirect identifier. Redirects may be implemented by different
 	// proxies.
 	redirects map[string]*Redirect
+
+	// drainingRedirects is the map of removed redirects which keep serving
+	// the connections established through them until they are closed at
+	// the end of the drain period, indexed by their proxy port
+	drainingRedirects map[uint16]*Redirect
+
+	// flushConntrack removes the conntrack entries of the connections
+	// which were redirected to the given proxy port
+	flushConntrack func(proxyPort uint16)
 }
 
 // StartProxySupport starts the servers to support L7 proxies: xDS GRPC server
-// and access log server.
+// and access log server. flushConntrack is called to remove the conntrack
+// entries which record a proxy port when the port is released.
 func StartProxySupport(minPort uint16, maxPort uint16, stateDir string,
-	accessLogFile string, accessLogNotifier logger.LogRecordNotifier, accessLogMetadata []string) *Proxy {
+	accessLogFile string, accessLogNotifier logger.LogRecordNotifier, accessLogMetadata []string,
+	flushConntrack func(proxyPort uint16)) *Proxy {
 	xdsServer := envoy.StartXDSServer(stateDir)
 
 	if accessLogFile != "" {
@@ -107,12 +120,14 @@ func StartProxySupport(minPort uint16, maxPort uint16, stateDir string,
 	envoy.StartAccessLogServer(stateDir, xdsServer, DefaultEndpointInfoRegistry)
 
 	return &Proxy{
-		XDSServer:      xdsServer,
-		stateDir:       stateDir,
-		rangeMin:       minPort,
-		rangeMax:       maxPort,
-		redirects:      make(map[string]*Redirect),
-		allocatedPorts: make(map[uint16]struct{}),
+		XDSServer:         xdsServer,
+		stateDir:          stateDir,
+		rangeMin:          minPort,
+		rangeMax:          maxPort,
+		redirects:         make(map[string]*Redirect),
+		drainingRedirects: make(map[uint16]*Redirect),
+		allocatedPorts:    make(map[uint16]struct{}),
+		flushConntrack:    flushConntrack,
 	}
 }
 
@@ -227,7 +242,11 @@ retryCreatePort:
 
 		switch l4.L7Parser {
 		case policy.ParserTypeKafka:
-			redir.implementation, err = createKafkaRedirect(redir, kafkaConfiguration{}, DefaultEndpointInfoRegistry)
+			if option.Config.KafkaProxy == option.KafkaProxyEnvoy {
+				redir.implementation, err = createEnvoyRedirect(redir, p.stateDir, p.XDSServer, wg)
+			} else {
+				redir.implementation, err = createKafkaRedirect(redir, kafkaConfiguration{}, DefaultEndpointInfoRegistry)
+			}
 
 		case policy.ParserTypeHTTP:
 			redir.implementation, err = createEnvoyRedirect(redir, p.stateDir, p.XDSServer, wg)
@@ -295,31 +314,37 @@ func (p *Proxy) removeRedirect(id string, wg *completion.WaitGroup) (err error,
 	}
 	delete(p.redirects, id)
 
+	// The datapath keeps redirecting the connections established through
+	// the redirect to its proxy port, so keep the redirect open until the
+	// drain period ends. Starting the drain can't be reverted, so do it in
+	// a FinalizeFunc.
+	if drainPeriod := option.Config.ProxyRedirectDrainPeriod; drainPeriod > 0 {
+		finalizeFunc = func() {
+			p.drainRedirect(r, drainPeriod)
+		}
+
+		revertFunc = func() error {
+			p.mutex.Lock()
+			p.redirects[id] = r
+			p.mutex.Unlock()
+
+			return nil
+		}
+
+		return
+	}
+
 	implFinalizeFunc, implRevertFunc := r.implementation.Close(wg)
 
 	// Delay the release and reuse of the port number so it is guaranteed to be
 	// safe to listen on the port again. This can't be reverted, so do it in a
 	// FinalizeFunc.
-	proxyPort := r.ProxyPort
 	finalizeFunc = func() {
 		if implFinalizeFunc != nil {
 			implFinalizeFunc()
 		}
 
-		go func() {
-			time.Sleep(portReuseDelay)
-
-			// The cleanup of the proxymap is delayed a bit to ensure that
-			// the datapath has implemented the redirect change and we
-			// cleanup the map before we release the port and allow reuse
-			proxymap.CleanupOnRedirectClose(proxyPort)
-
-			p.mutex.Lock()
-			delete(p.allocatedPorts, proxyPort)
-			p.mutex.Unlock()
-
-			log.WithField(fieldProxyRedirectID, id).Debugf("Delayed release of proxy port %d", proxyPort)
-		}()
+		p.releasePort(id, r.ProxyPort)
 	}
 
 	revertFunc = func() error {
@@ -337,6 +362,74 @@ func (p *Proxy) removeRedirect(id string, wg *completion.WaitGroup) (err error,
 	return
 }
 
+// drainRedirect keeps the removed redirect r open for drainPeriod and closes
+// it afterwards.
+func (p *Proxy) drainRedirect(r *Redirect, drainPeriod time.Duration) {
+	r.mutex.Lock()
+	r.drainDeadline = time.Now().Add(drainPeriod)
+	r.mutex.Unlock()
+
+	p.mutex.Lock()
+	p.drainingRedirects[r.ProxyPort] = r
+	p.mutex.Unlock()
+
+	log.WithField(fieldProxyRedirectID, r.id).
+		Debugf("Draining proxy redirect on port %d for %s", r.ProxyPort, drainPeriod)
+
+	time.AfterFunc(drainPeriod, func() {
+		p.closeDrainedRedirect(r)
+	})
+}
+
+// closeDrainedRedirect closes the redirect r at the end of its drain period.
+func (p *Proxy) closeDrainedRedirect(r *Redirect) {
+	p.mutex.Lock()
+	delete(p.drainingRedirects, r.ProxyPort)
+	p.mutex.Unlock()
+
+	completionCtx, cancel := context.WithCancel(context.Background())
+	proxyWaitGroup := completion.NewWaitGroup(completionCtx)
+	implFinalizeFunc, _ := r.implementation.Close(proxyWaitGroup)
+	// Don't wait for an ACK. This is best-effort. Just clean up the completions.
+	cancel()
+	proxyWaitGroup.Wait() // Ignore the returned error.
+	if implFinalizeFunc != nil {
+		implFinalizeFunc()
+	}
+
+	log.WithField(fieldProxyRedirectID, r.id).
+		Debugf("Closed drained proxy redirect on port %d", r.ProxyPort)
+
+	p.releasePort(r.id, r.ProxyPort)
+}
+
+// releasePort releases the proxy port of the closed redirect with the given
+// id. The release and reuse of the port number is delayed so it is
+// guaranteed to be safe to listen on the port again.
+func (p *Proxy) releasePort(id string, proxyPort uint16) {
+	go func() {
+		time.Sleep(portReuseDelay)
+
+		// The cleanup of the proxymap is delayed a bit to ensure that
+		// the datapath has implemented the redirect change and we
+		// cleanup the map before we release the port and allow reuse
+		proxymap.CleanupOnRedirectClose(proxyPort)
+
+		// If redirects are drained, the CT entries of the connections
+		// redirected to the port record it. Remove them so that they
+		// are not redirected to the next user of the port.
+		if option.Config.ProxyRedirectDrainPeriod > 0 && p.flushConntrack != nil {
+			p.flushConntrack(proxyPort)
+		}
+
+		p.mutex.Lock()
+		delete(p.allocatedPorts, proxyPort)
+		p.mutex.Unlock()
+
+		log.WithField(fieldProxyRedirectID, id).Debugf("Delayed release of proxy port %d", proxyPort)
+	}()
+}
+
 // ChangeLogLevel changes proxy log level to correspond to the logrus log level 'level'.
 func ChangeLogLevel(level logrus.Level) {
 	if envoyProxy != nil {
@@ -349,10 +442,23 @@ func (p *Proxy) GetStatusModel() *models.ProxyStatus {
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
 
-	return &models.ProxyStatus{
+	status := &models.ProxyStatus{
 		IP:        node.GetInternalIPv4().String(),
 		PortRange: fmt.Sprintf("%d-%d", p.rangeMin, p.rangeMax),
 	}
+	for _, r := range p.redirects {
+		status.Redirects = append(status.Redirects, r.getStatusModel())
+	}
+	for _, r := range p.drainingRedirects {
+		status.Redirects = append(status.Redirects, r.getStatusModel())
+	}
+	sort.Slice(status.Redirects, func(i, j int) bool {
+		return status.Redirects[i].ProxyPort < status.Redirects[j].ProxyPort
+	})
+	status.TotalRedirects = int64(len(status.Redirects))
+	status.TotalDrainingRedirects = int64(len(p.drainingRedirects))
+
+	return status
 }
 
 // UpdateRedirectMetrics updates the redirect metrics per application protocol
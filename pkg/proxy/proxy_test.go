@@ -0,0 +1,140 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package proxy
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/completion"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/revert"
+	"github.com/cilium/cilium/pkg/testutils"
+
+	. "gopkg.in/check.v1"
+)
+
+// redirectImplementationMock records when the redirect is closed
+type redirectImplementationMock struct {
+	lock.Mutex
+	closed bool
+}
+
+func (m *redirectImplementationMock) Close(wg *completion.WaitGroup) (revert.FinalizeFunc, revert.RevertFunc) {
+	return func() {
+		m.Lock()
+		m.closed = true
+		m.Unlock()
+	}, nil
+}
+
+func (m *redirectImplementationMock) isClosed() bool {
+	m.Lock()
+	defer m.Unlock()
+	return m.closed
+}
+
+func newTestProxy() (*Proxy, *redirectImplementationMock) {
+	impl := &redirectImplementationMock{}
+	redir := newRedirect(localEndpointMock, "1000:ingress:TCP:80")
+	redir.ProxyPort = 10080
+	redir.parserType = policy.ParserTypeHTTP
+	redir.implementation = impl
+
+	p := &Proxy{
+		rangeMin:          10000,
+		rangeMax:          20000,
+		redirects:         map[string]*Redirect{redir.id: redir},
+		drainingRedirects: make(map[uint16]*Redirect),
+		allocatedPorts:    map[uint16]struct{}{redir.ProxyPort: {}},
+	}
+	return p, impl
+}
+
+func (s *proxyTestSuite) TestRemoveRedirect(c *C) {
+	oldDrainPeriod := option.Config.ProxyRedirectDrainPeriod
+	option.Config.ProxyRedirectDrainPeriod = 0
+	defer func() { option.Config.ProxyRedirectDrainPeriod = oldDrainPeriod }()
+
+	p, impl := newTestProxy()
+
+	status := p.GetStatusModel()
+	c.Assert(status.TotalRedirects, Equals, int64(1))
+	c.Assert(status.Redirects, DeepEquals, []*models.ProxyRedirect{{
+		Name:      "1000:ingress:TCP:80",
+		Proxy:     "http",
+		ProxyPort: 10080,
+		State:     models.ProxyRedirectStateActive,
+	}})
+
+	err, finalizeFunc, _ := p.RemoveRedirect("1000:ingress:TCP:80", nil)
+	c.Assert(err, IsNil)
+	finalizeFunc()
+	c.Assert(impl.isClosed(), Equals, true)
+
+	status = p.GetStatusModel()
+	c.Assert(status.TotalRedirects, Equals, int64(0))
+	c.Assert(status.Redirects, HasLen, 0)
+}
+
+func (s *proxyTestSuite) TestRemoveRedirectDraining(c *C) {
+	oldDrainPeriod := option.Config.ProxyRedirectDrainPeriod
+	option.Config.ProxyRedirectDrainPeriod = 200 * time.Millisecond
+	defer func() { option.Config.ProxyRedirectDrainPeriod = oldDrainPeriod }()
+
+	p, impl := newTestProxy()
+
+	err, finalizeFunc, _ := p.RemoveRedirect("1000:ingress:TCP:80", nil)
+	c.Assert(err, IsNil)
+	finalizeFunc()
+	c.Assert(impl.isClosed(), Equals, false)
+
+	status := p.GetStatusModel()
+	c.Assert(status.TotalRedirects, Equals, int64(1))
+	c.Assert(status.TotalDrainingRedirects, Equals, int64(1))
+	c.Assert(status.Redirects, HasLen, 1)
+	c.Assert(status.Redirects[0].State, Equals, models.ProxyRedirectStateDraining)
+	c.Assert(time.Time(status.Redirects[0].DrainDeadline).After(time.Now()), Equals, true)
+
+	err = testutils.WaitUntil(impl.isClosed, 5*time.Second)
+	c.Assert(err, IsNil)
+
+	status = p.GetStatusModel()
+	c.Assert(status.TotalRedirects, Equals, int64(0))
+	c.Assert(status.TotalDrainingRedirects, Equals, int64(0))
+}
+
+func (s *proxyTestSuite) TestRemoveRedirectDrainingRevert(c *C) {
+	oldDrainPeriod := option.Config.ProxyRedirectDrainPeriod
+	option.Config.ProxyRedirectDrainPeriod = time.Minute
+	defer func() { option.Config.ProxyRedirectDrainPeriod = oldDrainPeriod }()
+
+	p, impl := newTestProxy()
+
+	err, _, revertFunc := p.RemoveRedirect("1000:ingress:TCP:80", nil)
+	c.Assert(err, IsNil)
+	c.Assert(p.GetStatusModel().TotalRedirects, Equals, int64(0))
+
+	c.Assert(revertFunc(), IsNil)
+	c.Assert(impl.isClosed(), Equals, false)
+
+	status := p.GetStatusModel()
+	c.Assert(status.TotalRedirects, Equals, int64(1))
+	c.Assert(status.Redirects[0].State, Equals, models.ProxyRedirectStateActive)
+}
@@ -20,11 +20,14 @@ import (
 	"net"
 	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/completion"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/maps/proxymap"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/proxy/logger"
+
+	"github.com/go-openapi/strfmt"
 )
 
 // RedirectImplementation is the generic proxy redirect interface that each
@@ -53,6 +56,10 @@ type Redirect struct {
 	mutex       lock.RWMutex
 	lastUpdated time.Time
 	rules       policy.L7DataMap
+
+	// drainDeadline is the time at which the redirect is closed after it
+	// has been removed, or zero if the redirect is active
+	drainDeadline time.Time
 }
 
 func newRedirect(localEndpoint logger.EndpointUpdater, id string) *Redirect {
@@ -89,3 +96,21 @@ func (r *Redirect) removeProxyMapEntryOnClose(c net.Conn) error {
 
 	return proxymap.Delete(key)
 }
+
+// getStatusModel returns the status of the redirect as API model
+func (r *Redirect) getStatusModel() *models.ProxyRedirect {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	status := &models.ProxyRedirect{
+		Name:      r.id,
+		Proxy:     string(r.parserType),
+		ProxyPort: int64(r.ProxyPort),
+		State:     models.ProxyRedirectStateActive,
+	}
+	if !r.drainDeadline.IsZero() {
+		status.State = models.ProxyRedirectStateDraining
+		status.DrainDeadline = strfmt.DateTime(r.drainDeadline)
+	}
+	return status
+}
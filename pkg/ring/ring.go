@@ -0,0 +1,101 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package ring
+
+import (
+	"github.com/cilium/cilium/pkg/lock"
+)
+
+// Ring is a buffer of a fixed number of entries. Once the buffer is full, each
+// added entry replaces the oldest entry in the buffer. It is safe for
+// concurrent use.
+type Ring struct {
+	mutex   lock.RWMutex
+	entries []interface{}
+
+	// next is the index at which the next entry is stored
+	next int
+
+	// full is true once the buffer has wrapped around
+	full bool
+}
+
+// NewRing returns a new ring buffer for size entries
+func NewRing(size int) *Ring {
+	return &Ring{entries: make([]interface{}, size)}
+}
+
+// Add adds an entry to the buffer. The entry must not be modified afterwards.
+func (r *Ring) Add(e interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.entries) == 0 {
+		return
+	}
+
+	r.entries[r.next] = e
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// Len returns the number of entries in the buffer
+func (r *Ring) Len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.len()
+}
+
+func (r *Ring) len() int {
+	if r.full {
+		return len(r.entries)
+	}
+	return r.next
+}
+
+// Cap returns the maximum number of entries in the buffer
+func (r *Ring) Cap() int {
+	return len(r.entries)
+}
+
+// Walk calls fn for the entries in the buffer from the most recent to the
+// oldest entry until fn returns false. The buffer must not be modified by fn.
+func (r *Ring) Walk(fn func(e interface{}) bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for i := 1; i <= r.len(); i++ {
+		if !fn(r.entries[(r.next-i+len(r.entries))%len(r.entries)]) {
+			return
+		}
+	}
+}
+
+// Entries returns all entries in the buffer, oldest first
+func (r *Ring) Entries() []interface{} {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]interface{}, 0, r.len())
+	if r.full {
+		result = append(result, r.entries[r.next:]...)
+	}
+	return append(result, r.entries[:r.next]...)
+}
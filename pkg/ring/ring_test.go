@@ -0,0 +1,75 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+
+package ring
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type RingSuite struct{}
+
+var _ = Suite(&RingSuite{})
+
+func walk(r *Ring, limit int) []interface{} {
+	result := []interface{}{}
+	r.Walk(func(e interface{}) bool {
+		result = append(result, e)
+		return len(result) < limit
+	})
+	return result
+}
+
+func (s *RingSuite) TestRing(c *C) {
+	r := NewRing(3)
+	c.Assert(r.Len(), Equals, 0)
+	c.Assert(r.Cap(), Equals, 3)
+	c.Assert(r.Entries(), HasLen, 0)
+	c.Assert(walk(r, 10), HasLen, 0)
+
+	r.Add(1)
+	r.Add(2)
+	c.Assert(r.Len(), Equals, 2)
+	c.Assert(r.Entries(), DeepEquals, []interface{}{1, 2})
+	c.Assert(walk(r, 10), DeepEquals, []interface{}{2, 1})
+
+	// Oldest entries are replaced once the buffer is full
+	r.Add(3)
+	r.Add(4)
+	r.Add(5)
+	c.Assert(r.Len(), Equals, 3)
+	c.Assert(r.Entries(), DeepEquals, []interface{}{3, 4, 5})
+	c.Assert(walk(r, 10), DeepEquals, []interface{}{5, 4, 3})
+
+	// Walk stops once fn returns false
+	c.Assert(walk(r, 2), DeepEquals, []interface{}{5, 4})
+}
+
+func (s *RingSuite) TestRingEmpty(c *C) {
+	r := NewRing(0)
+	r.Add(1)
+	c.Assert(r.Len(), Equals, 0)
+	c.Assert(r.Entries(), HasLen, 0)
+	c.Assert(walk(r, 10), HasLen, 0)
+}
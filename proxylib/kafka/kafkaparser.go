@@ -0,0 +1,288 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/cilium/cilium/pkg/envoy/cilium"
+	"github.com/cilium/cilium/pkg/kafka"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/proxylib/proxylib"
+
+	"github.com/optiopay/kafka/proto"
+	log "github.com/sirupsen/logrus"
+)
+
+//
+// Kafka Parser
+//
+// Kafka requests are decoded and matched against the Kafka rules of the
+// policy with the same request decoding and policy matching as the Kafka
+// proxy of the agent. A request is allowed if each of its topics is allowed
+// by one of the rules. Denied requests are answered with a topic
+// authorization error, or the connection is closed if no error response can
+// be created for the version of the request. Responses are passed without
+// inspection.
+//
+// Policy Examples:
+// {api_key: 0, api_version: -1, topic: "orders"} - Allow producing to the
+//                                                  'orders' topic.
+// {api_key: -1, api_version: -1, principal: "alice"} - Allow all requests of
+//                                                      clients authenticated
+//                                                      as SASL user 'alice'.
+//
+
+const (
+	// sizeLen is the length of the size field preceding each message
+	sizeLen = 4
+	// responseHeaderLen is the length of the size field and the
+	// correlation ID at the beginning of each response
+	responseHeaderLen = sizeLen + 4
+
+	// maxRequestLen limits the size of the requests we are willing to
+	// buffer, matching the limit of the request decoder
+	maxRequestLen = 100 * math.MaxUint16
+
+	// maxPendingRequests limits the number of forwarded requests kept for
+	// logging their responses. Produce requests without acknowledgements
+	// are never responded to.
+	maxPendingRequests = 1024
+)
+
+type kafkaRule struct {
+	rule api.PortRuleKafka
+}
+
+// kafkaRequestData is a request with one of its topics, or an empty topic
+// for requests without topics
+type kafkaRequestData struct {
+	req   *kafka.RequestMessage
+	topic string
+}
+
+func (rule *kafkaRule) Matches(data interface{}) bool {
+	// Cast 'data' to the type we give to 'Matches()'
+	reqData, ok := data.(kafkaRequestData)
+	if !ok {
+		log.Warning("Matches() called with type other than kafkaRequestData")
+		return false
+	}
+	return reqData.req.MatchesTopicRule(reqData.topic, rule.rule)
+}
+
+// ruleParser parses protobuf L7 rules to enforcement objects
+// May panic
+func ruleParser(rule *cilium.PortNetworkPolicyRule) []proxylib.L7NetworkPolicyRule {
+	kafkaRules := rule.GetKafkaRules()
+	var rules []proxylib.L7NetworkPolicyRule
+	if kafkaRules == nil {
+		return rules
+	}
+	for _, kr := range kafkaRules.GetKafkaRules() {
+		var r kafkaRule
+		if kr.ApiKey >= 0 {
+			name, ok := api.KafkaReverseAPIKeyMap[int16(kr.ApiKey)]
+			if !ok || kr.ApiKey > math.MaxInt16 {
+				proxylib.ParseError(fmt.Sprintf("Unsupported Kafka API key: %d", kr.ApiKey), rule)
+			}
+			r.rule.APIKey = name
+		}
+		if kr.ApiVersion >= 0 {
+			r.rule.APIVersion = strconv.Itoa(int(kr.ApiVersion))
+		}
+		r.rule.Topic = kr.Topic
+		r.rule.ClientID = kr.ClientId
+		r.rule.Principal = kr.Principal
+		if err := r.rule.Sanitize(); err != nil {
+			proxylib.ParseError(fmt.Sprintf("Unable to parse Kafka rule: %s", err), rule)
+		}
+		log.Debugf("Parsed rule '%v'", r.rule)
+		rules = append(rules, &r)
+	}
+	return rules
+}
+
+type factory struct{}
+
+func init() {
+	log.Info("init(): Registering kafkaParserFactory")
+	proxylib.RegisterParserFactory("kafka", &factory{})
+	proxylib.RegisterL7RuleParser("PortNetworkPolicyRule_KafkaRules", ruleParser)
+}
+
+// pendingRequest is a forwarded request waiting for its response
+type pendingRequest struct {
+	correlationID kafka.CorrelationID
+	apiKey        int16
+	apiVersion    int16
+	topics        []string
+}
+
+type parser struct {
+	connection *proxylib.Connection
+
+	// session tracks the SASL principal of the client
+	session kafka.Session
+
+	// pending are the forwarded requests in the order they were sent.
+	// The broker responds to requests in the same order.
+	pending []pendingRequest
+}
+
+func (f *factory) Create(connection *proxylib.Connection) proxylib.Parser {
+	log.Debugf("KafkaParserFactory: Create: %v", connection)
+
+	return &parser{connection: connection}
+}
+
+// peek returns the first 'n' bytes of 'dataArray', or the number of
+// additional bytes needed
+func peek(dataArray [][]byte, n int) ([]byte, int) {
+	var data []byte
+	for _, d := range dataArray {
+		if len(data) == 0 && len(d) >= n {
+			return d[:n], 0
+		}
+		data = append(data, d...)
+		if len(data) >= n {
+			return data[:n], 0
+		}
+	}
+	return nil, n - len(data)
+}
+
+func (p *parser) OnData(reply, endStream bool, dataArray [][]byte) (proxylib.OpType, int) {
+	if reply {
+		return p.onResponse(dataArray)
+	}
+	return p.onRequest(dataArray)
+}
+
+func (p *parser) onRequest(dataArray [][]byte) (proxylib.OpType, int) {
+	header, more := peek(dataArray, sizeLen)
+	if more > 0 {
+		return proxylib.MORE, more
+	}
+	size := int32(binary.BigEndian.Uint32(header))
+	if size <= 0 || size > maxRequestLen {
+		log.Debugf("Invalid Kafka request size %d", size)
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_LENGTH)
+	}
+	frameLen := sizeLen + int(size)
+	frame, more := peek(dataArray, frameLen)
+	if more > 0 {
+		return proxylib.MORE, more
+	}
+
+	req, err := kafka.ReadRequest(bytes.NewReader(frame))
+	if err != nil {
+		log.WithError(err).Debug("Unable to parse Kafka request")
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+	}
+	req.SetPrincipal(p.session.Principal())
+
+	if !p.matches(req) {
+		p.log(cilium.EntryType_Denied, req.GetAPIKey(), req.GetVersion(), req.GetCorrelationID(),
+			req.GetTopics(), kafka.ErrTopicAuthorizationFailed)
+
+		resp, err := req.CreateResponse(proto.ErrTopicAuthorizationFailed)
+		if err != nil {
+			// The client would wait for the response until it times
+			// out, so close the connection to fail the request now
+			log.WithError(err).Debug("Unable to create Kafka response, closing connection")
+			return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+		}
+		raw := resp.GetRaw()
+		if p.connection.Inject(true, raw) < len(raw) {
+			log.Debug("Unable to inject Kafka response, closing connection")
+			return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_LENGTH)
+		}
+		return proxylib.DROP, frameLen
+	}
+
+	p.log(cilium.EntryType_Request, req.GetAPIKey(), req.GetVersion(), req.GetCorrelationID(),
+		req.GetTopics(), kafka.ErrNone)
+	p.session.HandleRequest(req)
+
+	if len(p.pending) >= maxPendingRequests {
+		p.pending = p.pending[1:]
+	}
+	p.pending = append(p.pending, pendingRequest{
+		correlationID: req.GetCorrelationID(),
+		apiKey:        req.GetAPIKey(),
+		apiVersion:    req.GetVersion(),
+		topics:        req.GetTopics(),
+	})
+	return proxylib.PASS, frameLen
+}
+
+// matches returns true if the policy allows the request for each of its
+// topics
+func (p *parser) matches(req *kafka.RequestMessage) bool {
+	topics := req.GetTopics()
+	if len(topics) == 0 {
+		return p.connection.Matches(kafkaRequestData{req: req})
+	}
+	for _, topic := range topics {
+		if !p.connection.Matches(kafkaRequestData{req: req, topic: topic}) {
+			log.Debugf("Policy mismatch for topic %s", topic)
+			return false
+		}
+	}
+	return true
+}
+
+func (p *parser) onResponse(dataArray [][]byte) (proxylib.OpType, int) {
+	header, more := peek(dataArray, responseHeaderLen)
+	if more > 0 {
+		return proxylib.MORE, more
+	}
+	size := int32(binary.BigEndian.Uint32(header))
+	if size < responseHeaderLen-sizeLen {
+		log.Debugf("Invalid Kafka response size %d", size)
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_LENGTH)
+	}
+	correlationID := kafka.CorrelationID(binary.BigEndian.Uint32(header[sizeLen:]))
+
+	// Skip the requests which are never responded to
+	for i, req := range p.pending {
+		if req.correlationID == correlationID {
+			p.log(cilium.EntryType_Response, req.apiKey, req.apiVersion, correlationID, req.topics, kafka.ErrNone)
+			p.pending = p.pending[i+1:]
+			break
+		}
+	}
+
+	// The response is passed before all of it may have been received
+	return proxylib.PASS, sizeLen + int(size)
+}
+
+func (p *parser) log(entryType cilium.EntryType, apiKey, apiVersion int16, correlationID kafka.CorrelationID, topics []string, errorCode int) {
+	p.connection.Log(entryType,
+		&cilium.LogEntry_Kafka{
+			Kafka: &cilium.KafkaLogEntry{
+				ErrorCode:     int32(errorCode),
+				ApiVersion:    int32(apiVersion),
+				ApiKey:        int32(apiKey),
+				CorrelationId: int32(correlationID),
+				Topics:        topics,
+			},
+		})
+}
@@ -0,0 +1,338 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/cilium/cilium/pkg/envoy/cilium"
+	"github.com/cilium/cilium/pkg/kafka"
+	"github.com/cilium/cilium/proxylib/accesslog"
+	"github.com/cilium/cilium/proxylib/proxylib"
+	"github.com/cilium/cilium/proxylib/test"
+
+	"github.com/optiopay/kafka/proto"
+	// log "github.com/sirupsen/logrus"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	// logging.ToggleDebugLogs(true)
+	// log.SetLevel(log.DebugLevel)
+
+	TestingT(t)
+}
+
+type KafkaSuite struct {
+	logServer *test.AccessLogServer
+	ins       *proxylib.Instance
+}
+
+var _ = Suite(&KafkaSuite{})
+
+// Set up access log server and Library instance for all the test cases
+func (s *KafkaSuite) SetUpSuite(c *C) {
+	s.logServer = test.StartAccessLogServer("access_log.sock", 10)
+	c.Assert(s.logServer, Not(IsNil))
+	s.ins = proxylib.NewInstance("node1", accesslog.NewClient(s.logServer.Path))
+	c.Assert(s.ins, Not(IsNil))
+
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "kp"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 9092
+		  rules: <
+		    kafka_rules: <
+		      kafka_rules: <
+		        api_key: 0
+		        api_version: -1
+		        topic: "allowed"
+		      >
+		      kafka_rules: <
+		        api_key: 0
+		        api_version: -1
+		        topic: "also-allowed"
+		      >
+		      kafka_rules: <
+		        api_key: 3
+		        api_version: -1
+		      >
+		      kafka_rules: <
+		        api_key: 35
+		        api_version: -1
+		        topic: "allowed"
+		      >
+		    >
+		  >
+		>
+		`, `
+		name: "kp-sasl"
+		policy: 3
+		ingress_per_port_policies: <
+		  port: 9092
+		  rules: <
+		    kafka_rules: <
+		      kafka_rules: <
+		        api_key: -1
+		        api_version: -1
+		        principal: "alice"
+		      >
+		    >
+		  >
+		>
+		`})
+}
+
+func (s *KafkaSuite) checkAccessLogs(c *C, expPasses, expDrops int) {
+	passes, drops := s.logServer.Clear()
+	c.Check(passes, Equals, expPasses, Commentf("Unxpected number of passed access log messages"))
+	c.Check(drops, Equals, expDrops, Commentf("Unxpected number of passed access log messages"))
+}
+
+func (s *KafkaSuite) TearDownTest(c *C) {
+	s.logServer.Clear()
+}
+
+func (s *KafkaSuite) TearDownSuite(c *C) {
+	s.logServer.Close()
+}
+
+func produceRequest(c *C, topics ...string) []byte {
+	req := &proto.ProduceReq{
+		CorrelationID: 1,
+		ClientID:      "client",
+		RequiredAcks:  proto.RequiredAcksAll,
+	}
+	for _, topic := range topics {
+		req.Topics = append(req.Topics, proto.ProduceReqTopic{
+			Name: topic,
+			Partitions: []proto.ProduceReqPartition{
+				{ID: 0, Messages: []*proto.Message{{Value: []byte("value")}}},
+			},
+		})
+	}
+	raw, err := req.Bytes(proto.KafkaV0)
+	c.Assert(err, IsNil)
+	return raw
+}
+
+func metadataRequest(c *C, topics ...string) []byte {
+	req := &proto.MetadataReq{CorrelationID: 2, ClientID: "client", Topics: topics}
+	raw, err := req.Bytes(proto.KafkaV0)
+	c.Assert(err, IsNil)
+	return raw
+}
+
+// rawRequest returns a request with a non-flexible header and 'body'
+func rawRequest(kind, version int16, body []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, int32(10+len(body)))
+	binary.Write(&buf, binary.BigEndian, kind)
+	binary.Write(&buf, binary.BigEndian, version)
+	binary.Write(&buf, binary.BigEndian, int32(3)) // correlation ID
+	binary.Write(&buf, binary.BigEndian, int16(0)) // empty client ID
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// deniedResponse returns the response to the denied request 'raw'
+func deniedResponse(c *C, raw []byte) []byte {
+	req, err := kafka.ReadRequest(bytes.NewReader(raw))
+	c.Assert(err, IsNil)
+	resp, err := req.CreateResponse(proto.ErrTopicAuthorizationFailed)
+	c.Assert(err, IsNil)
+	return resp.GetRaw()
+}
+
+func (s *KafkaSuite) TestKafkaOnDataIncomplete(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "kafka", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:9092", "kp")
+	req := produceRequest(c, "allowed")
+
+	data := [][]byte{req[:2]}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{}, proxylib.MORE, 2)
+	data = [][]byte{req[:3], req[3:10]}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{}, proxylib.MORE, len(req)-10)
+	s.checkAccessLogs(c, 0, 0)
+}
+
+func (s *KafkaSuite) TestKafkaOnDataAllow(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "kafka", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:9092", "kp")
+	produce := produceRequest(c, "allowed", "also-allowed")
+	metadata := metadataRequest(c, "other")
+
+	data := [][]byte{produce[:20], produce[20:], metadata}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(produce),
+		proxylib.PASS, len(metadata),
+		proxylib.MORE, 4)
+	s.checkAccessLogs(c, 2, 0)
+}
+
+func (s *KafkaSuite) TestKafkaOnDataDeny(c *C) {
+	for _, topics := range [][]string{{"denied"}, {"allowed", "denied"}} {
+		conn := s.ins.CheckNewConnectionOK(c, "kafka", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:9092", "kp")
+		produce := produceRequest(c, topics...)
+
+		data := [][]byte{produce}
+		conn.CheckOnDataOK(c, false, false, &data, deniedResponse(c, produce),
+			proxylib.DROP, len(produce),
+			proxylib.MORE, 4)
+		s.checkAccessLogs(c, 0, 1)
+	}
+}
+
+func (s *KafkaSuite) TestKafkaOnDataDenyWithoutResponse(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "kafka", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:9092", "kp")
+
+	// DeleteTopics request for topic "denied"
+	deleteTopics := rawRequest(20, 0, []byte{0, 0, 0, 1, 0, 6, 'd', 'e', 'n', 'i', 'e', 'd', 0, 0, 0, 0})
+	data := [][]byte{deleteTopics}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE))
+	s.checkAccessLogs(c, 0, 1)
+}
+
+func (s *KafkaSuite) TestKafkaOnDataDenyAllTopics(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "kafka", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:9092", "kp")
+
+	// DescribeLogDirs request for topic "allowed"
+	describeLogDirs := rawRequest(35, 0, []byte{0, 0, 0, 1, 0, 7, 'a', 'l', 'l', 'o', 'w', 'e', 'd', 0, 0, 0, 0})
+	data := [][]byte{describeLogDirs}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(describeLogDirs),
+		proxylib.MORE, 4)
+	s.checkAccessLogs(c, 1, 0)
+
+	// DescribeLogDirs request with a null topic array for all topics
+	describeLogDirs = rawRequest(35, 0, []byte{0xff, 0xff, 0xff, 0xff})
+	data = [][]byte{describeLogDirs}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE))
+	s.checkAccessLogs(c, 0, 1)
+}
+
+func (s *KafkaSuite) TestKafkaOnDataInvalid(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "kafka", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:9092", "kp")
+	data := [][]byte{[]byte("GET / HTTP/1.1\r\n\r\n")}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_LENGTH))
+	s.checkAccessLogs(c, 0, 0)
+}
+
+func (s *KafkaSuite) TestKafkaOnDataResponse(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "kafka", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:9092", "kp")
+	metadata := metadataRequest(c, "other")
+	data := [][]byte{metadata}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(metadata),
+		proxylib.MORE, 4)
+	s.checkAccessLogs(c, 1, 0)
+
+	resp, err := (&proto.MetadataResp{CorrelationID: 2}).Bytes(proto.KafkaV0)
+	c.Assert(err, IsNil)
+
+	// The response is passed as soon as its header has been received
+	data = [][]byte{resp[:6]}
+	conn.CheckOnDataOK(c, true, false, &data, []byte{}, proxylib.MORE, 2)
+	data = [][]byte{resp[:8]}
+	conn.CheckOnDataOK(c, true, false, &data, []byte{},
+		proxylib.PASS, len(resp),
+		proxylib.MORE, 8)
+	s.checkAccessLogs(c, 1, 0)
+}
+
+func (s *KafkaSuite) TestKafkaOnDataPrincipal(c *C) {
+	metadata := metadataRequest(c, "other")
+
+	// Unauthenticated clients are denied
+	conn := s.ins.CheckNewConnectionOK(c, "kafka", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:9092", "kp-sasl")
+	data := [][]byte{metadata}
+	conn.CheckOnDataOK(c, false, false, &data, deniedResponse(c, metadata),
+		proxylib.DROP, len(metadata),
+		proxylib.MORE, 4)
+	s.checkAccessLogs(c, 0, 1)
+
+	for _, user := range []string{"alice", "bob"} {
+		conn = s.ins.CheckNewConnectionOK(c, "kafka", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:9092", "kp-sasl")
+		handshake := rawRequest(17, 1, []byte{0, 5, 'P', 'L', 'A', 'I', 'N'})
+		authBytes := []byte("\x00" + user + "\x00secret")
+		authenticate := rawRequest(36, 1, append([]byte{0, 0, 0, byte(len(authBytes))}, authBytes...))
+		data = [][]byte{handshake, authenticate}
+		conn.CheckOnDataOK(c, false, false, &data, []byte{},
+			proxylib.PASS, len(handshake),
+			proxylib.PASS, len(authenticate),
+			proxylib.MORE, 4)
+		s.checkAccessLogs(c, 2, 0)
+
+		data = [][]byte{metadata}
+		if user == "alice" {
+			conn.CheckOnDataOK(c, false, false, &data, []byte{},
+				proxylib.PASS, len(metadata),
+				proxylib.MORE, 4)
+			s.checkAccessLogs(c, 1, 0)
+		} else {
+			conn.CheckOnDataOK(c, false, false, &data, deniedResponse(c, metadata),
+				proxylib.DROP, len(metadata),
+				proxylib.MORE, 4)
+			s.checkAccessLogs(c, 0, 1)
+		}
+	}
+}
+
+func (s *KafkaSuite) TestRuleParser(c *C) {
+	rules := ruleParser(&cilium.PortNetworkPolicyRule{
+		L7: &cilium.PortNetworkPolicyRule_KafkaRules{
+			KafkaRules: &cilium.KafkaNetworkPolicyRules{
+				KafkaRules: []*cilium.KafkaNetworkPolicyRule{
+					{ApiKey: 0, ApiVersion: 2, Topic: "foo", ClientId: "client", Principal: "alice"},
+					{ApiKey: -1, ApiVersion: -1},
+				},
+			},
+		},
+	})
+	c.Assert(len(rules), Equals, 2)
+	rule := rules[0].(*kafkaRule).rule
+	c.Assert(rule.APIKey, Equals, "produce")
+	c.Assert(rule.APIVersion, Equals, "2")
+	c.Assert(rule.Topic, Equals, "foo")
+	c.Assert(rule.ClientID, Equals, "client")
+	c.Assert(rule.Principal, Equals, "alice")
+	rule = rules[1].(*kafkaRule).rule
+	c.Assert(rule.APIKey, Equals, "")
+	c.Assert(rule.APIVersion, Equals, "")
+
+	for _, kr := range []*cilium.KafkaNetworkPolicyRule{
+		{ApiKey: 1000, ApiVersion: -1},
+		{ApiKey: 65536, ApiVersion: -1},
+		{ApiKey: -1, ApiVersion: 65536},
+		{ApiKey: -1, ApiVersion: -1, Topic: "foo bar"},
+	} {
+		c.Assert(func() {
+			ruleParser(&cilium.PortNetworkPolicyRule{
+				L7: &cilium.PortNetworkPolicyRule_KafkaRules{
+					KafkaRules: &cilium.KafkaNetworkPolicyRules{
+						KafkaRules: []*cilium.KafkaNetworkPolicyRule{kr},
+					},
+				},
+			})
+		}, PanicMatches, "NPDS: .*", Commentf("%v", kr))
+	}
+}
@@ -0,0 +1,588 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/envoy/cilium"
+	"github.com/cilium/cilium/proxylib/proxylib"
+
+	log "github.com/sirupsen/logrus"
+)
+
+//
+// MySQL Parser
+//
+// Spec: https://dev.mysql.com/doc/dev/mysql-server/latest/PAGE_PROTOCOL.html
+//
+
+// Current MySQL parser supports filtering on the user and database of the
+// connection, and on the query_action and query_table of COM_QUERY and
+// COM_STMT_PREPARE statements. query_action is the first keyword of the
+// statement in lower case, with DDL statements (create, alter, drop,
+// truncate, rename) collapsed to "ddl". query_table is a regex which must
+// match each of the tables accessed by the statement in full, qualified with
+// the database name. Statements that do not access tables, e.g. SET or CALL, do not match
+// rules with a query_table.
+//
+// Examples:
+// user = 'reporting', query_action = 'select', query_table = 'sales\..*'
+// database = 'sales', query_action = 'insert', query_table = 'sales.orders'
+// user = 'admin'
+//
+// The connection and changes of the default database are allowed if any
+// rule matches the user and database, regardless of query_action and
+// query_table. A query with multiple statements is allowed only if all
+// its statements are allowed.
+//
+// SSL and query attributes are removed from the server capabilities, as the
+// parser can not enforce policy on encrypted connections, nor parse queries
+// with attributes.
+
+const (
+	pktHdrLen     = 4
+	maxPayloadLen = 0xffffff
+	maxPacketLen  = 1 << 30
+
+	// Capability flags
+	clientConnectWithDB                   = 0x00000008
+	clientProtocol41                      = 0x00000200
+	clientSSL                             = 0x00000800
+	clientSecureConnection                = 0x00008000
+	clientPluginAuthLenencClientData      = 0x00200000
+	clientQueryAttributes                 = 0x08000000
+	serverStatusNoBackslashEscapes        = 0x0200
+	protocolVersion                  byte = 10
+
+	// Commands
+	comInitDB      = 0x02
+	comQuery       = 0x03
+	comFieldList   = 0x04
+	comChangeUser  = 0x11
+	comStmtPrepare = 0x16
+	comStmtExecute = 0x17
+	comStmtClose   = 0x19
+
+	// Errors
+	erDBAccessDenied       = 1044
+	erAccessDenied         = 1045
+	erTableAccessDenied    = 1142
+	erUnknownStmtHandler   = 1243
+	sqlStateAccessDenied   = "28000"
+	sqlStateSyntaxOrAccess = "42000"
+	sqlStateGeneral        = "HY000"
+	connectDeniedText      = "Access denied by policy"
+	databaseDeniedText     = "Access to database denied by policy"
+	queryDeniedText        = "Query denied by policy"
+	unknownStmtHandlerText = "Unknown prepared statement handler"
+)
+
+type mysqlRule struct {
+	userExact          string
+	databaseExact      string
+	queryActionExact   string
+	tableRegexCompiled *regexp.Regexp
+}
+
+type mysqlRequestData struct {
+	connect  bool
+	user     string
+	database string
+	action   string
+	tables   []string
+}
+
+func (rule *mysqlRule) Matches(data interface{}) bool {
+	// Cast 'data' to the type we give to 'Matches()'
+	reqData, ok := data.(mysqlRequestData)
+	if !ok {
+		log.Warning("Matches() called with type other than mysqlRequestData")
+		return false
+	}
+	if rule.userExact != "" && rule.userExact != reqData.user {
+		log.Debugf("MySQLRule: user mismatch %s, %s", rule.userExact, reqData.user)
+		return false
+	}
+	if rule.databaseExact != "" && rule.databaseExact != reqData.database {
+		log.Debugf("MySQLRule: database mismatch %s, %s", rule.databaseExact, reqData.database)
+		return false
+	}
+	if reqData.connect {
+		return true
+	}
+	if rule.queryActionExact != "" && rule.queryActionExact != reqData.action {
+		log.Debugf("MySQLRule: query_action mismatch %s, %s", rule.queryActionExact, reqData.action)
+		return false
+	}
+	if rule.tableRegexCompiled != nil {
+		if len(reqData.tables) == 0 {
+			log.Debugf("MySQLRule: query_table mismatch '%v', no tables", rule.tableRegexCompiled)
+			return false
+		}
+		for _, table := range reqData.tables {
+			if !rule.tableRegexCompiled.MatchString(table) {
+				log.Debugf("MySQLRule: query_table mismatch '%v', '%s'", rule.tableRegexCompiled, table)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ruleParser parses protobuf L7 rules to enforcement objects
+// May panic
+func ruleParser(rule *cilium.PortNetworkPolicyRule) []proxylib.L7NetworkPolicyRule {
+	var rules []proxylib.L7NetworkPolicyRule
+	l7Rules := rule.GetL7Rules()
+	if l7Rules == nil {
+		return rules
+	}
+	for _, l7Rule := range l7Rules.GetL7Rules() {
+		var mr mysqlRule
+		for k, v := range l7Rule.Rule {
+			switch k {
+			case "user":
+				mr.userExact = v
+			case "database":
+				mr.databaseExact = v
+			case "query_action":
+				for i := 0; i < len(v); i++ {
+					if !isWordChar(v[i]) {
+						proxylib.ParseError(fmt.Sprintf("Unable to parse L7 mysql rule with invalid query_action: '%s'", v), rule)
+					}
+				}
+				mr.queryActionExact = strings.ToLower(v)
+			case "query_table":
+				if v != "" {
+					mr.tableRegexCompiled = regexp.MustCompile("^(?:" + v + ")$")
+				}
+			default:
+				proxylib.ParseError(fmt.Sprintf("Unsupported key: %s", k), rule)
+			}
+		}
+		log.Debugf("Parsed MySQLRule: %v", mr)
+		rules = append(rules, &mr)
+	}
+	return rules
+}
+
+type factory struct{}
+
+func init() {
+	log.Info("init(): Registering mysqlParserFactory")
+	proxylib.RegisterParserFactory("mysql", &factory{})
+	proxylib.RegisterL7RuleParser("mysql", ruleParser)
+}
+
+type parser struct {
+	connection *proxylib.Connection
+
+	greeted       bool // initial handshake received from the server
+	authenticated bool // handshake response sent to the server
+	commandPhase  bool // authentication completed
+	rejected      bool // connection denied by policy
+
+	clientCaps       uint32
+	backslashEscapes bool
+
+	user     string
+	database string
+
+	// statements of the prepare request waiting for the reply from the
+	// server with the statement id
+	pendingPrepare []statement
+	// statements of the prepared statements by id, allowing us to enforce
+	// policy at the time of execution
+	preparedStatements map[uint32][]statement
+
+	// replyInjected is the number of bytes injected to the reply direction
+	// yet to be reported with an INJECT op
+	replyInjected int
+}
+
+func (f *factory) Create(connection *proxylib.Connection) proxylib.Parser {
+	log.Debugf("MySQLParserFactory: Create: %v", connection)
+
+	return &parser{
+		connection:         connection,
+		backslashEscapes:   true,
+		preparedStatements: make(map[uint32][]statement),
+	}
+}
+
+// readPacket returns the payload of the possibly split packet at the start
+// of 'data', with the sequence id of its last part and its length in
+// 'data', or the number of bytes missing.
+func readPacket(data []byte) (payload []byte, seq byte, frameLen, more int, errCode proxylib.OpError) {
+	offset := 0
+	for {
+		if len(data) < offset+pktHdrLen {
+			return nil, 0, 0, offset + pktHdrLen - len(data), 0
+		}
+		n := int(data[offset]) | int(data[offset+1])<<8 | int(data[offset+2])<<16
+		seq = data[offset+3]
+		end := offset + pktHdrLen + n
+		if end > maxPacketLen {
+			log.Errorf("Packet length of %d is greater than %d", end, maxPacketLen)
+			return nil, 0, 0, 0, proxylib.ERROR_INVALID_FRAME_LENGTH
+		}
+		if len(data) < end {
+			return nil, 0, 0, end - len(data), 0
+		}
+		payload = append(payload, data[offset+pktHdrLen:end]...)
+		offset = end
+		if n < maxPayloadLen {
+			return payload, seq, offset, 0, 0
+		}
+	}
+}
+
+func packet(seq byte, payload []byte) []byte {
+	pkt := make([]byte, pktHdrLen, pktHdrLen+len(payload))
+	pkt[0] = byte(len(payload))
+	pkt[1] = byte(len(payload) >> 8)
+	pkt[2] = byte(len(payload) >> 16)
+	pkt[3] = seq
+	return append(pkt, payload...)
+}
+
+func errPacket(seq byte, code uint16, sqlState, text string) []byte {
+	payload := []byte{0xff, byte(code), byte(code >> 8), '#'}
+	payload = append(payload, sqlState...)
+	payload = append(payload, text...)
+	return packet(seq, payload)
+}
+
+// cstring returns the null-terminated string at the start of 'data' and
+// advances 'data' past it.
+func cstring(data *[]byte) (string, bool) {
+	i := bytes.IndexByte(*data, 0)
+	if i < 0 {
+		return "", false
+	}
+	s := string((*data)[:i])
+	*data = (*data)[i+1:]
+	return s, true
+}
+
+// lenenc returns the length-encoded integer at the start of 'data' and
+// advances 'data' past it.
+func lenenc(data *[]byte) (uint64, bool) {
+	d := *data
+	if len(d) == 0 {
+		return 0, false
+	}
+	n := 1
+	switch d[0] {
+	case 0xfc:
+		n = 3
+	case 0xfd:
+		n = 4
+	case 0xfe:
+		n = 9
+	}
+	if len(d) < n {
+		return 0, false
+	}
+	var v uint64
+	if n == 1 {
+		v = uint64(d[0])
+	} else {
+		for i := n - 1; i > 0; i-- {
+			v = v<<8 | uint64(d[i])
+		}
+	}
+	*data = d[n:]
+	return v, true
+}
+
+func (p *parser) OnData(reply, endStream bool, dataArray [][]byte) (proxylib.OpType, int) {
+	if reply && p.replyInjected > 0 {
+		n := p.replyInjected
+		p.replyInjected = 0
+		return proxylib.INJECT, n
+	}
+
+	// inefficient, but simple for now
+	data := bytes.Join(dataArray, []byte{})
+
+	payload, seq, frameLen, more, errCode := readPacket(data)
+	if errCode != 0 {
+		return proxylib.ERROR, int(errCode)
+	}
+	if more > 0 {
+		log.Debugf("Did not receive full packet, need %d more bytes", more)
+		return proxylib.MORE, more
+	}
+
+	if reply {
+		return p.onReply(payload, seq, frameLen)
+	}
+	if p.rejected {
+		return proxylib.DROP, frameLen
+	}
+	if !p.authenticated {
+		return p.onHandshakeResponse(payload, seq, frameLen)
+	}
+	if !p.commandPhase || len(payload) == 0 {
+		// authentication exchange
+		return proxylib.PASS, frameLen
+	}
+	return p.onCommand(payload, seq, frameLen)
+}
+
+func (p *parser) onReply(payload []byte, seq byte, frameLen int) (proxylib.OpType, int) {
+	if len(payload) == 0 {
+		return proxylib.PASS, frameLen
+	}
+	if !p.greeted {
+		p.greeted = true
+		if payload[0] != protocolVersion {
+			return proxylib.PASS, frameLen
+		}
+		// Initial handshake: remove capabilities we can not support
+		handshake := append([]byte{}, payload...)
+		rest := handshake[1:]
+		if _, ok := cstring(&rest); !ok || len(rest) < 15 {
+			return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+		}
+		// connection id (4), auth-plugin-data-part-1 (8), filler (1)
+		caps := rest[13:]
+		capsLower := binary.LittleEndian.Uint16(caps[0:2]) &^ clientSSL
+		binary.LittleEndian.PutUint16(caps[0:2], capsLower)
+		if len(caps) >= 7 {
+			// character set (1), status flags (2), capability flags upper (2)
+			status := binary.LittleEndian.Uint16(caps[3:5])
+			p.backslashEscapes = status&serverStatusNoBackslashEscapes == 0
+			capsUpper := binary.LittleEndian.Uint16(caps[5:7]) &^ (clientQueryAttributes >> 16)
+			binary.LittleEndian.PutUint16(caps[5:7], capsUpper)
+		}
+		p.replyInjected = p.connection.Inject(true, packet(seq, handshake))
+		return proxylib.DROP, frameLen
+	}
+	if !p.commandPhase {
+		if p.authenticated && payload[0] == 0x00 {
+			log.Debugf("Authentication completed")
+			p.commandPhase = true
+		}
+		return proxylib.PASS, frameLen
+	}
+	if seq != 1 {
+		return proxylib.PASS, frameLen
+	}
+
+	// First packet of a command response
+	pendingPrepare := p.pendingPrepare
+	p.pendingPrepare = nil
+	if payload[0] != 0x00 {
+		return proxylib.PASS, frameLen
+	}
+	if pendingPrepare != nil {
+		if len(payload) >= 5 {
+			id := binary.LittleEndian.Uint32(payload[1:5])
+			log.Debugf("Prepared statement id %d", id)
+			p.preparedStatements[id] = pendingPrepare
+		}
+		return proxylib.PASS, frameLen
+	}
+	// OK packet: affected rows, last insert id, status flags
+	rest := payload[1:]
+	_, ok1 := lenenc(&rest)
+	_, ok2 := lenenc(&rest)
+	if ok1 && ok2 && len(rest) >= 2 {
+		status := binary.LittleEndian.Uint16(rest[0:2])
+		p.backslashEscapes = status&serverStatusNoBackslashEscapes == 0
+	}
+	return proxylib.PASS, frameLen
+}
+
+func (p *parser) onHandshakeResponse(payload []byte, seq byte, frameLen int) (proxylib.OpType, int) {
+	if len(payload) < 32 {
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+	}
+	caps := binary.LittleEndian.Uint32(payload[0:4])
+	if caps&clientProtocol41 == 0 {
+		log.Errorf("Unsupported pre-4.1 handshake response")
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+	}
+	if caps&clientSSL != 0 {
+		log.Errorf("SSL requested while not supported by the proxy")
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+	}
+	p.clientCaps = caps
+
+	// max packet size (4), character set (1), filler (23)
+	rest := payload[32:]
+	user, ok := cstring(&rest)
+	if !ok {
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+	}
+	database := ""
+	if !p.skipAuthResponse(&rest) {
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+	}
+	if caps&clientConnectWithDB != 0 {
+		database, _ = cstring(&rest)
+	}
+	p.authenticated = true
+	p.user = user
+	return p.onConnect("connect", database, seq, frameLen, erAccessDenied, sqlStateAccessDenied, connectDeniedText)
+}
+
+// skipAuthResponse advances 'data' past the authentication response.
+func (p *parser) skipAuthResponse(data *[]byte) bool {
+	switch {
+	case p.clientCaps&clientPluginAuthLenencClientData != 0:
+		n, ok := lenenc(data)
+		if !ok || uint64(len(*data)) < n {
+			return false
+		}
+		*data = (*data)[n:]
+	case p.clientCaps&clientSecureConnection != 0:
+		if len(*data) < 1 || len(*data) < 1+int((*data)[0]) {
+			return false
+		}
+		*data = (*data)[1+int((*data)[0]):]
+	default:
+		_, ok := cstring(data)
+		return ok
+	}
+	return true
+}
+
+// onConnect enforces policy on the user and a new default database.
+func (p *parser) onConnect(verb, database string, seq byte, frameLen int, code uint16, sqlState, text string) (proxylib.OpType, int) {
+	reqData := mysqlRequestData{connect: true, user: p.user, database: database}
+	if !p.connection.Matches(reqData) {
+		p.log(cilium.EntryType_Denied, database, statement{verb: verb})
+		p.connection.Inject(true, errPacket(seq+1, code, sqlState, text))
+		if verb != "init_db" {
+			p.rejected = true
+		}
+		return proxylib.DROP, frameLen
+	}
+	p.log(cilium.EntryType_Request, database, statement{verb: verb})
+	p.database = database
+	return proxylib.PASS, frameLen
+}
+
+func (p *parser) onCommand(payload []byte, seq byte, frameLen int) (proxylib.OpType, int) {
+	switch payload[0] {
+	case comInitDB:
+		return p.onConnect("init_db", string(payload[1:]), seq, frameLen, erDBAccessDenied, sqlStateSyntaxOrAccess, databaseDeniedText)
+	case comChangeUser:
+		rest := payload[1:]
+		user, ok := cstring(&rest)
+		if !ok || !p.skipAuthResponse(&rest) {
+			return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+		}
+		database, _ := cstring(&rest)
+		p.user = user
+		p.commandPhase = false
+		p.preparedStatements = make(map[uint32][]statement)
+		return p.onConnect("change_user", database, seq, frameLen, erAccessDenied, sqlStateAccessDenied, connectDeniedText)
+	case comQuery, comStmtPrepare:
+		stmts := parseStatements(payload[1:], p.backslashEscapes, p.database)
+		if !p.matches(stmts) {
+			p.connection.Inject(true, errPacket(seq+1, erTableAccessDenied, sqlStateSyntaxOrAccess, queryDeniedText))
+			return proxylib.DROP, frameLen
+		}
+		if payload[0] == comStmtPrepare {
+			p.pendingPrepare = stmts
+		} else if len(stmts) > 0 {
+			p.database = stmts[len(stmts)-1].database
+		}
+	case comStmtExecute:
+		if len(payload) < 5 {
+			return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+		}
+		id := binary.LittleEndian.Uint32(payload[1:5])
+		stmts, exists := p.preparedStatements[id]
+		if !exists {
+			log.Warnf("No cached entry for prepared statement id %d", id)
+			p.connection.Inject(true, errPacket(seq+1, erUnknownStmtHandler, sqlStateGeneral, unknownStmtHandlerText))
+			return proxylib.DROP, frameLen
+		}
+		if !p.matches(stmts) {
+			p.connection.Inject(true, errPacket(seq+1, erTableAccessDenied, sqlStateSyntaxOrAccess, queryDeniedText))
+			return proxylib.DROP, frameLen
+		}
+	case comStmtClose:
+		if len(payload) >= 5 {
+			delete(p.preparedStatements, binary.LittleEndian.Uint32(payload[1:5]))
+		}
+	case comFieldList:
+		rest := payload[1:]
+		table, _ := cstring(&rest)
+		a := analyzer{database: p.database}
+		a.addTable(strings.ToLower(table))
+		if !p.matches([]statement{{verb: "show", action: "show", tables: a.stmt.tables, database: p.database}}) {
+			p.connection.Inject(true, errPacket(seq+1, erTableAccessDenied, sqlStateSyntaxOrAccess, queryDeniedText))
+			return proxylib.DROP, frameLen
+		}
+	}
+	return proxylib.PASS, frameLen
+}
+
+// matches returns true if all the statements are allowed, logging each
+// statement.
+func (p *parser) matches(stmts []statement) bool {
+	matches := true
+	for _, stmt := range stmts {
+		reqData := mysqlRequestData{
+			user:     p.user,
+			database: stmt.database,
+			action:   stmt.action,
+			tables:   stmt.tables,
+		}
+		if stmt.verb == "use" {
+			// Changing the default database is enforced as at connect
+			reqData.connect = true
+		}
+		if !p.connection.Matches(reqData) {
+			matches = false
+		}
+	}
+
+	entryType := cilium.EntryType_Request
+	if !matches {
+		entryType = cilium.EntryType_Denied
+	}
+	for _, stmt := range stmts {
+		p.log(entryType, stmt.database, stmt)
+	}
+	return matches
+}
+
+func (p *parser) log(entryType cilium.EntryType, database string, stmt statement) {
+	p.connection.Log(entryType,
+		&cilium.LogEntry_GenericL7{
+			GenericL7: &cilium.L7LogEntry{
+				Proto: "mysql",
+				Fields: map[string]string{
+					"user":         p.user,
+					"database":     database,
+					"query_verb":   stmt.verb,
+					"query_action": stmt.action,
+					"query_table":  strings.Join(stmt.tables, ", "),
+				},
+			},
+		})
+}
@@ -0,0 +1,329 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package mysql
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/cilium/cilium/proxylib/accesslog"
+	"github.com/cilium/cilium/proxylib/proxylib"
+	"github.com/cilium/cilium/proxylib/test"
+
+	// log "github.com/sirupsen/logrus"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	// logging.ToggleDebugLogs(true)
+	// log.SetLevel(log.DebugLevel)
+
+	TestingT(t)
+}
+
+type MySQLSuite struct {
+	logServer *test.AccessLogServer
+	ins       *proxylib.Instance
+}
+
+var _ = Suite(&MySQLSuite{})
+
+// Set up access log server and Library instance for all the test cases
+func (s *MySQLSuite) SetUpSuite(c *C) {
+	s.logServer = test.StartAccessLogServer("access_log.sock", 10)
+	c.Assert(s.logServer, Not(IsNil))
+	s.ins = proxylib.NewInstance("node1", accesslog.NewClient(s.logServer.Path))
+	c.Assert(s.ins, Not(IsNil))
+}
+
+func (s *MySQLSuite) checkAccessLogs(c *C, expPasses, expDrops int) {
+	passes, drops := s.logServer.Clear()
+	c.Check(passes, Equals, expPasses, Commentf("Unxpected number of passed access log messages"))
+	c.Check(drops, Equals, expDrops, Commentf("Unxpected number of passed access log messages"))
+}
+
+func (s *MySQLSuite) TearDownTest(c *C) {
+	s.logServer.Clear()
+}
+
+func (s *MySQLSuite) TearDownSuite(c *C) {
+	s.logServer.Close()
+}
+
+// util function used for MySQL tests, as we have mysql packets
+// as hex strings
+func hexData(c *C, dataHex ...string) [][]byte {
+	data := make([][]byte, 0, len(dataHex))
+	for i := range dataHex {
+		dataRaw, err := hex.DecodeString(dataHex[i])
+		c.Assert(err, IsNil)
+		data = append(data, dataRaw)
+	}
+	return data
+}
+
+const (
+	// Initial handshake from a MySQL 8.0.19 server, with all capabilities
+	handshake = "4a0000000a382e302e3139002a000000616263646566676800ffffff0200ffdf1500000000000000000000696a6b6c6d6e6f70717273740063616368696e675f736861325f70617373776f726400"
+	// Initial handshake with SSL and query attributes capabilities removed
+	handshakeModified = "4a0000000a382e302e3139002a000000616263646566676800fff7ff0200ffd71500000000000000000000696a6b6c6d6e6f70717273740063616368696e675f736861325f70617373776f726400"
+	// Handshake response for user "reporting" and database "sales"
+	responseReporting = "5b0000010982290000000001ff00000000000000000000000000000000000000000000007265706f7274696e670014111111111111111111111111111111111111111173616c65730063616368696e675f736861325f70617373776f726400"
+	// Handshake response for user "admin" and database "sales"
+	responseAdmin = "570000010982290000000001ff000000000000000000000000000000000000000000000061646d696e0014111111111111111111111111111111111111111173616c65730063616368696e675f736861325f70617373776f726400"
+	authOK        = "0700000200000002000000"
+	// COM_QUERY "SELECT * FROM orders"
+	querySelect = "150000000353454c454354202a2046524f4d206f7264657273"
+	// COM_QUERY "DELETE FROM orders"
+	queryDelete = "130000000344454c4554452046524f4d206f7264657273"
+	// COM_INIT_DB "secret"
+	initDBSecret = "0700000002736563726574"
+	// COM_STMT_PREPARE "SELECT * FROM orders WHERE id = ?"
+	prepareSelect = "220000001653454c454354202a2046524f4d206f7264657273205748455245206964203d203f"
+	// COM_STMT_PREPARE_OK with statement id 1
+	prepareOK = "0c000001000100000003000100000000"
+	// COM_STMT_EXECUTE with statement ids 1 and 2
+	execute1 = "1600000017010000000001000000000108002a00000000000000"
+	execute2 = "1600000017020000000001000000000108002a00000000000000"
+)
+
+func (s *MySQLSuite) insertPolicy(c *C) {
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "cp1"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 3306
+		  rules: <
+		    l7_proto: "mysql"
+		    l7_rules: <
+		      l7_rules: <
+		        rule: <
+		          key: "user"
+		          value: "reporting"
+		        >
+		        rule: <
+		          key: "database"
+		          value: "sales"
+		        >
+		        rule: <
+		          key: "query_action"
+		          value: "select"
+		        >
+		        rule: <
+		          key: "query_table"
+		          value: "sales\\..*"
+		        >
+		      >
+		    >
+		  >
+		>
+		`})
+}
+
+// connect passes the handshake for the user "reporting"
+func (s *MySQLSuite) connect(c *C, conn *proxylib.Connection) {
+	data := hexData(c, handshake)
+	expHandshake := hexData(c, handshakeModified)[0]
+	conn.CheckOnDataOK(c, true, false, &data, expHandshake,
+		proxylib.DROP, len(data[0]),
+		proxylib.INJECT, len(expHandshake),
+		proxylib.MORE, 4)
+	data = hexData(c, responseReporting)
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(data[0]),
+		proxylib.MORE, 4)
+	data = hexData(c, authOK)
+	conn.CheckOnDataOK(c, true, false, &data, []byte{},
+		proxylib.PASS, len(data[0]),
+		proxylib.MORE, 4)
+	s.checkAccessLogs(c, 1, 0)
+}
+
+func (s *MySQLSuite) TestMySQLOnDataNoHeader(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "mysql", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:3306", "no-policy")
+	data := hexData(c, "0100")
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.MORE, 4-len(data[0]))
+}
+
+func (s *MySQLSuite) TestMySQLOnDataConnectDenied(c *C) {
+	s.insertPolicy(c)
+	conn := s.ins.CheckNewConnectionOK(c, "mysql", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:3306", "cp1")
+	data := hexData(c, handshake)
+	expHandshake := hexData(c, handshakeModified)[0]
+	conn.CheckOnDataOK(c, true, false, &data, expHandshake,
+		proxylib.DROP, len(data[0]),
+		proxylib.INJECT, len(expHandshake),
+		proxylib.MORE, 4)
+	data = hexData(c, responseAdmin)
+	conn.CheckOnDataOK(c, false, false, &data,
+		errPacket(2, erAccessDenied, sqlStateAccessDenied, connectDeniedText),
+		proxylib.DROP, len(data[0]),
+		proxylib.MORE, 4)
+	s.checkAccessLogs(c, 0, 1)
+
+	data = hexData(c, querySelect)
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.DROP, len(data[0]),
+		proxylib.MORE, 4)
+}
+
+func (s *MySQLSuite) TestMySQLOnDataQuery(c *C) {
+	s.insertPolicy(c)
+	conn := s.ins.CheckNewConnectionOK(c, "mysql", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:3306", "cp1")
+	s.connect(c, conn)
+
+	data := hexData(c, querySelect)
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(data[0]),
+		proxylib.MORE, 4)
+	data = hexData(c, queryDelete)
+	conn.CheckOnDataOK(c, false, false, &data,
+		errPacket(1, erTableAccessDenied, sqlStateSyntaxOrAccess, queryDeniedText),
+		proxylib.DROP, len(data[0]),
+		proxylib.MORE, 4)
+	data = hexData(c, initDBSecret)
+	conn.CheckOnDataOK(c, false, false, &data,
+		errPacket(1, erDBAccessDenied, sqlStateSyntaxOrAccess, databaseDeniedText),
+		proxylib.DROP, len(data[0]),
+		proxylib.MORE, 4)
+	s.checkAccessLogs(c, 1, 2)
+}
+
+// queryPacket returns a COM_QUERY packet for 'query'
+func queryPacket(query string) []byte {
+	payload := append([]byte{comQuery}, query...)
+	return append([]byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), 0}, payload...)
+}
+
+func (s *MySQLSuite) TestMySQLOnDataQueryNoTable(c *C) {
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "cp2"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 3306
+		  rules: <
+		    l7_proto: "mysql"
+		    l7_rules: <
+		      l7_rules: <
+		        rule: <
+		          key: "user"
+		          value: "reporting"
+		        >
+		        rule: <
+		          key: "query_table"
+		          value: "sales\\..*"
+		        >
+		      >
+		    >
+		  >
+		>
+		`})
+	conn := s.ins.CheckNewConnectionOK(c, "mysql", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:3306", "cp2")
+	s.connect(c, conn)
+
+	data := [][]byte{queryPacket("UPDATE orders SET id = 1")}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(data[0]),
+		proxylib.MORE, 4)
+	for _, query := range []string{
+		"SET autocommit = 0",
+		"CALL proc()",
+		"SELECT 1",
+		"SELECT * FROM sales.t, (private.s CROSS JOIN sales.u)",
+		"SELECT * FROM sales.t JOIN (private.s) ON true",
+		"SELECT * FROM mysales.x",
+	} {
+		data = [][]byte{queryPacket(query)}
+		conn.CheckOnDataOK(c, false, false, &data,
+			errPacket(1, erTableAccessDenied, sqlStateSyntaxOrAccess, queryDeniedText),
+			proxylib.DROP, len(data[0]),
+			proxylib.MORE, 4)
+	}
+	s.checkAccessLogs(c, 1, 6)
+}
+
+func (s *MySQLSuite) TestMySQLOnDataPreparedStatement(c *C) {
+	s.insertPolicy(c)
+	conn := s.ins.CheckNewConnectionOK(c, "mysql", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:3306", "cp1")
+	s.connect(c, conn)
+
+	data := hexData(c, prepareSelect)
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(data[0]),
+		proxylib.MORE, 4)
+	data = hexData(c, prepareOK)
+	conn.CheckOnDataOK(c, true, false, &data, []byte{},
+		proxylib.PASS, len(data[0]),
+		proxylib.MORE, 4)
+	data = hexData(c, execute1)
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(data[0]),
+		proxylib.MORE, 4)
+	data = hexData(c, execute2)
+	conn.CheckOnDataOK(c, false, false, &data,
+		errPacket(1, erUnknownStmtHandler, sqlStateGeneral, unknownStmtHandlerText),
+		proxylib.DROP, len(data[0]),
+		proxylib.MORE, 4)
+	s.checkAccessLogs(c, 2, 0)
+}
+
+func (s *MySQLSuite) TestParseStatements(c *C) {
+	for _, tc := range []struct {
+		query  string
+		action string
+		tables []string
+	}{
+		{"SELECT 1", "select", nil},
+		{"select * from orders o, sales.customers AS c where o.id = c.id", "select", []string{"db.orders", "sales.customers"}},
+		{"SELECT * FROM `orders` JOIN secret.t USING (id)", "select", []string{"db.orders", "secret.t"}},
+		{"SELECT * FROM a WHERE id IN (SELECT id FROM b)", "select", []string{"db.a", "db.b"}},
+		{"SELECT * FROM sales.t, (private.s CROSS JOIN sales.u)", "select", []string{"sales.t", "private.s", "sales.u"}},
+		{"SELECT * FROM sales.t JOIN (private.s) ON true", "select", []string{"sales.t", "private.s"}},
+		{"SELECT * FROM ((a JOIN (SELECT * FROM b) x ON true) JOIN c USING (id))", "select", []string{"db.a", "db.b", "db.c"}},
+		{"DELETE a FROM a JOIN b USING (id)", "delete", []string{"db.a", "db.b"}},
+		{"SELECT EXTRACT(YEAR FROM created) FROM a", "select", []string{"db.a"}},
+		{"SELECT 'FROM x' FROM dual", "select", nil},
+		{"WITH recent AS (SELECT * FROM a) SELECT * FROM recent", "select", []string{"db.a"}},
+		{"INSERT INTO a (x) SELECT x FROM b", "insert", []string{"db.a", "db.b"}},
+		{"REPLACE LOW_PRIORITY a VALUES (1)", "replace", []string{"db.a"}},
+		{"UPDATE a, b SET a.x = b.x", "update", []string{"db.a", "db.b"}},
+		{"DELETE FROM a WHERE x = 1", "delete", []string{"db.a"}},
+		{"CREATE TABLE IF NOT EXISTS a (id INT)", "ddl", []string{"db.a"}},
+		{"RENAME TABLE a TO other.a", "ddl", []string{"db.a", "other.a"}},
+		{"CREATE INDEX i ON a (x)", "ddl", []string{"db.a"}},
+		{"DROP DATABASE IF EXISTS other", "ddl", []string{"other"}},
+		{"SELECT 1 /* FROM x */ -- FROM y", "select", nil},
+		{"SELECT 1 /*!50000 FROM x */", "select", []string{"db.x"}},
+		{`SELECT 'it\'s' FROM a`, "select", []string{"db.a"}},
+		{"SELECT 'unterminated", "", []string{""}},
+	} {
+		stmts := parseStatements([]byte(tc.query), true, "db")
+		c.Assert(stmts, HasLen, 1, Commentf("query: %s", tc.query))
+		c.Check(stmts[0].action, Equals, tc.action, Commentf("query: %s", tc.query))
+		c.Check(stmts[0].tables, DeepEquals, tc.tables, Commentf("query: %s", tc.query))
+	}
+
+	stmts := parseStatements([]byte("USE other; SELECT * FROM a; DROP TABLE b"), true, "db")
+	c.Assert(stmts, HasLen, 3)
+	c.Check(stmts[0].verb, Equals, "use")
+	c.Check(stmts[0].database, Equals, "other")
+	c.Check(stmts[1].tables, DeepEquals, []string{"other.a"})
+	c.Check(stmts[2].action, Equals, "ddl")
+	c.Check(stmts[2].verb, Equals, "drop")
+}
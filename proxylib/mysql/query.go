@@ -0,0 +1,457 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Lexical structure: https://dev.mysql.com/doc/refman/8.0/en/language-structure.html
+
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // unquoted identifier, keyword or number
+	tokenIdent                   // quoted identifier
+	tokenString                  // string literal
+	tokenPunct                   // any other character
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func (t token) isWord(word string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, word)
+}
+
+func (t token) isPunct(c string) bool {
+	return t.kind == tokenPunct && t.text == c
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '$' || c >= 0x80
+}
+
+// skipQuoted returns the position after the quoted string or identifier
+// starting at data[i], and its contents.
+func skipQuoted(data []byte, i int, backslashEscapes bool) (int, string, bool) {
+	quote := data[i]
+	var text []byte
+	for i++; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '\\' && backslashEscapes && quote != '`':
+			if i+1 < len(data) {
+				i++
+				text = append(text, data[i])
+			}
+		case c == quote:
+			if i+1 < len(data) && data[i+1] == quote {
+				// escaped quote
+				i++
+				text = append(text, c)
+				break
+			}
+			return i + 1, string(text), true
+		default:
+			text = append(text, c)
+		}
+	}
+	return 0, "", false
+}
+
+// tokenize splits 'query' into tokens, skipping whitespace and comments.
+// The contents of executable comments ("/*! ... */") are tokenized as they
+// are run by the server.
+func tokenize(query []byte, backslashEscapes bool) ([]token, bool) {
+	var tokens []token
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case isSpace(c):
+			i++
+		case c == '#' || c == '-' && i+2 < len(query) && query[i+1] == '-' && isSpace(query[i+2]) ||
+			c == '-' && i+2 == len(query) && query[i+1] == '-':
+			if j := bytes.IndexByte(query[i:], '\n'); j >= 0 {
+				i += j + 1
+			} else {
+				i = len(query)
+			}
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			if i+2 < len(query) && query[i+2] == '!' {
+				// executable comment, skip the version number
+				for i += 3; i < len(query) && query[i] >= '0' && query[i] <= '9'; i++ {
+				}
+				continue
+			}
+			if i+3 < len(query) && query[i+2] == 'M' && query[i+3] == '!' {
+				for i += 4; i < len(query) && query[i] >= '0' && query[i] <= '9'; i++ {
+				}
+				continue
+			}
+			j := bytes.Index(query[i+2:], []byte("*/"))
+			if j < 0 {
+				return nil, false
+			}
+			i += 2 + j + 2
+		case c == '*' && i+1 < len(query) && query[i+1] == '/':
+			// end of an executable comment
+			i += 2
+		case c == '\'' || c == '"' || c == '`':
+			var text string
+			var ok bool
+			kind := tokenString
+			if c == '`' {
+				kind = tokenIdent
+			}
+			if i, text, ok = skipQuoted(query, i, backslashEscapes); !ok {
+				return nil, false
+			}
+			tokens = append(tokens, token{kind: kind, text: text})
+		case isWordChar(c):
+			j := i
+			for j < len(query) && isWordChar(query[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(query[i:j])})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c)})
+			i++
+		}
+	}
+	return tokens, true
+}
+
+// statement is the result of the analysis of a single SQL statement
+type statement struct {
+	verb     string   // first keyword in lower case
+	action   string   // verb, with DDL verbs collapsed to "ddl"
+	tables   []string // tables accessed, qualified with the database name
+	database string   // default database for the statement
+}
+
+var actionByVerb = map[string]string{
+	"create":   "ddl",
+	"alter":    "ddl",
+	"drop":     "ddl",
+	"truncate": "ddl",
+	"rename":   "ddl",
+}
+
+// stopWords may not be used as table aliases
+var stopWords = map[string]bool{
+	"as": true, "where": true, "join": true, "inner": true, "left": true,
+	"right": true, "cross": true, "natural": true, "straight_join": true,
+	"outer": true, "full": true, "on": true, "using": true, "group": true,
+	"order": true, "limit": true, "having": true, "union": true,
+	"except": true, "intersect": true, "for": true, "lock": true,
+	"into": true, "partition": true, "set": true, "values": true,
+	"value": true, "select": true, "window": true, "procedure": true,
+	"to": true, "use": true, "force": true, "ignore": true, "read": true,
+	"write": true, "low_priority": true, "local": true, "if": true,
+	"like": true, "with": true, "returning": true, "default": true,
+	"character": true, "charset": true, "collate": true, "engine": true,
+	"add": true, "modify": true, "change": true, "drop": true,
+	"rename": true, "comment": true,
+}
+
+type analyzer struct {
+	tokens   []token
+	database string
+	ctes     map[string]bool
+	stmt     statement
+
+	// joins holds the positions of the opening parentheses of
+	// parenthesized joined tables
+	joins map[int]bool
+}
+
+// name returns the possibly qualified name starting at tokens[i], and the
+// position after it.
+func (a *analyzer) name(i int) (string, int) {
+	isName := func(i int) bool {
+		return i < len(a.tokens) &&
+			(a.tokens[i].kind == tokenIdent ||
+				a.tokens[i].kind == tokenWord && !stopWords[strings.ToLower(a.tokens[i].text)])
+	}
+	if !isName(i) {
+		return "", i
+	}
+	name := strings.ToLower(a.tokens[i].text)
+	i++
+	if i+1 < len(a.tokens) && a.tokens[i].isPunct(".") && isName(i+1) {
+		name += "." + strings.ToLower(a.tokens[i+1].text)
+		i += 2
+	}
+	return name, i
+}
+
+func (a *analyzer) addTable(name string) {
+	if !strings.Contains(name, ".") {
+		if name == "dual" || a.ctes[name] {
+			return
+		}
+		name = a.database + "." + name
+	}
+	for _, table := range a.stmt.tables {
+		if table == name {
+			return
+		}
+	}
+	a.stmt.tables = append(a.stmt.tables, name)
+}
+
+// tableList adds the comma separated tables with optional aliases starting
+// at tokens[i], including the tables of parenthesized joined tables.
+func (a *analyzer) tableList(i int) {
+	for {
+		if i < len(a.tokens) && a.tokens[i].isPunct("(") {
+			// Subqueries are analyzed when they are reached, the
+			// tables joined in the parenthesis are listed on their
+			// own.
+			if !a.subqueryStart(i+1) || a.tokens[i+1].isPunct("(") {
+				a.joins[i] = true
+				a.tableList(i + 1)
+			}
+			i = a.skipParens(i)
+		} else {
+			name, next := a.name(i)
+			if name == "" {
+				return
+			}
+			a.addTable(name)
+			i = next
+		}
+		if i < len(a.tokens) && a.tokens[i].isWord("as") {
+			i += 2
+		} else if alias, next := a.name(i); alias != "" {
+			i = next
+		}
+		if i < len(a.tokens) && (a.tokens[i].isPunct(",") || a.tokens[i].isWord("to")) {
+			i++
+			continue
+		}
+		return
+	}
+}
+
+// skipWords returns the position after the optional words at tokens[i].
+func (a *analyzer) skipWords(i int, words ...string) int {
+	for i < len(a.tokens) {
+		found := false
+		for _, word := range words {
+			if a.tokens[i].isWord(word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+		i++
+	}
+	return i
+}
+
+// skipParens returns the position after the parenthesized tokens starting at
+// tokens[i], if any.
+func (a *analyzer) skipParens(i int) int {
+	if i >= len(a.tokens) || !a.tokens[i].isPunct("(") {
+		return i
+	}
+	depth := 0
+	for ; i < len(a.tokens); i++ {
+		switch {
+		case a.tokens[i].isPunct("("):
+			depth++
+		case a.tokens[i].isPunct(")"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// subqueryStart returns true if the token following an opening parenthesis
+// starts a subquery
+func (a *analyzer) subqueryStart(i int) bool {
+	return i < len(a.tokens) &&
+		(a.tokens[i].isWord("select") || a.tokens[i].isWord("with") || a.tokens[i].isPunct("("))
+}
+
+// withClause records the names of the common table expressions, returning
+// the position of the statement following the WITH clause.
+func (a *analyzer) withClause(i int) int {
+	i = a.skipWords(i+1, "recursive")
+	for i < len(a.tokens) {
+		name, next := a.name(i)
+		if name == "" {
+			break
+		}
+		a.ctes[name] = true
+		i = a.skipParens(next)
+		if i < len(a.tokens) && a.tokens[i].isWord("as") {
+			i = a.skipParens(i + 1)
+		}
+		if i < len(a.tokens) && a.tokens[i].isPunct(",") {
+			i++
+			continue
+		}
+		break
+	}
+	return i
+}
+
+func (a *analyzer) analyze() statement {
+	a.stmt = statement{database: a.database}
+	a.ctes = make(map[string]bool)
+	a.joins = make(map[int]bool)
+
+	start := 0
+	for start < len(a.tokens) && a.tokens[start].isPunct("(") {
+		start++
+	}
+	if start < len(a.tokens) && a.tokens[start].isWord("with") {
+		start = a.withClause(start)
+	}
+	if start >= len(a.tokens) || a.tokens[start].kind != tokenWord {
+		return a.stmt
+	}
+	verb := strings.ToLower(a.tokens[start].text)
+	a.stmt.verb = verb
+	a.stmt.action = verb
+	if action, ok := actionByVerb[verb]; ok {
+		a.stmt.action = action
+	}
+
+	switch verb {
+	case "use":
+		if name, _ := a.name(start + 1); name != "" {
+			a.stmt.database = name
+		}
+		return a.stmt
+	case "update":
+		a.tableList(a.skipWords(start+1, "low_priority", "ignore"))
+	case "insert", "replace":
+		i := a.skipWords(start+1, "low_priority", "delayed", "high_priority", "ignore")
+		if i < len(a.tokens) && !a.tokens[i].isWord("into") {
+			a.tableList(i)
+		}
+	case "truncate":
+		if start+1 < len(a.tokens) && !a.tokens[start+1].isWord("table") {
+			a.tableList(start + 1)
+		}
+	case "describe", "desc", "explain":
+		i := a.skipWords(start+1, "analyze", "extended", "partitions")
+		if i+2 < len(a.tokens) && a.tokens[i].isWord("format") && a.tokens[i+1].isPunct("=") {
+			i += 3
+		}
+		a.tableList(i)
+	}
+
+	// Track whether the innermost parenthesis holds a subquery or joined
+	// tables, to tell tables from e.g., "EXTRACT(YEAR FROM date)".
+	var subquery []bool
+	index := false
+	for i := 0; i < len(a.tokens); i++ {
+		t := a.tokens[i]
+		switch {
+		case t.isPunct("("):
+			subquery = append(subquery, a.joins[i] || a.subqueryStart(i+1))
+			continue
+		case t.isPunct(")"):
+			if len(subquery) > 0 {
+				subquery = subquery[:len(subquery)-1]
+			}
+			continue
+		case t.kind != tokenWord:
+			continue
+		}
+		if len(subquery) > 0 && !subquery[len(subquery)-1] {
+			continue
+		}
+		switch strings.ToLower(t.text) {
+		case "from", "join", "straight_join":
+			a.tableList(i + 1)
+		case "using":
+			// "JOIN ... USING (id)" lists columns
+			if verb == "delete" && !(i+1 < len(a.tokens) && a.tokens[i+1].isPunct("(")) {
+				a.tableList(i + 1)
+			}
+		case "into":
+			a.tableList(a.skipWords(i+1, "table"))
+		case "table", "tables", "view":
+			a.tableList(a.skipWords(i+1, "if", "not", "exists"))
+		case "database", "schema":
+			if a.stmt.action == "ddl" {
+				if name, _ := a.name(a.skipWords(i+1, "if", "not", "exists")); name != "" {
+					// matched as a table name on its own
+					a.stmt.tables = append(a.stmt.tables, name)
+				}
+			}
+		case "to":
+			if a.stmt.action == "ddl" {
+				a.tableList(i + 1)
+			}
+		case "index":
+			index = a.stmt.action == "ddl"
+		case "on":
+			if index {
+				a.tableList(i + 1)
+				index = false
+			}
+		}
+	}
+	return a.stmt
+}
+
+// parseStatements splits 'query' into statements and analyzes them. If
+// the query can not be tokenized, a single statement with no action and an
+// empty table is returned.
+func parseStatements(query []byte, backslashEscapes bool, database string) []statement {
+	tokens, ok := tokenize(query, backslashEscapes)
+	if !ok {
+		return []statement{{tables: []string{""}, database: database}}
+	}
+	var stmts []statement
+	for len(tokens) > 0 {
+		end := 0
+		for end < len(tokens) && !tokens[end].isPunct(";") {
+			end++
+		}
+		if end > 0 {
+			a := analyzer{tokens: tokens[:end], database: database}
+			stmt := a.analyze()
+			database = stmt.database
+			stmts = append(stmts, stmt)
+		}
+		if end < len(tokens) {
+			end++
+		}
+		tokens = tokens[end:]
+	}
+	return stmts
+}
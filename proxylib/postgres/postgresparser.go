@@ -0,0 +1,593 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/envoy/cilium"
+	"github.com/cilium/cilium/proxylib/proxylib"
+
+	log "github.com/sirupsen/logrus"
+)
+
+//
+// PostgreSQL v3 Parser
+//
+// Spec: https://www.postgresql.org/docs/current/protocol.html
+//
+
+// Current PostgreSQL parser supports filtering on the user and database
+// given in the startup message, and on the statement type and the tables
+// accessed by simple queries, function calls and extended query protocol
+// Parse messages. The statement type is the first keyword of the statement in
+// upper case, except for "SELECT ... INTO", which creates a table and has the
+// type "CREATE". 'schema' and 'table' are matched against all tables accessed
+// by the statement. Statements which do not access any table do not match
+// rules with a 'schema' or 'table'.
+//
+// Policy Examples:
+// {user : "reporting"} - Allow all statements for the user "reporting".
+// {user : "reporting", database : "sales", statement : "SELECT"}
+//     - Allow the user "reporting" to run only SELECTs on the database "sales".
+// {statement : "SELECT", schema : "reports"}
+//     - Allow only SELECTs from tables in the schema "reports".
+// {statement : "INSERT", schema : "public", table : "orders"}
+//     - Allow only INSERTs into the table "public.orders".
+//
+// Unqualified table names are resolved in the first schema of the
+// search_path parameter of the startup message, or in "public" by default.
+// Schemas named after the user are not considered. Once the search path may
+// have been changed, e.g. with SET search_path, unqualified table names do
+// not match rules with a 'schema' anymore.
+//
+// The startup message is allowed if any rule matches the user and database,
+// regardless of the statement type and tables. A query with multiple
+// statements is allowed only if all its statements are allowed. Denied
+// queries are replied with an ErrorResponse, as if the server had failed to
+// run them.
+//
+// SSL and GSSAPI encryption requests are refused on behalf of the server, as
+// the parser can not enforce policy on encrypted connections.
+
+const (
+	msgHdrLen       = 5 // type (byte) and length (int32)
+	startupHdrLen   = 8 // length (int32) and protocol version (int32)
+	maxStartupLen   = 10000
+	maxMessageLen   = 1 << 30
+	protocolVersion = 3
+
+	defaultSearchPath = "public"
+
+	cancelRequestCode = 80877102
+	sslRequestCode    = 80877103
+	gssEncRequestCode = 80877104
+)
+
+// DeniedMsg is sent if policy denies a query. Exported for tests
+var DeniedMsg = errorResponse("ERROR", "42501", "permission denied by policy")
+
+// StartupDeniedMsg is sent if policy denies the startup message. Exported for tests
+var StartupDeniedMsg = errorResponse("FATAL", "28000", "connection denied by policy")
+
+// EncryptionRefusedMsg is sent to refuse SSL and GSSAPI encryption requests. Exported for tests
+var EncryptionRefusedMsg = []byte("N")
+
+func message(typ byte, body []byte) []byte {
+	msg := make([]byte, msgHdrLen, msgHdrLen+len(body))
+	msg[0] = typ
+	binary.BigEndian.PutUint32(msg[1:msgHdrLen], uint32(4+len(body)))
+	return append(msg, body...)
+}
+
+func errorResponse(severity, code, text string) []byte {
+	var body bytes.Buffer
+	for _, field := range []struct {
+		typ   byte
+		value string
+	}{
+		{'S', severity},
+		{'V', severity},
+		{'C', code},
+		{'M', text},
+	} {
+		body.WriteByte(field.typ)
+		body.WriteString(field.value)
+		body.WriteByte(0)
+	}
+	body.WriteByte(0)
+	return message('E', body.Bytes())
+}
+
+func readyForQuery(status byte) []byte {
+	return message('Z', []byte{status})
+}
+
+type postgresRule struct {
+	user      string
+	database  string
+	statement string
+	schema    string
+	table     string
+}
+
+type postgresRequestData struct {
+	startup   bool
+	user      string
+	database  string
+	statement string
+	tables    []tableName
+}
+
+func (rule *postgresRule) Matches(data interface{}) bool {
+	// Cast 'data' to the type we give to 'Matches()'
+	reqData, ok := data.(postgresRequestData)
+	if !ok {
+		log.Warning("Matches() called with type other than postgresRequestData")
+		return false
+	}
+	if rule.user != "" && rule.user != reqData.user {
+		log.Debugf("PostgresRule: user mismatch %s, %s", rule.user, reqData.user)
+		return false
+	}
+	if rule.database != "" && rule.database != reqData.database {
+		log.Debugf("PostgresRule: database mismatch %s, %s", rule.database, reqData.database)
+		return false
+	}
+	if reqData.startup {
+		return true
+	}
+	if rule.statement != "" && rule.statement != reqData.statement {
+		log.Debugf("PostgresRule: statement mismatch %s, %s", rule.statement, reqData.statement)
+		return false
+	}
+	if rule.schema == "" && rule.table == "" {
+		return true
+	}
+	if len(reqData.tables) == 0 {
+		log.Debugf("PostgresRule: no tables accessed by %s", reqData.statement)
+		return false
+	}
+	for _, table := range reqData.tables {
+		if rule.schema != "" && rule.schema != table.schema {
+			log.Debugf("PostgresRule: schema mismatch %s, %s", rule.schema, table)
+			return false
+		}
+		if rule.table != "" && rule.table != table.name {
+			log.Debugf("PostgresRule: table mismatch %s, %s", rule.table, table)
+			return false
+		}
+	}
+	return true
+}
+
+// ruleParser parses protobuf L7 rules to enforcement objects
+// May panic
+func ruleParser(rule *cilium.PortNetworkPolicyRule) []proxylib.L7NetworkPolicyRule {
+	l7Rules := rule.GetL7Rules()
+	var rules []proxylib.L7NetworkPolicyRule
+	if l7Rules == nil {
+		return rules
+	}
+	for _, l7Rule := range l7Rules.GetL7Rules() {
+		var pr postgresRule
+		for k, v := range l7Rule.Rule {
+			switch k {
+			case "user":
+				pr.user = v
+			case "database":
+				pr.database = v
+			case "statement":
+				for i := 0; i < len(v); i++ {
+					if !isIdentStart(v[i]) {
+						proxylib.ParseError(fmt.Sprintf("Unable to parse L7 postgres rule with invalid statement: '%s'", v), rule)
+					}
+				}
+				pr.statement = strings.ToUpper(v)
+			case "schema":
+				pr.schema = v
+			case "table":
+				pr.table = v
+			default:
+				proxylib.ParseError(fmt.Sprintf("Unsupported key: %s", k), rule)
+			}
+		}
+		log.Debugf("Parsed PostgresRule: %v", pr)
+		rules = append(rules, &pr)
+	}
+	return rules
+}
+
+type factory struct{}
+
+func init() {
+	log.Info("init(): Registering postgresParserFactory")
+	proxylib.RegisterParserFactory("postgres", &factory{})
+	proxylib.RegisterL7RuleParser("postgres", ruleParser)
+}
+
+// replyIntent tells what to do when the server becomes ready for the next
+// query
+type replyIntent int
+
+const (
+	// readyPass passes the ReadyForQuery from the server
+	readyPass replyIntent = iota
+	// readyDenied replaces the ReadyForQuery from the server with DeniedMsg
+	// and ReadyForQuery, some messages before the Sync were denied
+	readyDenied
+	// queryDenied injects DeniedMsg and ReadyForQuery, the query was not
+	// sent to the server
+	queryDenied
+)
+
+type parser struct {
+	connection *proxylib.Connection
+
+	started  bool // startup message has been allowed
+	rejected bool // startup message has been denied
+	user     string
+	database string
+
+	// searchPath is the schema of unqualified table names, empty if not
+	// known
+	searchPath string
+
+	// prepared statements by name, for enforcing policy on Bind messages
+	// after policy changes
+	statements map[string][]statement
+
+	// discarding is set after a denied extended query protocol message
+	// until the next Sync, as the server would do after an error
+	discarding bool
+
+	// replyQueue holds the intents for the pending ReadyForQuery messages
+	replyQueue []replyIntent
+	// replyInjected is the number of bytes injected to the reply direction
+	// yet to be reported with an INJECT op
+	replyInjected int
+	// txStatus is the transaction status of the last ReadyForQuery
+	txStatus byte
+}
+
+func (f *factory) Create(connection *proxylib.Connection) proxylib.Parser {
+	log.Debugf("PostgresParserFactory: Create: %v", connection)
+
+	return &parser{
+		connection: connection,
+		statements: make(map[string][]statement),
+		txStatus:   'I',
+	}
+}
+
+func (p *parser) OnData(reply, endStream bool, dataArray [][]byte) (proxylib.OpType, int) {
+	// inefficient, but simple
+	if reply {
+		return p.onReply(dataArray)
+	}
+	data := bytes.Join(dataArray, []byte{})
+	if !p.started && !p.rejected {
+		return p.onStartup(data)
+	}
+	return p.onRequest(data)
+}
+
+func (p *parser) onStartup(data []byte) (proxylib.OpType, int) {
+	if len(data) < startupHdrLen {
+		return proxylib.MORE, startupHdrLen - len(data)
+	}
+	msgLen := int(binary.BigEndian.Uint32(data[0:4]))
+	if msgLen < startupHdrLen || msgLen > maxStartupLen {
+		log.Errorf("Invalid startup message length %d", msgLen)
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_LENGTH)
+	}
+	if len(data) < msgLen {
+		return proxylib.MORE, msgLen - len(data)
+	}
+
+	code := binary.BigEndian.Uint32(data[4:8])
+	switch code {
+	case sslRequestCode, gssEncRequestCode:
+		log.Debugf("Refusing encryption request %d", code)
+		p.connection.Inject(true, EncryptionRefusedMsg)
+		return proxylib.DROP, msgLen
+	case cancelRequestCode:
+		return proxylib.PASS, msgLen
+	}
+	if code>>16 != protocolVersion {
+		log.Errorf("Unsupported protocol version %d.%d", code>>16, code&0xffff)
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+	}
+
+	// Parameters are pairs of null-terminated strings, terminated by an
+	// empty name
+	params := make(map[string]string)
+	rest := data[startupHdrLen:msgLen]
+	for {
+		name, ok := cstring(&rest)
+		if !ok {
+			return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+		}
+		if name == "" {
+			break
+		}
+		value, ok := cstring(&rest)
+		if !ok {
+			return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+		}
+		params[name] = value
+	}
+	p.user = params["user"]
+	p.database = params["database"]
+	if p.database == "" {
+		p.database = p.user
+	}
+	p.searchPath = defaultSearchPath
+	if searchPath, exists := params["search_path"]; exists {
+		p.searchPath = firstSchema(searchPath)
+	}
+	if strings.Contains(params["options"], "search_path") {
+		p.searchPath = ""
+	}
+
+	reqData := postgresRequestData{startup: true, user: p.user, database: p.database}
+	if !p.connection.Matches(reqData) {
+		p.log(cilium.EntryType_Denied, "startup", statement{})
+		p.rejected = true
+		p.connection.Inject(true, StartupDeniedMsg)
+		return proxylib.DROP, msgLen
+	}
+	p.log(cilium.EntryType_Request, "startup", statement{})
+	p.started = true
+	// The server is ready for query after authentication
+	p.queueReply(readyPass)
+	return proxylib.PASS, msgLen
+}
+
+func (p *parser) onRequest(data []byte) (proxylib.OpType, int) {
+	if len(data) < msgHdrLen {
+		return proxylib.MORE, msgHdrLen - len(data)
+	}
+	typ := data[0]
+	msgLen := int(binary.BigEndian.Uint32(data[1:msgHdrLen]))
+	if msgLen < 4 || msgLen > maxMessageLen {
+		log.Errorf("Invalid message length %d", msgLen)
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_LENGTH)
+	}
+	frameLen := 1 + msgLen
+	if len(data) < frameLen {
+		return proxylib.MORE, frameLen - len(data)
+	}
+	body := data[msgHdrLen:frameLen]
+
+	if p.rejected {
+		return proxylib.DROP, frameLen
+	}
+	if p.discarding {
+		if typ == 'S' {
+			p.discarding = false
+			p.queueReply(readyDenied)
+			return proxylib.PASS, frameLen
+		}
+		return proxylib.DROP, frameLen
+	}
+
+	switch typ {
+	case 'Q': // Query
+		query, ok := cstring(&body)
+		if !ok {
+			return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+		}
+		stmts := parseStatements([]byte(query), p.searchPath)
+		if !p.matches("query", stmts, true) {
+			p.queueReply(queryDenied)
+			return proxylib.DROP, frameLen
+		}
+		p.updateSearchPath(stmts)
+		p.queueReply(readyPass)
+	case 'F': // FunctionCall
+		if !p.matches("function-call", []statement{unknownStatement(p.searchPath)}, true) {
+			p.queueReply(queryDenied)
+			return proxylib.DROP, frameLen
+		}
+		p.queueReply(readyPass)
+	case 'P': // Parse
+		name, ok1 := cstring(&body)
+		query, ok2 := cstring(&body)
+		if !ok1 || !ok2 {
+			return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+		}
+		stmts := parseStatements([]byte(query), p.searchPath)
+		if !p.matches("parse", stmts, true) {
+			p.discarding = true
+			return proxylib.DROP, frameLen
+		}
+		p.updateSearchPath(stmts)
+		p.statements[name] = stmts
+	case 'B': // Bind
+		_, ok1 := cstring(&body) // portal
+		name, ok2 := cstring(&body)
+		if !ok1 || !ok2 {
+			return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+		}
+		stmts, exists := p.statements[name]
+		if !exists {
+			// Statement prepared with SQL PREPARE, it is not known
+			stmts = []statement{unknownStatement(p.searchPath)}
+		}
+		if !p.matches("bind", stmts, false) {
+			p.discarding = true
+			return proxylib.DROP, frameLen
+		}
+	case 'C': // Close
+		if len(body) > 0 && body[0] == 'S' {
+			body = body[1:]
+			if name, ok := cstring(&body); ok {
+				delete(p.statements, name)
+			}
+		}
+	case 'S': // Sync
+		p.queueReply(readyPass)
+	}
+	return proxylib.PASS, frameLen
+}
+
+// matches returns true if all the statements are allowed, logging each
+// statement if denied or 'logAllowed' is true.
+func (p *parser) matches(message string, stmts []statement, logAllowed bool) bool {
+	matches := true
+	for _, stmt := range stmts {
+		reqData := postgresRequestData{
+			user:      p.user,
+			database:  p.database,
+			statement: stmt.statementType,
+			tables:    stmt.tables,
+		}
+		if !p.connection.Matches(reqData) {
+			matches = false
+		}
+	}
+	if matches && !logAllowed {
+		return true
+	}
+
+	entryType := cilium.EntryType_Request
+	if !matches {
+		entryType = cilium.EntryType_Denied
+	}
+	for _, stmt := range stmts {
+		p.log(entryType, message, stmt)
+	}
+	return matches
+}
+
+// updateSearchPath records the search path after the allowed statements
+func (p *parser) updateSearchPath(stmts []statement) {
+	if len(stmts) > 0 {
+		p.searchPath = stmts[len(stmts)-1].searchPath
+	}
+}
+
+func (p *parser) log(entryType cilium.EntryType, message string, stmt statement) {
+	tables := make([]string, 0, len(stmt.tables))
+	for _, table := range stmt.tables {
+		tables = append(tables, table.String())
+	}
+	p.connection.Log(entryType,
+		&cilium.LogEntry_GenericL7{
+			GenericL7: &cilium.L7LogEntry{
+				Proto: "postgres",
+				Fields: map[string]string{
+					"message":   message,
+					"user":      p.user,
+					"database":  p.database,
+					"statement": stmt.statementType,
+					"tables":    strings.Join(tables, ", "),
+				},
+			},
+		})
+}
+
+// failedStatus returns the transaction status after an error
+func failedStatus(status byte) byte {
+	if status == 'I' {
+		return 'I'
+	}
+	return 'E'
+}
+
+// injectDenied injects DeniedMsg followed by ReadyForQuery with the
+// transaction status after an error, returning the number of bytes injected.
+func (p *parser) injectDenied(status byte) int {
+	p.txStatus = failedStatus(status)
+	return p.connection.Inject(true, DeniedMsg) +
+		p.connection.Inject(true, readyForQuery(p.txStatus))
+}
+
+// queueReply queues the intent for the next ReadyForQuery. Denied queries
+// are replied to right away if no replies are pending.
+func (p *parser) queueReply(intent replyIntent) {
+	if intent == queryDenied && len(p.replyQueue) == 0 {
+		p.injectDenied(p.txStatus)
+		return
+	}
+	p.replyQueue = append(p.replyQueue, intent)
+}
+
+// injectFromQueue injects replies for the denied queries at the head of the
+// reply queue, returning the number of bytes injected.
+func (p *parser) injectFromQueue() int {
+	injected := 0
+	for len(p.replyQueue) > 0 && p.replyQueue[0] == queryDenied {
+		injected += p.injectDenied(p.txStatus)
+		p.replyQueue = p.replyQueue[1:]
+	}
+	return injected
+}
+
+func (p *parser) onReply(dataArray [][]byte) (proxylib.OpType, int) {
+	if p.replyInjected > 0 {
+		n := p.replyInjected
+		p.replyInjected = 0
+		return proxylib.INJECT, n
+	}
+	if injected := p.injectFromQueue(); injected > 0 {
+		return proxylib.INJECT, injected
+	}
+
+	data := bytes.Join(dataArray, []byte{})
+	if len(data) < msgHdrLen {
+		return proxylib.MORE, msgHdrLen - len(data)
+	}
+	typ := data[0]
+	msgLen := int(binary.BigEndian.Uint32(data[1:msgHdrLen]))
+	if msgLen < 4 || msgLen > maxMessageLen {
+		log.Errorf("Invalid reply message length %d", msgLen)
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_LENGTH)
+	}
+	frameLen := 1 + msgLen
+	if len(data) < frameLen {
+		return proxylib.MORE, frameLen - len(data)
+	}
+
+	if typ == 'Z' && msgLen == 5 { // ReadyForQuery
+		status := data[msgHdrLen]
+		if len(p.replyQueue) > 0 {
+			intent := p.replyQueue[0]
+			p.replyQueue = p.replyQueue[1:]
+			if intent == readyDenied {
+				p.replyInjected = p.injectDenied(status)
+				return proxylib.DROP, frameLen
+			}
+		}
+		p.txStatus = status
+	}
+	return proxylib.PASS, frameLen
+}
+
+// cstring returns the null-terminated string at the start of 'data' and
+// advances 'data' past it.
+func cstring(data *[]byte) (string, bool) {
+	i := bytes.IndexByte(*data, 0)
+	if i < 0 {
+		return "", false
+	}
+	s := string((*data)[:i])
+	*data = (*data)[i+1:]
+	return s, true
+}
@@ -0,0 +1,380 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package postgres
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/cilium/cilium/proxylib/accesslog"
+	"github.com/cilium/cilium/proxylib/proxylib"
+	"github.com/cilium/cilium/proxylib/test"
+
+	// log "github.com/sirupsen/logrus"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	// logging.ToggleDebugLogs(true)
+	// log.SetLevel(log.DebugLevel)
+
+	TestingT(t)
+}
+
+type PostgresSuite struct {
+	logServer *test.AccessLogServer
+	ins       *proxylib.Instance
+}
+
+var _ = Suite(&PostgresSuite{})
+
+// Set up access log server and Library instance for all the test cases
+func (s *PostgresSuite) SetUpSuite(c *C) {
+	s.logServer = test.StartAccessLogServer("access_log.sock", 10)
+	c.Assert(s.logServer, Not(IsNil))
+	s.ins = proxylib.NewInstance("node1", accesslog.NewClient(s.logServer.Path))
+	c.Assert(s.ins, Not(IsNil))
+}
+
+func (s *PostgresSuite) checkAccessLogs(c *C, expPasses, expDrops int) {
+	passes, drops := s.logServer.Clear()
+	c.Check(passes, Equals, expPasses, Commentf("Unxpected number of passed access log messages"))
+	c.Check(drops, Equals, expDrops, Commentf("Unxpected number of passed access log messages"))
+}
+
+func (s *PostgresSuite) TearDownTest(c *C) {
+	s.logServer.Clear()
+}
+
+func (s *PostgresSuite) TearDownSuite(c *C) {
+	s.logServer.Close()
+}
+
+// util function used for PostgreSQL tests, as we have postgres messages
+// as hex strings
+func hexData(c *C, dataHex ...string) [][]byte {
+	data := make([][]byte, 0, len(dataHex))
+	for i := range dataHex {
+		dataRaw, err := hex.DecodeString(dataHex[i])
+		c.Assert(err, IsNil)
+		data = append(data, dataRaw)
+	}
+	return data
+}
+
+const (
+	// StartupMessage user=reporting database=sales application_name=psql
+	startupReporting = "0000003d0003000075736572007265706f7274696e670064617461626173650073616c6573006170706c69636174696f6e5f6e616d65007073716c0000"
+	// StartupMessage user=admin database=sales
+	startupAdmin = "0000002300030000757365720061646d696e0064617461626173650073616c65730000"
+	sslRequest   = "0000000804d2162f"
+	// Query "SELECT * FROM orders;"
+	querySelect = "510000001a53454c454354202a2046524f4d206f72646572733b00"
+	// Query "SELECT 1; DELETE FROM orders"
+	queryDelete = "510000002153454c45435420313b2044454c4554452046524f4d206f726465727300"
+	// Parse "SELECT * FROM orders WHERE id = $1"
+	parseSelect = "500000002e0053454c454354202a2046524f4d206f7264657273205748455245206964203d20243100000100000017"
+	// Parse "DELETE FROM orders WHERE id = $1"
+	parseDelete = "500000002c0044454c4554452046524f4d206f7264657273205748455245206964203d20243100000100000017"
+	bind        = "42000000120000000000010000000134320000"
+	execute     = "45000000090000000000"
+	sync        = "5300000004"
+
+	authOK          = "520000000800000000"
+	readyIdle       = "5a0000000549"
+	parseComplete   = "3100000004"
+	bindComplete    = "3200000004"
+	commandComplete = "430000000d53454c454354203100"
+)
+
+func (s *PostgresSuite) insertPolicy(c *C) {
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "cp1"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 5432
+		  rules: <
+		    l7_proto: "postgres"
+		    l7_rules: <
+		      l7_rules: <
+		        rule: <
+		          key: "user"
+		          value: "reporting"
+		        >
+		        rule: <
+		          key: "statement"
+		          value: "select"
+		        >
+		      >
+		    >
+		  >
+		>
+		`})
+}
+
+func (s *PostgresSuite) TestPostgresOnDataNoHeader(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "postgres", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:5432", "no-policy")
+	data := hexData(c, "0000")
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.MORE, 8-len(data[0]))
+}
+
+func (s *PostgresSuite) TestPostgresOnDataSSLRequest(c *C) {
+	s.insertPolicy(c)
+	conn := s.ins.CheckNewConnectionOK(c, "postgres", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:5432", "cp1")
+	data := hexData(c, sslRequest+startupReporting)
+	conn.CheckOnDataOK(c, false, false, &data, EncryptionRefusedMsg,
+		proxylib.DROP, len(sslRequest)/2,
+		proxylib.PASS, len(startupReporting)/2,
+		proxylib.MORE, 5)
+	s.checkAccessLogs(c, 1, 0)
+}
+
+func (s *PostgresSuite) TestPostgresOnDataStartupDenied(c *C) {
+	s.insertPolicy(c)
+	conn := s.ins.CheckNewConnectionOK(c, "postgres", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:5432", "cp1")
+	data := hexData(c, startupAdmin)
+	conn.CheckOnDataOK(c, false, false, &data, StartupDeniedMsg,
+		proxylib.DROP, len(data[0]),
+		proxylib.MORE, 5)
+	s.checkAccessLogs(c, 0, 1)
+
+	data = hexData(c, querySelect)
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.DROP, len(data[0]),
+		proxylib.MORE, 5)
+}
+
+func (s *PostgresSuite) TestPostgresOnDataSimpleQuery(c *C) {
+	s.insertPolicy(c)
+	conn := s.ins.CheckNewConnectionOK(c, "postgres", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:5432", "cp1")
+	data := hexData(c, startupReporting, querySelect, queryDelete)
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(data[0]),
+		proxylib.PASS, len(data[1]),
+		proxylib.DROP, len(data[2]),
+		proxylib.MORE, 5)
+	s.checkAccessLogs(c, 2, 2)
+
+	// The denied query is replied to after the server is ready
+	expReply := append(append([]byte{}, DeniedMsg...), readyForQuery('I')...)
+	data = hexData(c, authOK, readyIdle, commandComplete, readyIdle)
+	conn.CheckOnDataOK(c, true, false, &data, expReply,
+		proxylib.PASS, len(data[0]),
+		proxylib.PASS, len(data[1]),
+		proxylib.PASS, len(data[2]),
+		proxylib.PASS, len(data[3]),
+		proxylib.INJECT, len(expReply),
+		proxylib.MORE, 5)
+
+	// With no replies pending the denied query is replied to right away
+	data = hexData(c, queryDelete)
+	conn.CheckOnDataOK(c, false, false, &data, expReply,
+		proxylib.DROP, len(data[0]),
+		proxylib.MORE, 5)
+}
+
+func (s *PostgresSuite) TestPostgresOnDataExtendedQuery(c *C) {
+	s.insertPolicy(c)
+	conn := s.ins.CheckNewConnectionOK(c, "postgres", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:5432", "cp1")
+	data := hexData(c, startupReporting, parseSelect, bind, execute, sync, parseDelete, bind, execute, sync)
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(data[0]),
+		proxylib.PASS, len(data[1]),
+		proxylib.PASS, len(data[2]),
+		proxylib.PASS, len(data[3]),
+		proxylib.PASS, len(data[4]),
+		proxylib.DROP, len(data[5]),
+		proxylib.DROP, len(data[6]),
+		proxylib.DROP, len(data[7]),
+		proxylib.PASS, len(data[8]),
+		proxylib.MORE, 5)
+	s.checkAccessLogs(c, 2, 1)
+
+	// The ReadyForQuery for the second Sync is replaced with an error
+	expReply := append(append([]byte{}, DeniedMsg...), readyForQuery('I')...)
+	data = hexData(c, authOK, readyIdle, parseComplete, bindComplete, commandComplete, readyIdle, readyIdle)
+	conn.CheckOnDataOK(c, true, false, &data, expReply,
+		proxylib.PASS, len(data[0]),
+		proxylib.PASS, len(data[1]),
+		proxylib.PASS, len(data[2]),
+		proxylib.PASS, len(data[3]),
+		proxylib.PASS, len(data[4]),
+		proxylib.PASS, len(data[5]),
+		proxylib.DROP, len(data[6]),
+		proxylib.INJECT, len(expReply),
+		proxylib.MORE, 5)
+}
+
+func (s *PostgresSuite) TestPostgresOnDataSchema(c *C) {
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "cp2"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 5432
+		  rules: <
+		    l7_proto: "postgres"
+		    l7_rules: <
+		      l7_rules: <
+		        rule: <
+		          key: "statement"
+		          value: "SELECT"
+		        >
+		        rule: <
+		          key: "schema"
+		          value: "reports"
+		        >
+		      >
+		    >
+		  >
+		>
+		`})
+	conn := s.ins.CheckNewConnectionOK(c, "postgres", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:5432", "cp2")
+	data := hexData(c, startupReporting)
+	for _, query := range []string{
+		"SELECT * FROM reports.daily",
+		"SELECT * INTO reports.copy FROM reports.daily",
+		"SELECT * FROM sales.orders",
+		"SELECT * FROM daily",
+		"SELECT 1",
+		"SELECT * FROM reports.t, (private.secret a CROSS JOIN reports.u b)",
+		"SELECT * FROM reports.t JOIN (private.secret a JOIN reports.u b ON true) ON true",
+	} {
+		data = append(data, message('Q', append([]byte(query), 0)))
+	}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(data[0]),
+		proxylib.PASS, len(data[1]),
+		proxylib.DROP, len(data[2]),
+		proxylib.DROP, len(data[3]),
+		proxylib.DROP, len(data[4]),
+		proxylib.DROP, len(data[5]),
+		proxylib.DROP, len(data[6]),
+		proxylib.DROP, len(data[7]),
+		proxylib.MORE, 5)
+	s.checkAccessLogs(c, 2, 6)
+}
+
+func statementTypes(query string) []string {
+	var types []string
+	for _, stmt := range parseStatements([]byte(query), defaultSearchPath) {
+		types = append(types, stmt.statementType)
+	}
+	return types
+}
+
+func (s *PostgresSuite) TestStatementTypes(c *C) {
+	for _, tc := range []struct {
+		query string
+		types []string
+	}{
+		{"", nil},
+		{" ; ;", nil},
+		{"select 1", []string{"SELECT"}},
+		{"SELECT 1; delete from t;", []string{"SELECT", "DELETE"}},
+		{"(SELECT 1) UNION (SELECT 2)", []string{"SELECT"}},
+		{"-- comment; DELETE\nSELECT 1", []string{"SELECT"}},
+		{"/* a /* nested; */ DELETE; */ SELECT 1", []string{"SELECT"}},
+		{"SELECT ';DELETE' FROM t", []string{"SELECT"}},
+		{"SELECT 'it''s;' ; DELETE", []string{"SELECT", "DELETE"}},
+		{`SELECT "a;""b" FROM t`, []string{"SELECT"}},
+		{"SELECT $$;DELETE$$, $tag$ $$; $tag$", []string{"SELECT"}},
+		{"SELECT $1; DELETE", []string{"SELECT", "DELETE"}},
+		{`SELECT E'\';DELETE'`, []string{"SELECT"}},
+		{`SELECT e'\\'; DELETE`, []string{"SELECT", "DELETE"}},
+		{`SELECT '\\'; DELETE`, []string{"SELECT", "DELETE"}},
+		// depends on standard_conforming_strings
+		{`SELECT '\'; DELETE; SELECT '\''`, []string{""}},
+		// unterminated
+		{"SELECT 'a", []string{""}},
+		{"SELECT 1; /* DELETE", []string{"SELECT", ""}},
+		{"SELECT $x$ DELETE", []string{""}},
+		{`SELECT U&"d\0061ta" FROM t`, []string{""}},
+		// creates a table
+		{"SELECT * INTO new FROM t", []string{"CREATE"}},
+		{"select * into temp table new from t", []string{"CREATE"}},
+		{"INSERT INTO t SELECT * FROM u", []string{"INSERT"}},
+	} {
+		c.Check(statementTypes(tc.query), DeepEquals, tc.types, Commentf("query: %s", tc.query))
+	}
+}
+
+func (s *PostgresSuite) TestStatementTables(c *C) {
+	for _, tc := range []struct {
+		query  string
+		tables []string
+	}{
+		{"SELECT 1", nil},
+		{"SELECT * FROM orders", []string{"public.orders"}},
+		{`SELECT * FROM Sales.Orders o, "Sales"."Orders" AS p (a, b)`, []string{"sales.orders", "Sales.Orders"}},
+		{"SELECT * FROM db.sales.orders", []string{"sales.orders"}},
+		{"SELECT * FROM ONLY a JOIN b USING (id) LEFT JOIN c ON true", []string{"public.a", "public.b", "public.c"}},
+		{"SELECT * FROM generate_series(1, 3) g, a", []string{"public.a"}},
+		{"SELECT extract(year FROM ts), substring(s FROM 1) FROM a", []string{"public.a"}},
+		{"SELECT * FROM a WHERE x IS DISTINCT FROM y", []string{"public.a"}},
+		{"SELECT * FROM a WHERE id IN (SELECT id FROM s.b)", []string{"public.a", "s.b"}},
+		{"SELECT * FROM (SELECT * FROM a) x", []string{"public.a"}},
+		{"SELECT * FROM reports.t, (private.secret a CROSS JOIN reports.u b)", []string{"reports.t", "private.secret", "reports.u"}},
+		{"SELECT * FROM reports.t JOIN (private.secret a JOIN reports.u b ON true) ON true", []string{"reports.t", "private.secret", "reports.u"}},
+		{"SELECT * FROM reports.t JOIN (private.secret) ON true", []string{"reports.t", "private.secret"}},
+		{"SELECT * FROM ((a JOIN (SELECT * FROM b) x ON true) JOIN c ON true) j", []string{"public.a", "public.b", "public.c"}},
+		{"SELECT * INTO s.new FROM a", []string{"s.new", "public.a"}},
+		{"WITH x AS (DELETE FROM a RETURNING *) SELECT * FROM x", []string{"public.a"}},
+		{"INSERT INTO a (id) VALUES (1) ON CONFLICT (id) DO UPDATE SET id = 2", []string{"public.a"}},
+		{"UPDATE ONLY a SET x = 1 FROM b WHERE a.id = b.id", []string{"public.a", "public.b"}},
+		{"DELETE FROM a USING b", []string{"public.a", "public.b"}},
+		{"SELECT * FROM a FOR UPDATE OF a", []string{"public.a"}},
+		{"TRUNCATE a, s.b", []string{"public.a", "s.b"}},
+		{"TABLE a", []string{"public.a"}},
+		{"COPY a (x) FROM stdin", []string{"public.a"}},
+		{"COPY (SELECT * FROM a) TO stdout", []string{"public.a"}},
+		{"CREATE TABLE IF NOT EXISTS a (id int REFERENCES s.b (id))", []string{"public.a", "s.b"}},
+		{"CREATE INDEX i ON ONLY a (x)", []string{"public.a"}},
+		{"DROP TABLE a, b", []string{"public.a", "public.b"}},
+		{"ALTER TABLE a ADD FOREIGN KEY (x) REFERENCES b", []string{"public.a", "public.b"}},
+		{"SET search_path TO s", nil},
+	} {
+		stmts := parseStatements([]byte(tc.query), defaultSearchPath)
+		c.Assert(stmts, HasLen, 1, Commentf("query: %s", tc.query))
+		var tables []string
+		for _, table := range stmts[0].tables {
+			tables = append(tables, table.String())
+		}
+		c.Check(tables, DeepEquals, tc.tables, Commentf("query: %s", tc.query))
+	}
+}
+
+func (s *PostgresSuite) TestSearchPath(c *C) {
+	c.Assert(firstSchema("sales, public"), Equals, "sales")
+	c.Assert(firstSchema(`"Sales"`), Equals, "Sales")
+	c.Assert(firstSchema(`"$user", public`), Equals, "")
+	c.Assert(firstSchema("$user, public"), Equals, "")
+	c.Assert(firstSchema(""), Equals, "")
+
+	// Unqualified names are not resolved once the search path may have
+	// changed
+	stmts := parseStatements([]byte("SELECT * FROM a; SET search_path = s; SELECT * FROM b"), "sales")
+	c.Assert(stmts, HasLen, 3)
+	c.Assert(stmts[0].tables, DeepEquals, []tableName{{schema: "sales", name: "a"}})
+	c.Assert(stmts[2].tables, DeepEquals, []tableName{{schema: "", name: "b"}})
+	c.Assert(stmts[2].searchPath, Equals, "")
+
+	stmts = parseStatements([]byte("SELECT set_config('search_path', 's', false), * FROM x.a"), "sales")
+	c.Assert(stmts, HasLen, 1)
+	c.Assert(stmts[0].searchPath, Equals, "")
+}
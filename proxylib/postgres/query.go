@@ -0,0 +1,590 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Lexical structure: https://www.postgresql.org/docs/current/sql-syntax-lexical.html
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9' || c == '$'
+}
+
+// skipString returns the position after the string constant starting at
+// data[i]. Backslash escapes are processed only for escape string constants.
+// As the meaning of a backslash before a quote in other string constants
+// depends on the standard_conforming_strings setting of the server, such
+// strings are rejected.
+func skipString(data []byte, i int, escapes bool) (int, bool) {
+	backslashes := 0
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			if escapes {
+				i++
+				continue
+			}
+			backslashes++
+			continue
+		case '\'':
+			if backslashes%2 != 0 {
+				return 0, false
+			}
+			if i+1 < len(data) && data[i+1] == '\'' {
+				// escaped quote
+				i++
+				break
+			}
+			return i + 1, true
+		}
+		backslashes = 0
+	}
+	return 0, false
+}
+
+// skipQuotedIdent returns the position after the quoted identifier starting
+// at data[i].
+func skipQuotedIdent(data []byte, i int) (int, bool) {
+	for i++; i < len(data); i++ {
+		if data[i] == '"' {
+			if i+1 < len(data) && data[i+1] == '"' {
+				// escaped quote
+				i++
+				continue
+			}
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// dollarTag returns the tag of the dollar-quoted string constant starting at
+// data[i], or nil if data[i] does not start one.
+func dollarTag(data []byte, i int) []byte {
+	j := i + 1
+	if j < len(data) && isIdentStart(data[j]) {
+		for j++; j < len(data) && isIdentChar(data[j]) && data[j] != '$'; j++ {
+		}
+	}
+	if j < len(data) && data[j] == '$' {
+		return data[i : j+1]
+	}
+	return nil
+}
+
+// skipBlockComment returns the position after the possibly nested block
+// comment starting at data[i].
+func skipBlockComment(data []byte, i int) (int, bool) {
+	depth := 0
+	for i < len(data)-1 {
+		switch {
+		case data[i] == '/' && data[i+1] == '*':
+			depth++
+			i += 2
+		case data[i] == '*' && data[i+1] == '/':
+			depth--
+			i += 2
+			if depth == 0 {
+				return i, true
+			}
+		default:
+			i++
+		}
+	}
+	return 0, false
+}
+
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // unquoted identifier, keyword or number
+	tokenIdent                   // quoted identifier
+	tokenString                  // string constant
+	tokenPunct                   // any other character
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func (t token) isWord(word string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, word)
+}
+
+func (t token) isPunct(c string) bool {
+	return t.kind == tokenPunct && t.text == c
+}
+
+// tokenize splits 'query' into tokens, skipping whitespace and comments. If
+// the query can not be safely tokenized, the tokens before the failure are
+// returned with false.
+func tokenize(query []byte) ([]token, bool) {
+	var tokens []token
+	for i := 0; i < len(query); {
+		c := query[i]
+		start := i
+		ok := true
+		switch {
+		case isSpace(c):
+			i++
+			continue
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			if j := bytes.IndexByte(query[i:], '\n'); j >= 0 {
+				i += j + 1
+			} else {
+				i = len(query)
+			}
+			continue
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			i, ok = skipBlockComment(query, i)
+		case c == '\'':
+			i, ok = skipString(query, i, false)
+			tokens = append(tokens, token{kind: tokenString})
+		case c == '"':
+			if i, ok = skipQuotedIdent(query, i); ok {
+				text := strings.Replace(string(query[start+1:i-1]), `""`, `"`, -1)
+				tokens = append(tokens, token{kind: tokenIdent, text: text})
+			}
+		case c == '$':
+			if tag := dollarTag(query, i); tag != nil {
+				end := bytes.Index(query[i+len(tag):], tag)
+				if end < 0 {
+					ok = false
+				} else {
+					i += len(tag) + end + len(tag)
+					tokens = append(tokens, token{kind: tokenString})
+				}
+			} else {
+				i++
+				tokens = append(tokens, token{kind: tokenPunct, text: "$"})
+			}
+		case isIdentChar(c):
+			j := i
+			for j < len(query) && isIdentChar(query[j]) {
+				j++
+			}
+			switch {
+			case j < len(query) && query[j] == '\'' && j-i == 1 && (c == 'E' || c == 'e'):
+				// Escape string constant
+				i, ok = skipString(query, j, true)
+				tokens = append(tokens, token{kind: tokenString})
+			case j+1 < len(query) && query[j] == '&' && query[j+1] == '"' && j-i == 1 && (c == 'U' || c == 'u'):
+				// Quoted identifiers with Unicode escapes are not
+				// decoded
+				ok = false
+			default:
+				i = j
+				tokens = append(tokens, token{kind: tokenWord, text: string(query[start:j])})
+			}
+		default:
+			i++
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c)})
+		}
+		if !ok {
+			return tokens, false
+		}
+	}
+	return tokens, true
+}
+
+// tableName is the name of a table accessed by a statement. The schema is
+// empty if the table name is not qualified and the search path is not known.
+type tableName struct {
+	schema string
+	name   string
+}
+
+func (t tableName) String() string {
+	return t.schema + "." + t.name
+}
+
+// statement is the result of the analysis of a single SQL statement
+type statement struct {
+	// statement type, i.e., the first keyword in upper case. "SELECT ...
+	// INTO" creates a table and has the type "CREATE".
+	statementType string
+	// tables accessed by the statement
+	tables []tableName
+	// searchPath is the schema of unqualified table names after the
+	// statement
+	searchPath string
+}
+
+// unknownStatement is the result of the analysis of a statement which can not
+// be parsed. It does not match any rule on the statement type or tables.
+func unknownStatement(searchPath string) statement {
+	return statement{tables: []tableName{{}}, searchPath: searchPath}
+}
+
+// stopWords may not be used as table names or aliases
+var stopWords = map[string]bool{
+	"as": true, "where": true, "join": true, "inner": true, "left": true,
+	"right": true, "cross": true, "natural": true, "full": true,
+	"outer": true, "on": true, "using": true, "group": true, "order": true,
+	"limit": true, "offset": true, "fetch": true, "having": true,
+	"union": true, "except": true, "intersect": true, "for": true,
+	"into": true, "set": true, "values": true, "select": true,
+	"window": true, "returning": true, "default": true, "with": true,
+	"lateral": true, "only": true, "tablesample": true, "of": true,
+	"nowait": true, "skip": true, "do": true, "from": true, "to": true,
+	"stdin": true, "stdout": true, "program": true, "if": true,
+	"not": true, "exists": true, "like": true, "in": true, "table": true,
+}
+
+type analyzer struct {
+	tokens     []token
+	searchPath string
+	ctes       map[string]bool
+	stmt       statement
+
+	// joins holds the positions of the opening parentheses of
+	// parenthesized joined tables in FROM lists
+	joins map[int]bool
+}
+
+func (a *analyzer) isName(i int) bool {
+	return i < len(a.tokens) &&
+		(a.tokens[i].kind == tokenIdent ||
+			a.tokens[i].kind == tokenWord && !stopWords[strings.ToLower(a.tokens[i].text)])
+}
+
+// name returns the parts of the possibly qualified name starting at
+// tokens[i], and the position after it. Unquoted parts are folded to lower
+// case.
+func (a *analyzer) name(i int) ([]string, int) {
+	var parts []string
+	for a.isName(i) {
+		part := a.tokens[i].text
+		if a.tokens[i].kind == tokenWord {
+			part = strings.ToLower(part)
+		}
+		parts = append(parts, part)
+		i++
+		if i+1 >= len(a.tokens) || !a.tokens[i].isPunct(".") {
+			break
+		}
+		i++
+	}
+	return parts, i
+}
+
+func (a *analyzer) addTable(parts []string) {
+	var table tableName
+	switch len(parts) {
+	case 0:
+		return
+	case 1:
+		if a.ctes[parts[0]] {
+			return
+		}
+		table = tableName{schema: a.searchPath, name: parts[0]}
+	default:
+		// A leading database name must be the current database
+		table = tableName{schema: parts[len(parts)-2], name: parts[len(parts)-1]}
+	}
+	for _, t := range a.stmt.tables {
+		if t == table {
+			return
+		}
+	}
+	a.stmt.tables = append(a.stmt.tables, table)
+}
+
+// tableList adds the comma separated tables with optional aliases starting
+// at tokens[i]. Functions are skipped in FROM lists, and the tables of
+// parenthesized joined tables are added.
+func (a *analyzer) tableList(i int, from bool) {
+	for {
+		if from && i < len(a.tokens) && a.tokens[i].isPunct("(") {
+			// Subqueries are analyzed when they are reached, the
+			// tables joined in the parenthesis are listed like in
+			// FROM.
+			if i+1 < len(a.tokens) && (a.tokens[i+1].isPunct("(") || !a.subqueryStart(i+1)) {
+				a.joins[i] = true
+				a.tableList(i+1, true)
+			}
+			i = a.skipParens(i)
+		} else {
+			parts, next := a.name(i)
+			if len(parts) == 0 {
+				return
+			}
+			i = next
+			if i < len(a.tokens) && a.tokens[i].isPunct("(") {
+				if !from {
+					a.addTable(parts)
+					return
+				}
+				i = a.skipParens(i)
+			} else {
+				a.addTable(parts)
+			}
+		}
+		if i < len(a.tokens) && a.tokens[i].isPunct("*") {
+			i++
+		}
+		if i < len(a.tokens) && a.tokens[i].isWord("as") {
+			i++
+		}
+		if a.isName(i) {
+			i = a.skipParens(i + 1)
+		}
+		if i < len(a.tokens) && a.tokens[i].isPunct(",") {
+			i++
+			continue
+		}
+		return
+	}
+}
+
+// skipWords returns the position after the optional words at tokens[i].
+func (a *analyzer) skipWords(i int, words ...string) int {
+	for i < len(a.tokens) {
+		found := false
+		for _, word := range words {
+			if a.tokens[i].isWord(word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+		i++
+	}
+	return i
+}
+
+// skipParens returns the position after the parenthesized tokens starting at
+// tokens[i], if any.
+func (a *analyzer) skipParens(i int) int {
+	if i >= len(a.tokens) || !a.tokens[i].isPunct("(") {
+		return i
+	}
+	depth := 0
+	for ; i < len(a.tokens); i++ {
+		switch {
+		case a.tokens[i].isPunct("("):
+			depth++
+		case a.tokens[i].isPunct(")"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// withClause records the names of the common table expressions
+func (a *analyzer) withClause(i int) {
+	i = a.skipWords(i+1, "recursive")
+	for i < len(a.tokens) {
+		parts, next := a.name(i)
+		if len(parts) != 1 {
+			return
+		}
+		a.ctes[parts[0]] = true
+		i = a.skipParens(next)
+		if i < len(a.tokens) && a.tokens[i].isWord("as") {
+			i = a.skipWords(i+1, "not", "materialized")
+			i = a.skipParens(i)
+		}
+		if i < len(a.tokens) && a.tokens[i].isPunct(",") {
+			i++
+			continue
+		}
+		return
+	}
+}
+
+// subqueryStart returns true if the token following an opening parenthesis
+// starts a subquery
+func (a *analyzer) subqueryStart(i int) bool {
+	if i >= len(a.tokens) {
+		return false
+	}
+	t := a.tokens[i]
+	if t.isPunct("(") {
+		return true
+	}
+	for _, word := range []string{"select", "with", "values", "insert", "update", "delete", "table"} {
+		if t.isWord(word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *analyzer) analyze() statement {
+	a.stmt = statement{searchPath: a.searchPath}
+	a.ctes = make(map[string]bool)
+	a.joins = make(map[int]bool)
+
+	start := 0
+	for start < len(a.tokens) && a.tokens[start].isPunct("(") {
+		start++
+	}
+	if start >= len(a.tokens) || a.tokens[start].kind != tokenWord || !isIdentStart(a.tokens[start].text[0]) {
+		return a.stmt
+	}
+	verb := strings.ToUpper(a.tokens[start].text)
+	a.stmt.statementType = verb
+
+	switch verb {
+	case "WITH":
+		a.withClause(start)
+	case "TRUNCATE", "LOCK", "TABLE":
+		a.tableList(a.skipWords(start+1, "table", "only"), false)
+	case "COPY":
+		a.tableList(start+1, false)
+	case "SET", "RESET", "DISCARD":
+		// The search path is not known after it has been changed
+		i := a.skipWords(start+1, "session", "local")
+		if i < len(a.tokens) && (a.tokens[i].isWord("search_path") || a.tokens[i].isWord("all")) {
+			a.searchPath = ""
+		}
+	}
+
+	// Track whether the innermost parenthesis holds a subquery or joined
+	// tables, to tell tables from e.g., "EXTRACT(YEAR FROM date)".
+	var subquery []bool
+	index := false
+	for i := 0; i < len(a.tokens); i++ {
+		t := a.tokens[i]
+		switch {
+		case t.isPunct("("):
+			subquery = append(subquery, a.joins[i] || a.subqueryStart(i+1))
+			continue
+		case t.isPunct(")"):
+			if len(subquery) > 0 {
+				subquery = subquery[:len(subquery)-1]
+			}
+			continue
+		case t.kind != tokenWord:
+			continue
+		}
+		if len(subquery) > 0 && !subquery[len(subquery)-1] {
+			// Column definitions may reference other tables
+			switch {
+			case t.isWord("references"):
+				a.tableList(i+1, false)
+			case t.isWord("set_config"):
+				a.searchPath = ""
+			}
+			continue
+		}
+		switch strings.ToLower(t.text) {
+		case "from":
+			// "IS DISTINCT FROM" compares values
+			if i > 0 && a.tokens[i-1].isWord("distinct") {
+				break
+			}
+			if verb != "COPY" || len(subquery) > 0 {
+				a.tableList(a.skipWords(i+1, "only"), true)
+			}
+		case "join":
+			a.tableList(a.skipWords(i+1, "only"), true)
+		case "using":
+			if verb == "DELETE" || verb == "MERGE" {
+				a.tableList(a.skipWords(i+1, "only"), true)
+			}
+		case "into":
+			if verb == "SELECT" {
+				a.stmt.statementType = "CREATE"
+			}
+			a.tableList(a.skipWords(i+1, "temporary", "temp", "unlogged", "table"), false)
+		case "update":
+			a.tableList(a.skipWords(i+1, "only"), false)
+		case "table", "view", "sequence":
+			a.tableList(a.skipWords(i+1, "if", "not", "exists", "only"), false)
+		case "index":
+			index = verb == "CREATE" || verb == "ALTER" || verb == "DROP"
+		case "on":
+			if index {
+				a.tableList(a.skipWords(i+1, "only"), false)
+				index = false
+			}
+		case "references":
+			a.tableList(i+1, false)
+		case "set_config":
+			a.searchPath = ""
+		}
+	}
+	a.stmt.searchPath = a.searchPath
+	return a.stmt
+}
+
+// parseStatements splits 'query' into statements and analyzes them, with
+// unqualified table names in the schema 'searchPath'. If the query can not
+// be safely split to statements, the last statement returned is unknown.
+func parseStatements(query []byte, searchPath string) []statement {
+	tokens, ok := tokenize(query)
+	var stmts []statement
+	for len(tokens) > 0 {
+		end := 0
+		for end < len(tokens) && !tokens[end].isPunct(";") {
+			end++
+		}
+		if end == len(tokens) && !ok {
+			// The statement is incomplete
+			break
+		}
+		if end > 0 {
+			a := analyzer{tokens: tokens[:end], searchPath: searchPath}
+			stmt := a.analyze()
+			searchPath = stmt.searchPath
+			stmts = append(stmts, stmt)
+		}
+		if end < len(tokens) {
+			end++
+		}
+		tokens = tokens[end:]
+	}
+	if !ok {
+		stmts = append(stmts, unknownStatement(searchPath))
+	}
+	return stmts
+}
+
+// firstSchema returns the first schema of the search path 'value' of the
+// search_path parameter, or an empty string if it can not be determined.
+func firstSchema(value string) string {
+	tokens, ok := tokenize([]byte(value))
+	if !ok || len(tokens) == 0 || len(tokens) > 1 && !tokens[1].isPunct(",") {
+		return ""
+	}
+	switch tokens[0].kind {
+	case tokenWord:
+		return strings.ToLower(tokens[0].text)
+	case tokenIdent:
+		if tokens[0].text != "$user" {
+			return tokens[0].text
+		}
+	}
+	return ""
+}
@@ -22,11 +22,16 @@ import "C"
 import (
 	"github.com/cilium/cilium/proxylib/accesslog"
 	_ "github.com/cilium/cilium/proxylib/cassandra"
+	_ "github.com/cilium/cilium/proxylib/kafka"
 	_ "github.com/cilium/cilium/proxylib/memcached"
+	_ "github.com/cilium/cilium/proxylib/mysql"
 	"github.com/cilium/cilium/proxylib/npds"
+	_ "github.com/cilium/cilium/proxylib/postgres"
 	. "github.com/cilium/cilium/proxylib/proxylib"
 	_ "github.com/cilium/cilium/proxylib/r2d2"
+	_ "github.com/cilium/cilium/proxylib/redis"
 	_ "github.com/cilium/cilium/proxylib/testparsers"
+	_ "github.com/cilium/cilium/proxylib/tls"
 
 	"github.com/cilium/cilium/pkg/lock"
 	log "github.com/sirupsen/logrus"
@@ -0,0 +1,257 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// keySpec describes the positions of the keys in the arguments of a
+// command, with the command name at position 0.
+type keySpec struct {
+	first int // position of the first key, 0 if none
+	last  int // position of the last key, negative values count from the end
+	step  int // distance between the keys
+	// numKeys is the position of an argument holding the number of keys
+	// that immediately follow it, 0 if none
+	numKeys int
+	// options is true if the keys referenced by the BY, GET and STORE
+	// options of SORT are keys of the command as well
+	options bool
+}
+
+var (
+	firstKey     = keySpec{first: 1, last: 1, step: 1}
+	firstTwoKeys = keySpec{first: 1, last: 2, step: 1}
+	allKeys      = keySpec{first: 1, last: -1, step: 1}
+	keyValues    = keySpec{first: 1, last: -1, step: 2}
+	// blocking commands have a timeout as the last argument
+	blockingKeys = keySpec{first: 1, last: -2, step: 1}
+	storeNumKeys = keySpec{first: 1, last: 1, step: 1, numKeys: 2}
+	scriptKeys   = keySpec{numKeys: 2}
+	sortKeys     = keySpec{first: 1, last: 1, step: 1, options: true}
+)
+
+// keySpecs maps the command names to the positions of their keys. Commands
+// missing from the table are considered to have no keys.
+var keySpecs = map[string]keySpec{
+	// keyspace
+	"DEL":       allKeys,
+	"UNLINK":    allKeys,
+	"EXISTS":    allKeys,
+	"TOUCH":     allKeys,
+	"WATCH":     allKeys,
+	"TYPE":      firstKey,
+	"TTL":       firstKey,
+	"PTTL":      firstKey,
+	"EXPIRE":    firstKey,
+	"PEXPIRE":   firstKey,
+	"EXPIREAT":  firstKey,
+	"PEXPIREAT": firstKey,
+	"PERSIST":   firstKey,
+	"DUMP":      firstKey,
+	"RESTORE":   firstKey,
+	"SORT":      sortKeys,
+	"MOVE":      firstKey,
+	"RENAME":    firstTwoKeys,
+	"RENAMENX":  firstTwoKeys,
+	"OBJECT":    {first: 2, last: 2, step: 1},
+
+	// strings
+	"GET":         firstKey,
+	"SET":         firstKey,
+	"SETNX":       firstKey,
+	"SETEX":       firstKey,
+	"PSETEX":      firstKey,
+	"GETSET":      firstKey,
+	"APPEND":      firstKey,
+	"STRLEN":      firstKey,
+	"INCR":        firstKey,
+	"DECR":        firstKey,
+	"INCRBY":      firstKey,
+	"DECRBY":      firstKey,
+	"INCRBYFLOAT": firstKey,
+	"GETRANGE":    firstKey,
+	"SETRANGE":    firstKey,
+	"GETBIT":      firstKey,
+	"SETBIT":      firstKey,
+	"BITCOUNT":    firstKey,
+	"BITPOS":      firstKey,
+	"BITFIELD":    firstKey,
+	"BITOP":       {first: 2, last: -1, step: 1},
+	"MGET":        allKeys,
+	"MSET":        keyValues,
+	"MSETNX":      keyValues,
+
+	// hashes
+	"HGET":         firstKey,
+	"HSET":         firstKey,
+	"HSETNX":       firstKey,
+	"HMSET":        firstKey,
+	"HMGET":        firstKey,
+	"HDEL":         firstKey,
+	"HEXISTS":      firstKey,
+	"HGETALL":      firstKey,
+	"HKEYS":        firstKey,
+	"HVALS":        firstKey,
+	"HLEN":         firstKey,
+	"HSTRLEN":      firstKey,
+	"HINCRBY":      firstKey,
+	"HINCRBYFLOAT": firstKey,
+	"HSCAN":        firstKey,
+
+	// lists
+	"LPUSH":      firstKey,
+	"RPUSH":      firstKey,
+	"LPUSHX":     firstKey,
+	"RPUSHX":     firstKey,
+	"LPOP":       firstKey,
+	"RPOP":       firstKey,
+	"LLEN":       firstKey,
+	"LRANGE":     firstKey,
+	"LINDEX":     firstKey,
+	"LSET":       firstKey,
+	"LREM":       firstKey,
+	"LTRIM":      firstKey,
+	"LINSERT":    firstKey,
+	"RPOPLPUSH":  firstTwoKeys,
+	"BRPOPLPUSH": firstTwoKeys,
+	"BLPOP":      blockingKeys,
+	"BRPOP":      blockingKeys,
+
+	// sets
+	"SADD":        firstKey,
+	"SREM":        firstKey,
+	"SMEMBERS":    firstKey,
+	"SISMEMBER":   firstKey,
+	"SCARD":       firstKey,
+	"SPOP":        firstKey,
+	"SRANDMEMBER": firstKey,
+	"SSCAN":       firstKey,
+	"SMOVE":       firstTwoKeys,
+	"SINTER":      allKeys,
+	"SUNION":      allKeys,
+	"SDIFF":       allKeys,
+	"SINTERSTORE": allKeys,
+	"SUNIONSTORE": allKeys,
+	"SDIFFSTORE":  allKeys,
+
+	// sorted sets
+	"ZADD":             firstKey,
+	"ZREM":             firstKey,
+	"ZCARD":            firstKey,
+	"ZCOUNT":           firstKey,
+	"ZSCORE":           firstKey,
+	"ZINCRBY":          firstKey,
+	"ZRANK":            firstKey,
+	"ZREVRANK":         firstKey,
+	"ZRANGE":           firstKey,
+	"ZREVRANGE":        firstKey,
+	"ZRANGEBYSCORE":    firstKey,
+	"ZREVRANGEBYSCORE": firstKey,
+	"ZRANGEBYLEX":      firstKey,
+	"ZREVRANGEBYLEX":   firstKey,
+	"ZLEXCOUNT":        firstKey,
+	"ZREMRANGEBYRANK":  firstKey,
+	"ZREMRANGEBYSCORE": firstKey,
+	"ZREMRANGEBYLEX":   firstKey,
+	"ZPOPMIN":          firstKey,
+	"ZPOPMAX":          firstKey,
+	"ZSCAN":            firstKey,
+	"BZPOPMIN":         blockingKeys,
+	"BZPOPMAX":         blockingKeys,
+	"ZUNIONSTORE":      storeNumKeys,
+	"ZINTERSTORE":      storeNumKeys,
+
+	// hyperloglog, geo and streams
+	"PFADD":     firstKey,
+	"PFCOUNT":   allKeys,
+	"PFMERGE":   allKeys,
+	"GEOADD":    firstKey,
+	"GEOPOS":    firstKey,
+	"GEODIST":   firstKey,
+	"GEOHASH":   firstKey,
+	"XADD":      firstKey,
+	"XLEN":      firstKey,
+	"XRANGE":    firstKey,
+	"XREVRANGE": firstKey,
+	"XDEL":      firstKey,
+	"XTRIM":     firstKey,
+
+	// scripting
+	"EVAL":    scriptKeys,
+	"EVALSHA": scriptKeys,
+}
+
+// commandKeys returns the keys in the arguments of 'command'.
+func commandKeys(command string, args [][]byte) [][]byte {
+	spec, ok := keySpecs[command]
+	if !ok {
+		return nil
+	}
+	var keys [][]byte
+	if spec.first > 0 {
+		last := spec.last
+		if last < 0 {
+			last += len(args)
+		}
+		if last >= len(args) {
+			last = len(args) - 1
+		}
+		for i := spec.first; i <= last; i += spec.step {
+			keys = append(keys, args[i])
+		}
+	}
+	if spec.numKeys > 0 && spec.numKeys < len(args) {
+		n, err := strconv.Atoi(string(args[spec.numKeys]))
+		if err == nil {
+			for i := spec.numKeys + 1; i <= spec.numKeys+n && i < len(args); i++ {
+				keys = append(keys, args[i])
+			}
+		}
+	}
+	if spec.options {
+		keys = append(keys, sortOptionKeys(args)...)
+	}
+	return keys
+}
+
+// sortOptionKeys returns the keys and key patterns referenced by the options
+// of a SORT command. The patterns are matched against the key rules like keys,
+// except for the special patterns which do not refer to any key.
+func sortOptionKeys(args [][]byte) [][]byte {
+	var keys [][]byte
+	for i := 2; i < len(args)-1; i++ {
+		switch {
+		case bytes.EqualFold(args[i], []byte("LIMIT")):
+			i += 2
+		case bytes.EqualFold(args[i], []byte("BY")):
+			i++
+			if !bytes.EqualFold(args[i], []byte("nosort")) {
+				keys = append(keys, args[i])
+			}
+		case bytes.EqualFold(args[i], []byte("GET")):
+			i++
+			if !bytes.Equal(args[i], []byte("#")) {
+				keys = append(keys, args[i])
+			}
+		case bytes.EqualFold(args[i], []byte("STORE")):
+			i++
+			keys = append(keys, args[i])
+		}
+	}
+	return keys
+}
@@ -0,0 +1,612 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// RESP protocol parser based on https://redis.io/topics/protocol
+
+package redis
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/envoy/cilium"
+	"github.com/cilium/cilium/proxylib/proxylib"
+
+	log "github.com/sirupsen/logrus"
+)
+
+//
+// Redis Parser
+//
+// Requests are RESP arrays of bulk strings or inline commands, the first
+// element being the command name. Inline commands are split into arguments
+// with the quoting rules of the server. Replies are parsed only to find their
+// boundaries so that injected error replies for denied requests can be
+// placed in the right order when the client pipelines requests.
+//
+// Policy Examples:
+// {command : "GET"} - Allow GET on all keys, no other commands.
+// {keyPrefix : "app1:"} - Allow all commands with keys beginning with "app1:".
+// {command : "SET", keyPrefix : "app1:"} - Allow SET on keys beginning with "app1:".
+//
+// A rule with a 'keyPrefix' only allows commands which have keys, all of
+// which must begin with the prefix. The keys and key patterns referenced by
+// the BY, GET and STORE options of SORT must begin with the prefix as well.
+// MULTI, EXEC and DISCARD are always allowed. If a command within a
+// transaction is denied, the EXEC is replaced with a DISCARD and the client
+// receives an EXECABORT error, as if the command had failed to be queued by
+// the server.
+
+const (
+	// Limits as enforced by the Redis server
+	maxMultiBulkLen = 1024 * 1024
+	maxBulkLen      = 512 * 1024 * 1024
+	maxInlineLen    = 64 * 1024
+	maxNestingDepth = 32
+)
+
+var crlf = []byte("\r\n")
+
+// DeniedMsg is sent if policy denies the request. Exported for tests
+var DeniedMsg = []byte("-ERR access denied\r\n")
+
+// ExecAbortMsg is sent as the reply to an EXEC of a transaction that
+// contained denied commands. Exported for tests
+var ExecAbortMsg = []byte("-EXECABORT Transaction discarded because of previous errors.\r\n")
+
+// DiscardCmd is sent to the server in place of an EXEC of a transaction
+// that contained denied commands. Exported for tests
+var DiscardCmd = []byte("*1\r\n$7\r\nDISCARD\r\n")
+
+type redisRule struct {
+	command   string
+	keyPrefix []byte
+}
+
+type redisRequestData struct {
+	command string
+	keys    [][]byte
+}
+
+func (rule *redisRule) Matches(data interface{}) bool {
+	// Cast 'data' to the type we give to 'Matches()'
+	reqData, ok := data.(redisRequestData)
+	if !ok {
+		log.Warning("Matches() called with type other than redisRequestData")
+		return false
+	}
+	if rule.command != "" && rule.command != reqData.command {
+		log.Debugf("RedisRule: command mismatch %s, %s", rule.command, reqData.command)
+		return false
+	}
+	if len(rule.keyPrefix) > 0 {
+		if len(reqData.keys) == 0 {
+			log.Debugf("RedisRule: command %s has no keys", reqData.command)
+			return false
+		}
+		for _, key := range reqData.keys {
+			if !bytes.HasPrefix(key, rule.keyPrefix) {
+				log.Debugf("RedisRule: key prefix mismatch %s, %s", rule.keyPrefix, key)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ruleParser parses protobuf L7 rules to enforcement objects
+// May panic
+func ruleParser(rule *cilium.PortNetworkPolicyRule) []proxylib.L7NetworkPolicyRule {
+	l7Rules := rule.GetL7Rules()
+	var rules []proxylib.L7NetworkPolicyRule
+	if l7Rules == nil {
+		return rules
+	}
+	for _, l7Rule := range l7Rules.GetL7Rules() {
+		var rr redisRule
+		for k, v := range l7Rule.Rule {
+			switch k {
+			case "command":
+				if strings.ContainsAny(v, " \t\r\n") {
+					proxylib.ParseError(fmt.Sprintf("Unable to parse L7 redis rule with invalid command: '%s'", v), rule)
+				}
+				rr.command = strings.ToUpper(v)
+			case "keyPrefix":
+				rr.keyPrefix = []byte(v)
+			default:
+				proxylib.ParseError(fmt.Sprintf("Unsupported key: %s", k), rule)
+			}
+		}
+		log.Debugf("Parsed rule '%s' '%s'", rr.command, rr.keyPrefix)
+		rules = append(rules, &rr)
+	}
+	return rules
+}
+
+type factory struct{}
+
+func init() {
+	log.Info("init(): Registering redisParserFactory")
+	proxylib.RegisterParserFactory("redis", &factory{})
+	proxylib.RegisterL7RuleParser("redis", ruleParser)
+}
+
+// replyIntent tells what to do with the reply to a request
+type replyIntent int
+
+const (
+	// replyPass passes the reply from the server
+	replyPass replyIntent = iota
+	// replyDenied injects DeniedMsg, the request was not sent to the server
+	replyDenied
+	// replyAbort replaces the reply to DiscardCmd with ExecAbortMsg
+	replyAbort
+)
+
+type parser struct {
+	connection *proxylib.Connection
+
+	// replyQueue holds the intents for the replies of the pipelined requests
+	replyQueue []replyIntent
+
+	// injected bytes yet to be reported with an INJECT op in the original
+	// and reply directions, respectively
+	origInjected  int
+	replyInjected int
+
+	inMulti     bool // within a MULTI/EXEC transaction
+	multiDenied bool // a command in the current transaction was denied
+
+	// passthrough is set when the server may send replies without requests,
+	// e.g., after SUBSCRIBE or MONITOR, and all replies are passed as is
+	passthrough bool
+}
+
+func (f *factory) Create(connection *proxylib.Connection) proxylib.Parser {
+	log.Debugf("RedisParserFactory: Create: %v", connection)
+
+	return &parser{connection: connection}
+}
+
+func (p *parser) OnData(reply, endStream bool, dataArray [][]byte) (proxylib.OpType, int) {
+	if reply {
+		return p.onReply(dataArray)
+	}
+	return p.onRequest(dataArray)
+}
+
+func (p *parser) onRequest(dataArray [][]byte) (proxylib.OpType, int) {
+	if p.origInjected > 0 {
+		n := p.origInjected
+		p.origInjected = 0
+		return proxylib.INJECT, n
+	}
+
+	// inefficient, but simple
+	data := bytes.Join(dataArray, []byte{})
+	if len(data) == 0 {
+		return proxylib.MORE, 1
+	}
+
+	args, frameLen, more, errCode := parseCommand(data)
+	if errCode != 0 {
+		log.Debugf("Invalid request frame: %s", errCode)
+		return proxylib.ERROR, int(errCode)
+	}
+	if more > 0 {
+		return proxylib.MORE, more
+	}
+	if len(args) == 0 {
+		// Empty inline commands are ignored by the server
+		return proxylib.PASS, frameLen
+	}
+
+	reqData := redisRequestData{command: strings.ToUpper(string(args[0]))}
+	reqData.keys = commandKeys(reqData.command, args)
+
+	matches := true
+	entryType := cilium.EntryType_Request
+
+	switch reqData.command {
+	case "MULTI", "EXEC", "DISCARD":
+	default:
+		if !p.connection.Matches(reqData) {
+			matches = false
+			entryType = cilium.EntryType_Denied
+		}
+	}
+
+	p.connection.Log(entryType,
+		&cilium.LogEntry_GenericL7{
+			GenericL7: &cilium.L7LogEntry{
+				Proto: "redis",
+				Fields: map[string]string{
+					"command": reqData.command,
+					"keys":    string(bytes.Join(reqData.keys, []byte(", "))),
+				},
+			},
+		})
+
+	if !matches {
+		if p.inMulti {
+			p.multiDenied = true
+		}
+		p.queueReply(replyDenied)
+		log.Debugf("Policy mismatch, dropping %d bytes", frameLen)
+		return proxylib.DROP, frameLen
+	}
+
+	switch reqData.command {
+	case "MULTI":
+		p.inMulti = true
+		p.multiDenied = false
+	case "EXEC":
+		aborted := p.inMulti && p.multiDenied
+		p.inMulti = false
+		p.multiDenied = false
+		if aborted {
+			// Discard the transaction on the server, and fail the
+			// EXEC on the client when the server replies.
+			p.origInjected = p.connection.Inject(false, DiscardCmd)
+			p.queueReply(replyAbort)
+			return proxylib.DROP, frameLen
+		}
+	case "DISCARD":
+		p.inMulti = false
+		p.multiDenied = false
+	case "SUBSCRIBE", "PSUBSCRIBE", "MONITOR":
+		p.passthrough = true
+	}
+	p.queueReply(replyPass)
+	return proxylib.PASS, frameLen
+}
+
+// queueReply queues the intent for the reply of the current request.
+// Denied requests are replied to right away if no replies are pending.
+func (p *parser) queueReply(intent replyIntent) {
+	if intent == replyDenied && (p.passthrough || len(p.replyQueue) == 0) {
+		p.connection.Inject(true, DeniedMsg)
+		return
+	}
+	if p.passthrough {
+		return
+	}
+	p.replyQueue = append(p.replyQueue, intent)
+}
+
+// injectFromQueue injects replies for the denied requests at the head of the
+// reply queue, returning the number of bytes injected.
+func (p *parser) injectFromQueue() int {
+	injected := 0
+	for len(p.replyQueue) > 0 && p.replyQueue[0] == replyDenied {
+		injected += p.connection.Inject(true, DeniedMsg)
+		p.replyQueue = p.replyQueue[1:]
+	}
+	return injected
+}
+
+func (p *parser) onReply(dataArray [][]byte) (proxylib.OpType, int) {
+	if p.replyInjected > 0 {
+		n := p.replyInjected
+		p.replyInjected = 0
+		return proxylib.INJECT, n
+	}
+	if injected := p.injectFromQueue(); injected > 0 {
+		return proxylib.INJECT, injected
+	}
+
+	// inefficient, but simple
+	data := bytes.Join(dataArray, []byte{})
+	if len(data) == 0 {
+		return proxylib.MORE, 1
+	}
+
+	frameLen, more, errCode := parseValue(data, 0, 0)
+	if errCode != 0 {
+		log.Debugf("Invalid reply frame: %s", errCode)
+		return proxylib.ERROR, int(errCode)
+	}
+	if more > 0 {
+		return proxylib.MORE, more
+	}
+
+	// Push messages are not replies to any request
+	if p.passthrough || len(p.replyQueue) == 0 || data[0] == '>' {
+		return proxylib.PASS, frameLen
+	}
+
+	intent := p.replyQueue[0]
+	p.replyQueue = p.replyQueue[1:]
+	if intent == replyAbort {
+		p.replyInjected = p.connection.Inject(true, ExecAbortMsg)
+		return proxylib.DROP, frameLen
+	}
+	return proxylib.PASS, frameLen
+}
+
+// parseLength parses the length in the header line of a RESP frame
+func parseLength(line []byte, max int) (int, proxylib.OpError) {
+	n, err := strconv.Atoi(string(line))
+	if err != nil || n < -1 || n > max {
+		return 0, proxylib.ERROR_INVALID_FRAME_LENGTH
+	}
+	return n, 0
+}
+
+// lineEnd returns the position of the CRLF terminating the line starting at
+// 'offset', or -1 if the line is not complete.
+func lineEnd(data []byte, offset int) (int, proxylib.OpError) {
+	i := bytes.Index(data[offset:], crlf)
+	if i < 0 {
+		if len(data)-offset > maxInlineLen {
+			return -1, proxylib.ERROR_INVALID_FRAME_LENGTH
+		}
+		return -1, 0
+	}
+	return offset + i, 0
+}
+
+// bulkEnd returns the position after the CRLF terminated bulk data of
+// 'n' bytes starting at 'offset', or the number of bytes missing.
+func bulkEnd(data []byte, offset, n int) (next, more int, errCode proxylib.OpError) {
+	end := offset + n + 2
+	if end > len(data) {
+		return 0, end - len(data), 0
+	}
+	if !bytes.Equal(data[end-2:end], crlf) {
+		return 0, 0, proxylib.ERROR_INVALID_FRAME_TYPE
+	}
+	return end, 0, 0
+}
+
+// parseCommand parses a request at the start of 'data' into its arguments.
+// Returns the frame length, or the number of bytes missing.
+func parseCommand(data []byte) (args [][]byte, frameLen, more int, errCode proxylib.OpError) {
+	if data[0] != '*' {
+		// Inline command
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			if len(data) > maxInlineLen {
+				return nil, 0, 0, proxylib.ERROR_INVALID_FRAME_LENGTH
+			}
+			return nil, 0, 1, 0
+		}
+		line := data[:end]
+		if len(line) > 0 && line[len(line)-1] == '\r' {
+			line = line[:len(line)-1]
+		}
+		args, ok := splitInlineArgs(line)
+		if !ok {
+			return nil, 0, 0, proxylib.ERROR_INVALID_FRAME_TYPE
+		}
+		return args, end + 1, 0, 0
+	}
+
+	end, errCode := lineEnd(data, 0)
+	if end < 0 {
+		return nil, 0, 1, errCode
+	}
+	n, errCode := parseLength(data[1:end], maxMultiBulkLen)
+	if errCode != 0 {
+		return nil, 0, 0, errCode
+	}
+	if n < 1 {
+		return nil, 0, 0, proxylib.ERROR_INVALID_FRAME_LENGTH
+	}
+	next := end + 2
+	for i := 0; i < n; i++ {
+		if next >= len(data) {
+			return nil, 0, 1, 0
+		}
+		if data[next] != '$' {
+			return nil, 0, 0, proxylib.ERROR_INVALID_FRAME_TYPE
+		}
+		end, errCode = lineEnd(data, next)
+		if end < 0 {
+			return nil, 0, 1, errCode
+		}
+		l, errCode := parseLength(data[next+1:end], maxBulkLen)
+		if errCode != 0 {
+			return nil, 0, 0, errCode
+		}
+		if l < 0 {
+			return nil, 0, 0, proxylib.ERROR_INVALID_FRAME_LENGTH
+		}
+		start := end + 2
+		next, more, errCode = bulkEnd(data, start, l)
+		if more > 0 || errCode != 0 {
+			return nil, 0, more, errCode
+		}
+		args = append(args, data[start:start+l])
+	}
+	return args, next, 0, 0
+}
+
+// isSpace returns true for the characters of C isspace()
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\v' || c == '\f' || c == '\r'
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func hexDigitValue(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	}
+	return c - '0'
+}
+
+// splitInlineArgs splits an inline command into its arguments the same way
+// as sdssplitargs() of the Redis server, which honours double quotes with
+// escapes and single quotes. Returns false if the quotes are unbalanced, in
+// which case the server rejects the command, or if the line contains a
+// NUL byte, which the server does not read past.
+func splitInlineArgs(line []byte) ([][]byte, bool) {
+	if bytes.IndexByte(line, 0) >= 0 {
+		return nil, false
+	}
+	var args [][]byte
+	p := 0
+	// at returns the byte at 'i', or NUL at the end of the line
+	at := func(i int) byte {
+		if i < len(line) {
+			return line[i]
+		}
+		return 0
+	}
+	for {
+		for p < len(line) && isSpace(line[p]) {
+			p++
+		}
+		if p == len(line) {
+			return args, true
+		}
+		inq, insq, done := false, false, false
+		current := []byte{}
+		for !done {
+			c := at(p)
+			switch {
+			case inq:
+				switch {
+				case c == '\\' && at(p+1) == 'x' && isHexDigit(at(p+2)) && isHexDigit(at(p+3)):
+					current = append(current, hexDigitValue(at(p+2))<<4|hexDigitValue(at(p+3)))
+					p += 3
+				case c == '\\' && at(p+1) != 0:
+					p++
+					switch c = line[p]; c {
+					case 'n':
+						c = '\n'
+					case 'r':
+						c = '\r'
+					case 't':
+						c = '\t'
+					case 'b':
+						c = '\b'
+					case 'a':
+						c = '\a'
+					}
+					current = append(current, c)
+				case c == '"':
+					// The closing quote must be followed by a space
+					// or nothing at all
+					if at(p+1) != 0 && !isSpace(at(p+1)) {
+						return nil, false
+					}
+					done = true
+				case c == 0:
+					return nil, false
+				default:
+					current = append(current, c)
+				}
+			case insq:
+				switch {
+				case c == '\\' && at(p+1) == '\'':
+					p++
+					current = append(current, '\'')
+				case c == '\'':
+					if at(p+1) != 0 && !isSpace(at(p+1)) {
+						return nil, false
+					}
+					done = true
+				case c == 0:
+					return nil, false
+				default:
+					current = append(current, c)
+				}
+			default:
+				switch c {
+				case ' ', '\n', '\r', '\t', 0:
+					done = true
+				case '"':
+					inq = true
+				case '\'':
+					insq = true
+				default:
+					current = append(current, c)
+				}
+			}
+			if p < len(line) {
+				p++
+			}
+		}
+		args = append(args, current)
+	}
+}
+
+// parseValue finds the end of the RESP value starting at 'offset' in
+// 'data'. Returns the position after the value, or the number of bytes
+// missing.
+func parseValue(data []byte, offset, depth int) (next, more int, errCode proxylib.OpError) {
+	if offset >= len(data) {
+		return 0, 1, 0
+	}
+	end, errCode := lineEnd(data, offset)
+	if end < 0 {
+		return 0, 1, errCode
+	}
+	// The type byte is missing on an empty line
+	if end == offset {
+		return 0, 0, proxylib.ERROR_INVALID_FRAME_TYPE
+	}
+	line := data[offset+1 : end]
+	next = end + 2
+
+	typ := data[offset]
+	switch typ {
+	case '+', '-', ':', '_', ',', '#', '(':
+		// simple string, error, integer, null, double, boolean, big number
+		return next, 0, 0
+	case '$', '!', '=':
+		// bulk string, bulk error, verbatim string
+		n, errCode := parseLength(line, maxBulkLen)
+		if errCode != 0 {
+			return 0, 0, errCode
+		}
+		if n < 0 {
+			return next, 0, 0
+		}
+		return bulkEnd(data, next, n)
+	case '*', '~', '>', '%', '|':
+		// array, set, push, map, attribute
+		if depth >= maxNestingDepth {
+			return 0, 0, proxylib.ERROR_INVALID_FRAME_LENGTH
+		}
+		n, errCode := parseLength(line, maxMultiBulkLen)
+		if errCode != 0 {
+			return 0, 0, errCode
+		}
+		if typ == '%' || typ == '|' {
+			n *= 2
+		}
+		// Attributes are followed by the value they describe
+		if typ == '|' {
+			n++
+		}
+		for i := 0; i < n; i++ {
+			next, more, errCode = parseValue(data, next, depth+1)
+			if more > 0 || errCode != 0 {
+				return 0, more, errCode
+			}
+		}
+		return next, 0, 0
+	}
+	return 0, 0, proxylib.ERROR_INVALID_FRAME_TYPE
+}
@@ -0,0 +1,379 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cilium/cilium/proxylib/accesslog"
+	"github.com/cilium/cilium/proxylib/proxylib"
+	"github.com/cilium/cilium/proxylib/test"
+
+	// log "github.com/sirupsen/logrus"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	// logging.ToggleDebugLogs(true)
+	// log.SetLevel(log.DebugLevel)
+
+	TestingT(t)
+}
+
+type RedisSuite struct {
+	logServer *test.AccessLogServer
+	ins       *proxylib.Instance
+}
+
+var _ = Suite(&RedisSuite{})
+
+// Set up access log server and Library instance for all the test cases
+func (s *RedisSuite) SetUpSuite(c *C) {
+	s.logServer = test.StartAccessLogServer("access_log.sock", 10)
+	c.Assert(s.logServer, Not(IsNil))
+	s.ins = proxylib.NewInstance("node1", accesslog.NewClient(s.logServer.Path))
+	c.Assert(s.ins, Not(IsNil))
+}
+
+func (s *RedisSuite) checkAccessLogs(c *C, expPasses, expDrops int) {
+	passes, drops := s.logServer.Clear()
+	c.Check(passes, Equals, expPasses, Commentf("Unxpected number of passed access log messages"))
+	c.Check(drops, Equals, expDrops, Commentf("Unxpected number of passed access log messages"))
+}
+
+func (s *RedisSuite) TearDownTest(c *C) {
+	s.logServer.Clear()
+}
+
+func (s *RedisSuite) TearDownSuite(c *C) {
+	s.logServer.Close()
+}
+
+func command(args ...string) string {
+	cmd := fmt.Sprintf("*%d\r\n", len(args))
+	for _, arg := range args {
+		cmd += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return cmd
+}
+
+func (s *RedisSuite) TestRedisOnDataIncomplete(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "redis", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:6379", "no-policy")
+	data := [][]byte{[]byte("*2\r\n$3\r\nGET\r\n$3\r\nke")}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{}, proxylib.MORE, 3)
+	data = [][]byte{[]byte("*2\r\n$3\r\nGET\r\n$")}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{}, proxylib.MORE, 1)
+}
+
+func (s *RedisSuite) TestRedisOnDataInvalid(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "redis", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:6379", "no-policy")
+	data := [][]byte{[]byte("*1\r\n+PING\r\n")}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE))
+	data = [][]byte{[]byte("*-5\r\n")}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_LENGTH))
+}
+
+func (s *RedisSuite) TestRedisOnDataInvalidReply(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "redis", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:6379", "no-policy")
+	data := [][]byte{[]byte("*1\r\n\r\n")}
+	conn.CheckOnDataOK(c, true, false, &data, []byte{},
+		proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE))
+
+	conn = s.ins.CheckNewConnectionOK(c, "redis", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:6379", "no-policy")
+	data = [][]byte{[]byte("\r\n")}
+	conn.CheckOnDataOK(c, true, false, &data, []byte{},
+		proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE))
+}
+
+func (s *RedisSuite) TestRedisOnDataBasicPass(c *C) {
+
+	// allow all rule
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "cp1"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 6379
+		  rules: <
+		    l7_proto: "redis"
+		  >
+		>
+		`})
+	conn := s.ins.CheckNewConnectionOK(c, "redis", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:6379", "cp1")
+	msg1 := command("SET", "key", "value")
+	msg2 := "PING\r\n"
+	msg3 := command("LRANGE", "list", "0", "-1")
+	data := [][]byte{[]byte(msg1 + msg2 + msg3)}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(msg1),
+		proxylib.PASS, len(msg2),
+		proxylib.PASS, len(msg3),
+		proxylib.MORE, 1)
+	s.checkAccessLogs(c, 3, 0)
+
+	reply1 := "+OK\r\n"
+	reply2 := "+PONG\r\n"
+	reply3 := "*3\r\n$1\r\na\r\n$-1\r\n*1\r\n:1\r\n"
+	data = [][]byte{[]byte(reply1 + reply2 + reply3)}
+	conn.CheckOnDataOK(c, true, false, &data, []byte{},
+		proxylib.PASS, len(reply1),
+		proxylib.PASS, len(reply2),
+		proxylib.PASS, len(reply3),
+		proxylib.MORE, 1)
+}
+
+func (s *RedisSuite) TestRedisOnDataAllowDenyCommand(c *C) {
+
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "cp2"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 6379
+		  rules: <
+		    l7_proto: "redis"
+		    l7_rules: <
+		      l7_rules: <
+		        rule: <
+		          key: "command"
+		          value: "get"
+		        >
+		      >
+		    >
+		  >
+		>
+		`})
+	conn := s.ins.CheckNewConnectionOK(c, "redis", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:6379", "cp2")
+	msg1 := command("FLUSHALL")
+	msg2 := command("GET", "key")
+	msg3 := command("CONFIG", "SET", "maxmemory", "0")
+	data := [][]byte{[]byte(msg1 + msg2 + msg3)}
+	// The first denied request is replied to right away, the second one
+	// after the reply to the GET.
+	conn.CheckOnDataOK(c, false, false, &data, DeniedMsg,
+		proxylib.DROP, len(msg1),
+		proxylib.PASS, len(msg2),
+		proxylib.DROP, len(msg3),
+		proxylib.MORE, 1)
+	s.checkAccessLogs(c, 1, 2)
+
+	reply := "$5\r\nvalue\r\n"
+	data = [][]byte{[]byte(reply)}
+	conn.CheckOnDataOK(c, true, false, &data, DeniedMsg,
+		proxylib.PASS, len(reply),
+		proxylib.INJECT, len(DeniedMsg),
+		proxylib.MORE, 1)
+}
+
+func (s *RedisSuite) TestRedisOnDataKeyPrefix(c *C) {
+
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "cp3"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 6379
+		  rules: <
+		    l7_proto: "redis"
+		    l7_rules: <
+		      l7_rules: <
+		        rule: <
+		          key: "keyPrefix"
+		          value: "app1:"
+		        >
+		      >
+		    >
+		  >
+		>
+		`})
+	conn := s.ins.CheckNewConnectionOK(c, "redis", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:6379", "cp3")
+	msg1 := command("MSET", "app1:a", "1", "app1:b", "2")
+	msg2 := command("MSET", "app1:a", "1", "app2:b", "2")
+	msg3 := command("KEYS", "*")
+	msg4 := command("EVAL", "return 1", "1", "app1:a")
+	data := [][]byte{[]byte(msg1 + msg2 + msg3 + msg4)}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(msg1),
+		proxylib.DROP, len(msg2),
+		proxylib.DROP, len(msg3),
+		proxylib.PASS, len(msg4),
+		proxylib.MORE, 1)
+	s.checkAccessLogs(c, 2, 2)
+
+	reply1 := "+OK\r\n"
+	reply2 := ":1\r\n"
+	data = [][]byte{[]byte(reply1 + reply2)}
+	conn.CheckOnDataOK(c, true, false, &data, append(append([]byte{}, DeniedMsg...), DeniedMsg...),
+		proxylib.PASS, len(reply1),
+		proxylib.INJECT, 2*len(DeniedMsg),
+		proxylib.PASS, len(reply2),
+		proxylib.MORE, 1)
+}
+
+func (s *RedisSuite) TestRedisOnDataKeyPrefixSort(c *C) {
+
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "cp5"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 6379
+		  rules: <
+		    l7_proto: "redis"
+		    l7_rules: <
+		      l7_rules: <
+		        rule: <
+		          key: "keyPrefix"
+		          value: "app1:"
+		        >
+		      >
+		    >
+		  >
+		>
+		`})
+	conn := s.ins.CheckNewConnectionOK(c, "redis", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:6379", "cp5")
+	msg1 := command("SORT", "app1:list", "store", "app1:sorted")
+	msg2 := command("SORT", "app1:list", "STORE", "app2:sorted")
+	msg3 := command("SORT", "app1:list", "BY", "app2:weight_*", "GET", "#")
+	msg4 := command("SORT", "app1:list", "LIMIT", "0", "10", "BY", "nosort", "GET", "app1:object_*", "GET", "#", "ALPHA")
+	data := [][]byte{[]byte(msg1 + msg2 + msg3 + msg4)}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(msg1),
+		proxylib.DROP, len(msg2),
+		proxylib.DROP, len(msg3),
+		proxylib.PASS, len(msg4),
+		proxylib.MORE, 1)
+	s.checkAccessLogs(c, 2, 2)
+}
+
+func (s *RedisSuite) TestRedisOnDataTransaction(c *C) {
+
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "cp4"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 6379
+		  rules: <
+		    l7_proto: "redis"
+		    l7_rules: <
+		      l7_rules: <
+		        rule: <
+		          key: "command"
+		          value: "INCR"
+		        >
+		      >
+		    >
+		  >
+		>
+		`})
+	conn := s.ins.CheckNewConnectionOK(c, "redis", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:6379", "cp4")
+	msg1 := command("MULTI")
+	msg2 := command("INCR", "counter")
+	msg3 := command("DEL", "counter")
+	msg4 := command("EXEC")
+	data := [][]byte{[]byte(msg1 + msg2 + msg3 + msg4)}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(msg1),
+		proxylib.PASS, len(msg2),
+		proxylib.DROP, len(msg3),
+		proxylib.DROP, len(msg4),
+		proxylib.INJECT, len(DiscardCmd),
+		proxylib.MORE, 1)
+	c.Check(*conn.OrigBuf, DeepEquals, DiscardCmd)
+	*conn.OrigBuf = (*conn.OrigBuf)[:0]
+	s.checkAccessLogs(c, 3, 1)
+
+	reply1 := "+OK\r\n"
+	reply2 := "+QUEUED\r\n"
+	reply3 := "+OK\r\n"
+	data = [][]byte{[]byte(reply1 + reply2 + reply3)}
+	conn.CheckOnDataOK(c, true, false, &data, append(append([]byte{}, DeniedMsg...), ExecAbortMsg...),
+		proxylib.PASS, len(reply1),
+		proxylib.PASS, len(reply2),
+		proxylib.INJECT, len(DeniedMsg),
+		proxylib.DROP, len(reply3),
+		proxylib.INJECT, len(ExecAbortMsg),
+		proxylib.MORE, 1)
+}
+
+func (s *RedisSuite) TestRedisOnDataKeyPrefixInline(c *C) {
+
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "cp5"
+		policy: 2
+		ingress_per_port_policies: <
+		  port: 6379
+		  rules: <
+		    l7_proto: "redis"
+		    l7_rules: <
+		      l7_rules: <
+		        rule: <
+		          key: "keyPrefix"
+		          value: "app1:"
+		        >
+		      >
+		    >
+		  >
+		>
+		`})
+	conn := s.ins.CheckNewConnectionOK(c, "redis", true, 1, 2, "1.1.1.1:34567", "2.2.2.2:6379", "cp5")
+	msg1 := "SET \"app1:a b\" 'x y'\r\n"
+	msg2 := "RENAME app1:\"x app1:y\" other:z\r\n"
+	msg3 := "MSET app1:a \"1 app2:b\" app2:c 2\r\n"
+	data := [][]byte{[]byte(msg1 + msg2 + msg3)}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(msg1),
+		proxylib.DROP, len(msg2),
+		proxylib.DROP, len(msg3),
+		proxylib.MORE, 1)
+	s.checkAccessLogs(c, 1, 2)
+
+	data = [][]byte{[]byte("GET \"app1:a\r\n")}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE))
+}
+
+func (s *RedisSuite) TestSplitInlineArgs(c *C) {
+	for _, tc := range []struct {
+		line string
+		args []string
+		ok   bool
+	}{
+		{"", nil, true},
+		{"  GET  key ", []string{"GET", "key"}, true},
+		{"GET\tkey\vx", []string{"GET", "key\vx"}, true},
+		{`SET "a b" 'c d'`, []string{"SET", "a b", "c d"}, true},
+		{`SET a"b c"d`, nil, false},
+		{`SET a"b c" d`, []string{"SET", "ab c", "d"}, true},
+		{`SET "\x41\n\"\q" 'it\'s'`, []string{"SET", "A\n\"q", "it's"}, true},
+		{`SET "" ''`, []string{"SET", "", ""}, true},
+		{`GET "key`, nil, false},
+		{`GET 'key`, nil, false},
+		{"GET a\x00b", nil, false},
+	} {
+		args, ok := splitInlineArgs([]byte(tc.line))
+		c.Assert(ok, Equals, tc.ok, Commentf("line: %q", tc.line))
+		if !ok {
+			continue
+		}
+		var strs []string
+		for _, arg := range args {
+			strs = append(strs, string(arg))
+		}
+		c.Assert(strs, DeepEquals, tc.args, Commentf("line: %q", tc.line))
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// TLS ClientHello parser based on https://tools.ietf.org/html/rfc5246 and
+// the server_name extension defined in https://tools.ietf.org/html/rfc6066
+
+package tls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/envoy/cilium"
+	"github.com/cilium/cilium/proxylib/proxylib"
+
+	log "github.com/sirupsen/logrus"
+)
+
+//
+// TLS Parser
+//
+// The ClientHello starting a TLS connection is parsed for the Server Name
+// Indication (SNI) the client is connecting to. If the policy allows the
+// server name, the rest of the connection is passed record by record in
+// both directions without further inspection. Otherwise the client
+// receives a fatal access_denied alert and all further data from the
+// client is dropped.
+//
+// Policy Examples:
+// {serverName : "api.github.com"} - Allow connections to api.github.com only.
+// {serverName : "*.example.com"} - Allow connections to any name directly
+//                                  below example.com, but not example.com.
+//
+// A ClientHello without a server name only matches rules without a
+// 'serverName'.
+
+const (
+	recordHeaderLen    = 5
+	handshakeHeaderLen = 4
+
+	// TLSCiphertext records may be up to 2^14 + 2048 bytes long
+	maxRecordLen = 16384 + 2048
+	// Limit the size of the ClientHello we are willing to reassemble
+	maxClientHelloLen = 64 * 1024
+
+	recordTypeAlert          = 21
+	recordTypeHandshake      = 22
+	handshakeTypeClientHello = 1
+	extensionServerName      = 0
+	serverNameTypeHostName   = 0
+)
+
+// AccessDeniedAlert is the fatal access_denied alert sent if policy denies
+// the connection. Exported for tests
+var AccessDeniedAlert = []byte{recordTypeAlert, 3, 3, 0, 2, 2, 49}
+
+type tlsRule struct {
+	serverName string
+}
+
+type tlsRequestData struct {
+	serverName string
+}
+
+func (rule *tlsRule) Matches(data interface{}) bool {
+	// Cast 'data' to the type we give to 'Matches()'
+	reqData, ok := data.(tlsRequestData)
+	if !ok {
+		log.Warning("Matches() called with type other than tlsRequestData")
+		return false
+	}
+	if rule.serverName == "" {
+		return true
+	}
+	if strings.HasPrefix(rule.serverName, "*.") {
+		// The wildcard matches exactly one non-empty label
+		suffix := rule.serverName[1:]
+		label := strings.TrimSuffix(reqData.serverName, suffix)
+		if len(label) < len(reqData.serverName) && label != "" && !strings.Contains(label, ".") {
+			return true
+		}
+	} else if rule.serverName == reqData.serverName {
+		return true
+	}
+	log.Debugf("TLSRule: server name mismatch %s, %s", rule.serverName, reqData.serverName)
+	return false
+}
+
+// ruleParser parses protobuf L7 rules to enforcement objects
+// May panic
+func ruleParser(rule *cilium.PortNetworkPolicyRule) []proxylib.L7NetworkPolicyRule {
+	l7Rules := rule.GetL7Rules()
+	var rules []proxylib.L7NetworkPolicyRule
+	if l7Rules == nil {
+		return rules
+	}
+	for _, l7Rule := range l7Rules.GetL7Rules() {
+		var tr tlsRule
+		for k, v := range l7Rule.Rule {
+			switch k {
+			case "serverName":
+				if v == "" || strings.ContainsAny(v, " \t\r\n") {
+					proxylib.ParseError(fmt.Sprintf("Unable to parse L7 tls rule with invalid serverName: '%s'", v), rule)
+				}
+				tr.serverName = canonicalServerName(v)
+			default:
+				proxylib.ParseError(fmt.Sprintf("Unsupported key: %s", k), rule)
+			}
+		}
+		log.Debugf("Parsed rule '%s'", tr.serverName)
+		rules = append(rules, &tr)
+	}
+	return rules
+}
+
+// canonicalServerName returns 'name' in lowercase without a trailing dot
+func canonicalServerName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
+
+type factory struct{}
+
+func init() {
+	log.Info("init(): Registering tlsParserFactory")
+	proxylib.RegisterParserFactory("tls", &factory{})
+	proxylib.RegisterL7RuleParser("tls", ruleParser)
+}
+
+type parser struct {
+	connection *proxylib.Connection
+
+	allowed bool // ClientHello was allowed by the policy
+	denied  bool // ClientHello was denied by the policy
+}
+
+func (f *factory) Create(connection *proxylib.Connection) proxylib.Parser {
+	log.Debugf("TLSParserFactory: Create: %v", connection)
+
+	return &parser{connection: connection}
+}
+
+func (p *parser) OnData(reply, endStream bool, dataArray [][]byte) (proxylib.OpType, int) {
+	// inefficient, but simple
+	data := bytes.Join(dataArray, []byte{})
+	if len(data) == 0 {
+		return proxylib.MORE, 1
+	}
+
+	if p.denied && !reply {
+		return proxylib.DROP, len(data)
+	}
+	if p.allowed || reply {
+		return passRecord(data)
+	}
+
+	msg, recordsLen, more, errCode := readClientHello(data)
+	if errCode != 0 {
+		log.Debugf("Invalid ClientHello: %s", errCode)
+		return proxylib.ERROR, int(errCode)
+	}
+	if more > 0 {
+		return proxylib.MORE, more
+	}
+
+	serverName, ok := parseServerName(msg)
+	if !ok {
+		log.Debug("Malformed ClientHello")
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE)
+	}
+	reqData := tlsRequestData{serverName: serverName}
+
+	entryType := cilium.EntryType_Request
+	if p.connection.Matches(reqData) {
+		p.allowed = true
+	} else {
+		p.denied = true
+		entryType = cilium.EntryType_Denied
+	}
+
+	p.connection.Log(entryType,
+		&cilium.LogEntry_GenericL7{
+			GenericL7: &cilium.L7LogEntry{
+				Proto: "tls",
+				Fields: map[string]string{
+					"serverName": serverName,
+				},
+			},
+		})
+
+	if p.denied {
+		p.connection.Inject(true, AccessDeniedAlert)
+		log.Debugf("Policy mismatch, dropping %d bytes", len(data))
+		return proxylib.DROP, len(data)
+	}
+	return proxylib.PASS, recordsLen
+}
+
+// passRecord passes the TLS record at the beginning of 'data', which may
+// not have been fully received yet
+func passRecord(data []byte) (proxylib.OpType, int) {
+	if len(data) < recordHeaderLen {
+		return proxylib.MORE, recordHeaderLen - len(data)
+	}
+	length := int(binary.BigEndian.Uint16(data[3:5]))
+	if length > maxRecordLen {
+		return proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_LENGTH)
+	}
+	return proxylib.PASS, recordHeaderLen + length
+}
+
+// readClientHello reassembles the ClientHello handshake message from the
+// TLS records at the beginning of 'data'. Returns the message body and the
+// total length of the records carrying it, or the number of additional
+// bytes needed.
+func readClientHello(data []byte) (msg []byte, recordsLen int, more int, errCode proxylib.OpError) {
+	var handshake []byte
+	offset := 0
+	for {
+		if len(data) < offset+recordHeaderLen {
+			return nil, 0, offset + recordHeaderLen - len(data), 0
+		}
+		header := data[offset : offset+recordHeaderLen]
+		if header[0] != recordTypeHandshake || header[1] != 3 {
+			return nil, 0, 0, proxylib.ERROR_INVALID_FRAME_TYPE
+		}
+		length := int(binary.BigEndian.Uint16(header[3:5]))
+		if length == 0 || length > maxRecordLen {
+			return nil, 0, 0, proxylib.ERROR_INVALID_FRAME_LENGTH
+		}
+		end := offset + recordHeaderLen + length
+		if len(data) < end {
+			return nil, 0, end - len(data), 0
+		}
+		handshake = append(handshake, data[offset+recordHeaderLen:end]...)
+		offset = end
+
+		if len(handshake) >= handshakeHeaderLen {
+			if handshake[0] != handshakeTypeClientHello {
+				return nil, 0, 0, proxylib.ERROR_INVALID_FRAME_TYPE
+			}
+			msgLen := handshakeHeaderLen + (int(handshake[1])<<16 | int(handshake[2])<<8 | int(handshake[3]))
+			if msgLen > maxClientHelloLen {
+				return nil, 0, 0, proxylib.ERROR_INVALID_FRAME_LENGTH
+			}
+			if len(handshake) >= msgLen {
+				return handshake[handshakeHeaderLen:msgLen], offset, 0, 0
+			}
+		}
+	}
+}
+
+// reader consumes big-endian fields from a byte slice. Reads past the end
+// of the data clear 'ok' and return zero values.
+type reader struct {
+	data []byte
+	ok   bool
+}
+
+func (r *reader) bytes(n int) []byte {
+	if !r.ok || n > len(r.data) {
+		r.ok = false
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) uint8() int {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return int(b[0])
+}
+
+func (r *reader) uint16() int {
+	b := r.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return int(binary.BigEndian.Uint16(b))
+}
+
+// parseServerName returns the host name of the server_name extension of
+// the ClientHello body 'msg', or an empty string if there is none. Returns
+// false if 'msg' is malformed.
+func parseServerName(msg []byte) (string, bool) {
+	r := reader{data: msg, ok: true}
+	r.bytes(2)          // client_version
+	r.bytes(32)         // random
+	r.bytes(r.uint8())  // session_id
+	r.bytes(r.uint16()) // cipher_suites
+	r.bytes(r.uint8())  // compression_methods
+	if r.ok && len(r.data) == 0 {
+		// No extensions
+		return "", true
+	}
+	extensions := reader{data: r.bytes(r.uint16()), ok: r.ok}
+	for extensions.ok && len(extensions.data) > 0 {
+		extType := extensions.uint16()
+		extData := extensions.bytes(extensions.uint16())
+		if !extensions.ok || extType != extensionServerName {
+			continue
+		}
+		ext := reader{data: extData, ok: true}
+		list := reader{data: ext.bytes(ext.uint16()), ok: ext.ok}
+		for list.ok && len(list.data) > 0 {
+			nameType := list.uint8()
+			name := list.bytes(list.uint16())
+			if list.ok && nameType == serverNameTypeHostName {
+				return canonicalServerName(string(name)), true
+			}
+		}
+		return "", list.ok
+	}
+	return "", extensions.ok
+}
@@ -0,0 +1,204 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !privileged_tests
+
+package tls
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/proxylib/accesslog"
+	"github.com/cilium/cilium/proxylib/proxylib"
+	"github.com/cilium/cilium/proxylib/test"
+
+	// log "github.com/sirupsen/logrus"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	// logging.ToggleDebugLogs(true)
+	// log.SetLevel(log.DebugLevel)
+
+	TestingT(t)
+}
+
+type TLSSuite struct {
+	logServer *test.AccessLogServer
+	ins       *proxylib.Instance
+}
+
+var _ = Suite(&TLSSuite{})
+
+// Set up access log server and Library instance for all the test cases
+func (s *TLSSuite) SetUpSuite(c *C) {
+	s.logServer = test.StartAccessLogServer("access_log.sock", 10)
+	c.Assert(s.logServer, Not(IsNil))
+	s.ins = proxylib.NewInstance("node1", accesslog.NewClient(s.logServer.Path))
+	c.Assert(s.ins, Not(IsNil))
+
+	s.ins.CheckInsertPolicyText(c, "1", []string{`
+		name: "sni"
+		policy: 2
+		egress_per_port_policies: <
+		  port: 443
+		  rules: <
+		    l7_proto: "tls"
+		    l7_rules: <
+		      l7_rules: <
+		        rule: <
+		          key: "serverName"
+		          value: "api.github.com"
+		        >
+		      >
+		      l7_rules: <
+		        rule: <
+		          key: "serverName"
+		          value: "*.Example.com"
+		        >
+		      >
+		    >
+		  >
+		>
+		`})
+}
+
+func (s *TLSSuite) checkAccessLogs(c *C, expPasses, expDrops int) {
+	passes, drops := s.logServer.Clear()
+	c.Check(passes, Equals, expPasses, Commentf("Unxpected number of passed access log messages"))
+	c.Check(drops, Equals, expDrops, Commentf("Unxpected number of passed access log messages"))
+}
+
+func (s *TLSSuite) TearDownTest(c *C) {
+	s.logServer.Clear()
+}
+
+func (s *TLSSuite) TearDownSuite(c *C) {
+	s.logServer.Close()
+}
+
+// clientHello returns the first flight of a crypto/tls client connecting
+// to 'serverName'
+func clientHello(c *C, serverName string) []byte {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	go func() {
+		conn := tls.Client(client, &tls.Config{ServerName: serverName, InsecureSkipVerify: true})
+		conn.Handshake()
+		conn.Close()
+	}()
+
+	server.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 64*1024)
+	n, err := server.Read(buf)
+	c.Assert(err, IsNil)
+	return buf[:n]
+}
+
+// fragment splits the handshake record 'record' into two records, the
+// first one carrying 'n' bytes of the handshake data
+func fragment(record []byte, n int) []byte {
+	payload := record[recordHeaderLen:]
+	first := append([]byte{record[0], record[1], record[2], byte(n >> 8), byte(n)}, payload[:n]...)
+	rest := len(payload) - n
+	second := append([]byte{record[0], record[1], record[2], byte(rest >> 8), byte(rest)}, payload[n:]...)
+	return append(first, second...)
+}
+
+var appData = []byte{23, 3, 3, 0, 4, 1, 2, 3, 4}
+
+func (s *TLSSuite) TestTLSOnDataIncomplete(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "tls", false, 1, 2, "1.1.1.1:34567", "2.2.2.2:443", "sni")
+	hello := clientHello(c, "api.github.com")
+
+	data := [][]byte{hello[:3]}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{}, proxylib.MORE, 2)
+	data = [][]byte{hello[:10]}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{}, proxylib.MORE, len(hello)-10)
+	s.checkAccessLogs(c, 0, 0)
+}
+
+func (s *TLSSuite) TestTLSOnDataAllow(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "tls", false, 1, 2, "1.1.1.1:34567", "2.2.2.2:443", "sni")
+	hello := clientHello(c, "api.github.com")
+
+	data := [][]byte{hello, appData[:7]}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(hello),
+		proxylib.PASS, len(appData),
+		proxylib.MORE, 1)
+	data = [][]byte{appData}
+	conn.CheckOnDataOK(c, true, false, &data, []byte{},
+		proxylib.PASS, len(appData),
+		proxylib.MORE, 1)
+	s.checkAccessLogs(c, 1, 0)
+}
+
+func (s *TLSSuite) TestTLSOnDataAllowWildcard(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "tls", false, 1, 2, "1.1.1.1:34567", "2.2.2.2:443", "sni")
+	hello := fragment(clientHello(c, "WWW.example.com"), 20)
+
+	data := [][]byte{hello[:30]}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{}, proxylib.MORE, len(hello)-30)
+	data = [][]byte{hello}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.PASS, len(hello),
+		proxylib.MORE, 1)
+	s.checkAccessLogs(c, 1, 0)
+}
+
+func (s *TLSSuite) TestTLSOnDataDeny(c *C) {
+	for _, serverName := range []string{"github.com", "example.com", "a.b.example.com", ""} {
+		conn := s.ins.CheckNewConnectionOK(c, "tls", false, 1, 2, "1.1.1.1:34567", "2.2.2.2:443", "sni")
+		hello := clientHello(c, serverName)
+
+		data := [][]byte{hello}
+		conn.CheckOnDataOK(c, false, false, &data, AccessDeniedAlert,
+			proxylib.DROP, len(hello),
+			proxylib.MORE, 1)
+		// Nothing more is sent to the server
+		data = [][]byte{appData}
+		conn.CheckOnDataOK(c, false, false, &data, []byte{},
+			proxylib.DROP, len(appData),
+			proxylib.MORE, 1)
+		s.checkAccessLogs(c, 0, 1)
+	}
+}
+
+func (s *TLSSuite) TestTLSOnDataNotHandshake(c *C) {
+	conn := s.ins.CheckNewConnectionOK(c, "tls", false, 1, 2, "1.1.1.1:34567", "2.2.2.2:443", "sni")
+	data := [][]byte{[]byte("GET / HTTP/1.1\r\n\r\n")}
+	conn.CheckOnDataOK(c, false, false, &data, []byte{},
+		proxylib.ERROR, int(proxylib.ERROR_INVALID_FRAME_TYPE))
+	s.checkAccessLogs(c, 0, 0)
+}
+
+func (s *TLSSuite) TestParseServerName(c *C) {
+	hello := clientHello(c, "api.github.com.")
+	msg, recordsLen, more, errCode := readClientHello(hello)
+	c.Assert(errCode, Equals, proxylib.OpError(0))
+	c.Assert(more, Equals, 0)
+	c.Assert(recordsLen, Equals, len(hello))
+	name, ok := parseServerName(msg)
+	c.Assert(ok, Equals, true)
+	c.Assert(name, Equals, "api.github.com")
+
+	_, ok = parseServerName(msg[:50])
+	c.Assert(ok, Equals, false)
+}